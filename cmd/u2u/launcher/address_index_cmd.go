@@ -0,0 +1,102 @@
+package launcher
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/unicornultrafoundation/go-u2u/cmd/utils"
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/gossip"
+	"github.com/unicornultrafoundation/go-u2u/gossip/evmstore"
+	txtrace "github.com/unicornultrafoundation/go-u2u/gossip/txtracer"
+	"github.com/unicornultrafoundation/go-u2u/log"
+	"github.com/unicornultrafoundation/go-u2u/utils/caution"
+	"github.com/unicornultrafoundation/go-u2u/utils/signers/gsignercache"
+)
+
+// indexAddresses backfills the index of the transactions by address from the stored blocks
+func indexAddresses(ctx *cli.Context) (err error) {
+	cfg := makeAllConfigs(ctx)
+	cfg.U2UStore.EVM.AddressIndex = true
+
+	rawDbs := makeDirectDBsProducer(cfg)
+	defer caution.CloseAndReportError(&err, rawDbs, "failed to close raw DBs")
+	gdb := makeGossipStore(rawDbs, cfg)
+	defer caution.CloseAndReportError(&err, gdb, "failed to close Gossip DB")
+
+	from := idx.Block(1)
+	if len(ctx.Args()) > 0 {
+		n, err := strconv.ParseUint(ctx.Args().Get(0), 10, 64)
+		if err != nil {
+			return err
+		}
+		from = idx.Block(n)
+	}
+	to := gdb.GetLatestBlockIndex()
+	if len(ctx.Args()) > 1 {
+		n, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+		if err != nil {
+			return err
+		}
+		to = idx.Block(n)
+	}
+
+	log.Info("Indexing transactions by address", "from block", from, "to block", to)
+	err = backfillAddressIndex(gdb, from, to)
+	if err != nil {
+		utils.Fatalf("Indexing addresses error: %v\n", err)
+	}
+
+	return nil
+}
+
+// backfillAddressIndex adds the transactions of the blocks into the address index.
+// The internal calls are indexed only for the transactions with stored traces.
+func backfillAddressIndex(gdb *gossip.Store, from, to idx.Block) error {
+	start, reported := time.Now(), time.Now()
+
+	signer := gsignercache.Wrap(types.LatestSignerForChainID(gdb.GetEvmChainConfig().ChainID))
+	var counter int
+	for n := from; n <= to; n++ {
+		block := gdb.GetBlock(n)
+		if block == nil {
+			continue
+		}
+		txs := gdb.GetBlockTxs(n, block)
+		if len(txs) == 0 {
+			continue
+		}
+		receipts := gdb.EvmStore().GetReceipts(n, signer, common.Hash(block.Atropos), txs)
+		if len(receipts) == 0 {
+			// receipts aren't stored if the transaction index is disabled
+			continue
+		}
+		calls := make(map[common.Hash]*evmstore.InternalCalls)
+		if gdb.TxTraceStore() != nil {
+			for _, tx := range txs {
+				traces := gdb.TxTraceStore().GetTx(tx.Hash())
+				if len(traces) == 0 {
+					continue
+				}
+				addrs, created, err := txtrace.TraceAddresses(traces)
+				if err != nil {
+					return fmt.Errorf("transaction %s: %w", tx.Hash().String(), err)
+				}
+				calls[tx.Hash()] = &evmstore.InternalCalls{Addresses: addrs, Created: created}
+			}
+		}
+		gdb.EvmStore().IndexAddresses(n, txs, receipts, signer, calls)
+		counter += len(txs)
+		if time.Since(reported) >= statsReportLimit {
+			log.Info("Indexing transactions by address", "block", n, "indexed", counter, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	log.Info("Indexed transactions by address", "indexed", counter, "from block", from, "to block", to, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
@@ -70,7 +70,8 @@ The import command imports EVM storage (trie nodes, code, preimages) from files.
 			U2U import txtracer
 
 			The import command imports transaction traces and replaces the old ones 
-			with traces from a file.
+			with traces from a file. The address index of the imported traces is
+			rebuilt along with them.
 			`,
 			},
 		},
@@ -173,6 +174,48 @@ be gzipped
 Optional first and second arguments control the first and
 last block to delete transaction traces from. If the file ends with .gz, the output will
 be gzipped
+`,
+			},
+		},
+	}
+	indexCommand = cli.Command{
+		Name:     "index",
+		Usage:    "Build blockchain data indexes",
+		Category: "MISCELLANEOUS COMMANDS",
+
+		Subcommands: []cli.Command{
+			{
+				Name:   "txtraces",
+				Usage:  "Build the address index of stored transaction traces",
+				Action: utils.MigrateFlags(indexTxTracer),
+				Flags: []cli.Flag{
+					DataDirFlag,
+				},
+				Description: `
+    u2u index txtraces
+
+Adds the stored transaction traces into the address index used by trace_filter
+and recalculates the size accounting of the traces. Required once for databases
+created before the index was introduced, the new traces are indexed when they
+are stored.
+`,
+			},
+			{
+				Name:      "addresses",
+				Usage:     "Backfill the index of transactions by address",
+				ArgsUsage: "[<blockFrom> <blockTo>]",
+				Action:    utils.MigrateFlags(indexAddresses),
+				Flags: []cli.Flag{
+					DataDirFlag,
+				},
+				Description: `
+    u2u index addresses
+
+Adds the transactions of the stored blocks into the index of transactions by address
+used by u2u_getTransactionsByAddress and the ots_ namespace. Optional first and second
+arguments control the first and last block to index. The participants of the internal
+calls are indexed only for the transactions whose traces are stored. Run it once after
+enabling the index with --addressindex, the new blocks are indexed during processing.
 `,
 			},
 		},
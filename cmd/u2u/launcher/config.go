@@ -14,11 +14,13 @@ import (
 	"github.com/naoina/toml"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/unicornultrafoundation/go-helios/consensus"
+	"github.com/unicornultrafoundation/go-helios/native/idx"
 	"github.com/unicornultrafoundation/go-helios/utils/cachescale"
 	"gopkg.in/urfave/cli.v1"
 
 	"github.com/unicornultrafoundation/go-u2u/cmd/utils"
 	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
 	"github.com/unicornultrafoundation/go-u2u/evmcore"
 	"github.com/unicornultrafoundation/go-u2u/gossip"
 	"github.com/unicornultrafoundation/go-u2u/gossip/emitter"
@@ -127,6 +129,50 @@ var (
 		Name:  "enabletxtracer",
 		Usage: "DO NOT RUN THIS OPTION AS VALIDATOR. Enable node records inner transaction traces for debugging purpose",
 	}
+	TxTracerRetentionBlocksFlag = cli.Uint64Flag{
+		Name:  "txtracer.retention.blocks",
+		Usage: "Number of latest blocks to keep transaction traces for (0 = keep all)",
+	}
+	TxTracerRetentionEpochsFlag = cli.Uint64Flag{
+		Name:  "txtracer.retention.epochs",
+		Usage: "Number of latest epochs to keep transaction traces for (0 = keep all)",
+	}
+
+	// AddressIndexFlag enables indexing of the transactions by the appearing addresses
+	AddressIndexFlag = cli.BoolFlag{
+		Name:  "addressindex",
+		Usage: "Enable the index of transactions by address (sender, recipient, internal calls, log emitters) for u2u_getTransactionsByAddress and ots_ APIs",
+	}
+
+	// AncientEpochsFlag enables the migration of the old events, blocks and receipts into the ancient store
+	AncientEpochsFlag = cli.Uint64Flag{
+		Name:  "db.ancient.epochs",
+		Usage: "Number of latest epochs to keep in the key-value database, older events, blocks and receipts are moved into the ancient store (0 = keep all)",
+	}
+
+	// EventsHistoryEpochsFlag enables the pruning of the old DAG events
+	EventsHistoryEpochsFlag = cli.Uint64Flag{
+		Name:  "events.history.epochs",
+		Usage: "Number of latest epochs to keep DAG events for, older events are pruned while blocks and LLR records are kept. Requires --gcmode other than 'archive' (0 = keep all)",
+	}
+
+	// ReceiptsHistoryFlag enables the pruning of the old receipts and logs
+	ReceiptsHistoryFlag = cli.Uint64Flag{
+		Name:  "history.receipts",
+		Usage: "Number of latest blocks to keep receipts, transaction positions and logs index for, older ones are pruned (0 = keep all)",
+	}
+
+	// StatePruningBlocksFlag enables the online pruning of the EVM state
+	StatePruningBlocksFlag = cli.Uint64Flag{
+		Name:  "state.prune.blocks",
+		Usage: "Number of latest blocks to keep EVM state for, older trie nodes are pruned in background while the node is running. Requires --gcmode other than 'archive' (0 = keep all)",
+	}
+
+	// StateHistoryFlag enables the path scheme of the EVM state
+	StateHistoryFlag = cli.BoolFlag{
+		Name:  "state.history",
+		Usage: "Store the EVM state by paths and record the reverse diffs of the EVM state, so the state of old blocks is served without an archive node (same as the '-path' DB presets)",
+	}
 
 	DBMigrationModeFlag = cli.StringFlag{
 		Name:  "db.migration.mode",
@@ -134,7 +180,7 @@ var (
 	}
 	DBPresetFlag = cli.StringFlag{
 		Name:  "db.preset",
-		Usage: "DBs layout preset ('pebble' or 'legacy-pebble')",
+		Usage: "DBs layout preset ('pebble', 'legacy-pebble', 'pebble-path' or 'legacy-pebble-path'). The '-path' presets store the EVM state by paths along with the state history, an existing EVM state is migrated",
 	}
 
 	// MonitoringFlag defines APIs endpoint to mornitor metrics
@@ -364,6 +410,9 @@ func gossipConfigWithFlags(ctx *cli.Context, src gossip.Config) (gossip.Config,
 	if ctx.GlobalIsSet(utils.AllowUnprotectedTxs.Name) {
 		cfg.AllowUnprotectedTxs = ctx.GlobalBool(utils.AllowUnprotectedTxs.Name)
 	}
+	if ctx.GlobalIsSet(utils.SFCHaltOnDivergenceFlag.Name) {
+		cfg.HaltOnSfcDivergence = ctx.GlobalBool(utils.SFCHaltOnDivergenceFlag.Name)
+	}
 
 	return cfg, nil
 }
@@ -377,6 +426,24 @@ func gossipStoreConfigWithFlags(ctx *cli.Context, src gossip.StoreConfig) (gossi
 		cfg.EVM.Cache.TrieDirtyDisabled = ctx.GlobalString(utils.GCModeFlag.Name) == "archive"
 		cfg.EVM.Cache.GreedyGC = ctx.GlobalString(utils.GCModeFlag.Name) == "full"
 	}
+	if ctx.GlobalIsSet(EventsHistoryEpochsFlag.Name) {
+		cfg.EventsHistory.Epochs = idx.Epoch(ctx.GlobalUint64(EventsHistoryEpochsFlag.Name))
+	}
+	if cfg.EventsHistory.Epochs != 0 && cfg.EVM.Cache.TrieDirtyDisabled {
+		return cfg, fmt.Errorf("events history pruning isn't compatible with --%s=archive", GCModeFlag.Name)
+	}
+	if ctx.GlobalIsSet(ReceiptsHistoryFlag.Name) {
+		cfg.ReceiptsRetention.Blocks = idx.Block(ctx.GlobalUint64(ReceiptsHistoryFlag.Name))
+	}
+	if ctx.GlobalIsSet(StatePruningBlocksFlag.Name) {
+		cfg.StatePruning.Blocks = idx.Block(ctx.GlobalUint64(StatePruningBlocksFlag.Name))
+	}
+	if cfg.StatePruning.Blocks != 0 && cfg.EVM.Cache.TrieDirtyDisabled {
+		return cfg, fmt.Errorf("online state pruning isn't compatible with --%s=archive", GCModeFlag.Name)
+	}
+	if ctx.GlobalBool(StateHistoryFlag.Name) {
+		cfg.EVM.StateScheme = rawdb.PathScheme
+	}
 	return cfg, nil
 }
 
@@ -397,30 +464,38 @@ func setDBConfigStr(cfg integration.DBsConfig, cacheRatio cachescale.Func, prese
 		cfg = integration.Pbl1DBsConfig(cacheRatio.U64, uint64(utils.MakeDatabaseHandles()))
 	case "legacy-pebble":
 		cfg = integration.PblLegacyDBsConfig(cacheRatio.U64, uint64(utils.MakeDatabaseHandles()))
+	case "pebble-path":
+		cfg = integration.Pbl1PathDBsConfig(cacheRatio.U64, uint64(utils.MakeDatabaseHandles()))
+	case "legacy-pebble-path":
+		cfg = integration.PblLegacyPathDBsConfig(cacheRatio.U64, uint64(utils.MakeDatabaseHandles()))
 	default:
-		utils.Fatalf("--%s must be 'pebble' or 'legacy-pebble'", DBPresetFlag.Name)
+		utils.Fatalf("--%s must be 'pebble', 'legacy-pebble', 'pebble-path' or 'legacy-pebble-path'", DBPresetFlag.Name)
 	}
 	// sanity check
-	if preset != reversePresetName(cfg.Routing) {
+	if preset != reversePresetName(cfg) {
 		log.Error("Preset name cannot be reversed")
 	}
 	return cfg
 }
 
-func reversePresetName(cfg integration.RoutingConfig) string {
+func reversePresetName(cfg integration.DBsConfig) string {
 	pbl1 := integration.Pbl1RoutingConfig()
 	pblLegacy := integration.PblLegacyRoutingConfig()
-	if cfg.Equal(pbl1) {
-		return "pebble"
+	suffix := ""
+	if cfg.StateScheme == rawdb.PathScheme {
+		suffix = "-path"
 	}
-	if cfg.Equal(pblLegacy) {
-		return "legacy-pebble"
+	if cfg.Routing.Equal(pbl1) {
+		return "pebble" + suffix
+	}
+	if cfg.Routing.Equal(pblLegacy) {
+		return "legacy-pebble" + suffix
 	}
 	return ""
 }
 
 func memorizeDBPreset(cfg *config) {
-	preset := reversePresetName(cfg.DBs.Routing)
+	preset := reversePresetName(cfg.DBs)
 	pPath := path.Join(cfg.Node.DataDir, "chaindata", "preset")
 	if len(preset) != 0 {
 		futils.FilePut(pPath, []byte(preset), true)
@@ -550,6 +625,10 @@ func mayMakeAllConfigs(ctx *cli.Context) (*config, error) {
 
 	// Process DBs defaults in the end because they are applied only in absence of config or flags
 	cfg = setDBConfigDefault(cfg, cacheRatio)
+	// the EVM state scheme is selected by the DBs layout
+	if cfg.DBs.StateScheme != "" {
+		cfg.U2UStore.EVM.StateScheme = cfg.DBs.StateScheme
+	}
 	// Sanitize GPO config
 	if cfg.U2U.GPO.MinGasTip == nil || cfg.U2U.GPO.MinGasTip.Sign() == 0 {
 		cfg.U2U.GPO.MinGasTip = new(big.Int).SetUint64(cfg.TxPool.PriceLimit)
@@ -565,6 +644,28 @@ func mayMakeAllConfigs(ctx *cli.Context) (*config, error) {
 	if ctx.GlobalIsSet(EnableTxTracerFlag.Name) {
 		cfg.U2UStore.TraceTransactions = true
 	}
+	if ctx.GlobalIsSet(TxTracerRetentionBlocksFlag.Name) {
+		cfg.U2UStore.TxTraceRetention.Blocks = idx.Block(ctx.GlobalUint64(TxTracerRetentionBlocksFlag.Name))
+	}
+	if ctx.GlobalIsSet(TxTracerRetentionEpochsFlag.Name) {
+		cfg.U2UStore.TxTraceRetention.Epochs = idx.Epoch(ctx.GlobalUint64(TxTracerRetentionEpochsFlag.Name))
+	}
+	if ctx.GlobalIsSet(AddressIndexFlag.Name) {
+		cfg.U2UStore.EVM.AddressIndex = true
+	}
+	if ctx.GlobalIsSet(AncientEpochsFlag.Name) {
+		cfg.U2UStore.Ancient.Epochs = idx.Epoch(ctx.GlobalUint64(AncientEpochsFlag.Name))
+	}
+	if ctx.GlobalIsSet(utils.AncientFlag.Name) {
+		cfg.U2UStore.Ancient.Dir = ctx.GlobalString(utils.AncientFlag.Name)
+	}
+	if cfg.U2UStore.Ancient.Dir == "" {
+		// the existing ancient store stays readable even if the migration is disabled
+		ancientDir := path.Join(cfg.Node.DataDir, "chaindata", "ancient")
+		if cfg.U2UStore.Ancient.Epochs != 0 || futils.FileExists(ancientDir) {
+			cfg.U2UStore.Ancient.Dir = ancientDir
+		}
+	}
 
 	if ctx.GlobalIsSet(EnableMonitorFlag.Name) {
 		cfg.Monitoring = setMonitoringConfig(ctx, cfg.Monitoring)
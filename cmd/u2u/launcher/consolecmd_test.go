@@ -15,7 +15,7 @@ import (
 )
 
 const (
-	ipcAPIs  = "abft:1.0 admin:1.0 dag:1.0 debug:1.0 net:1.0 personal:1.0 rpc:1.0 trace:1.0 txpool:1.0 web3:1.0"
+	ipcAPIs  = "abft:1.0 admin:1.0 dag:1.0 debug:1.0 net:1.0 ots:1.0 personal:1.0 rpc:1.0 trace:1.0 txpool:1.0 u2u:1.0 web3:1.0"
 	httpAPIs = "abft:1.0 dag:1.0 rpc:1.0 web3:1.0"
 )
 
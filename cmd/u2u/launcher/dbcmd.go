@@ -94,6 +94,22 @@ Experimental - try to heal dirty DB.
 u2u db dump-sfc --experimental
 Experimental - try to dump the storage of SFC contract to a separated KVDB.
 Need to heal the dirty DB after dumping to continue syncing.
+`,
+			},
+			{
+				Name:      "sfc-diff",
+				Usage:     "Print the storage slots of SFC contracts, which differ in the SFC state",
+				ArgsUsage: "<block>",
+				Action:    utils.MigrateFlags(sfcDiff),
+				Category:  "DB COMMANDS",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					verbosityFlag,
+				},
+				Description: `
+u2u db sfc-diff <block>
+will compare the storage of SFC contracts in the state of the block with the SFC state
+of the block, and print the slots which differ.
 `,
 			},
 		},
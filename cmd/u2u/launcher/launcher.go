@@ -21,6 +21,7 @@ import (
 	"github.com/unicornultrafoundation/go-u2u/flags"
 	"github.com/unicornultrafoundation/go-u2u/gossip"
 	"github.com/unicornultrafoundation/go-u2u/gossip/emitter"
+	"github.com/unicornultrafoundation/go-u2u/graphql"
 	"github.com/unicornultrafoundation/go-u2u/integration"
 	"github.com/unicornultrafoundation/go-u2u/log"
 	evmetrics "github.com/unicornultrafoundation/go-u2u/metrics"
@@ -33,8 +34,9 @@ import (
 	"github.com/unicornultrafoundation/go-u2u/valkeystore"
 	_ "github.com/unicornultrafoundation/go-u2u/version"
 
-	// Force-load js tracers package, to trigger registration
+	// Force-load the js and native tracers packages, to trigger registration
 	_ "github.com/unicornultrafoundation/go-u2u/eth/tracers/js"
+	_ "github.com/unicornultrafoundation/go-u2u/eth/tracers/native"
 )
 
 const (
@@ -107,6 +109,7 @@ func initFlags() {
 	}
 	u2uFlags = []cli.Flag{
 		utils.SFCFlag,
+		utils.SFCHaltOnDivergenceFlag,
 		GenesisFlag,
 		ExperimentalGenesisFlag,
 		utils.IdentityFlag,
@@ -126,6 +129,15 @@ func initFlags() {
 		DBPresetFlag,
 		DBMigrationModeFlag,
 		EnableTxTracerFlag,
+		TxTracerRetentionBlocksFlag,
+		TxTracerRetentionEpochsFlag,
+		AddressIndexFlag,
+		utils.AncientFlag,
+		AncientEpochsFlag,
+		EventsHistoryEpochsFlag,
+		ReceiptsHistoryFlag,
+		StatePruningBlocksFlag,
+		StateHistoryFlag,
 		EnableMonitorFlag,
 		PrometheusMonitoringPortFlag,
 	}
@@ -144,6 +156,9 @@ func initFlags() {
 		utils.WSApiFlag,
 		utils.WSAllowedOriginsFlag,
 		utils.WSPathPrefixFlag,
+		utils.GraphQLEnabledFlag,
+		utils.GraphQLCORSDomainFlag,
+		utils.GraphQLVirtualHostsFlag,
 		utils.IPCDisabledFlag,
 		utils.IPCPathFlag,
 		utils.AllowUnprotectedTxs,
@@ -213,6 +228,7 @@ func initApp() {
 		importCommand,
 		exportCommand,
 		checkCommand,
+		indexCommand,
 		// See snapshot.go
 		snapshotCommand,
 		// See dbcmd.go
@@ -356,6 +372,12 @@ func makeNode(ctx *cli.Context, cfg *config, genesisStore *genesisstore.Store) (
 	}
 
 	stack.RegisterAPIs(svc.APIs())
+	if ctx.GlobalIsSet(utils.GraphQLEnabledFlag.Name) {
+		err = graphql.New(stack, svc.EthAPI, cfg.U2U.FilterAPI, cfg.Node.GraphQLCors, cfg.Node.GraphQLVirtualHosts)
+		if err != nil {
+			utils.Fatalf("Failed to register the GraphQL service: %v", err)
+		}
+	}
 	stack.RegisterProtocols(svc.Protocols())
 	stack.RegisterLifecycle(svc)
 
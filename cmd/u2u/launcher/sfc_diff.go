@@ -0,0 +1,168 @@
+package launcher
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/unicornultrafoundation/go-u2u/cmd/utils"
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/state"
+	"github.com/unicornultrafoundation/go-u2u/log"
+	"github.com/unicornultrafoundation/go-u2u/rlp"
+	"github.com/unicornultrafoundation/go-u2u/trie"
+	"github.com/unicornultrafoundation/go-u2u/u2u"
+	"github.com/unicornultrafoundation/go-u2u/utils/caution"
+)
+
+// storageSlotDiff is a storage slot, which differs in the main state and in the SFC state.
+// Key is the hashed slot key, the values are zero for the missing slots.
+type storageSlotDiff struct {
+	Key      common.Hash
+	Value    common.Hash
+	SfcValue common.Hash
+}
+
+// sfcDiff is the 'db sfc-diff' command.
+func sfcDiff(ctx *cli.Context) (err error) {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	n, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
+	if err != nil {
+		utils.Fatalf("Invalid block number: %v", err)
+	}
+	glogger := log.NewGlogHandler(log.StreamHandler(os.Stderr, log.TerminalFormat(false)))
+	glogger.Verbosity(log.Lvl(ctx.GlobalInt(verbosityFlag.Name)))
+	log.Root().SetHandler(glogger)
+
+	cfg := makeAllConfigs(ctx)
+	cfg.U2UStore.EVM.SfcEnabled = true
+
+	rawDbs := makeDirectDBsProducer(cfg)
+	defer caution.CloseAndReportError(&err, rawDbs, "failed to close raw DBs")
+	gdb := makeGossipStore(rawDbs, cfg)
+	defer caution.CloseAndReportError(&err, gdb, "failed to close Gossip DB")
+	evms := gdb.EvmStore()
+
+	block := gdb.GetBlock(idx.Block(n))
+	if block == nil {
+		return fmt.Errorf("block %d isn't found", n)
+	}
+	stateDb, err := evms.StateDB(block.Root)
+	if err != nil {
+		return fmt.Errorf("state of block %d isn't available: %v", n, err)
+	}
+	sfcStateDb, err := evms.SfcStateDB(block.SfcStateRoot)
+	if err != nil {
+		return fmt.Errorf("SFC state of block %d isn't available: %v", n, err)
+	}
+
+	addrs := make([]common.Address, 0, len(u2u.DefaultVMConfig.SfcPrecompiles))
+	for addr := range u2u.DefaultVMConfig.SfcPrecompiles {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i].Bytes(), addrs[j].Bytes()) < 0
+	})
+
+	total := 0
+	for _, addr := range addrs {
+		storageTrie := stateDb.StorageTrie(addr)
+		diffs, err := diffStorageTries(storageTrie, sfcStateDb.StorageTrie(addr))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Contract %s: %d slots differ\n", addr.String(), len(diffs))
+		for _, d := range diffs {
+			key := "unknown"
+			if storageTrie != nil {
+				if preimage := storageTrie.GetKey(d.Key.Bytes()); preimage != nil {
+					key = common.BytesToHash(preimage).Hex()
+				}
+			}
+			fmt.Printf("  slot %s (key %s): state %s, SFC state %s\n", d.Key.Hex(), key, d.Value.Hex(), d.SfcValue.Hex())
+		}
+		total += len(diffs)
+	}
+	fmt.Printf("Block %d: %d slots differ in total\n", n, total)
+	return nil
+}
+
+// diffStorageTries walks two storage tries in the order of keys, and returns the
+// slots which are different in them. A nil trie is considered as an empty one.
+func diffStorageTries(a, b state.Trie) ([]storageSlotDiff, error) {
+	itA, itB := storageIterator(a), storageIterator(b)
+	okA, okB := nextSlot(itA), nextSlot(itB)
+	var diffs []storageSlotDiff
+	for okA || okB {
+		var cmp int
+		switch {
+		case !okA:
+			cmp = 1
+		case !okB:
+			cmp = -1
+		default:
+			cmp = bytes.Compare(itA.Key, itB.Key)
+		}
+		switch {
+		case cmp < 0:
+			value, err := decodeStorageValue(itA.Value)
+			if err != nil {
+				return nil, err
+			}
+			diffs = append(diffs, storageSlotDiff{Key: common.BytesToHash(itA.Key), Value: value})
+			okA = nextSlot(itA)
+		case cmp > 0:
+			value, err := decodeStorageValue(itB.Value)
+			if err != nil {
+				return nil, err
+			}
+			diffs = append(diffs, storageSlotDiff{Key: common.BytesToHash(itB.Key), SfcValue: value})
+			okB = nextSlot(itB)
+		default:
+			if !bytes.Equal(itA.Value, itB.Value) {
+				value, err := decodeStorageValue(itA.Value)
+				if err != nil {
+					return nil, err
+				}
+				sfcValue, err := decodeStorageValue(itB.Value)
+				if err != nil {
+					return nil, err
+				}
+				diffs = append(diffs, storageSlotDiff{Key: common.BytesToHash(itA.Key), Value: value, SfcValue: sfcValue})
+			}
+			okA, okB = nextSlot(itA), nextSlot(itB)
+		}
+	}
+	for _, it := range []*trie.Iterator{itA, itB} {
+		if it != nil && it.Err != nil {
+			return nil, it.Err
+		}
+	}
+	return diffs, nil
+}
+
+func storageIterator(t state.Trie) *trie.Iterator {
+	if t == nil {
+		return nil
+	}
+	return trie.NewIterator(t.NodeIterator(nil))
+}
+
+func nextSlot(it *trie.Iterator) bool {
+	return it != nil && it.Next()
+}
+
+func decodeStorageValue(enc []byte) (common.Hash, error) {
+	_, content, _, err := rlp.Split(enc)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(content), nil
+}
@@ -303,7 +303,7 @@ func traverseState(ctx *cli.Context) (err error) {
 			return err
 		}
 		if acc.Root != types.EmptyRootHash {
-			storageTrie, err := trie.NewSecure(acc.Root, triedb)
+			storageTrie, err := trie.NewSecureWithOwner(common.BytesToHash(accIter.Key), acc.Root, triedb)
 			if err != nil {
 				log.Error("Failed to open storage trie", "root", acc.Root, "err", err)
 				return err
@@ -408,7 +408,7 @@ func traverseRawState(ctx *cli.Context) (err error) {
 				return errors.New("invalid account")
 			}
 			if acc.Root != types.EmptyRootHash {
-				storageTrie, err := trie.NewSecure(acc.Root, triedb)
+				storageTrie, err := trie.NewSecureWithOwner(common.BytesToHash(accIter.LeafKey()), acc.Root, triedb)
 				if err != nil {
 					log.Error("Failed to open storage trie", "root", acc.Root, "err", err)
 					return errors.New("missing storage trie")
@@ -19,6 +19,7 @@ import (
 	"github.com/unicornultrafoundation/go-u2u/cmd/utils"
 	"github.com/unicornultrafoundation/go-u2u/common"
 	"github.com/unicornultrafoundation/go-u2u/gossip"
+	txtrace "github.com/unicornultrafoundation/go-u2u/gossip/txtracer"
 	"github.com/unicornultrafoundation/go-u2u/log"
 	"github.com/unicornultrafoundation/go-u2u/native"
 	"github.com/unicornultrafoundation/go-u2u/rlp"
@@ -285,3 +286,64 @@ func deleteTraces(gdb *gossip.Store, from, to idx.Block) (err error) {
 	log.Info("Deleting transaction traces done", "deleted", counter, "from block", from, "to block", to, "elapsed", common.PrettyDuration(time.Since(start)))
 	return
 }
+
+// indexTxTracer builds the address index of the stored transaction traces
+func indexTxTracer(ctx *cli.Context) (err error) {
+	cfg := makeAllConfigs(ctx)
+
+	rawDbs := makeDirectDBsProducer(cfg)
+	defer caution.CloseAndReportError(&err, rawDbs, "failed to close raw DBs")
+	gdb, err := makeRawGossipStoreTrace(rawDbs, cfg)
+	if err != nil {
+		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
+	}
+	defer caution.CloseAndReportError(&err, gdb, "failed to close Gossip DB")
+
+	if gdb.TxTraceStore().IsIndexed() {
+		log.Info("Transaction traces are already indexed")
+		return nil
+	}
+
+	log.Info("Indexing transaction traces")
+	err = indexTraces(gdb)
+	if err != nil {
+		utils.Fatalf("Indexing traces error: %v\n", err)
+	}
+
+	return nil
+}
+
+// indexTraces adds all the stored transaction traces into the address index
+// and recalculates their size accounting
+func indexTraces(gdb *gossip.Store) (err error) {
+	start, reported := time.Now(), time.Now()
+
+	var (
+		counter int
+		stats   txtrace.Stats
+	)
+	gdb.TxTraceStore().ForEachTxtrace(func(key common.Hash, traces []byte) bool {
+		err = gdb.TxTraceStore().IndexTxTrace(key, traces)
+		if err != nil {
+			err = fmt.Errorf("transaction %s: %w", key.String(), err)
+			return false
+		}
+		counter++
+		stats.Traces++
+		stats.Size += uint64(len(traces))
+		if time.Since(reported) >= statsReportLimit {
+			log.Info("Indexing transaction traces", "indexed", counter, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	log.Info("Indexed transaction traces", "indexed", counter, "size", common.StorageSize(stats.Size), "elapsed", common.PrettyDuration(time.Since(start)))
+
+	if err = gdb.TxTraceStore().SetStats(stats); err != nil {
+		return err
+	}
+	return gdb.TxTraceStore().SetIndexed()
+}
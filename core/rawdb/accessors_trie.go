@@ -0,0 +1,92 @@
+package rawdb
+
+import (
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/crypto"
+	"github.com/unicornultrafoundation/go-u2u/ethdb"
+	"github.com/unicornultrafoundation/go-u2u/log"
+)
+
+// The schemes of the persisted state trie nodes.
+const (
+	// HashScheme stores the trie nodes by their hashes, so every version of
+	// a node is kept until it's pruned.
+	HashScheme = "hash"
+
+	// PathScheme stores the trie nodes by their paths in the trie, so only
+	// the latest version of a node is kept.
+	PathScheme = "path"
+)
+
+// ReadStateScheme retrieves the scheme of the state trie nodes. The hash scheme
+// is assumed if no scheme was recorded.
+func ReadStateScheme(db ethdb.KeyValueReader) string {
+	data, _ := db.Get(stateSchemeKey)
+	if len(data) == 0 {
+		return HashScheme
+	}
+	return string(data)
+}
+
+// WriteStateScheme stores the scheme of the state trie nodes.
+func WriteStateScheme(db ethdb.KeyValueWriter, scheme string) {
+	if err := db.Put(stateSchemeKey, []byte(scheme)); err != nil {
+		log.Crit("Failed to store the state scheme", "err", err)
+	}
+}
+
+// ReadAccountTrieNode retrieves the account trie node with the given path.
+func ReadAccountTrieNode(db ethdb.KeyValueReader, path []byte) []byte {
+	data, _ := db.Get(accountTrieNodeKey(path))
+	return data
+}
+
+// WriteAccountTrieNode writes the account trie node with the given path.
+func WriteAccountTrieNode(db ethdb.KeyValueWriter, path []byte, node []byte) {
+	if err := db.Put(accountTrieNodeKey(path), node); err != nil {
+		log.Crit("Failed to store account trie node", "err", err)
+	}
+}
+
+// ReadStorageTrieNode retrieves the storage trie node of the given account
+// with the given path.
+func ReadStorageTrieNode(db ethdb.KeyValueReader, accountHash common.Hash, path []byte) []byte {
+	data, _ := db.Get(storageTrieNodeKey(accountHash, path))
+	return data
+}
+
+// WriteStorageTrieNode writes the storage trie node of the given account with
+// the given path.
+func WriteStorageTrieNode(db ethdb.KeyValueWriter, accountHash common.Hash, path []byte, node []byte) {
+	if err := db.Put(storageTrieNodeKey(accountHash, path), node); err != nil {
+		log.Crit("Failed to store storage trie node", "err", err)
+	}
+}
+
+// ReadTrieNodeByPath retrieves the trie node with the given path. The owner is the
+// hash of the account of a storage trie, or the zero hash for the account trie.
+func ReadTrieNodeByPath(db ethdb.KeyValueReader, owner common.Hash, path []byte) []byte {
+	if owner == (common.Hash{}) {
+		return ReadAccountTrieNode(db, path)
+	}
+	return ReadStorageTrieNode(db, owner, path)
+}
+
+// WriteTrieNodeByPath writes the trie node with the given path. The owner is the
+// hash of the account of a storage trie, or the zero hash for the account trie.
+func WriteTrieNodeByPath(db ethdb.KeyValueWriter, owner common.Hash, path []byte, node []byte) {
+	if owner == (common.Hash{}) {
+		WriteAccountTrieNode(db, path, node)
+	} else {
+		WriteStorageTrieNode(db, owner, path, node)
+	}
+}
+
+// IsLegacyTrieNode reports whether the key-value pair is a trie node (or a legacy
+// contract code) stored by the hash scheme.
+func IsLegacyTrieNode(key []byte, val []byte) bool {
+	if len(key) != common.HashLength {
+		return false
+	}
+	return crypto.Keccak256Hash(val) == common.BytesToHash(key)
+}
@@ -91,6 +91,12 @@ type freezer struct {
 // newFreezer creates a chain freezer that moves ancient chain data into
 // append-only flat file containers.
 func newFreezer(datadir string, namespace string, readonly bool) (*freezer, error) {
+	return newTablesFreezer(datadir, namespace, readonly, FreezerNoSnappy)
+}
+
+// newTablesFreezer creates a freezer of the given data tables. The tables map
+// configures whether compression is disabled for each of the tables.
+func newTablesFreezer(datadir string, namespace string, readonly bool, tables map[string]bool) (*freezer, error) {
 	// Create the initial freezer object
 	var (
 		readMeter  = metrics.NewRegisteredMeter(namespace+"ancient/read", nil)
@@ -119,7 +125,7 @@ func newFreezer(datadir string, namespace string, readonly bool) (*freezer, erro
 		trigger:      make(chan chan struct{}),
 		quit:         make(chan struct{}),
 	}
-	for name, disableSnappy := range FreezerNoSnappy {
+	for name, disableSnappy := range tables {
 		table, err := newTable(datadir, name, readMeter, writeMeter, sizeGauge, disableSnappy)
 		if err != nil {
 			for _, table := range freezer.tables {
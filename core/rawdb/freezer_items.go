@@ -0,0 +1,94 @@
+package rawdb
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	"github.com/unicornultrafoundation/go-u2u/log"
+)
+
+// ItemsFreezer is an append-only store of immutable items in flat files, where
+// every item consists of a binary blob per data table. Unlike the chain freezer,
+// it doesn't move any data by itself, the items are appended by the owner.
+type ItemsFreezer struct {
+	f *freezer
+}
+
+// NewItemsFreezer opens the freezer of the given data tables, creating the
+// directory if it doesn't exist. The tables map configures whether compression
+// is disabled for each of the tables.
+func NewItemsFreezer(datadir string, namespace string, tables map[string]bool) (*ItemsFreezer, error) {
+	if err := os.MkdirAll(datadir, 0700); err != nil {
+		return nil, err
+	}
+	f, err := newTablesFreezer(datadir, namespace, false, tables)
+	if err != nil {
+		return nil, err
+	}
+	return &ItemsFreezer{f}, nil
+}
+
+// Items returns the number of the stored items.
+func (f *ItemsFreezer) Items() uint64 {
+	return atomic.LoadUint64(&f.f.frozen)
+}
+
+// Has returns true if the item with the given number is stored.
+func (f *ItemsFreezer) Has(number uint64) bool {
+	return number < f.Items()
+}
+
+// Retrieve returns the blob of the item in the given table.
+func (f *ItemsFreezer) Retrieve(kind string, number uint64) ([]byte, error) {
+	return f.f.Ancient(kind, number)
+}
+
+// Append injects the blobs of the next item, one per data table. All the data
+// tables are rolled back to the previous item if any of the insertions fail.
+//
+// Note, the appended data isn't flushed to disk until Sync is called.
+func (f *ItemsFreezer) Append(number uint64, blobs map[string][]byte) (err error) {
+	if f.f.readonly {
+		return errReadOnly
+	}
+	if f.Items() != number {
+		return errOutOrderInsertion
+	}
+	if len(blobs) != len(f.f.tables) {
+		return fmt.Errorf("expected %d blobs, got %d", len(f.f.tables), len(blobs))
+	}
+	defer func() {
+		if err != nil {
+			if rerr := f.f.repair(); rerr != nil {
+				log.Crit("Failed to repair freezer", "err", rerr)
+			}
+		}
+	}()
+	for kind, blob := range blobs {
+		table := f.f.tables[kind]
+		if table == nil {
+			return errUnknownTable
+		}
+		if err := table.Append(number, blob); err != nil {
+			return err
+		}
+	}
+	atomic.AddUint64(&f.f.frozen, 1)
+	return nil
+}
+
+// Truncate discards all the items but the first n ones.
+func (f *ItemsFreezer) Truncate(n uint64) error {
+	return f.f.TruncateAncients(n)
+}
+
+// Sync flushes all the data tables to disk.
+func (f *ItemsFreezer) Sync() error {
+	return f.f.Sync()
+}
+
+// Close closes all the data tables.
+func (f *ItemsFreezer) Close() error {
+	return f.f.Close()
+}
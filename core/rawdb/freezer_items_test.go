@@ -0,0 +1,64 @@
+package rawdb
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestItemsFreezer tests appending, reading back, truncating and reopening of the items freezer.
+func TestItemsFreezer(t *testing.T) {
+	dir := t.TempDir()
+	tables := map[string]bool{"a": false, "b": true}
+	f, err := NewItemsFreezer(dir, "", tables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := uint64(0); i < 10; i++ {
+		if err := f.Append(i, map[string][]byte{"a": getChunk(20, int(i)), "b": getChunk(10, int(i))}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.Append(11, map[string][]byte{"a": nil, "b": nil}); err == nil {
+		t.Fatal("expected out of order insertion error")
+	}
+	if err := f.Append(10, map[string][]byte{"a": nil}); err == nil {
+		t.Fatal("expected missing blob error")
+	}
+	if err := f.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Truncate(8); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err = NewItemsFreezer(dir, "", tables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if f.Items() != 8 {
+		t.Fatalf("expected 8 items, got %d", f.Items())
+	}
+	if f.Has(8) || !f.Has(7) {
+		t.Fatal("wrong items after truncation")
+	}
+	for i := uint64(0); i < 8; i++ {
+		blob, err := f.Retrieve("a", i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(blob, getChunk(20, int(i))) {
+			t.Fatalf("wrong blob %d: %x", i, blob)
+		}
+		blob, err = f.Retrieve("b", i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(blob, getChunk(10, int(i))) {
+			t.Fatalf("wrong blob %d: %x", i, blob)
+		}
+	}
+}
@@ -113,6 +113,17 @@ func NewDatabase(db ethdb.Database) Database {
 // is safe for concurrent use and retains a lot of collapsed RLP trie nodes in a
 // large memory cache.
 func NewDatabaseWithConfig(db ethdb.Database, config *trie.Config) Database {
+	if config == nil || config.Scheme == "" {
+		// the scheme of the persisted trie nodes is recorded in the database
+		if scheme := rawdb.ReadStateScheme(db); scheme != rawdb.HashScheme {
+			cpy := trie.Config{Preimages: true}
+			if config != nil {
+				cpy = *config
+			}
+			cpy.Scheme = scheme
+			config = &cpy
+		}
+	}
 	csc, _ := lru.New(codeSizeCacheSize)
 	return &cachingDB{
 		db:            trie.NewDatabaseWithConfig(db, config),
@@ -138,7 +149,7 @@ func (db *cachingDB) OpenTrie(root common.Hash) (Trie, error) {
 
 // OpenStorageTrie opens the storage trie of an account.
 func (db *cachingDB) OpenStorageTrie(addrHash, root common.Hash) (Trie, error) {
-	tr, err := trie.NewSecure(root, db.db)
+	tr, err := trie.NewSecureWithOwner(addrHash, root, db.db)
 	if err != nil {
 		return nil, err
 	}
@@ -112,6 +112,10 @@ type (
 		account       *common.Address
 		key, prevalue common.Hash
 	}
+	transientStorageChange struct {
+		account       *common.Address
+		key, prevalue common.Hash
+	}
 	codeChange struct {
 		account            *common.Address
 		prevcode, prevhash []byte
@@ -267,3 +271,11 @@ func (ch accessListAddSlotChange) revert(s *StateDB) {
 func (ch accessListAddSlotChange) dirtied() *common.Address {
 	return nil
 }
+
+func (ch transientStorageChange) revert(s *StateDB) {
+	s.setTransientState(*ch.account, ch.key, ch.prevalue)
+}
+
+func (ch transientStorageChange) dirtied() *common.Address {
+	return nil
+}
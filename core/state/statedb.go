@@ -100,6 +100,9 @@ type StateDB struct {
 	// Per-transaction access list
 	accessList *accessList
 
+	// Transient storage
+	transientStorage transientStorage
+
 	// Journal of state modifications. This is the backbone of
 	// Snapshot and RevertToSnapshot.
 	journal        *journal
@@ -142,6 +145,7 @@ func NewWithSnapLayers(root common.Hash, db Database, snaps *snapshot.Tree, laye
 		preimages:           make(map[common.Hash][]byte),
 		journal:             newJournal(),
 		accessList:          newAccessList(),
+		transientStorage:    newTransientStorage(),
 		hasher:              crypto.NewKeccakState(),
 		snapMaxLayers:       layers,
 	}
@@ -155,6 +159,21 @@ func NewWithSnapLayers(root common.Hash, db Database, snaps *snapshot.Tree, laye
 	return sdb, nil
 }
 
+// NewWithSnapshot creates a read-only state, which reads the accounts and storage slots from
+// the given snapshot layer rather than from the trie. The trie of the root is used for the
+// methods, which require the trie nodes (e.g. proofs). The state must not be committed.
+func NewWithSnapshot(root common.Hash, db Database, snap snapshot.Snapshot) (*StateDB, error) {
+	sdb, err := NewWithSnapLayers(root, db, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	sdb.snap = snap
+	sdb.snapDestructs = make(map[common.Hash]struct{})
+	sdb.snapAccounts = make(map[common.Hash][]byte)
+	sdb.snapStorage = make(map[common.Hash]map[common.Hash][]byte)
+	return sdb, nil
+}
+
 // StartPrefetcher initializes a new trie prefetcher to pull in nodes from the
 // state trie concurrently while the state is mutated so that when we reach the
 // commit phase, most of the needed data is already hot.
@@ -286,6 +305,25 @@ func (s *StateDB) GetStorageRoot(addr common.Address) common.Hash {
 	return common.Hash{}
 }
 
+// GetDirtyStorageKeys retrieves the storage keys of the given address, which
+// were modified since the state was finalised last time.
+func (s *StateDB) GetDirtyStorageKeys(addr common.Address) []common.Hash {
+	stateObject := s.getStateObject(addr)
+	if stateObject == nil {
+		return nil
+	}
+	keys := make([]common.Hash, 0, len(stateObject.dirtyStorage))
+	for key := range stateObject.dirtyStorage {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// TxHash returns the current transaction hash set by Prepare.
+func (s *StateDB) TxHash() common.Hash {
+	return s.thash
+}
+
 // TxIndex returns the current transaction index set by Prepare.
 func (s *StateDB) TxIndex() int {
 	return s.txIndex
@@ -459,6 +497,45 @@ func (s *StateDB) Suicide(addr common.Address) bool {
 	return true
 }
 
+// Selfdestruct6780 marks the given account as suicided only if it was created
+// in the current transaction, according to EIP-6780.
+func (s *StateDB) Selfdestruct6780(addr common.Address) {
+	stateObject := s.getStateObject(addr)
+	if stateObject == nil {
+		return
+	}
+	if stateObject.created {
+		s.Suicide(addr)
+	}
+}
+
+// SetTransientState sets transient storage for a given account. It
+// adds the change to the journal so that it can be rolled back
+// to its previous value if there is a revert.
+func (s *StateDB) SetTransientState(addr common.Address, key, value common.Hash) {
+	prev := s.GetTransientState(addr, key)
+	if prev == value {
+		return
+	}
+	s.journal.append(transientStorageChange{
+		account:  &addr,
+		key:      key,
+		prevalue: prev,
+	})
+	s.setTransientState(addr, key, value)
+}
+
+// setTransientState is a lower level setter for transient storage. It
+// is called during a revert to prevent modifications to the journal.
+func (s *StateDB) setTransientState(addr common.Address, key, value common.Hash) {
+	s.transientStorage.Set(addr, key, value)
+}
+
+// GetTransientState gets transient storage for a given account.
+func (s *StateDB) GetTransientState(addr common.Address, key common.Hash) common.Hash {
+	return s.transientStorage.Get(addr, key)
+}
+
 //
 // Setting, updating & deleting state object methods.
 //
@@ -600,6 +677,7 @@ func (s *StateDB) createObject(addr common.Address) (newobj, prev *stateObject)
 		}
 	}
 	newobj = newObject(s, addr, Account{})
+	newobj.created = true
 	if prev == nil {
 		s.journal.append(createObjectChange{account: &addr})
 	} else {
@@ -723,6 +801,7 @@ func (s *StateDB) Copy() *StateDB {
 	// However, it doesn't cost us much to copy an empty list, so we do it anyway
 	// to not blow up if we ever decide copy it in the middle of a transaction
 	state.accessList = s.accessList.Copy()
+	state.transientStorage = s.transientStorage.Copy()
 
 	// If there's a prefetcher running, make an inactive copy of it that can
 	// only access data but does not actively preload (since the user will not
@@ -730,7 +809,7 @@ func (s *StateDB) Copy() *StateDB {
 	if s.prefetcher != nil {
 		state.prefetcher = s.prefetcher.copy()
 	}
-	if s.snaps != nil {
+	if s.snaps != nil || s.snap != nil {
 		// In order for the miner to be able to use and make additions
 		// to the snapshot tree, we need to copy that aswell.
 		// Otherwise, any block mined by ourselves will cause gaps in the tree,
@@ -803,6 +882,8 @@ func (s *StateDB) Finalise(deleteEmptyObjects bool) {
 			// Thus, we can safely ignore it here
 			continue
 		}
+		// The account isn't considered as created in the next transaction
+		obj.created = false
 		if obj.suicided || (deleteEmptyObjects && obj.empty()) {
 			obj.deleted = true
 
@@ -900,6 +981,8 @@ func (s *StateDB) Prepare(thash common.Hash, ti int) {
 	s.thash = thash
 	s.txIndex = ti
 	s.accessList = newAccessList()
+	// Reset transient storage at the beginning of transaction execution
+	s.transientStorage = newTransientStorage()
 }
 
 func (s *StateDB) clearJournalAndRefund() {
@@ -962,7 +1045,7 @@ func (s *StateDB) Commit(deleteEmptyObjects bool) (common.Hash, error) {
 		s.AccountCommits += time.Since(start)
 	}
 	// If snapshotting is enabled, update the snapshot tree with this new version
-	if s.snap != nil {
+	if s.snap != nil && s.snaps != nil {
 		if metrics.EnabledExpensive {
 			defer func(start time.Time) { s.SnapshotCommits += time.Since(start) }(time.Now())
 		}
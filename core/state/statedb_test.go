@@ -67,6 +67,61 @@ func TestUpdateLeaks(t *testing.T) {
 	it.Release()
 }
 
+// Tests that the state committed with the path scheme is readable from a new
+// database, including the storage tries shared by several accounts.
+func TestPathSchemeCommit(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	rawdb.WriteStateScheme(db, rawdb.PathScheme)
+
+	commit := func(root common.Hash, update func(*StateDB)) common.Hash {
+		state, err := New(root, NewDatabase(db), nil)
+		if err != nil {
+			t.Fatalf("failed to open state %x: %v", root, err)
+		}
+		update(state)
+		root, err = state.Commit(true)
+		if err != nil {
+			t.Fatalf("failed to commit state: %v", err)
+		}
+		if err := state.Database().TrieDB().Commit(root, false, nil); err != nil {
+			t.Fatalf("failed to commit trie %x: %v", root, err)
+		}
+		return root
+	}
+	slot := common.Hash{1}
+	root := commit(common.Hash{}, func(state *StateDB) {
+		for i := byte(0); i < 100; i++ {
+			addr := common.BytesToAddress([]byte{i})
+			state.SetNonce(addr, uint64(i)+1)
+			state.SetState(addr, slot, common.Hash{i % 2})
+		}
+	})
+	root = commit(root, func(state *StateDB) {
+		state.SetState(common.BytesToAddress([]byte{2}), slot, common.Hash{0xff})
+	})
+
+	state, err := New(root, NewDatabase(db), nil)
+	if err != nil {
+		t.Fatalf("failed to open state %x: %v", root, err)
+	}
+	for i := byte(0); i < 100; i++ {
+		addr := common.BytesToAddress([]byte{i})
+		want := common.Hash{i % 2}
+		if i == 2 {
+			want = common.Hash{0xff}
+		}
+		if have := state.GetState(addr, slot); have != want {
+			t.Errorf("storage mismatch of %x: have %x, want %x", addr, have, want)
+		}
+		if have := state.GetNonce(addr); have != uint64(i)+1 {
+			t.Errorf("nonce mismatch of %x: have %d, want %d", addr, have, i+1)
+		}
+	}
+	if err := state.Error(); err != nil {
+		t.Fatalf("failed to read state: %v", err)
+	}
+}
+
 // Tests that no intermediate state of an object is stored into the database,
 // only the one right before the commit.
 func TestIntermediateLeaks(t *testing.T) {
@@ -280,6 +335,16 @@ func newTestAction(addr common.Address, r *rand.Rand) testAction {
 			},
 			args: make([]int64, 2),
 		},
+		{
+			name: "SetTransientState",
+			fn: func(a testAction, s *StateDB) {
+				var key, val common.Hash
+				binary.BigEndian.PutUint16(key[:], uint16(a.args[0]))
+				binary.BigEndian.PutUint16(val[:], uint16(a.args[1]))
+				s.SetTransientState(addr, key, val)
+			},
+			args: make([]int64, 2),
+		},
 		{
 			name: "SetCode",
 			fn: func(a testAction, s *StateDB) {
@@ -458,6 +523,9 @@ func (test *snapshotTest) checkEqual(state, checkstate *StateDB) error {
 			return err
 		}
 	}
+	if !reflect.DeepEqual(state.transientStorage, checkstate.transientStorage) {
+		return fmt.Errorf("got transient storage %v, want %v", state.transientStorage, checkstate.transientStorage)
+	}
 
 	if state.GetRefund() != checkstate.GetRefund() {
 		return fmt.Errorf("got GetRefund() == %d, want GetRefund() == %d",
@@ -915,3 +983,70 @@ func TestStateDBAccessList(t *testing.T) {
 		t.Fatalf("expected empty, got %d", got)
 	}
 }
+
+func TestTransientStorage(t *testing.T) {
+	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+
+	key := common.Hash{0x01}
+	value := common.Hash{0x02}
+	addr := common.Address{}
+
+	state.SetTransientState(addr, key, value)
+	if exp, got := 1, state.journal.length(); exp != got {
+		t.Fatalf("journal length mismatch: have %d, want %d", got, exp)
+	}
+	// the retrieved value should equal what was set
+	if got := state.GetTransientState(addr, key); got != value {
+		t.Fatalf("transient storage mismatch: have %x, want %x", got, value)
+	}
+
+	// revert the transient state being set and then check that the
+	// value is now the empty hash
+	state.journal.revert(state, 0)
+	if got, exp := state.GetTransientState(addr, key), (common.Hash{}); exp != got {
+		t.Fatalf("transient storage mismatch: have %x, want %x", got, exp)
+	}
+
+	// set transient state and then copy the statedb and ensure that
+	// the transient state is copied
+	state.SetTransientState(addr, key, value)
+	cpy := state.Copy()
+	if got := cpy.GetTransientState(addr, key); got != value {
+		t.Fatalf("transient storage mismatch: have %x, want %x", got, value)
+	}
+
+	// transient storage is cleared at the beginning of the next transaction
+	state.Prepare(common.Hash{0x01}, 1)
+	if got, exp := state.GetTransientState(addr, key), (common.Hash{}); exp != got {
+		t.Fatalf("transient storage mismatch: have %x, want %x", got, exp)
+	}
+}
+
+func TestSelfdestruct6780(t *testing.T) {
+	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	created, existing := common.Address{0x01}, common.Address{0x02}
+
+	state.SetNonce(existing, 1)
+	state.Finalise(true)
+	state.CreateAccount(created)
+	state.SetNonce(created, 1)
+
+	state.Selfdestruct6780(existing)
+	if state.HasSuicided(existing) {
+		t.Fatalf("account created in a previous transaction is destructed")
+	}
+	state.Selfdestruct6780(created)
+	if !state.HasSuicided(created) {
+		t.Fatalf("account created in the same transaction isn't destructed")
+	}
+
+	// the account isn't considered as created in the next transaction
+	other := common.Address{0x03}
+	state.CreateAccount(other)
+	state.SetNonce(other, 1)
+	state.Finalise(true)
+	state.Selfdestruct6780(other)
+	if state.HasSuicided(other) {
+		t.Fatalf("account created in a previous transaction is destructed")
+	}
+}
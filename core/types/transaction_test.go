@@ -488,6 +488,73 @@ func TestTransactionCoding(t *testing.T) {
 	}
 }
 
+// TestSetCodeTxCoding tests serializing/de-serializing of EIP-7702 transactions,
+// and the recovery of their senders and authorities.
+func TestSetCodeTxCoding(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	authKey, _ := crypto.GenerateKey()
+	var (
+		signer    = NewPragueSigner(common.Big1)
+		from      = crypto.PubkeyToAddress(key.PublicKey)
+		authority = crypto.PubkeyToAddress(authKey.PublicKey)
+		target    = common.HexToAddress("0x0000000000000000000000000000000000000aaa")
+	)
+	auth, err := SignSetCode(authKey, SetCodeAuthorization{
+		ChainID: common.Big1,
+		Address: target,
+		Nonce:   7,
+	})
+	if err != nil {
+		t.Fatalf("could not sign authorization: %v", err)
+	}
+	tx, err := SignNewTx(key, signer, &SetCodeTx{
+		ChainID:    big.NewInt(1),
+		Nonce:      1,
+		GasTipCap:  big.NewInt(1),
+		GasFeeCap:  big.NewInt(10),
+		Gas:        123457,
+		To:         authority,
+		Data:       []byte("abcdef"),
+		AccessList: AccessList{{Address: target, StorageKeys: []common.Hash{{0}}}},
+		AuthList:   []SetCodeAuthorization{auth},
+	})
+	if err != nil {
+		t.Fatalf("could not sign transaction: %v", err)
+	}
+	for _, coding := range []func(*Transaction) (*Transaction, error){encodeDecodeBinary, encodeDecodeJSON} {
+		parsedTx, err := coding(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := assertEqual(parsedTx, tx); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(parsedTx.SetCodeAuthorizations(), tx.SetCodeAuthorizations()) {
+			t.Fatalf("authorization list wrong, want %v, got %v", tx.SetCodeAuthorizations(), parsedTx.SetCodeAuthorizations())
+		}
+		sender, err := Sender(signer, parsedTx)
+		if err != nil || sender != from {
+			t.Fatalf("unexpected sender %v, err %v, want %v", sender, err, from)
+		}
+		if auths := parsedTx.SetCodeAuthorities(); len(auths) != 1 || auths[0] != authority {
+			t.Fatalf("unexpected authorities %v, want %v", auths, authority)
+		}
+	}
+	// Only the Prague signer supports set code transactions
+	if _, err := Sender(NewLondonSigner(common.Big1), tx); err != ErrTxTypeNotSupported {
+		t.Fatalf("expected %v, got %v", ErrTxTypeNotSupported, err)
+	}
+	// An authorization with a wrong signature recovers a different authority
+	auth.Nonce++
+	if got, err := auth.Authority(); err == nil && got == authority {
+		t.Fatalf("modified authorization recovered the original authority")
+	}
+	auth.V = 2
+	if _, err := auth.Authority(); err == nil {
+		t.Fatalf("expected error for an invalid signature")
+	}
+}
+
 func encodeDecodeJSON(tx *Transaction) (*Transaction, error) {
 	data, err := json.Marshal(tx)
 	if err != nil {
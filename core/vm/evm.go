@@ -26,6 +26,7 @@ import (
 	"github.com/holiman/uint256"
 
 	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
 	"github.com/unicornultrafoundation/go-u2u/crypto"
 	"github.com/unicornultrafoundation/go-u2u/log"
 	"github.com/unicornultrafoundation/go-u2u/params"
@@ -48,6 +49,8 @@ type (
 func (evm *EVM) precompile(addr common.Address) (PrecompiledContract, bool) {
 	var precompiles map[common.Address]PrecompiledContract
 	switch {
+	case evm.chainRules.IsPrague:
+		precompiles = PrecompiledContractsPrague
 	case evm.chainRules.IsBerlin:
 		precompiles = PrecompiledContractsBerlin
 	case evm.chainRules.IsIstanbul:
@@ -58,6 +61,9 @@ func (evm *EVM) precompile(addr common.Address) (PrecompiledContract, bool) {
 		precompiles = PrecompiledContractsHomestead
 	}
 	p, ok := precompiles[addr]
+	if !ok && evm.chainRules.IsP256Verify {
+		p, ok = PrecompiledContractsP256Verify[addr]
+	}
 	return p, ok
 }
 
@@ -205,6 +211,7 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 		return nil, gas, ErrInsufficientBalance
 	}
 	snapshot := evm.StateDB.Snapshot()
+	sfcSnapshot := evm.sfcSnapshot()
 	p, isPrecompile := evm.precompile(addr)
 	sp, isStatePrecompile := evm.statePrecompile(addr)
 
@@ -244,19 +251,18 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 	if isPrecompile {
 		ret, gas, err = RunPrecompiledContract(p, input, gas)
 	} else if isStatePrecompile {
-		ret, gas, err = sp.Run(evm.StateDB, evm.Context, evm.TxContext, caller.Address(), input, gas)
+		ret, gas, err = sp.Run(evm.StateDB, evm.Context, evm.TxContext, caller.Address(), input, gas, value)
 	} else {
 		sp, isSfcPrecompile := evm.sfcPrecompile(addr)
 		if isSfcPrecompile && evm.SfcStateDB != nil {
 			snapshot := evm.SfcStateDB.Snapshot()
-			// Create a state object if not exist, then transfer any value
-			if !evm.SfcStateDB.Exist(addr) {
-				evm.SfcStateDB.CreateAccount(addr)
-			}
-			evm.Context.Transfer(evm.SfcStateDB, caller.Address(), addr, value)
+			// Credit any value. The SFC state only tracks the system contracts,
+			// so the sender side of the transfer is skipped.
+			evm.touchSfcAccount(addr)
+			evm.SfcStateDB.AddBalance(addr, value)
 			// Run SFC precompiled
 			start := time.Now()
-			_, _, sfcErr := sp.Run(evm.SfcStateDB, evm.Context, evm.TxContext, caller.Address(), input, gas)
+			_, _, sfcErr := sp.Run(evm.SfcStateDB, evm.Context, evm.TxContext, caller.Address(), input, gas, value)
 			// TODO(trinhdn97): compared sfc state precompiled gas used/output/error with the correct execution from smc
 			// as well for call code, delegate and static calls.
 			sfcExecutionElapsed = time.Since(start)
@@ -272,7 +278,7 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 		}
 		// Initialise a new contract and set the code that is to be used by the EVM.
 		// The contract is a scoped environment for this execution context only.
-		code := evm.StateDB.GetCode(addr)
+		code := evm.resolveCode(addr)
 		if len(code) == 0 {
 			ret, err = nil, nil // gas is unchanged
 		} else {
@@ -280,7 +286,7 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 			// If the account has no code, we can abort here
 			// The depth-check is already done, and precompiles handled above
 			contract := NewContract(caller, AccountRef(addrCopy), value, gas)
-			contract.SetCallCode(&addrCopy, evm.StateDB.GetCodeHash(addrCopy), code)
+			contract.SetCallCode(&addrCopy, evm.resolveCodeHash(addrCopy), code)
 			start := time.Now()
 			ret, err = evm.interpreter.Run(contract, input, false)
 			evmExecutionElapsed = time.Since(start)
@@ -299,6 +305,7 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 	// Additionally, when we're in homestead this also counts for code storage gas errors.
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
+		evm.revertSfcToSnapshot(sfcSnapshot)
 		if !errors.Is(err, ErrExecutionReverted) {
 			gas = 0
 		}
@@ -332,6 +339,7 @@ func (evm *EVM) CallCode(caller ContractRef, addr common.Address, input []byte,
 		return nil, gas, ErrInsufficientBalance
 	}
 	var snapshot = evm.StateDB.Snapshot()
+	sfcSnapshot := evm.sfcSnapshot()
 
 	// Invoke tracer hooks that signal entering/exiting a call frame
 	if evm.Config.Debug {
@@ -353,7 +361,7 @@ func (evm *EVM) CallCode(caller ContractRef, addr common.Address, input []byte,
 		if isSfcPrecompile && evm.SfcStateDB != nil {
 			snapshot := evm.SfcStateDB.Snapshot()
 			start := time.Now()
-			_, _, sfcErr := sp.Run(evm.SfcStateDB, evm.Context, evm.TxContext, caller.Address(), input, gas)
+			_, _, sfcErr := sp.Run(evm.SfcStateDB, evm.Context, evm.TxContext, caller.Address(), input, gas, value)
 			sfcExecutionElapsed = time.Since(start)
 			if sfcErr != nil {
 				evm.SfcStateDB.RevertToSnapshot(snapshot)
@@ -365,7 +373,7 @@ func (evm *EVM) CallCode(caller ContractRef, addr common.Address, input []byte,
 		// Initialise a new contract and set the code that is to be used by the EVM.
 		// The contract is a scoped environment for this execution context only.
 		contract := NewContract(caller, AccountRef(caller.Address()), value, gas)
-		contract.SetCallCode(&addrCopy, evm.StateDB.GetCodeHash(addrCopy), evm.StateDB.GetCode(addrCopy))
+		contract.SetCallCode(&addrCopy, evm.resolveCodeHash(addrCopy), evm.resolveCode(addrCopy))
 		start := time.Now()
 		ret, err = evm.interpreter.Run(contract, input, false)
 		evmExecutionElapsed = time.Since(start)
@@ -380,6 +388,7 @@ func (evm *EVM) CallCode(caller ContractRef, addr common.Address, input []byte,
 	}
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
+		evm.revertSfcToSnapshot(sfcSnapshot)
 		if !errors.Is(err, ErrExecutionReverted) {
 			gas = 0
 		}
@@ -401,6 +410,7 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []by
 		return nil, gas, ErrDepth
 	}
 	var snapshot = evm.StateDB.Snapshot()
+	sfcSnapshot := evm.sfcSnapshot()
 
 	// Invoke tracer hooks that signal entering/exiting a call frame
 	if evm.Config.Debug {
@@ -422,7 +432,7 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []by
 		if isSfcPrecompile && evm.SfcStateDB != nil {
 			snapshot := evm.SfcStateDB.Snapshot()
 			start := time.Now()
-			_, _, sfcErr := sp.Run(evm.SfcStateDB, evm.Context, evm.TxContext, caller.Address(), input, gas)
+			_, _, sfcErr := sp.Run(evm.SfcStateDB, evm.Context, evm.TxContext, caller.Address(), input, gas, big0)
 			sfcExecutionElapsed = time.Since(start)
 			if sfcErr != nil {
 				evm.SfcStateDB.RevertToSnapshot(snapshot)
@@ -433,7 +443,7 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []by
 		addrCopy := addr
 		// Initialise a new contract and make initialise the delegate values
 		contract := NewContract(caller, AccountRef(caller.Address()), nil, gas).AsDelegate()
-		contract.SetCallCode(&addrCopy, evm.StateDB.GetCodeHash(addrCopy), evm.StateDB.GetCode(addrCopy))
+		contract.SetCallCode(&addrCopy, evm.resolveCodeHash(addrCopy), evm.resolveCode(addrCopy))
 		start := time.Now()
 		ret, err = evm.interpreter.Run(contract, input, false)
 		evmExecutionElapsed = time.Since(start)
@@ -448,6 +458,7 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []by
 	}
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
+		evm.revertSfcToSnapshot(sfcSnapshot)
 		if !errors.Is(err, ErrExecutionReverted) {
 			gas = 0
 		}
@@ -473,6 +484,7 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 	// then certain tests start failing; stRevertTest/RevertPrecompiledTouchExactOOG.json.
 	// We could change this, but for now it's left for legacy reasons
 	var snapshot = evm.StateDB.Snapshot()
+	sfcSnapshot := evm.sfcSnapshot()
 
 	// We do an AddBalance of zero here, just in order to trigger a touch.
 	// This doesn't matter on Mainnet, where all empties are gone at the time of Byzantium,
@@ -498,9 +510,9 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 		sp, isSfcPrecompile := evm.sfcPrecompile(addr)
 		if isSfcPrecompile && evm.SfcStateDB != nil {
 			snapshot := evm.SfcStateDB.Snapshot()
-			evm.SfcStateDB.AddBalance(addr, big0)
+			evm.touchSfcAccount(addr)
 			start := time.Now()
-			_, _, sfcErr := sp.Run(evm.SfcStateDB, evm.Context, evm.TxContext, caller.Address(), input, gas)
+			_, _, sfcErr := sp.Run(evm.SfcStateDB, evm.Context, evm.TxContext, caller.Address(), input, gas, big0)
 			sfcExecutionElapsed = time.Since(start)
 			if sfcErr != nil {
 				evm.SfcStateDB.RevertToSnapshot(snapshot)
@@ -516,7 +528,7 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 		// Initialise a new contract and set the code that is to be used by the EVM.
 		// The contract is a scoped environment for this execution context only.
 		contract := NewContract(caller, AccountRef(addrCopy), new(big.Int), gas)
-		contract.SetCallCode(&addrCopy, evm.StateDB.GetCodeHash(addrCopy), evm.StateDB.GetCode(addrCopy))
+		contract.SetCallCode(&addrCopy, evm.resolveCodeHash(addrCopy), evm.resolveCode(addrCopy))
 		// When an error was returned by the EVM or when setting the creation code
 		// above, we revert to the snapshot and consume any gas remaining.
 		// Additionally, when we're in Homestead this also counts for code storage gas errors.
@@ -534,6 +546,7 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 	}
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
+		evm.revertSfcToSnapshot(sfcSnapshot)
 		if !errors.Is(err, ErrExecutionReverted) {
 			gas = 0
 		}
@@ -541,6 +554,35 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 	return ret, gas, err
 }
 
+// resolveCode returns the code associated with the provided account. After
+// Prague, it can also resolve code pointed to by a delegation designator.
+func (evm *EVM) resolveCode(addr common.Address) []byte {
+	code := evm.StateDB.GetCode(addr)
+	if !evm.chainRules.IsPrague {
+		return code
+	}
+	if target, ok := types.ParseDelegation(code); ok {
+		// Note we only follow one level of delegation.
+		return evm.StateDB.GetCode(target)
+	}
+	return code
+}
+
+// resolveCodeHash returns the code hash associated with the provided address.
+// After Prague, it can also resolve code hash of the account pointed to by a
+// delegation designator. Although this is not accessible in the EVM it is used
+// internally to associate jumpdest analysis to code.
+func (evm *EVM) resolveCodeHash(addr common.Address) common.Hash {
+	if evm.chainRules.IsPrague {
+		code := evm.StateDB.GetCode(addr)
+		if target, ok := types.ParseDelegation(code); ok {
+			// Note we only follow one level of delegation.
+			return evm.StateDB.GetCodeHash(target)
+		}
+	}
+	return evm.StateDB.GetCodeHash(addr)
+}
+
 type codeAndHash struct {
 	code []byte
 	hash common.Hash
@@ -577,6 +619,7 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	}
 	// Create a new account on the state
 	snapshot := evm.StateDB.Snapshot()
+	sfcSnapshot := evm.sfcSnapshot()
 	evm.StateDB.CreateAccount(address)
 	if evm.chainRules.IsEIP158 {
 		evm.StateDB.SetNonce(address, 1)
@@ -632,6 +675,7 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	// Additionally, when we're in Homestead this also counts for code storage gas errors.
 	if err != nil && (evm.chainRules.IsHomestead || !errors.Is(err, ErrCodeStoreOutOfGas)) {
 		evm.StateDB.RevertToSnapshot(snapshot)
+		evm.revertSfcToSnapshot(sfcSnapshot)
 		if !errors.Is(err, ErrExecutionReverted) {
 			contract.UseGas(contract.Gas)
 		}
@@ -677,6 +721,32 @@ func (evm *EVM) IsSfcCorrupted() (corrupted bool, sfcAddr common.Address) {
 	return corrupted, sfcAddr
 }
 
+// sfcSnapshot creates a revision of the SFC state alongside the main state one,
+// so the shadow SFC execution is rolled back together with the frame it ran in.
+func (evm *EVM) sfcSnapshot() int {
+	if evm.SfcStateDB == nil {
+		return -1
+	}
+	return evm.SfcStateDB.Snapshot()
+}
+
+// touchSfcAccount makes sure the SFC state keeps an account of the precompile.
+// The precompiles have no code there, so the nonce keeps the account and its
+// storage from being deleted as an empty one.
+func (evm *EVM) touchSfcAccount(addr common.Address) {
+	if evm.SfcStateDB.GetNonce(addr) == 0 {
+		evm.SfcStateDB.SetNonce(addr, 1)
+	}
+}
+
+// revertSfcToSnapshot reverts the SFC state to a revision taken by sfcSnapshot.
+func (evm *EVM) revertSfcToSnapshot(revid int) {
+	if evm.SfcStateDB == nil || revid < 0 {
+		return
+	}
+	evm.SfcStateDB.RevertToSnapshot(revid)
+}
+
 func isNilInterface(i interface{}) bool {
 	if i == nil {
 		return true
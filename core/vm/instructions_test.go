@@ -20,11 +20,17 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/holiman/uint256"
 	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/common/hexutil"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/core/state"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
 	"github.com/unicornultrafoundation/go-u2u/crypto"
 	"github.com/unicornultrafoundation/go-u2u/params"
 )
@@ -537,6 +543,152 @@ func TestOpMstore(t *testing.T) {
 	}
 }
 
+func TestPush0(t *testing.T) {
+	londonConfig := *params.TestChainConfig
+	londonConfig.ShanghaiBlock = nil
+	londonConfig.CancunBlock = nil
+	londonConfig.PragueBlock = nil
+	for _, tt := range []struct {
+		config  *params.ChainConfig
+		invalid bool
+	}{
+		{params.TestChainConfig, false},
+		{&londonConfig, true},
+	} {
+		address := common.BytesToAddress([]byte("contract"))
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		statedb.CreateAccount(address)
+		// PUSH1 0x2a, PUSH0, MSTORE, PUSH1 0x20, PUSH0, RETURN
+		statedb.SetCode(address, hexutil.MustDecode("0x602a5f5260205ff3"))
+		vmctx := BlockContext{
+			CanTransfer: func(StateDB, common.Address, *big.Int) bool { return true },
+			Transfer:    func(StateDB, common.Address, common.Address, *big.Int) {},
+			BlockNumber: big.NewInt(0),
+		}
+		env := NewEVM(vmctx, TxContext{}, statedb, nil, tt.config, Config{})
+		ret, _, err := env.Call(AccountRef(common.Address{}), address, nil, 100000, new(big.Int))
+		if tt.invalid {
+			if _, ok := err.(*ErrInvalidOpCode); !ok {
+				t.Fatalf("expected invalid opcode error before Shanghai, got %v", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := common.BytesToHash(ret); got != common.BigToHash(big.NewInt(0x2a)) {
+			t.Fatalf("PUSH0 failed, got %v", got)
+		}
+	}
+}
+
+func TestDelegation(t *testing.T) {
+	cancunConfig := *params.TestChainConfig
+	cancunConfig.PragueBlock = nil
+	var (
+		aa = common.BytesToAddress([]byte("delegated"))
+		bb = common.BytesToAddress([]byte("target"))
+		cc = common.BytesToAddress([]byte("caller"))
+	)
+	newEVM := func(config *params.ChainConfig) (*EVM, *state.StateDB) {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		statedb.SetCode(aa, types.AddressToDelegation(bb))
+		// ADDRESS, EXTCODESIZE, PUSH0, MSTORE, PUSH1 0x20, PUSH0, RETURN
+		statedb.SetCode(bb, hexutil.MustDecode("0x303b5f5260205ff3"))
+		// PUSH0, PUSH0, PUSH0, PUSH0, PUSH0, PUSH20 aa, GAS, CALL, STOP
+		statedb.SetCode(cc, append(append(hexutil.MustDecode("0x5f5f5f5f5f73"), aa.Bytes()...), hexutil.MustDecode("0x5af100")...))
+		vmctx := BlockContext{
+			CanTransfer: func(StateDB, common.Address, *big.Int) bool { return true },
+			Transfer:    func(StateDB, common.Address, common.Address, *big.Int) {},
+			BlockNumber: big.NewInt(0),
+		}
+		return NewEVM(vmctx, TxContext{}, statedb, nil, config, Config{}), statedb
+	}
+
+	// The code of the delegation target is executed in the context of the delegated
+	// account, and EXTCODESIZE observes the delegation designator itself
+	env, _ := newEVM(params.TestChainConfig)
+	ret, _, err := env.Call(AccountRef(common.Address{}), aa, nil, 100000, new(big.Int))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := common.BytesToHash(ret); got != common.BigToHash(big.NewInt(23)) {
+		t.Fatalf("unexpected EXTCODESIZE of a delegated account, got %v", got)
+	}
+
+	// The delegation designator isn't resolved before Prague
+	env, _ = newEVM(&cancunConfig)
+	_, _, err = env.Call(AccountRef(common.Address{}), aa, nil, 100000, new(big.Int))
+	if _, ok := err.(*ErrInvalidOpCode); !ok {
+		t.Fatalf("expected invalid opcode error before Prague, got %v", err)
+	}
+
+	// The access of the delegation target is charged as a cold or warm account access
+	gasUsed := func(warm bool) uint64 {
+		env, statedb := newEVM(params.TestChainConfig)
+		if warm {
+			statedb.AddAddressToAccessList(bb)
+		}
+		_, left, err := env.Call(AccountRef(common.Address{}), cc, nil, 100000, new(big.Int))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return 100000 - left
+	}
+	if cold, warm := gasUsed(false), gasUsed(true); cold-warm != params.ColdAccountAccessCostEIP2929-params.WarmStorageReadCostEIP2929 {
+		t.Fatalf("unexpected cost of the delegation target access, cold %d, warm %d", cold, warm)
+	}
+}
+
+func TestOpMCopy(t *testing.T) {
+	// Test cases from https://eips.ethereum.org/EIPS/eip-5656#test-cases
+	for i, tc := range []struct {
+		dst, src, len string
+		pre           string
+		want          string
+	}{
+		{ // MCOPY 0 32 32 - copy 32 bytes from offset 32 to offset 0.
+			dst: "0x0", src: "0x20", len: "0x20",
+			pre:  "0000000000000000000000000000000000000000000000000000000000000000 000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f",
+			want: "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f 000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f",
+		},
+		{ // MCOPY 0 0 32 - copy 32 bytes from offset 0 to offset 0.
+			dst: "0x0", src: "0x0", len: "0x20",
+			pre:  "0101010101010101010101010101010101010101010101010101010101010101",
+			want: "0101010101010101010101010101010101010101010101010101010101010101",
+		},
+		{ // MCOPY 0 1 8 - copy 8 bytes from offset 1 to offset 0 (overlapping).
+			dst: "0x0", src: "0x1", len: "0x8",
+			pre:  "000102030405060708 000000000000000000000000000000000000000000000000",
+			want: "010203040506070808 000000000000000000000000000000000000000000000000",
+		},
+		{ // MCOPY 1 0 8 - copy 8 bytes from offset 0 to offset 1 (overlapping).
+			dst: "0x1", src: "0x0", len: "0x8",
+			pre:  "000102030405060708 000000000000000000000000000000000000000000000000",
+			want: "000001020304050607 000000000000000000000000000000000000000000000000",
+		},
+	} {
+		var (
+			env            = NewEVM(BlockContext{}, TxContext{}, nil, nil, params.TestChainConfig, Config{})
+			stack          = newstack()
+			pc             = uint64(0)
+			evmInterpreter = NewEVMInterpreter(env, env.Config)
+		)
+		data := common.FromHex(strings.ReplaceAll(tc.pre, " ", ""))
+		mem := NewMemory()
+		mem.Resize(uint64(len(data)))
+		mem.Set(0, uint64(len(data)), data)
+		stack.push(uint256.MustFromHex(tc.len))
+		stack.push(uint256.MustFromHex(tc.src))
+		stack.push(uint256.MustFromHex(tc.dst))
+		opMcopy(&pc, evmInterpreter, &ScopeContext{mem, stack, nil})
+		want := common.FromHex(strings.ReplaceAll(tc.want, " ", ""))
+		if have := mem.store; !bytes.Equal(want, have) {
+			t.Errorf("case %d: want: %#x\nhave: %#x\n", i, want, have)
+		}
+	}
+}
+
 func BenchmarkOpMstore(bench *testing.B) {
 	var (
 		env            = NewEVM(BlockContext{}, TxContext{}, nil, nil, params.TestChainConfig, Config{})
@@ -48,8 +48,12 @@ type StateDB interface {
 	SetState(common.Address, common.Hash, common.Hash)
 	GetStorageRoot(addr common.Address) common.Hash
 
+	GetTransientState(addr common.Address, key common.Hash) common.Hash
+	SetTransientState(addr common.Address, key, value common.Hash)
+
 	Suicide(common.Address) bool
 	HasSuicided(common.Address) bool
+	Selfdestruct6780(common.Address)
 
 	// Exist reports whether the given account exists in state.
 	// Notably this should also return true for suicided accounts.
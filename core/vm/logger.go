@@ -112,6 +112,15 @@ type Tracer interface {
 	CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error)
 }
 
+// TxTracer is a Tracer which is additionally notified about the start and the
+// end of the transaction processing, i.e. about the gas bought for the whole
+// transaction and the gas left after the refunds.
+type TxTracer interface {
+	Tracer
+	CaptureTxStart(gasLimit uint64)
+	CaptureTxEnd(restGas uint64)
+}
+
 // StructLogger is an EVM state logger and implements Tracer.
 //
 // StructLogger can capture state based on the given Log configuration and also keeps
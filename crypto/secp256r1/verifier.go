@@ -0,0 +1,51 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package secp256r1 implements signature verification on the NIST P-256 curve,
+// as required by the RIP-7212 precompile.
+package secp256r1
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"math/big"
+)
+
+// Verify checks the given signature (r, s) of the hash against the public key (x, y).
+// It returns false if the public key isn't a valid point on the curve, or if the
+// signature values are out of range.
+func Verify(hash []byte, r, s, x, y *big.Int) bool {
+	publicKey := newPublicKey(x, y)
+	if publicKey == nil {
+		return false
+	}
+	return ecdsa.Verify(publicKey, hash, r, s)
+}
+
+// newPublicKey creates a public key from the given coordinates, nil is returned
+// if the point isn't on the curve. The point at infinity (0, 0) is rejected too.
+func newPublicKey(x, y *big.Int) *ecdsa.PublicKey {
+	curve := elliptic.P256()
+	// IsOnCurve also rejects the coordinates which are not reduced modulo p
+	if x == nil || y == nil || !curve.IsOnCurve(x, y) {
+		return nil
+	}
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     x,
+		Y:     y,
+	}
+}
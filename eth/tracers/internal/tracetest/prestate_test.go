@@ -0,0 +1,146 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracetest
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/common/hexutil"
+	"github.com/unicornultrafoundation/go-u2u/core"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+	"github.com/unicornultrafoundation/go-u2u/crypto"
+	"github.com/unicornultrafoundation/go-u2u/eth/tracers"
+	"github.com/unicornultrafoundation/go-u2u/evmcore"
+	"github.com/unicornultrafoundation/go-u2u/params"
+	"github.com/unicornultrafoundation/go-u2u/tests"
+)
+
+type prestateAccount struct {
+	Balance *hexutil.Big                `json:"balance,omitempty"`
+	Nonce   uint64                      `json:"nonce,omitempty"`
+	Code    hexutil.Bytes               `json:"code,omitempty"`
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+}
+
+// runPrestateTracer applies a transaction storing 0x2a into slot 0x01 of a
+// contract and returns the raw output of the prestate tracer.
+func runPrestateTracer(t *testing.T, cfg string) (common.Address, common.Address, *big.Int, json.RawMessage) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		from     = crypto.PubkeyToAddress(key.PublicKey)
+		to       = common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+		balance  = big.NewInt(500000000000000)
+		signer   = types.LatestSignerForChainID(params.TestChainConfig.ChainID)
+		alloc    = core.GenesisAlloc{}
+		contract = []byte{
+			byte(vm.PUSH1), 0x2a,
+			byte(vm.PUSH1), 0x01,
+			byte(vm.SSTORE),
+		}
+	)
+	alloc[to] = core.GenesisAccount{Nonce: 1, Code: contract, Balance: big.NewInt(0)}
+	alloc[from] = core.GenesisAccount{Nonce: 1, Balance: balance}
+
+	tx, err := types.SignNewTx(key, signer, &types.LegacyTx{
+		Nonce:    1,
+		GasPrice: big.NewInt(500),
+		Gas:      100000,
+		To:       &to,
+		Value:    big.NewInt(1000),
+	})
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	_, statedb := tests.MakePreState(rawdb.NewMemoryDatabase(), alloc, false)
+	tracer, err := tracers.New("prestateTracer", new(tracers.Context), json.RawMessage(cfg))
+	if err != nil {
+		t.Fatalf("failed to create prestate tracer: %v", err)
+	}
+	context := vm.BlockContext{
+		CanTransfer: evmcore.CanTransfer,
+		Transfer:    evmcore.Transfer,
+		BlockNumber: big.NewInt(5),
+		Time:        big.NewInt(5),
+		Difficulty:  big.NewInt(0),
+		GasLimit:    tx.Gas(),
+		BaseFee:     big.NewInt(8),
+	}
+	txContext := vm.TxContext{Origin: from, GasPrice: tx.GasPrice()}
+	evm := vm.NewEVM(context, txContext, statedb, nil, params.TestChainConfig, vm.Config{Debug: true, Tracer: tracer})
+	msg, err := tx.AsMessage(signer, nil)
+	if err != nil {
+		t.Fatalf("failed to prepare transaction for tracing: %v", err)
+	}
+	if _, err := evmcore.ApplyMessage(evm, msg, new(evmcore.GasPool).AddGas(tx.Gas())); err != nil {
+		t.Fatalf("failed to execute transaction: %v", err)
+	}
+	res, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("failed to retrieve trace result: %v", err)
+	}
+	return from, to, balance, res
+}
+
+func TestPrestateTracer(t *testing.T) {
+	from, to, balance, res := runPrestateTracer(t, `{}`)
+
+	var pre map[common.Address]*prestateAccount
+	if err := json.Unmarshal(res, &pre); err != nil {
+		t.Fatalf("failed to unmarshal trace result: %v", err)
+	}
+	if have := pre[from]; have == nil || have.Balance.ToInt().Cmp(balance) != 0 || have.Nonce != 1 {
+		t.Fatalf("sender prestate mismatch: have %+v, want balance %v and nonce 1", have, balance)
+	}
+	if have := pre[to]; have == nil || have.Balance.ToInt().Sign() != 0 {
+		t.Fatalf("recipient prestate mismatch: have %+v, want zero balance", have)
+	}
+	if have := pre[to].Storage[common.HexToHash("0x01")]; have != (common.Hash{}) {
+		t.Fatalf("storage prestate mismatch: have %x, want empty slot", have)
+	}
+}
+
+func TestPrestateTracerDiffMode(t *testing.T) {
+	from, to, balance, res := runPrestateTracer(t, `{"diffMode": true}`)
+
+	var diff struct {
+		Pre  map[common.Address]*prestateAccount `json:"pre"`
+		Post map[common.Address]*prestateAccount `json:"post"`
+	}
+	if err := json.Unmarshal(res, &diff); err != nil {
+		t.Fatalf("failed to unmarshal trace result: %v", err)
+	}
+	if have := diff.Pre[from]; have == nil || have.Balance.ToInt().Cmp(balance) != 0 {
+		t.Fatalf("sender prestate mismatch: have %+v, want balance %v", have, balance)
+	}
+	if have := diff.Post[from]; have == nil || have.Balance.ToInt().Cmp(balance) >= 0 || have.Nonce != 2 {
+		t.Fatalf("sender poststate mismatch: have %+v", have)
+	}
+	if have := diff.Post[to]; have == nil || have.Balance.ToInt().Cmp(big.NewInt(1000)) != 0 {
+		t.Fatalf("recipient poststate mismatch: have %+v, want balance 1000", have)
+	}
+	if have, want := diff.Post[to].Storage[common.HexToHash("0x01")], common.HexToHash("0x2a"); have != want {
+		t.Fatalf("storage poststate mismatch: have %x, want %x", have, want)
+	}
+	if _, ok := diff.Pre[to].Storage[common.HexToHash("0x01")]; ok {
+		t.Fatalf("empty slot included into the prestate")
+	}
+}
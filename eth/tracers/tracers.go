@@ -33,12 +33,10 @@ type Context struct {
 	TxHash    common.Hash // Hash of the transaction being traced (zero if dangling call)
 }
 
-// Tracer interface extends vm.EVMLogger and additionally
+// Tracer interface extends vm.TxTracer and additionally
 // allows collecting the tracing result.
 type Tracer interface {
-	vm.Tracer
-	CaptureTxStart(gasLimit uint64)
-	CaptureTxEnd(restGas uint64)
+	vm.TxTracer
 
 	GetResult() (json.RawMessage, error)
 	// Stop terminates execution of the tracer at the first opportune moment.
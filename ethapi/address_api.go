@@ -0,0 +1,152 @@
+package ethapi
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/common/hexutil"
+	"github.com/unicornultrafoundation/go-u2u/evmcore"
+	"github.com/unicornultrafoundation/go-u2u/log"
+	"github.com/unicornultrafoundation/go-u2u/rpc"
+)
+
+const (
+	// defaultAddressTxsLimit is the number of transactions returned on a page if the limit isn't specified
+	defaultAddressTxsLimit = 100
+	// maxAddressTxsLimit limits the number of transactions returned on a page
+	maxAddressTxsLimit = 1000
+
+	addressTxsCursorSize = 8 + 4
+)
+
+// PublicAddressAPI provides an API to access the transaction history of the addresses.
+type PublicAddressAPI struct {
+	b Backend
+}
+
+// NewPublicAddressAPI creates a new address history API.
+func NewPublicAddressAPI(b Backend) *PublicAddressAPI {
+	return &PublicAddressAPI{b}
+}
+
+// AddressTransactions is a page of the transactions in which an address appears
+type AddressTransactions struct {
+	Transactions []*RPCTransaction `json:"transactions"`
+	// NextCursor is the position of the first transaction of the next page, nil if it's the last page
+	NextCursor *hexutil.Bytes `json:"nextCursor"`
+}
+
+// addressTxsCursor is a position of a transaction in the chain
+type addressTxsCursor struct {
+	Block       idx.Block
+	BlockOffset uint32
+}
+
+func (c addressTxsCursor) less(o addressTxsCursor) bool {
+	return c.Block < o.Block || (c.Block == o.Block && c.BlockOffset < o.BlockOffset)
+}
+
+func (c addressTxsCursor) bytes() *hexutil.Bytes {
+	b := make(hexutil.Bytes, addressTxsCursorSize)
+	binary.BigEndian.PutUint64(b, uint64(c.Block))
+	binary.BigEndian.PutUint32(b[8:], c.BlockOffset)
+	return &b
+}
+
+func parseAddressTxsCursor(b hexutil.Bytes) (addressTxsCursor, error) {
+	if len(b) != addressTxsCursorSize {
+		return addressTxsCursor{}, errors.New("invalid cursor")
+	}
+	return addressTxsCursor{
+		Block:       idx.Block(binary.BigEndian.Uint64(b)),
+		BlockOffset: binary.BigEndian.Uint32(b[8:]),
+	}, nil
+}
+
+// GetTransactionsByAddress returns a page of the transactions in which the address appears as the sender,
+// the recipient, the created contract, a participant of an internal call or a log emitter.
+// The direction is either "asc" or "desc" (default). The cursor is the nextCursor of the previous page,
+// the first page is returned if it's omitted. The internal calls are missing for the blocks which
+// aren't executed by the node, but only imported from the LLR full block records.
+func (s *PublicAddressAPI) GetTransactionsByAddress(ctx context.Context, address common.Address, cursor *hexutil.Bytes, limit *uint64, direction *string) (*AddressTransactions, error) {
+	reverse := true
+	if direction != nil {
+		switch *direction {
+		case "asc":
+			reverse = false
+		case "desc":
+		default:
+			return nil, fmt.Errorf("invalid direction %q, expected \"asc\" or \"desc\"", *direction)
+		}
+	}
+	pageSize := uint64(defaultAddressTxsLimit)
+	if limit != nil {
+		if *limit == 0 || *limit > maxAddressTxsLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", maxAddressTxsLimit)
+		}
+		pageSize = *limit
+	}
+
+	var (
+		start *addressTxsCursor
+		from  idx.Block
+		res   = &AddressTransactions{Transactions: make([]*RPCTransaction, 0)}
+		block *evmcore.EvmBlock
+		err   error
+	)
+	if cursor != nil {
+		c, err := parseAddressTxsCursor(*cursor)
+		if err != nil {
+			return nil, err
+		}
+		start = &c
+		from = c.Block
+	}
+	if reverse {
+		latest := idx.Block(s.b.CurrentBlock().NumberU64()) + 1
+		if start != nil && start.Block < latest {
+			from = start.Block + 1
+		} else {
+			from = latest
+		}
+	}
+
+	iterErr := s.b.AddressTxs(ctx, address, from, reverse, func(tx AddressTx) bool {
+		pos := addressTxsCursor{tx.Block, tx.BlockOffset}
+		if start != nil && ((!reverse && pos.less(*start)) || (reverse && start.less(pos))) {
+			return true
+		}
+		if uint64(len(res.Transactions)) >= pageSize {
+			res.NextCursor = pos.bytes()
+			return false
+		}
+		if block == nil || block.NumberU64() != uint64(tx.Block) {
+			block, err = s.b.BlockByNumber(ctx, rpc.BlockNumber(tx.Block))
+			if block == nil || err != nil {
+				if err == nil {
+					err = fmt.Errorf("block %d not found", tx.Block)
+				}
+				return false
+			}
+		}
+		i := int(tx.BlockOffset)
+		if i >= len(block.Transactions) || block.Transactions[i].Hash() != tx.TxHash {
+			log.Warn("Address index points to a missing transaction", "block", tx.Block, "tx", tx.TxHash)
+			return true
+		}
+		res.Transactions = append(res.Transactions, newRPCTransactionFromBlockIndex(block, uint64(i)))
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if iterErr != nil {
+		return nil, iterErr
+	}
+	return res, nil
+}
@@ -19,6 +19,7 @@ package ethapi
 
 import (
 	"context"
+	"errors"
 	"math/big"
 	"time"
 
@@ -50,6 +51,43 @@ type PeerProgress struct {
 	HighestEpoch     idx.Epoch
 }
 
+// ErrAddressIndexDisabled is returned by the queries of the address index if it isn't enabled
+var ErrAddressIndexDisabled = errors.New("address index is disabled, enable it with --addressindex and backfill it with 'u2u index addresses'")
+
+// AddressTx locates a transaction in which an address appears
+type AddressTx struct {
+	Block       idx.Block
+	BlockOffset uint32
+	TxHash      common.Hash
+}
+
+// ContractCreator is the transaction which created a contract
+type ContractCreator struct {
+	TxHash  common.Hash
+	Creator common.Address
+}
+
+// TraceAddressRole is a role of an address in an indexed trace
+type TraceAddressRole int
+
+const (
+	// TraceRoleFrom is the sender of a call, the creator of a contract
+	// or the self-destructed contract
+	TraceRoleFrom TraceAddressRole = iota
+	// TraceRoleTo is the recipient of a call or the refund address of a self-destruct
+	TraceRoleTo
+	// TraceRoleCreated is the address of a created contract
+	TraceRoleCreated
+)
+
+// TraceRef locates an indexed trace
+type TraceRef struct {
+	Block        uint64
+	TxPosition   uint32
+	TraceAddress []uint32
+	TxHash       common.Hash
+}
+
 // Backend interface provides the common API services (that are provided by
 // both full and light clients) with access to necessary functions.
 type Backend interface {
@@ -65,8 +103,10 @@ type Backend interface {
 	RPCTimeout() time.Duration
 	UnprotectedAllowed() bool // allows only for EIP155 transactions.
 	CalcBlockExtApi() bool
-	StateAtBlock(ctx context.Context, block *evmcore.EvmBlock, reexec uint64, base *state.StateDB, checkLive bool) (*state.StateDB, *state.StateDB, error)
+	StateAtBlock(ctx context.Context, block *evmcore.EvmBlock, reexec uint64, base, baseSfc *state.StateDB, checkLive bool) (*state.StateDB, *state.StateDB, error)
 	StateAtTransaction(ctx context.Context, block *evmcore.EvmBlock, txIndex int, reexec uint64) (evmcore.Message, vm.BlockContext, *state.StateDB, error)
+	ReplayBlock(ctx context.Context, block *evmcore.EvmBlock, statedb, sfcStatedb *state.StateDB, vmCfg vm.Config, onTx func(i int, tx *types.Transaction, receipt *types.Receipt) error) error
+	TraceDir() string // directory for the trace files written by the debug API
 	// Blockchain API
 	HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmHeader, error)
 	HeaderByHash(ctx context.Context, hash common.Hash) (*evmcore.EvmHeader, error)
@@ -84,6 +124,13 @@ type Backend interface {
 	// Transaction trace API
 	TxTraceByHash(ctx context.Context, h common.Hash) (*[]txtracer.ActionTrace, error)
 	TxTraceSave(ctx context.Context, h common.Hash, traces []byte) error
+	TxTraceIndexed(ctx context.Context) bool
+	TxTraceRefs(ctx context.Context, role TraceAddressRole, addr common.Address, from, to uint64, onRef func(ref TraceRef) bool) error
+
+	// Address index API
+	AddressTxs(ctx context.Context, addr common.Address, from idx.Block, reverse bool, onTx func(tx AddressTx) bool) error
+	TxBySenderAndNonce(ctx context.Context, addr common.Address, nonce uint64) (*common.Hash, error)
+	ContractCreator(ctx context.Context, addr common.Address) (*ContractCreator, error)
 
 	// Transaction pool API
 	SendTx(ctx context.Context, signedTx *types.Transaction) error
@@ -103,6 +150,7 @@ type Backend interface {
 	GetEventPayload(ctx context.Context, shortEventID string) (*native.EventPayload, error)
 	GetEvent(ctx context.Context, shortEventID string) (*native.Event, error)
 	GetHeads(ctx context.Context, epoch rpc.BlockNumber) (hash.Events, error)
+	GetBlockEvents(ctx context.Context, number rpc.BlockNumber) (hash.Events, error)
 	CurrentEpoch(ctx context.Context) idx.Epoch
 	SealedEpochTiming(ctx context.Context) (start native.Timestamp, end native.Timestamp)
 
@@ -173,6 +221,16 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 			Version:   "1.0",
 			Service:   NewPublicTxTraceAPI(apiBackend),
 			Public:    true,
+		}, {
+			Namespace: "ots",
+			Version:   "1.0",
+			Service:   NewPublicOtterscanAPI(apiBackend),
+			Public:    true,
+		}, {
+			Namespace: "u2u",
+			Version:   "1.0",
+			Service:   NewPublicAddressAPI(apiBackend),
+			Public:    true,
 		},
 	}
 
@@ -0,0 +1,77 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/core/state"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+	"github.com/unicornultrafoundation/go-u2u/evmcore"
+	"github.com/unicornultrafoundation/go-u2u/native"
+	"github.com/unicornultrafoundation/go-u2u/params"
+	"github.com/unicornultrafoundation/go-u2u/rpc"
+)
+
+// testBackend serves the API calls from the in-memory states on top of a single block.
+// The methods not overridden here panic, so a test fails loudly if it needs more.
+type testBackend struct {
+	Backend
+	statedb  *state.StateDB
+	sfcState *state.StateDB
+	header   *evmcore.EvmHeader
+	gasCap   uint64
+}
+
+func newTestBackend(t *testing.T) *testBackend {
+	newState := func() *state.StateDB {
+		statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		require.NoError(t, err)
+		return statedb
+	}
+	return &testBackend{
+		statedb:  newState(),
+		sfcState: newState(),
+		header: &evmcore.EvmHeader{
+			Number:   big.NewInt(10),
+			Hash:     common.Hash{0x10},
+			Time:     native.FromUnix(1000),
+			GasLimit: 30_000_000,
+			BaseFee:  big.NewInt(0),
+		},
+		gasCap: 10_000_000,
+	}
+}
+
+func (b *testBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *evmcore.EvmHeader, error) {
+	return b.statedb, b.header, nil
+}
+
+func (b *testBackend) SfcStateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *evmcore.EvmHeader, error) {
+	return b.sfcState, b.header, nil
+}
+
+func (b *testBackend) GetBlockContext(header *evmcore.EvmHeader) vm.BlockContext {
+	return evmcore.NewEVMBlockContext(header, nil, nil)
+}
+
+func (b *testBackend) ChainConfig() *params.ChainConfig {
+	return params.TestChainConfig
+}
+
+func (b *testBackend) RPCGasCap() uint64 {
+	return b.gasCap
+}
+
+func (b *testBackend) RPCTimeout() time.Duration {
+	return time.Second
+}
+
+// storageCode is the code of a test contract, which stores the first word of the
+// input into the slot 0 and emits an empty log, or returns the slot 0 on an empty input
+var storageCode = common.FromHex("0x36600f5760005460005260206000f35b60003560005560006000a000")
@@ -0,0 +1,221 @@
+package ethapi
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/state"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+	"github.com/unicornultrafoundation/go-u2u/evmcore"
+	"github.com/unicornultrafoundation/go-u2u/log"
+	"github.com/unicornultrafoundation/go-u2u/rpc"
+	"github.com/unicornultrafoundation/go-u2u/u2u"
+)
+
+// IntermediateRoot is the state of the block after a transaction
+type IntermediateRoot struct {
+	TxHash  common.Hash  `json:"txHash"`
+	Root    common.Hash  `json:"root"`
+	SfcRoot *common.Hash `json:"sfcRoot,omitempty"` // nil if the SFC state isn't available
+	Skipped bool         `json:"skipped,omitempty"`
+}
+
+// StdTraceConfig holds extra parameters to standard-json trace functions.
+type StdTraceConfig struct {
+	vm.LogConfig
+	Reexec *uint64
+	TxHash common.Hash
+}
+
+// replayState returns the block and the states of its parent block to replay the block on.
+func (api *PublicDebugAPI) replayState(ctx context.Context, hash common.Hash, reexec *uint64) (*evmcore.EvmBlock, *state.StateDB, *state.StateDB, error) {
+	block, err := api.blockByHash(ctx, hash)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if block.NumberU64() == 0 {
+		return nil, nil, nil, errors.New("genesis is not traceable")
+	}
+	parent, err := api.blockByNumber(ctx, rpc.BlockNumber(block.NumberU64()-1))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	n := defaultTraceReexec
+	if reexec != nil {
+		n = *reexec
+	}
+	statedb, sfcStatedb, err := api.b.StateAtBlock(ctx, parent, n, nil, nil, true)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return block, statedb, sfcStatedb, nil
+}
+
+// IntermediateRoots executes a block and returns a list of intermediate roots:
+// the state root and the SFC state root after each transaction.
+func (api *PublicDebugAPI) IntermediateRoots(ctx context.Context, hash common.Hash, config *TraceConfig) ([]IntermediateRoot, error) {
+	var reexec *uint64
+	if config != nil {
+		reexec = config.Reexec
+	}
+	block, statedb, sfcStatedb, err := api.replayState(ctx, hash, reexec)
+	if err != nil {
+		return nil, err
+	}
+	roots := make([]IntermediateRoot, 0, len(block.Transactions))
+	err = api.b.ReplayBlock(ctx, block, statedb, sfcStatedb, u2u.DefaultVMConfig, func(i int, tx *types.Transaction, receipt *types.Receipt) error {
+		root := IntermediateRoot{
+			TxHash:  tx.Hash(),
+			Root:    statedb.IntermediateRoot(true),
+			Skipped: receipt == nil,
+		}
+		if sfcStatedb != nil {
+			sfcRoot := sfcStatedb.IntermediateRoot(true)
+			root.SfcRoot = &sfcRoot
+		}
+		roots = append(roots, root)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return roots, nil
+}
+
+// StandardTraceBlockToFile dumps the structured logs created during the
+// execution of EVM to the local file system and returns a list of files
+// to the caller.
+func (api *PublicDebugAPI) StandardTraceBlockToFile(ctx context.Context, hash common.Hash, config *StdTraceConfig) ([]string, error) {
+	if config == nil {
+		config = new(StdTraceConfig)
+	}
+	block, statedb, sfcStatedb, err := api.replayState(ctx, hash, config.Reexec)
+	if err != nil {
+		return nil, err
+	}
+	if config.TxHash != (common.Hash{}) {
+		found := false
+		for _, tx := range block.Transactions {
+			if tx.Hash() == config.TxHash {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("transaction %#x not found in block", config.TxHash)
+		}
+	}
+	dir := api.b.TraceDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	var (
+		tracer = new(switchTracer)
+		dumps  []string
+		dump   *os.File
+		writer *bufio.Writer
+	)
+	// closeDump flushes the trace of the previous transaction
+	closeDump := func() error {
+		if dump == nil {
+			return nil
+		}
+		tracer.Tracer = nil
+		err := writer.Flush()
+		if closeErr := dump.Close(); err == nil {
+			err = closeErr
+		}
+		dump = nil
+		if err == nil {
+			log.Info("Wrote standard trace", "file", dumps[len(dumps)-1])
+		}
+		return err
+	}
+	// openDump creates the trace file of the transaction
+	openDump := func(i int, tx *types.Transaction) error {
+		if config.TxHash != (common.Hash{}) && tx.Hash() != config.TxHash {
+			return nil
+		}
+		prefix := fmt.Sprintf("block_%#x-%d-%#x-", block.Hash.Bytes()[:4], i, tx.Hash().Bytes()[:4])
+		var err error
+		dump, err = os.CreateTemp(dir, prefix)
+		if err != nil {
+			return err
+		}
+		dumps = append(dumps, dump.Name())
+		writer = bufio.NewWriter(dump)
+		tracer.Tracer = vm.NewJSONLogger(&config.LogConfig, writer)
+		return nil
+	}
+
+	vmCfg := u2u.DefaultVMConfig
+	vmCfg.Debug = true
+	vmCfg.Tracer = tracer
+	if len(block.Transactions) != 0 {
+		if err := openDump(0, block.Transactions[0]); err != nil {
+			return dumps, err
+		}
+	}
+	err = api.b.ReplayBlock(ctx, block, statedb, sfcStatedb, vmCfg, func(i int, tx *types.Transaction, receipt *types.Receipt) error {
+		if err := closeDump(); err != nil {
+			return err
+		}
+		if i+1 < len(block.Transactions) {
+			return openDump(i+1, block.Transactions[i+1])
+		}
+		return nil
+	})
+	if closeErr := closeDump(); err == nil {
+		err = closeErr
+	}
+	return dumps, err
+}
+
+// switchTracer forwards the calls to the tracer of the current transaction, if any
+type switchTracer struct {
+	vm.Tracer
+}
+
+func (t *switchTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	if t.Tracer != nil {
+		t.Tracer.CaptureStart(env, from, to, create, input, gas, value)
+	}
+}
+
+func (t *switchTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if t.Tracer != nil {
+		t.Tracer.CaptureState(env, pc, op, gas, cost, scope, rData, depth, err)
+	}
+}
+
+func (t *switchTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.Tracer != nil {
+		t.Tracer.CaptureEnter(typ, from, to, input, gas, value)
+	}
+}
+
+func (t *switchTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if t.Tracer != nil {
+		t.Tracer.CaptureExit(output, gasUsed, err)
+	}
+}
+
+func (t *switchTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+	if t.Tracer != nil {
+		t.Tracer.CaptureFault(env, pc, op, gas, cost, scope, depth, err)
+	}
+}
+
+func (t *switchTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) {
+	if t.Tracer != nil {
+		t.Tracer.CaptureEnd(output, gasUsed, d, err)
+	}
+}
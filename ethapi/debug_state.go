@@ -0,0 +1,246 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/common/hexutil"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/core/state"
+	"github.com/unicornultrafoundation/go-u2u/evmcore"
+	"github.com/unicornultrafoundation/go-u2u/rlp"
+	"github.com/unicornultrafoundation/go-u2u/rpc"
+	"github.com/unicornultrafoundation/go-u2u/trie"
+)
+
+// AccountRangeMaxResults is the maximum number of results to be returned per call
+const AccountRangeMaxResults = 256
+
+// StorageRangeResult is the result of a debug_storageRangeAt API call.
+type StorageRangeResult struct {
+	Storage storageMap   `json:"storage"`
+	NextKey *common.Hash `json:"nextKey"` // nil if Storage includes the last key in the trie.
+}
+
+type storageMap map[common.Hash]storageEntry
+
+type storageEntry struct {
+	Key   *common.Hash `json:"key"`
+	Value common.Hash  `json:"value"`
+}
+
+var (
+	errSfcStateUnavailable         = errors.New("SFC state is not available")
+	errModifiedAccountsUnavailable = errors.New("modified accounts are not available")
+)
+
+// isSfc returns true if the optional state selector points to the SFC state
+func isSfc(sfc *bool) bool {
+	return sfc != nil && *sfc
+}
+
+// stateAndHeader returns the main EVM state or the SFC state at the block.
+func stateAndHeader(ctx context.Context, b Backend, blockNrOrHash rpc.BlockNumberOrHash, sfc *bool) (*state.StateDB, *evmcore.EvmHeader, error) {
+	var (
+		statedb *state.StateDB
+		header  *evmcore.EvmHeader
+		err     error
+	)
+	if isSfc(sfc) {
+		statedb, header, err = b.SfcStateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	} else {
+		statedb, header, err = b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if statedb == nil || header == nil {
+		return nil, nil, errors.New("state not found")
+	}
+	return statedb, header, nil
+}
+
+// stateRoot returns the root of the main EVM state or the SFC state of the block
+func stateRoot(header *evmcore.EvmHeader, sfc *bool) common.Hash {
+	if isSfc(sfc) {
+		return header.SfcStateRoot
+	}
+	return header.Root
+}
+
+// DumpBlock retrieves the entire state of the database at a given block.
+// The optional sfc flag selects the SFC state instead of the main EVM state.
+func (api *PublicDebugAPI) DumpBlock(ctx context.Context, blockNr rpc.BlockNumber, sfc *bool) (state.Dump, error) {
+	opts := &state.DumpConfig{
+		OnlyWithAddresses: true,
+		Max:               AccountRangeMaxResults, // Sanity limit over RPC
+	}
+	statedb, _, err := stateAndHeader(ctx, api.b, rpc.BlockNumberOrHashWithNumber(blockNr), sfc)
+	if err != nil {
+		return state.Dump{}, err
+	}
+	return statedb.RawDump(opts), nil
+}
+
+// AccountRange enumerates all accounts in the given block and start point in paging request.
+// The optional sfc flag selects the SFC state instead of the main EVM state.
+func (api *PublicDebugAPI) AccountRange(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, start hexutil.Bytes, maxResults int, nocode, nostorage, incompletes bool, sfc *bool) (state.IteratorDump, error) {
+	statedb, _, err := stateAndHeader(ctx, api.b, blockNrOrHash, sfc)
+	if err != nil {
+		return state.IteratorDump{}, err
+	}
+	opts := &state.DumpConfig{
+		SkipCode:          nocode,
+		SkipStorage:       nostorage,
+		OnlyWithAddresses: !incompletes,
+		Start:             start,
+		Max:               uint64(maxResults),
+	}
+	if maxResults > AccountRangeMaxResults || maxResults <= 0 {
+		opts.Max = AccountRangeMaxResults
+	}
+	return statedb.IteratorDump(opts), nil
+}
+
+// Preimage is a debug API function that returns the preimage for a sha3 hash, if known.
+// The optional sfc flag selects the preimages of the SFC state instead of the main EVM state.
+func (api *PrivateDebugAPI) Preimage(ctx context.Context, hash common.Hash, sfc *bool) (hexutil.Bytes, error) {
+	statedb, _, err := stateAndHeader(ctx, api.b, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), sfc)
+	if err != nil {
+		return nil, err
+	}
+	if preimage := statedb.Database().TrieDB().Preimage(hash); preimage != nil {
+		return preimage, nil
+	}
+	return nil, errors.New("unknown preimage")
+}
+
+// StorageRangeAt returns the storage at the given block height and transaction index.
+// The optional sfc flag selects the SFC state instead of the main EVM state.
+func (api *PrivateDebugAPI) StorageRangeAt(ctx context.Context, blockHash common.Hash, txIndex int, contractAddress common.Address, keyStart hexutil.Bytes, maxResult int, sfc *bool) (StorageRangeResult, error) {
+	debug := NewPublicDebugAPI(api.b)
+	block, err := debug.blockByHash(ctx, blockHash)
+	if err != nil {
+		return StorageRangeResult{}, err
+	}
+	_, _, statedb, sfcStatedb, err := debug.stateAtTransaction(ctx, block, txIndex)
+	if err != nil {
+		return StorageRangeResult{}, err
+	}
+	if isSfc(sfc) {
+		if sfcStatedb == nil {
+			return StorageRangeResult{}, errSfcStateUnavailable
+		}
+		statedb = sfcStatedb
+	}
+	st := statedb.StorageTrie(contractAddress)
+	if st == nil {
+		return StorageRangeResult{}, fmt.Errorf("account %x doesn't exist", contractAddress)
+	}
+	return storageRangeAt(st, keyStart, maxResult)
+}
+
+func storageRangeAt(st state.Trie, start []byte, maxResult int) (StorageRangeResult, error) {
+	it := trie.NewIterator(st.NodeIterator(start))
+	result := StorageRangeResult{Storage: storageMap{}}
+	for i := 0; i < maxResult && it.Next(); i++ {
+		_, content, _, err := rlp.Split(it.Value)
+		if err != nil {
+			return StorageRangeResult{}, err
+		}
+		e := storageEntry{Value: common.BytesToHash(content)}
+		if preimage := st.GetKey(it.Key); preimage != nil {
+			preimage := common.BytesToHash(preimage)
+			e.Key = &preimage
+		}
+		result.Storage[common.BytesToHash(it.Key)] = e
+	}
+	// Add the 'next key' so clients can continue downloading.
+	if it.Next() {
+		next := common.BytesToHash(it.Key)
+		result.NextKey = &next
+	}
+	return result, nil
+}
+
+// GetModifiedAccountsByNumber returns all accounts that have changed between the
+// two blocks specified. A change is defined as a difference in nonce, balance,
+// code hash, or storage hash.
+//
+// With one parameter, returns the list of accounts modified in the specified block.
+// The optional sfc flag selects the SFC state instead of the main EVM state.
+func (api *PrivateDebugAPI) GetModifiedAccountsByNumber(ctx context.Context, startNum uint64, endNum *uint64, sfc *bool) ([]common.Address, error) {
+	start := rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(startNum))
+	if endNum == nil {
+		if startNum == 0 {
+			return nil, errors.New("genesis has no parent block")
+		}
+		start, endNum = rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(startNum-1)), &startNum
+	}
+	return api.getModifiedAccounts(ctx, start, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(*endNum)), sfc)
+}
+
+// GetModifiedAccountsByHash returns all accounts that have changed between the
+// two blocks specified. A change is defined as a difference in nonce, balance,
+// code hash, or storage hash.
+//
+// With one parameter, returns the list of accounts modified in the specified block.
+// The optional sfc flag selects the SFC state instead of the main EVM state.
+func (api *PrivateDebugAPI) GetModifiedAccountsByHash(ctx context.Context, startHash common.Hash, endHash *common.Hash, sfc *bool) ([]common.Address, error) {
+	start := rpc.BlockNumberOrHashWithHash(startHash, false)
+	if endHash == nil {
+		block, err := NewPublicDebugAPI(api.b).blockByHash(ctx, startHash)
+		if err != nil {
+			return nil, err
+		}
+		start, endHash = rpc.BlockNumberOrHashWithHash(block.ParentHash, false), &startHash
+	}
+	return api.getModifiedAccounts(ctx, start, rpc.BlockNumberOrHashWithHash(*endHash, false), sfc)
+}
+
+func (api *PrivateDebugAPI) getModifiedAccounts(ctx context.Context, start, end rpc.BlockNumberOrHash, sfc *bool) ([]common.Address, error) {
+	_, startHeader, err := stateAndHeader(ctx, api.b, start, sfc)
+	if err != nil {
+		return nil, err
+	}
+	statedb, endHeader, err := stateAndHeader(ctx, api.b, end, sfc)
+	if err != nil {
+		return nil, err
+	}
+	if startHeader.Number.Cmp(endHeader.Number) >= 0 {
+		return nil, fmt.Errorf("start block height (%d) must be less than end block height (%d)", startHeader.Number, endHeader.Number)
+	}
+	db := statedb.Database()
+	startRoot, endRoot := stateRoot(startHeader, sfc), stateRoot(endHeader, sfc)
+	// The path scheme keeps only the tries of the latest state, the older states
+	// are restored from the state history account by account
+	if db.TrieDB().Scheme() == rawdb.PathScheme {
+		for _, header := range []*evmcore.EvmHeader{startHeader, endHeader} {
+			if root := stateRoot(header, sfc); !db.TrieDB().Readable(root) {
+				return nil, fmt.Errorf("%w: state trie %s of block %d isn't kept by the path-based state storage", errModifiedAccountsUnavailable, root.Hex(), header.Number)
+			}
+		}
+	}
+	oldTrie, err := db.OpenTrie(startRoot)
+	if err != nil {
+		return nil, err
+	}
+	newTrie, err := db.OpenTrie(endRoot)
+	if err != nil {
+		return nil, err
+	}
+	diff, _ := trie.NewDifferenceIterator(oldTrie.NodeIterator([]byte{}), newTrie.NodeIterator([]byte{}))
+	iter := trie.NewIterator(diff)
+
+	var dirty []common.Address
+	for iter.Next() {
+		key := newTrie.GetKey(iter.Key)
+		if key == nil {
+			return nil, fmt.Errorf("no preimage found for hash %x", iter.Key)
+		}
+		dirty = append(dirty, common.BytesToAddress(key))
+	}
+	return dirty, nil
+}
@@ -0,0 +1,225 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/core/state"
+	"github.com/unicornultrafoundation/go-u2u/evmcore"
+	"github.com/unicornultrafoundation/go-u2u/rpc"
+	"github.com/unicornultrafoundation/go-u2u/trie"
+)
+
+// debugTestBackend serves the committed EVM and SFC states of a short chain.
+// Like the path scheme, it may serve the states of all the blocks on top of the latest trie.
+type debugTestBackend struct {
+	Backend
+	db, sfcDB       state.Database
+	roots, sfcRoots []common.Hash
+	latestOnly      bool
+}
+
+func newDebugTestBackend(t *testing.T, config *trie.Config) *debugTestBackend {
+	return &debugTestBackend{
+		db:    state.NewDatabaseWithConfig(rawdb.NewMemoryDatabase(), config),
+		sfcDB: state.NewDatabaseWithConfig(rawdb.NewMemoryDatabase(), config),
+	}
+}
+
+// commitBlock commits the changes of the next block into both the states
+func (b *debugTestBackend) commitBlock(t *testing.T, modify, modifySfc func(*state.StateDB)) {
+	commit := func(db state.Database, roots []common.Hash, modify func(*state.StateDB)) []common.Hash {
+		root := common.Hash{}
+		if len(roots) != 0 {
+			root = roots[len(roots)-1]
+		}
+		statedb, err := state.New(root, db, nil)
+		require.NoError(t, err)
+		if modify != nil {
+			modify(statedb)
+		}
+		root, err = statedb.Commit(true)
+		require.NoError(t, err)
+		require.NoError(t, db.TrieDB().Commit(root, false, nil))
+		return append(roots, root)
+	}
+	b.roots = commit(b.db, b.roots, modify)
+	b.sfcRoots = commit(b.sfcDB, b.sfcRoots, modifySfc)
+}
+
+func (b *debugTestBackend) header(n int) *evmcore.EvmHeader {
+	return &evmcore.EvmHeader{
+		Number:       big.NewInt(int64(n)),
+		Hash:         common.Hash{byte(n + 1)},
+		ParentHash:   common.Hash{byte(n)},
+		Root:         b.roots[n],
+		SfcStateRoot: b.sfcRoots[n],
+	}
+}
+
+func (b *debugTestBackend) number(blockNrOrHash rpc.BlockNumberOrHash) int {
+	if blockNrOrHash.BlockHash != nil {
+		return int(blockNrOrHash.BlockHash[0]) - 1
+	}
+	if *blockNrOrHash.BlockNumber < 0 {
+		return len(b.roots) - 1
+	}
+	return int(*blockNrOrHash.BlockNumber)
+}
+
+func (b *debugTestBackend) stateAndHeader(db state.Database, roots []common.Hash, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *evmcore.EvmHeader, error) {
+	n := b.number(blockNrOrHash)
+	root := roots[n]
+	if b.latestOnly {
+		root = roots[len(roots)-1]
+	}
+	statedb, err := state.New(root, db, nil)
+	return statedb, b.header(n), err
+}
+
+func (b *debugTestBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *evmcore.EvmHeader, error) {
+	return b.stateAndHeader(b.db, b.roots, blockNrOrHash)
+}
+
+func (b *debugTestBackend) SfcStateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *evmcore.EvmHeader, error) {
+	return b.stateAndHeader(b.sfcDB, b.sfcRoots, blockNrOrHash)
+}
+
+func (b *debugTestBackend) BlockByHash(ctx context.Context, hash common.Hash) (*evmcore.EvmBlock, error) {
+	return evmcore.NewEvmBlock(b.header(int(hash[0])-1), nil), nil
+}
+
+func TestStorageRangeAt(t *testing.T) {
+	require := require.New(t)
+
+	contract := common.Address{0xcc}
+	b := newDebugTestBackend(t, nil)
+	b.commitBlock(t, func(statedb *state.StateDB) {
+		statedb.SetNonce(contract, 1)
+		for i := byte(1); i <= 3; i++ {
+			statedb.SetState(contract, common.Hash{i}, common.Hash{i})
+		}
+	}, func(statedb *state.StateDB) {
+		statedb.SetNonce(contract, 1)
+		statedb.SetState(contract, common.Hash{4}, common.Hash{4})
+	})
+	b.commitBlock(t, nil, nil)
+	api := NewPrivateDebugAPI(b)
+	sfc := true
+
+	// the storage is paged by the hashes of the keys
+	blockHash := b.header(1).Hash
+	first, err := api.StorageRangeAt(context.Background(), blockHash, 0, contract, nil, 2, nil)
+	require.NoError(err)
+	require.Len(first.Storage, 2)
+	require.NotNil(first.NextKey)
+	rest, err := api.StorageRangeAt(context.Background(), blockHash, 0, contract, first.NextKey.Bytes(), 2, nil)
+	require.NoError(err)
+	require.Len(rest.Storage, 1)
+	require.Nil(rest.NextKey)
+	for hash, entry := range rest.Storage {
+		first.Storage[hash] = entry
+	}
+	for i := byte(1); i <= 3; i++ {
+		found := false
+		for _, entry := range first.Storage {
+			if entry.Key != nil && *entry.Key == (common.Hash{i}) {
+				require.Equal(common.Hash{i}, entry.Value)
+				found = true
+			}
+		}
+		require.True(found, i)
+	}
+
+	// the SFC storage is selected by the flag
+	res, err := api.StorageRangeAt(context.Background(), blockHash, 0, contract, nil, 10, &sfc)
+	require.NoError(err)
+	require.Len(res.Storage, 1)
+	for _, entry := range res.Storage {
+		require.Equal(common.Hash{4}, *entry.Key)
+		require.Equal(common.Hash{4}, entry.Value)
+	}
+
+	// the account which doesn't exist
+	_, err = api.StorageRangeAt(context.Background(), blockHash, 0, common.Address{0xdd}, nil, 10, nil)
+	require.Error(err)
+}
+
+func TestGetModifiedAccounts(t *testing.T) {
+	var (
+		a, c    = common.Address{0xa}, common.Address{0xc}
+		sfcAddr = common.Address{0xfc}
+		sfc     = true
+	)
+	build := func(config *trie.Config) *debugTestBackend {
+		b := newDebugTestBackend(t, config)
+		b.commitBlock(t, func(statedb *state.StateDB) {
+			statedb.SetNonce(a, 1)
+		}, func(statedb *state.StateDB) {
+			statedb.SetNonce(sfcAddr, 1)
+		})
+		b.commitBlock(t, func(statedb *state.StateDB) {
+			statedb.SetNonce(a, 2)
+			statedb.SetNonce(c, 1)
+		}, func(statedb *state.StateDB) {
+			statedb.SetState(sfcAddr, common.Hash{1}, common.Hash{1})
+		})
+		b.commitBlock(t, func(statedb *state.StateDB) {
+			statedb.SetNonce(c, 2)
+		}, nil)
+		return b
+	}
+
+	t.Run("hash scheme", func(t *testing.T) {
+		require := require.New(t)
+		api := NewPrivateDebugAPI(build(nil))
+
+		res, err := api.GetModifiedAccountsByNumber(context.Background(), 1, nil, nil)
+		require.NoError(err)
+		require.ElementsMatch([]common.Address{a, c}, res)
+
+		end := uint64(2)
+		res, err = api.GetModifiedAccountsByNumber(context.Background(), 0, &end, nil)
+		require.NoError(err)
+		require.ElementsMatch([]common.Address{a, c}, res)
+
+		res, err = api.GetModifiedAccountsByNumber(context.Background(), 2, nil, nil)
+		require.NoError(err)
+		require.Equal([]common.Address{c}, res)
+
+		// the SFC state is selected by the flag
+		res, err = api.GetModifiedAccountsByNumber(context.Background(), 1, nil, &sfc)
+		require.NoError(err)
+		require.Equal([]common.Address{sfcAddr}, res)
+		res, err = api.GetModifiedAccountsByNumber(context.Background(), 2, nil, &sfc)
+		require.NoError(err)
+		require.Empty(res)
+
+		res, err = api.GetModifiedAccountsByHash(context.Background(), common.Hash{3}, nil, nil)
+		require.NoError(err)
+		require.Equal([]common.Address{c}, res)
+
+		_, err = api.GetModifiedAccountsByNumber(context.Background(), 0, nil, nil)
+		require.Error(err)
+		_, err = api.GetModifiedAccountsByNumber(context.Background(), 2, &end, nil)
+		require.Error(err)
+	})
+
+	t.Run("path scheme", func(t *testing.T) {
+		require := require.New(t)
+		b := build(&trie.Config{Scheme: rawdb.PathScheme, Preimages: true})
+		b.latestOnly = true
+		api := NewPrivateDebugAPI(b)
+
+		_, err := api.GetModifiedAccountsByNumber(context.Background(), 2, nil, nil)
+		require.True(errors.Is(err, errModifiedAccountsUnavailable), err)
+		_, err = api.GetModifiedAccountsByNumber(context.Background(), 1, nil, &sfc)
+		require.True(errors.Is(err, errModifiedAccountsUnavailable), err)
+	})
+}
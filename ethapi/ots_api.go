@@ -0,0 +1,395 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/common/hexutil"
+	"github.com/unicornultrafoundation/go-u2u/common/math"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+	"github.com/unicornultrafoundation/go-u2u/evmcore"
+	"github.com/unicornultrafoundation/go-u2u/log"
+	"github.com/unicornultrafoundation/go-u2u/rpc"
+	"github.com/unicornultrafoundation/go-u2u/u2u"
+	"github.com/unicornultrafoundation/go-u2u/utils/signers/gsignercache"
+)
+
+// otsApiLevel is the version of the Otterscan API, which is implemented
+const otsApiLevel = 8
+
+// maxOtsPageSize limits the number of transactions returned on a single page
+const maxOtsPageSize = 1000
+
+// PublicOtterscanAPI provides the ots_ namespace used by the Otterscan block explorer.
+type PublicOtterscanAPI struct {
+	b     Backend
+	chain *PublicBlockChainAPI
+}
+
+// NewPublicOtterscanAPI creates a new Otterscan API.
+func NewPublicOtterscanAPI(b Backend) *PublicOtterscanAPI {
+	return &PublicOtterscanAPI{b: b, chain: NewPublicBlockChainAPI(b)}
+}
+
+// ContractCreatorData is the transaction which created a contract and its sender
+type ContractCreatorData struct {
+	Tx      common.Hash    `json:"hash"`
+	Creator common.Address `json:"creator"`
+}
+
+// TransactionsWithReceipts is a page of the transactions in which an address appears
+type TransactionsWithReceipts struct {
+	Txs       []*RPCTransaction        `json:"txs"`
+	Receipts  []map[string]interface{} `json:"receipts"`
+	FirstPage bool                     `json:"firstPage"`
+	LastPage  bool                     `json:"lastPage"`
+}
+
+// GetApiLevel returns the version of the implemented Otterscan API.
+func (api *PublicOtterscanAPI) GetApiLevel() uint8 {
+	return otsApiLevel
+}
+
+// HasCode returns true if the account has a code at the given block.
+func (api *PublicOtterscanAPI) HasCode(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (bool, error) {
+	state, _, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return false, err
+	}
+	return state.GetCodeSize(address) > 0, nil
+}
+
+// traceTx re-executes the transaction with the Otterscan tracer
+func (api *PublicOtterscanAPI) traceTx(ctx context.Context, hash common.Hash) (*otsTracer, *evmcore.ExecutionResult, error) {
+	tx, blockNumber, index, err := api.b.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tx == nil {
+		return nil, nil, fmt.Errorf("transaction %s not found", hash.String())
+	}
+	block, err := api.b.BlockByNumber(ctx, rpc.BlockNumber(blockNumber))
+	if err != nil {
+		return nil, nil, err
+	}
+	if block == nil {
+		return nil, nil, fmt.Errorf("block %d not found", blockNumber)
+	}
+	msg, blockCtx, statedb, sfcState, err := NewPublicDebugAPI(api.b).stateAtTransaction(ctx, block, int(index))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tracer := newOtsTracer()
+	cfg := u2u.DefaultVMConfig
+	cfg.Debug = true
+	cfg.Tracer = tracer
+	cfg.NoBaseFee = true
+
+	var timeout time.Duration = 5 * time.Second
+	if api.b.RPCTimeout() > 0 {
+		timeout = api.b.RPCTimeout()
+	}
+	var cancel context.CancelFunc
+	ctx, cancel = context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	vmenv := vm.NewEVM(blockCtx, evmcore.NewEVMTxContext(msg), statedb, sfcState, api.b.ChainConfig(), cfg)
+
+	// Wait for the context to be done and cancel the evm. Even if the
+	// EVM has finished, cancelling may be done (repeatedly)
+	go func() {
+		<-ctx.Done()
+		vmenv.Cancel()
+	}()
+
+	statedb.Prepare(hash, int(index))
+	result, err := evmcore.ApplyMessage(vmenv, msg, new(evmcore.GasPool).AddGas(math.MaxUint64))
+	if err != nil {
+		return nil, nil, fmt.Errorf("tracing failed: %w", err)
+	}
+	if vmenv.Cancelled() {
+		return nil, nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
+	}
+	return tracer, result, nil
+}
+
+// GetInternalOperations returns the value transfers, contract creations and
+// self-destructs performed by the contracts during the transaction execution.
+func (api *PublicOtterscanAPI) GetInternalOperations(ctx context.Context, hash common.Hash) ([]*InternalOperation, error) {
+	tracer, _, err := api.traceTx(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return tracer.operations, nil
+}
+
+// GetTransactionError returns the revert data of the transaction.
+func (api *PublicOtterscanAPI) GetTransactionError(ctx context.Context, hash common.Hash) (hexutil.Bytes, error) {
+	_, result, err := api.traceTx(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return result.Revert(), nil
+}
+
+// TraceTransaction returns the call frames of the transaction execution.
+func (api *PublicOtterscanAPI) TraceTransaction(ctx context.Context, hash common.Hash) ([]*TraceEntry, error) {
+	tracer, _, err := api.traceTx(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return tracer.entries, nil
+}
+
+// blockDetails returns the block header fields with the transactions count, the issuance and the paid fees
+func (api *PublicOtterscanAPI) blockDetails(ctx context.Context, block *evmcore.EvmBlock) (map[string]interface{}, error) {
+	if block == nil {
+		return nil, nil
+	}
+	number := rpc.BlockNumber(block.NumberU64())
+	fields, err := api.chain.rpcMarshalBlock(block, api.chain.calculateExtBlockApi(ctx, number), false, false)
+	if err != nil {
+		return nil, err
+	}
+	fields["transactionCount"] = hexutil.Uint64(len(block.Transactions))
+	fields["logsBloom"] = nil
+
+	receipts, err := api.b.GetReceiptsByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	totalFees := new(big.Int)
+	for i, receipt := range receipts {
+		if i >= len(block.Transactions) {
+			break
+		}
+		gasPrice := block.Transactions[i].GasPrice()
+		if block.BaseFee != nil {
+			gasPrice = new(big.Int).Add(block.BaseFee, block.Transactions[i].EffectiveGasTipValue(block.BaseFee))
+		}
+		totalFees.Add(totalFees, new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(receipt.GasUsed)))
+	}
+	// there are no block rewards, validators are rewarded by the SFC at the end of epoch
+	return map[string]interface{}{
+		"block": fields,
+		"issuance": map[string]interface{}{
+			"blockReward": (*hexutil.Big)(new(big.Int)),
+			"uncleReward": (*hexutil.Big)(new(big.Int)),
+			"issuance":    (*hexutil.Big)(new(big.Int)),
+		},
+		"totalFees": (*hexutil.Big)(totalFees),
+	}, nil
+}
+
+// GetBlockDetails returns the block header fields with the transactions count, the issuance and the paid fees.
+func (api *PublicOtterscanAPI) GetBlockDetails(ctx context.Context, number rpc.BlockNumber) (map[string]interface{}, error) {
+	block, err := api.b.BlockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return api.blockDetails(ctx, block)
+}
+
+// GetBlockDetailsByHash returns the block header fields with the transactions count, the issuance and the paid fees.
+func (api *PublicOtterscanAPI) GetBlockDetailsByHash(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
+	block, err := api.b.BlockByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return api.blockDetails(ctx, block)
+}
+
+// GetBlockTransactions returns a page of the block transactions with their receipts.
+// The pages are counted from the end of the block.
+func (api *PublicOtterscanAPI) GetBlockTransactions(ctx context.Context, number rpc.BlockNumber, pageNumber uint8, pageSize uint8) (map[string]interface{}, error) {
+	block, err := api.b.BlockByNumber(ctx, number)
+	if block == nil || err != nil {
+		return nil, err
+	}
+	receipts, err := api.b.GetReceiptsByNumber(ctx, rpc.BlockNumber(block.NumberU64()))
+	if err != nil {
+		return nil, err
+	}
+	if len(receipts) != len(block.Transactions) {
+		return nil, fmt.Errorf("block %d receipts not found", block.NumberU64())
+	}
+	fields, err := api.chain.rpcMarshalBlock(block, api.chain.calculateExtBlockApi(ctx, number), false, false)
+	if err != nil {
+		return nil, err
+	}
+	fields["transactionCount"] = hexutil.Uint64(len(block.Transactions))
+
+	pageEnd := len(block.Transactions) - int(pageNumber)*int(pageSize)
+	pageStart := pageEnd - int(pageSize)
+	if pageEnd < 0 {
+		pageEnd = 0
+	}
+	if pageStart < 0 {
+		pageStart = 0
+	}
+	signer := gsignercache.Wrap(types.MakeSigner(api.b.ChainConfig(), block.Number))
+	txs := make([]*RPCTransaction, 0, pageEnd-pageStart)
+	rpcReceipts := make([]map[string]interface{}, 0, pageEnd-pageStart)
+	for i := pageStart; i < pageEnd; i++ {
+		tx := newRPCTransactionFromBlockIndex(block, uint64(i))
+		// only the method selector is needed to display the transactions list
+		if len(tx.Input) > 4 {
+			tx.Input = tx.Input[:4]
+		}
+		txs = append(txs, tx)
+		receipt := marshalReceipt(receipts[i], signer, block.Transactions[i], uint64(i), block.BaseFee)
+		receipt["logs"] = nil
+		receipt["logsBloom"] = nil
+		rpcReceipts = append(rpcReceipts, receipt)
+	}
+	fields["transactions"] = txs
+	return map[string]interface{}{
+		"fullblock": fields,
+		"receipts":  rpcReceipts,
+	}, nil
+}
+
+// addressTxsPage collects the transactions with receipts in which the address appears. The collection
+// stops once the page is filled and the current block is complete. Returns true if there are more transactions.
+func (api *PublicOtterscanAPI) addressTxsPage(ctx context.Context, addr common.Address, from idx.Block, reverse bool,
+	include func(n idx.Block) bool, pageSize uint16) (*TransactionsWithReceipts, bool, error) {
+	if pageSize > maxOtsPageSize {
+		pageSize = maxOtsPageSize
+	}
+	var (
+		res = &TransactionsWithReceipts{
+			Txs:      make([]*RPCTransaction, 0),
+			Receipts: make([]map[string]interface{}, 0),
+		}
+		block    *evmcore.EvmBlock
+		receipts types.Receipts
+		signer   types.Signer
+		hasMore  bool
+		err      error
+	)
+	iterErr := api.b.AddressTxs(ctx, addr, from, reverse, func(tx AddressTx) bool {
+		if !include(tx.Block) {
+			return true
+		}
+		if block == nil || block.NumberU64() != uint64(tx.Block) {
+			if len(res.Txs) >= int(pageSize) {
+				hasMore = true
+				return false
+			}
+			block, err = api.b.BlockByNumber(ctx, rpc.BlockNumber(tx.Block))
+			if block == nil || err != nil {
+				if err == nil {
+					err = fmt.Errorf("block %d not found", tx.Block)
+				}
+				return false
+			}
+			receipts, err = api.b.GetReceiptsByNumber(ctx, rpc.BlockNumber(tx.Block))
+			if err != nil {
+				return false
+			}
+			signer = gsignercache.Wrap(types.MakeSigner(api.b.ChainConfig(), block.Number))
+		}
+		i := int(tx.BlockOffset)
+		if i >= len(block.Transactions) || i >= len(receipts) || block.Transactions[i].Hash() != tx.TxHash {
+			log.Warn("Address index points to a missing transaction", "block", tx.Block, "tx", tx.TxHash)
+			return true
+		}
+		receipt := marshalReceipt(receipts[i], signer, block.Transactions[i], uint64(i), block.BaseFee)
+		receipt["timestamp"] = hexutil.Uint64(block.Time.Unix())
+		res.Txs = append(res.Txs, newRPCTransactionFromBlockIndex(block, uint64(i)))
+		res.Receipts = append(res.Receipts, receipt)
+		return true
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if iterErr != nil {
+		return nil, false, iterErr
+	}
+	return res, hasMore, nil
+}
+
+// SearchTransactionsBefore returns a page of the transactions in which the address appears,
+// in the blocks before the given one, in descending order. Block 0 means the latest block.
+func (api *PublicOtterscanAPI) SearchTransactionsBefore(ctx context.Context, addr common.Address, blockNum uint64, pageSize uint16) (*TransactionsWithReceipts, error) {
+	isFirstPage := blockNum == 0
+	before := idx.Block(blockNum)
+	if latest := idx.Block(api.b.CurrentBlock().NumberU64()) + 1; isFirstPage || before > latest {
+		before = latest
+	}
+	res, hasMore, err := api.addressTxsPage(ctx, addr, before, true, func(idx.Block) bool { return true }, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	res.FirstPage = isFirstPage
+	res.LastPage = !hasMore
+	return res, nil
+}
+
+// SearchTransactionsAfter returns a page of the transactions in which the address appears,
+// in the blocks after the given one, in descending order. Block 0 means the earliest block.
+func (api *PublicOtterscanAPI) SearchTransactionsAfter(ctx context.Context, addr common.Address, blockNum uint64, pageSize uint16) (*TransactionsWithReceipts, error) {
+	isLastPage := blockNum == 0
+	after := idx.Block(blockNum)
+	res, hasMore, err := api.addressTxsPage(ctx, addr, after, false, func(n idx.Block) bool {
+		return isLastPage || n > after
+	}, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	// the pages are always returned in descending order
+	for i, j := 0, len(res.Txs)-1; i < j; i, j = i+1, j-1 {
+		res.Txs[i], res.Txs[j] = res.Txs[j], res.Txs[i]
+		res.Receipts[i], res.Receipts[j] = res.Receipts[j], res.Receipts[i]
+	}
+	res.FirstPage = !hasMore
+	res.LastPage = isLastPage
+	return res, nil
+}
+
+// GetTransactionBySenderAndNonce returns the hash of the transaction with the sender and nonce.
+func (api *PublicOtterscanAPI) GetTransactionBySenderAndNonce(ctx context.Context, addr common.Address, nonce uint64) (*common.Hash, error) {
+	return api.b.TxBySenderAndNonce(ctx, addr, nonce)
+}
+
+// GetContractCreator returns the transaction which created the contract and its sender.
+// The contracts created by other contracts are found only if the address index
+// or the transaction traces are stored.
+func (api *PublicOtterscanAPI) GetContractCreator(ctx context.Context, addr common.Address) (*ContractCreatorData, error) {
+	creator, err := api.b.ContractCreator(ctx, addr)
+	if err != nil && (!errors.Is(err, ErrAddressIndexDisabled) || !api.b.TxTraceIndexed(ctx)) {
+		return nil, err
+	}
+	if creator != nil {
+		return &ContractCreatorData{Tx: creator.TxHash, Creator: creator.Creator}, nil
+	}
+	if !api.b.TxTraceIndexed(ctx) {
+		return nil, nil
+	}
+	var ref *TraceRef
+	err = api.b.TxTraceRefs(ctx, TraceRoleCreated, addr, 0, math.MaxUint64, func(r TraceRef) bool {
+		ref = &r
+		return false
+	})
+	if err != nil || ref == nil {
+		return nil, err
+	}
+	traces, err := api.b.TxTraceByHash(ctx, ref.TxHash)
+	if err != nil {
+		return nil, err
+	}
+	for _, trace := range *traces {
+		if trace.Result != nil && trace.Result.Address != nil && *trace.Result.Address == addr && trace.Action.From != nil {
+			return &ContractCreatorData{Tx: ref.TxHash, Creator: *trace.Action.From}, nil
+		}
+	}
+	return nil, errors.New("contract creation trace not found")
+}
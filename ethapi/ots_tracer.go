@@ -0,0 +1,121 @@
+package ethapi
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/common/hexutil"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+)
+
+// Types of the internal operations, as defined by Otterscan
+const (
+	OpTransfer     = 0
+	OpSelfDestruct = 1
+	OpCreate       = 2
+	OpCreate2      = 3
+)
+
+// InternalOperation is a value transfer, a contract creation or a self-destruct
+// performed by a contract during the transaction execution
+type InternalOperation struct {
+	Type  int            `json:"type"`
+	From  common.Address `json:"from"`
+	To    common.Address `json:"to"`
+	Value *hexutil.Big   `json:"value"`
+}
+
+// TraceEntry is a call frame of the transaction execution
+type TraceEntry struct {
+	Type   string         `json:"type"`
+	Depth  int            `json:"depth"`
+	From   common.Address `json:"from"`
+	To     common.Address `json:"to"`
+	Value  *hexutil.Big   `json:"value"`
+	Input  hexutil.Bytes  `json:"input"`
+	Output hexutil.Bytes  `json:"output"`
+}
+
+// otsTracer collects the call frames and the internal operations of a transaction
+type otsTracer struct {
+	entries    []*TraceEntry
+	operations []*InternalOperation
+	stack      []*TraceEntry
+}
+
+func newOtsTracer() *otsTracer {
+	return &otsTracer{
+		entries:    make([]*TraceEntry, 0),
+		operations: make([]*InternalOperation, 0),
+	}
+}
+
+func (t *otsTracer) push(typ vm.OpCode, from, to common.Address, input []byte, value *big.Int) {
+	entry := &TraceEntry{
+		Type:  typ.String(),
+		Depth: len(t.stack),
+		From:  from,
+		To:    to,
+		Input: common.CopyBytes(input),
+	}
+	if value != nil {
+		entry.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+	t.entries = append(t.entries, entry)
+	t.stack = append(t.stack, entry)
+}
+
+func (t *otsTracer) pop(output []byte) {
+	if len(t.stack) == 0 {
+		return
+	}
+	t.stack[len(t.stack)-1].Output = common.CopyBytes(output)
+	t.stack = t.stack[:len(t.stack)-1]
+}
+
+func (t *otsTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	typ := vm.CALL
+	if create {
+		typ = vm.CREATE
+	}
+	t.push(typ, from, to, input, value)
+}
+
+func (t *otsTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+func (t *otsTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	var opType = -1
+	switch typ {
+	case vm.CALL, vm.CALLCODE:
+		if value != nil && value.Sign() > 0 {
+			opType = OpTransfer
+		}
+	case vm.CREATE:
+		opType = OpCreate
+	case vm.CREATE2:
+		opType = OpCreate2
+	case vm.SELFDESTRUCT:
+		opType = OpSelfDestruct
+	}
+	if opType >= 0 {
+		op := &InternalOperation{Type: opType, From: from, To: to, Value: (*hexutil.Big)(new(big.Int))}
+		if value != nil {
+			op.Value = (*hexutil.Big)(new(big.Int).Set(value))
+		}
+		t.operations = append(t.operations, op)
+	}
+	t.push(typ, from, to, input, value)
+}
+
+func (t *otsTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	t.pop(output)
+}
+
+func (t *otsTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+func (t *otsTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) {
+	t.pop(output)
+}
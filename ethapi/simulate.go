@@ -0,0 +1,637 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/common/hexutil"
+	"github.com/unicornultrafoundation/go-u2u/core/state"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+	"github.com/unicornultrafoundation/go-u2u/crypto"
+	"github.com/unicornultrafoundation/go-u2u/evmcore"
+	"github.com/unicornultrafoundation/go-u2u/native"
+	"github.com/unicornultrafoundation/go-u2u/rpc"
+	"github.com/unicornultrafoundation/go-u2u/trie"
+	"github.com/unicornultrafoundation/go-u2u/u2u"
+)
+
+const (
+	// maxSimulateBlocks is the maximum number of blocks that can be simulated
+	// in a single request, including the gaps filled with empty blocks.
+	maxSimulateBlocks = 256
+
+	// timestampIncrement is the default increment between the simulated blocks.
+	timestampIncrement = 1
+)
+
+// JSON-RPC error codes of eth_simulateV1, as specified by the execution APIs.
+const (
+	errCodeNonceTooLow           = -38010
+	errCodeNonceTooHigh          = -38011
+	errCodeFeeCapTooLow          = -38012
+	errCodeIntrinsicGas          = -38013
+	errCodeInsufficientFunds     = -38014
+	errCodeBlockGasLimitReached  = -38015
+	errCodeBlockNumberInvalid    = -38020
+	errCodeBlockTimestampInvalid = -38021
+	errCodeSenderIsNotEOA        = -38024
+	errCodeMaxInitCodeSizeExceed = -38025
+	errCodeClientLimitExceeded   = -38026
+	errCodeInternalError         = -32603
+	errCodeInvalidParams         = -32602
+	errCodeReverted              = 3
+	errCodeVMError               = -32015
+)
+
+// transferLogAddress is the pseudo-address of the ERC-7528 native token
+// transfer logs.
+var transferLogAddress = common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+
+// transferTopic is the topic of the ERC-20 Transfer(address,address,uint256) event.
+var transferTopic = common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+
+// simulateError is an API error of eth_simulateV1 with a JSON error code.
+type simulateError struct {
+	code int
+	msg  string
+}
+
+func (e *simulateError) Error() string { return e.msg }
+
+// ErrorCode returns the JSON error code of the simulation error.
+func (e *simulateError) ErrorCode() int { return e.code }
+
+// txValidationError converts an error of the transaction pre-check into an API error.
+func txValidationError(err error) *simulateError {
+	code := errCodeInternalError
+	switch {
+	case errors.Is(err, evmcore.ErrNonceTooLow):
+		code = errCodeNonceTooLow
+	case errors.Is(err, evmcore.ErrNonceTooHigh):
+		code = errCodeNonceTooHigh
+	case errors.Is(err, evmcore.ErrFeeCapTooLow):
+		code = errCodeFeeCapTooLow
+	case errors.Is(err, evmcore.ErrIntrinsicGas):
+		code = errCodeIntrinsicGas
+	case errors.Is(err, evmcore.ErrInsufficientFunds), errors.Is(err, evmcore.ErrInsufficientFundsForTransfer):
+		code = errCodeInsufficientFunds
+	case errors.Is(err, evmcore.ErrGasLimitReached):
+		code = errCodeBlockGasLimitReached
+	case errors.Is(err, evmcore.ErrSenderNoEOA):
+		code = errCodeSenderIsNotEOA
+	case errors.Is(err, evmcore.ErrMaxInitCodeSizeExceeded):
+		code = errCodeMaxInitCodeSizeExceed
+	}
+	return &simulateError{code: code, msg: err.Error()}
+}
+
+// SimOpts are the inputs of eth_simulateV1.
+type SimOpts struct {
+	BlockStateCalls        []SimBlock `json:"blockStateCalls"`
+	TraceTransfers         bool       `json:"traceTransfers"`
+	Validation             bool       `json:"validation"`
+	ReturnFullTransactions bool       `json:"returnFullTransactions"`
+}
+
+// SimBlock is a batch of calls to be simulated sequentially within a block.
+type SimBlock struct {
+	BlockOverrides *BlockOverrides   `json:"blockOverrides"`
+	StateOverrides *StateOverride    `json:"stateOverrides"`
+	Calls          []TransactionArgs `json:"calls"`
+}
+
+// simCallResult is the result of a simulated call.
+type simCallResult struct {
+	ReturnValue hexutil.Bytes  `json:"returnData"`
+	Logs        []*types.Log   `json:"logs"`
+	GasUsed     hexutil.Uint64 `json:"gasUsed"`
+	Status      hexutil.Uint64 `json:"status"`
+	Error       *callError     `json:"error,omitempty"`
+}
+
+// callError is the error of a failed simulated call.
+type callError struct {
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+	Data    string `json:"data,omitempty"`
+}
+
+// simulator executes the simulated blocks on top of a base block.
+type simulator struct {
+	b              Backend
+	state          *state.StateDB
+	sfcState       *state.StateDB
+	base           *evmcore.EvmHeader
+	hashes         map[uint64]common.Hash
+	gasRemaining   uint64
+	traceTransfers bool
+	validate       bool
+	fullTx         bool
+}
+
+// SimulateV1 executes a series of blocks with calls on top of the given block,
+// carrying the EVM and SFC states forward between the calls. Every block may override
+// the header fields and the state before its calls are executed.
+func (s *PublicBlockChainAPI) SimulateV1(ctx context.Context, opts SimOpts, blockNrOrHash *rpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
+	if len(opts.BlockStateCalls) == 0 {
+		return nil, &simulateError{code: errCodeInvalidParams, msg: "empty input"}
+	} else if len(opts.BlockStateCalls) > maxSimulateBlocks {
+		return nil, &simulateError{code: errCodeClientLimitExceeded, msg: "too many blocks"}
+	}
+	if blockNrOrHash == nil {
+		n := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		blockNrOrHash = &n
+	}
+	statedb, base, err := s.b.StateAndHeaderByNumberOrHash(ctx, *blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	sfcState, _, _ := s.b.SfcStateAndHeaderByNumberOrHash(ctx, *blockNrOrHash)
+
+	// Setup context so it may be cancelled after the timeout
+	var cancel context.CancelFunc
+	if timeout := s.b.RPCTimeout(); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	// Zero gas cap means no limit
+	gasCap := s.b.RPCGasCap()
+	if gasCap == 0 {
+		gasCap = math.MaxUint64
+	}
+	sim := &simulator{
+		b:              s.b,
+		state:          statedb,
+		sfcState:       sfcState,
+		base:           base,
+		hashes:         make(map[uint64]common.Hash),
+		gasRemaining:   gasCap,
+		traceTransfers: opts.TraceTransfers,
+		validate:       opts.Validation,
+		fullTx:         opts.ReturnFullTransactions,
+	}
+	return sim.execute(ctx, opts.BlockStateCalls)
+}
+
+// execute runs the simulated blocks and returns their RPC representation
+func (sim *simulator) execute(ctx context.Context, blocks []SimBlock) ([]map[string]interface{}, error) {
+	blocks, err := sim.sanitizeChain(blocks)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		results = make([]map[string]interface{}, len(blocks))
+		parent  = sim.base
+	)
+	for i, block := range blocks {
+		result, header, err := sim.processBlock(ctx, &block, parent)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = result
+		parent = header
+	}
+	return results, nil
+}
+
+// sanitizeChain checks the numbers and timestamps of the simulated blocks,
+// fills in the missing ones and adds empty blocks into the gaps
+func (sim *simulator) sanitizeChain(blocks []SimBlock) ([]SimBlock, error) {
+	var (
+		res           = make([]SimBlock, 0, len(blocks))
+		prevNumber    = new(big.Int).Set(sim.base.Number)
+		prevTimestamp = uint64(sim.base.Time.Unix())
+	)
+	for _, block := range blocks {
+		if block.BlockOverrides == nil {
+			block.BlockOverrides = new(BlockOverrides)
+		}
+		if block.BlockOverrides.Number == nil {
+			n := new(big.Int).Add(prevNumber, common.Big1)
+			block.BlockOverrides.Number = (*hexutil.Big)(n)
+		}
+		diff := new(big.Int).Sub(block.BlockOverrides.Number.ToInt(), prevNumber)
+		if diff.Sign() <= 0 {
+			return nil, &simulateError{code: errCodeBlockNumberInvalid,
+				msg: fmt.Sprintf("block numbers must be in order: %d <= %d", block.BlockOverrides.Number.ToInt().Uint64(), prevNumber)}
+		}
+		if total := new(big.Int).Sub(block.BlockOverrides.Number.ToInt(), sim.base.Number); total.Cmp(big.NewInt(maxSimulateBlocks)) > 0 {
+			return nil, &simulateError{code: errCodeClientLimitExceeded, msg: "too many blocks"}
+		}
+		// Fill the gap with empty blocks
+		for gap := diff.Uint64(); gap > 1; gap-- {
+			n := new(big.Int).Add(prevNumber, common.Big1)
+			t := prevTimestamp + timestampIncrement
+			res = append(res, SimBlock{BlockOverrides: &BlockOverrides{Number: (*hexutil.Big)(n), Time: (*hexutil.Uint64)(&t)}})
+			prevNumber, prevTimestamp = n, t
+		}
+		prevNumber = block.BlockOverrides.Number.ToInt()
+		if block.BlockOverrides.Time == nil {
+			t := prevTimestamp + timestampIncrement
+			block.BlockOverrides.Time = (*hexutil.Uint64)(&t)
+		} else if t := uint64(*block.BlockOverrides.Time); t <= prevTimestamp {
+			return nil, &simulateError{code: errCodeBlockTimestampInvalid,
+				msg: fmt.Sprintf("block timestamps must be in order: %d <= %d", t, prevTimestamp)}
+		}
+		prevTimestamp = uint64(*block.BlockOverrides.Time)
+		res = append(res, block)
+	}
+	return res, nil
+}
+
+// makeHeader derives the header of a simulated block from its parent and the overrides
+func (sim *simulator) makeHeader(overrides *BlockOverrides, parent *evmcore.EvmHeader) *evmcore.EvmHeader {
+	header := &evmcore.EvmHeader{
+		Number:     overrides.Number.ToInt(),
+		ParentHash: parent.Hash,
+		Time:       native.FromUnix(int64(*overrides.Time)),
+		Coinbase:   parent.Coinbase,
+		GasLimit:   parent.GasLimit,
+	}
+	// The base fee is applied only in the validation mode, unless it's overridden
+	if sim.validate && parent.BaseFee != nil {
+		header.BaseFee = new(big.Int).Set(parent.BaseFee)
+	} else if parent.BaseFee != nil {
+		header.BaseFee = new(big.Int)
+	}
+	if overrides.Coinbase != nil {
+		header.Coinbase = *overrides.Coinbase
+	}
+	if overrides.GasLimit != nil {
+		header.GasLimit = uint64(*overrides.GasLimit)
+	}
+	if overrides.BaseFee != nil {
+		header.BaseFee = overrides.BaseFee.ToInt()
+	}
+	return header
+}
+
+// blockContext returns the EVM context of a simulated block, resolving the hashes
+// of the previously simulated blocks
+func (sim *simulator) blockContext(header *evmcore.EvmHeader, overrides *BlockOverrides) vm.BlockContext {
+	blockCtx := sim.b.GetBlockContext(sim.base)
+	getHash := blockCtx.GetHash
+	baseNumber := sim.base.Number.Uint64()
+	blockCtx.GetHash = func(n uint64) common.Hash {
+		if n >= header.Number.Uint64() {
+			return common.Hash{}
+		}
+		if n == baseNumber {
+			return sim.base.Hash
+		}
+		if h, ok := sim.hashes[n]; ok {
+			return h
+		}
+		if n < baseNumber {
+			return getHash(n)
+		}
+		return common.Hash{}
+	}
+	blockCtx.BlockNumber = new(big.Int).Set(header.Number)
+	blockCtx.Time = new(big.Int).SetUint64(uint64(header.Time.Unix()))
+	blockCtx.Coinbase = header.Coinbase
+	blockCtx.GasLimit = header.GasLimit
+	blockCtx.BaseFee = header.BaseFee
+	overrides.Apply(&blockCtx)
+	return blockCtx
+}
+
+// processBlock executes the calls of a simulated block on top of the current states
+func (sim *simulator) processBlock(ctx context.Context, block *SimBlock, parent *evmcore.EvmHeader) (map[string]interface{}, *evmcore.EvmHeader, error) {
+	if err := block.StateOverrides.Apply(sim.state); err != nil {
+		return nil, nil, err
+	}
+	if sim.sfcState != nil {
+		if err := block.StateOverrides.Apply(sim.sfcState); err != nil {
+			return nil, nil, err
+		}
+	}
+	header := sim.makeHeader(block.BlockOverrides, parent)
+	blockCtx := sim.blockContext(header, block.BlockOverrides)
+
+	var tracer *transferTracer
+	vmConfig := u2u.DefaultVMConfig
+	vmConfig.NoBaseFee = !sim.validate
+	if sim.traceTransfers {
+		tracer = newTransferTracer()
+		vmConfig.Debug = true
+		vmConfig.Tracer = tracer
+	}
+	evm := vm.NewEVM(blockCtx, vm.TxContext{}, sim.state, sim.sfcState, sim.b.ChainConfig(), vmConfig)
+
+	// Wait for the context to be done and cancel the evm. Even if the
+	// EVM has finished, cancelling may be done (repeatedly)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			evm.Cancel()
+		case <-done:
+		}
+	}()
+
+	var (
+		gp       = new(evmcore.GasPool).AddGas(header.GasLimit)
+		txs      = make(types.Transactions, len(block.Calls))
+		senders  = make([]common.Address, len(block.Calls))
+		receipts = make(types.Receipts, len(block.Calls))
+		calls    = make([]simCallResult, len(block.Calls))
+		allLogs  = make([]*types.Log, 0)
+		usedGas  uint64
+	)
+	for i := range block.Calls {
+		args := &block.Calls[i]
+		if err := sim.callDefaults(args, header, gp.Gas()); err != nil {
+			return nil, nil, err
+		}
+		tx := args.ToTransaction()
+		msg, err := sim.message(args, header)
+		if err != nil {
+			return nil, nil, err
+		}
+		txs[i], senders[i] = tx, msg.From()
+
+		sim.state.Prepare(tx.Hash(), i)
+		if tracer != nil {
+			tracer.reset(tx.Hash(), uint(i))
+		}
+		evm.Reset(evmcore.NewEVMTxContext(msg), sim.state, sim.sfcState)
+		result, err := evmcore.ApplyMessage(evm, msg, gp)
+		if evm.Cancelled() {
+			return nil, nil, fmt.Errorf("execution aborted (timeout = %v)", sim.b.RPCTimeout())
+		}
+		if err != nil {
+			return nil, nil, txValidationError(fmt.Errorf("err: %w (supplied gas %d)", err, msg.Gas()))
+		}
+		if sim.sfcState != nil {
+			sim.sfcState.Finalise(true)
+		}
+		sim.state.Finalise(true)
+		usedGas += result.UsedGas
+		if result.UsedGas > sim.gasRemaining {
+			return nil, nil, &simulateError{code: errCodeClientLimitExceeded, msg: "RPC gas cap exhausted"}
+		}
+		sim.gasRemaining -= result.UsedGas
+
+		var logs []*types.Log
+		if tracer != nil {
+			logs = tracer.logs()
+		} else {
+			logs = sim.state.GetLogs(tx.Hash(), common.Hash{})
+		}
+		for _, l := range logs {
+			l.BlockNumber = header.Number.Uint64()
+			l.Index = uint(len(allLogs))
+			allLogs = append(allLogs, l)
+		}
+
+		receipt := &types.Receipt{
+			Type:              tx.Type(),
+			CumulativeGasUsed: usedGas,
+			TxHash:            tx.Hash(),
+			GasUsed:           result.UsedGas,
+			Logs:              logs,
+			BlockNumber:       header.Number,
+			TransactionIndex:  uint(i),
+		}
+		if msg.To() == nil {
+			receipt.ContractAddress = crypto.CreateAddress(msg.From(), tx.Nonce())
+		}
+		call := simCallResult{
+			ReturnValue: result.Return(),
+			Logs:        logs,
+			GasUsed:     hexutil.Uint64(result.UsedGas),
+		}
+		if result.Failed() {
+			receipt.Status = types.ReceiptStatusFailed
+			call.Status = hexutil.Uint64(types.ReceiptStatusFailed)
+			if len(result.Revert()) > 0 {
+				revertErr := newRevertError(result.Revert())
+				call.Error = &callError{Message: revertErr.Error(), Code: errCodeReverted, Data: revertErr.reason}
+			} else {
+				call.Error = &callError{Message: result.Err.Error(), Code: errCodeVMError}
+			}
+		} else {
+			receipt.Status = types.ReceiptStatusSuccessful
+			call.Status = hexutil.Uint64(types.ReceiptStatusSuccessful)
+		}
+		if call.Logs == nil {
+			call.Logs = []*types.Log{}
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+		receipts[i] = receipt
+		calls[i] = call
+	}
+
+	header.GasUsed = usedGas
+	header.Root = sim.state.IntermediateRoot(true)
+	if sim.sfcState != nil {
+		header.SfcStateRoot = sim.sfcState.IntermediateRoot(true)
+	}
+	evmBlock := evmcore.NewEvmBlock(header, txs)
+	ext := extBlockApi{
+		receiptsRoot: types.DeriveSha(receipts, trie.NewStackTrie(nil)),
+		bloom:        types.CreateBloom(receipts),
+	}
+	evmBlock.Hash = crypto.Keccak256Hash(header.ParentHash.Bytes(), header.Number.Bytes(),
+		header.Root.Bytes(), evmBlock.TxHash.Bytes(), ext.receiptsRoot.Bytes())
+	sim.hashes[header.Number.Uint64()] = evmBlock.Hash
+
+	// Assign the block hash once it's known
+	for _, l := range allLogs {
+		l.BlockHash = evmBlock.Hash
+	}
+	for _, receipt := range receipts {
+		receipt.BlockHash = evmBlock.Hash
+	}
+	return sim.marshalBlock(evmBlock, ext, senders, receipts, calls), evmBlock.Header(), nil
+}
+
+// callDefaults fills in the nonce and the gas limit of a simulated call,
+// so that the call fits into the remaining block gas and the global gas cap
+func (sim *simulator) callDefaults(args *TransactionArgs, header *evmcore.EvmHeader, blockGasRemaining uint64) error {
+	if args.Nonce == nil {
+		nonce := hexutil.Uint64(sim.state.GetNonce(args.from()))
+		args.Nonce = &nonce
+	}
+	gasCap := blockGasRemaining
+	if sim.gasRemaining < gasCap {
+		gasCap = sim.gasRemaining
+	}
+	if args.Gas == nil {
+		gas := hexutil.Uint64(gasCap)
+		args.Gas = &gas
+	} else if uint64(*args.Gas) > blockGasRemaining {
+		return &simulateError{code: errCodeBlockGasLimitReached, msg: fmt.Sprintf("block gas limit reached: %d >= %d", uint64(*args.Gas), blockGasRemaining)}
+	}
+	if err := args.CallDefaults(gasCap, header.BaseFee, sim.b.ChainConfig().ChainID); err != nil {
+		return &simulateError{code: errCodeInvalidParams, msg: err.Error()}
+	}
+	return nil
+}
+
+// message converts the call arguments into a message. In the validation mode
+// the message is checked as a real transaction, except for the signature
+func (sim *simulator) message(args *TransactionArgs, header *evmcore.EvmHeader) (types.Message, error) {
+	msg, err := args.ToMessage(0, header.BaseFee)
+	if err != nil {
+		return msg, err
+	}
+	if !sim.validate {
+		return msg, nil
+	}
+	if header.BaseFee != nil && msg.GasFeeCap().Cmp(header.BaseFee) < 0 {
+		return msg, txValidationError(fmt.Errorf("%w: address %v, maxFeePerGas: %s baseFee: %s",
+			evmcore.ErrFeeCapTooLow, msg.From(), msg.GasFeeCap(), header.BaseFee))
+	}
+	return types.NewMessage(msg.From(), msg.To(), uint64(*args.Nonce), msg.Value(), msg.Gas(), msg.GasPrice(),
+		msg.GasFeeCap(), msg.GasTipCap(), msg.Data(), msg.AccessList(), false), nil
+}
+
+// marshalBlock converts a simulated block into the RPC representation, including the
+// results of the calls and the receipts
+func (sim *simulator) marshalBlock(block *evmcore.EvmBlock, ext extBlockApi, senders []common.Address,
+	receipts types.Receipts, calls []simCallResult) map[string]interface{} {
+	fields := RPCMarshalHeader(block.Header(), ext)
+	fields["size"] = hexutil.Uint64(block.EthBlock().Size())
+	fields["uncles"] = []common.Hash{}
+
+	// The simulated transactions aren't signed, so the senders are assigned explicitly
+	transactions := make([]interface{}, len(block.Transactions))
+	rpcReceipts := make([]map[string]interface{}, len(block.Transactions))
+	signer := types.LatestSignerForChainID(sim.b.ChainConfig().ChainID)
+	for i, tx := range block.Transactions {
+		if sim.fullTx {
+			rpcTx := newRPCTransaction(tx, block.Hash, block.NumberU64(), uint64(i), block.BaseFee)
+			rpcTx.From = senders[i]
+			transactions[i] = rpcTx
+		} else {
+			transactions[i] = tx.Hash()
+		}
+		rpcReceipts[i] = marshalReceipt(receipts[i], signer, tx, uint64(i), block.BaseFee)
+		rpcReceipts[i]["from"] = senders[i]
+	}
+	fields["transactions"] = transactions
+	fields["receipts"] = rpcReceipts
+	fields["calls"] = calls
+	return fields
+}
+
+// transferTracer collects the logs of a simulated call, including the ERC-7528
+// logs of the native token transfers. The logs of the reverted frames are dropped.
+type transferTracer struct {
+	frames  [][]*types.Log
+	txHash  common.Hash
+	txIndex uint
+}
+
+func newTransferTracer() *transferTracer {
+	return &transferTracer{}
+}
+
+// reset prepares the tracer for the next call
+func (t *transferTracer) reset(txHash common.Hash, txIndex uint) {
+	t.frames = make([][]*types.Log, 0, 1)
+	t.txHash = txHash
+	t.txIndex = txIndex
+}
+
+// logs returns the logs of the last call
+func (t *transferTracer) logs() []*types.Log {
+	if len(t.frames) == 0 {
+		return nil
+	}
+	return t.frames[0]
+}
+
+func (t *transferTracer) addLog(address common.Address, topics []common.Hash, data []byte) {
+	if len(t.frames) == 0 {
+		return
+	}
+	last := len(t.frames) - 1
+	t.frames[last] = append(t.frames[last], &types.Log{
+		Address: address,
+		Topics:  topics,
+		Data:    data,
+		TxHash:  t.txHash,
+		TxIndex: t.txIndex,
+	})
+}
+
+func (t *transferTracer) addTransfer(from, to common.Address, value *big.Int) {
+	if value == nil || value.Sign() <= 0 {
+		return
+	}
+	topics := []common.Hash{transferTopic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())}
+	t.addLog(transferLogAddress, topics, common.BigToHash(value).Bytes())
+}
+
+func (t *transferTracer) enter() {
+	t.frames = append(t.frames, make([]*types.Log, 0))
+}
+
+// exit merges the logs of the frame into the parent frame, unless the frame failed
+func (t *transferTracer) exit(failed bool) {
+	if len(t.frames) <= 1 {
+		if failed && len(t.frames) == 1 {
+			t.frames[0] = nil
+		}
+		return
+	}
+	last := len(t.frames) - 1
+	if !failed {
+		t.frames[last-1] = append(t.frames[last-1], t.frames[last]...)
+	}
+	t.frames = t.frames[:last]
+}
+
+func (t *transferTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.enter()
+	t.addTransfer(from, to, value)
+}
+
+func (t *transferTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if err != nil || op < vm.LOG0 || op > vm.LOG4 {
+		return
+	}
+	size := int(op - vm.LOG0)
+	stack := scope.Stack
+	if len(stack.Data()) < 2+size {
+		return
+	}
+	offset, length := stack.Back(0), stack.Back(1)
+	topics := make([]common.Hash, size)
+	for i := 0; i < size; i++ {
+		topics[i] = common.Hash(stack.Back(2 + i).Bytes32())
+	}
+	data := scope.Memory.GetCopy(int64(offset.Uint64()), int64(length.Uint64()))
+	t.addLog(scope.Contract.Address(), topics, data)
+}
+
+func (t *transferTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	t.enter()
+	if typ != vm.DELEGATECALL {
+		t.addTransfer(from, to, value)
+	}
+}
+
+func (t *transferTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	t.exit(err != nil)
+}
+
+func (t *transferTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+func (t *transferTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) {
+	t.exit(err != nil)
+}
@@ -0,0 +1,332 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/common/hexutil"
+	"github.com/unicornultrafoundation/go-u2u/params"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/sfc"
+)
+
+func TestSimulateV1GapBlocks(t *testing.T) {
+	require := require.New(t)
+
+	b := newTestBackend(t)
+	api := NewPublicBlockChainAPI(b)
+
+	to := common.Address{2}
+	last := hexutil.Big(*big.NewInt(14))
+	res, err := api.SimulateV1(context.Background(), SimOpts{BlockStateCalls: []SimBlock{
+		{Calls: []TransactionArgs{{From: &common.Address{1}, To: &to}}},
+		{BlockOverrides: &BlockOverrides{Number: &last}, Calls: []TransactionArgs{{From: &common.Address{1}, To: &to}}},
+	}}, nil)
+	require.NoError(err)
+
+	// the blocks 12 and 13 are filled in as empty ones
+	require.Len(res, 4)
+	parent := b.header.Hash
+	for i, block := range res {
+		require.Equal(big.NewInt(int64(11+i)), block["number"].(*hexutil.Big).ToInt())
+		require.Equal(hexutil.Uint64(1001+i), block["timestamp"])
+		require.Equal(parent, block["parentHash"])
+		parent = block["hash"].(common.Hash)
+	}
+	require.Len(res[0]["calls"], 1)
+	require.Empty(res[1]["calls"])
+	require.Empty(res[2]["calls"])
+	require.Len(res[3]["calls"], 1)
+
+	// the block numbers must grow
+	_, err = api.SimulateV1(context.Background(), SimOpts{BlockStateCalls: []SimBlock{
+		{BlockOverrides: &BlockOverrides{Number: &last}},
+		{BlockOverrides: &BlockOverrides{Number: &last}},
+	}}, nil)
+	require.Error(err)
+	require.Equal(errCodeBlockNumberInvalid, err.(*simulateError).ErrorCode())
+}
+
+func TestSimulateV1SfcStateOverrides(t *testing.T) {
+	require := require.New(t)
+
+	b := newTestBackend(t)
+	b.statedb.SetCode(sfc.ContractAddress, []byte{1})
+	b.sfcState.SetCode(sfc.ContractAddress, []byte{1})
+	api := NewPublicBlockChainAPI(b)
+
+	slot, value := common.Hash{1}, common.Hash{2}
+	diff := map[common.Hash]common.Hash{slot: value}
+	overrides := StateOverride{sfc.ContractAddress: OverrideAccount{StateDiff: &diff}}
+	_, err := api.SimulateV1(context.Background(), SimOpts{BlockStateCalls: []SimBlock{
+		{StateOverrides: &overrides},
+	}}, nil)
+	require.NoError(err)
+
+	require.Equal(value, b.statedb.GetState(sfc.ContractAddress, slot))
+	require.Equal(value, b.sfcState.GetState(sfc.ContractAddress, slot))
+}
+
+func TestSimulateV1TraceTransfers(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		from     = common.Address{1}
+		to       = common.Address{2}
+		balance  = (*hexutil.Big)(big.NewInt(params.Ether))
+		value    = (*hexutil.Big)(big.NewInt(1000))
+		contract = common.Address{0xcc}
+		code     = hexutil.Bytes(storageCode)
+		input    = hexutil.Bytes(common.Hash{31: 1}.Bytes())
+		reverter = common.Address{0xdd}
+		revert   = hexutil.Bytes(common.FromHex("0x60006000fd"))
+	)
+	simulate := func(traceTransfers bool) []simCallResult {
+		overrides := StateOverride{
+			from:     OverrideAccount{Balance: &balance},
+			contract: OverrideAccount{Code: &code},
+			reverter: OverrideAccount{Code: &revert},
+		}
+		opts := SimOpts{
+			BlockStateCalls: []SimBlock{{
+				StateOverrides: &overrides,
+				Calls: []TransactionArgs{
+					{From: &from, To: &to, Value: value},
+					{From: &from, To: &contract, Data: &input},
+					{From: &from, To: &reverter, Value: value},
+				},
+			}},
+			TraceTransfers: traceTransfers,
+		}
+		res, err := NewPublicBlockChainAPI(newTestBackend(t)).SimulateV1(context.Background(), opts, nil)
+		require.NoError(err)
+		require.Len(res, 1)
+		calls := res[0]["calls"].([]simCallResult)
+		require.Len(calls, 3)
+		for _, call := range calls {
+			for _, l := range call.Logs {
+				require.Equal(res[0]["hash"], l.BlockHash)
+			}
+		}
+		return calls
+	}
+	calls := simulate(true)
+
+	// the native transfer is reported as an ERC-7528 log
+	require.Len(calls[0].Logs, 1)
+	log := calls[0].Logs[0]
+	require.Equal(transferLogAddress, log.Address)
+	require.Equal([]common.Hash{transferTopic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())}, log.Topics)
+	require.Equal(common.BigToHash(value.ToInt()).Bytes(), log.Data)
+
+	// the logs of the contract are kept, calls without a value don't produce transfer logs
+	require.Len(calls[1].Logs, 1)
+	require.Equal(contract, calls[1].Logs[0].Address)
+	require.Equal(uint(1), calls[1].Logs[0].Index)
+
+	// the transfers of the reverted calls aren't reported
+	require.Equal(hexutil.Uint64(0), calls[2].Status)
+	require.Empty(calls[2].Logs)
+
+	// without the option only the contract logs are reported
+	calls = simulate(false)
+	require.Empty(calls[0].Logs)
+	require.Len(calls[1].Logs, 1)
+	require.Equal(contract, calls[1].Logs[0].Address)
+}
+
+func TestSimulateV1GasCap(t *testing.T) {
+	require := require.New(t)
+
+	b := newTestBackend(t)
+	b.gasCap = params.TxGas
+	api := NewPublicBlockChainAPI(b)
+
+	// a call may use up the whole gas cap
+	to := common.Address{2}
+	_, err := api.SimulateV1(context.Background(), SimOpts{BlockStateCalls: []SimBlock{
+		{Calls: []TransactionArgs{{From: &common.Address{1}, To: &to}}},
+	}}, nil)
+	require.NoError(err)
+
+	// the exhausted gas cap leaves no gas for the next call
+	_, err = api.SimulateV1(context.Background(), SimOpts{BlockStateCalls: []SimBlock{
+		{Calls: []TransactionArgs{{From: &common.Address{1}, To: &to}, {From: &common.Address{1}, To: &to}}},
+	}}, nil)
+	require.Error(err)
+	require.Equal(errCodeIntrinsicGas, err.(*simulateError).ErrorCode())
+}
+
+func TestSimulateV1SanitizeChain(t *testing.T) {
+	num := func(n int64) *hexutil.Big { return (*hexutil.Big)(big.NewInt(n)) }
+	ts := func(t uint64) *hexutil.Uint64 { return (*hexutil.Uint64)(&t) }
+
+	// the base block is 10 with the timestamp 1000
+	for _, tt := range []struct {
+		name       string
+		overrides  []*BlockOverrides
+		numbers    []int64
+		timestamps []uint64
+		code       int
+	}{
+		{name: "defaults", overrides: []*BlockOverrides{nil, nil},
+			numbers: []int64{11, 12}, timestamps: []uint64{1001, 1002}},
+		{name: "timestamp", overrides: []*BlockOverrides{{Time: ts(1005)}, nil},
+			numbers: []int64{11, 12}, timestamps: []uint64{1005, 1006}},
+		{name: "gap", overrides: []*BlockOverrides{{Number: num(13), Time: ts(1010)}},
+			numbers: []int64{11, 12, 13}, timestamps: []uint64{1001, 1002, 1010}},
+		{name: "gap after timestamp", overrides: []*BlockOverrides{{Time: ts(1005)}, {Number: num(14)}},
+			numbers: []int64{11, 12, 13, 14}, timestamps: []uint64{1005, 1006, 1007, 1008}},
+		{name: "same number", overrides: []*BlockOverrides{{Number: num(12)}, {Number: num(12)}},
+			code: errCodeBlockNumberInvalid},
+		{name: "base number", overrides: []*BlockOverrides{{Number: num(10)}},
+			code: errCodeBlockNumberInvalid},
+		{name: "base timestamp", overrides: []*BlockOverrides{{Time: ts(1000)}},
+			code: errCodeBlockTimestampInvalid},
+		{name: "timestamp of the gap", overrides: []*BlockOverrides{{Number: num(13), Time: ts(1002)}},
+			code: errCodeBlockTimestampInvalid},
+		{name: "too many blocks", overrides: []*BlockOverrides{{Number: num(10 + maxSimulateBlocks + 1)}},
+			code: errCodeClientLimitExceeded},
+		{name: "empty", code: errCodeInvalidParams},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			blocks := make([]SimBlock, len(tt.overrides))
+			for i, overrides := range tt.overrides {
+				blocks[i].BlockOverrides = overrides
+			}
+			res, err := NewPublicBlockChainAPI(newTestBackend(t)).SimulateV1(context.Background(), SimOpts{BlockStateCalls: blocks}, nil)
+			if tt.code != 0 {
+				require.Error(err)
+				require.Equal(tt.code, err.(*simulateError).ErrorCode())
+				return
+			}
+			require.NoError(err)
+			require.Len(res, len(tt.numbers))
+			for i, block := range res {
+				require.Equal(big.NewInt(tt.numbers[i]), block["number"].(*hexutil.Big).ToInt())
+				require.Equal(hexutil.Uint64(tt.timestamps[i]), block["timestamp"])
+			}
+		})
+	}
+}
+
+func TestSimulateV1Validation(t *testing.T) {
+	var (
+		from = common.Address{1}
+		to   = common.Address{2}
+	)
+	nonce := func(n uint64) *hexutil.Uint64 { return (*hexutil.Uint64)(&n) }
+	gas := func(n uint64) *hexutil.Uint64 { return (*hexutil.Uint64)(&n) }
+	value := func(n int64) *hexutil.Big { return (*hexutil.Big)(big.NewInt(n)) }
+
+	for _, tt := range []struct {
+		name      string
+		setup     func(b *testBackend)
+		overrides *BlockOverrides
+		call      TransactionArgs
+		validate  bool
+		code      int
+	}{
+		{name: "nonce too low", validate: true,
+			setup: func(b *testBackend) { b.statedb.SetNonce(from, 1) },
+			call:  TransactionArgs{Nonce: nonce(0)}, code: errCodeNonceTooLow},
+		{name: "nonce too low without validation",
+			setup: func(b *testBackend) { b.statedb.SetNonce(from, 1) },
+			call:  TransactionArgs{Nonce: nonce(0)}},
+		{name: "nonce too high", validate: true,
+			call: TransactionArgs{Nonce: nonce(5)}, code: errCodeNonceTooHigh},
+		{name: "fee cap too low", validate: true,
+			overrides: &BlockOverrides{BaseFee: value(10)},
+			call:      TransactionArgs{MaxFeePerGas: value(1)}, code: errCodeFeeCapTooLow},
+		{name: "fee cap too low without validation",
+			setup:     func(b *testBackend) { b.statedb.AddBalance(from, big.NewInt(params.Ether)) },
+			overrides: &BlockOverrides{BaseFee: value(10)},
+			call:      TransactionArgs{MaxFeePerGas: value(1)}},
+		{name: "intrinsic gas",
+			call: TransactionArgs{Gas: gas(params.TxGas - 1)}, code: errCodeIntrinsicGas},
+		{name: "insufficient funds", validate: true,
+			call: TransactionArgs{GasPrice: value(1)}, code: errCodeInsufficientFunds},
+		{name: "block gas limit",
+			call: TransactionArgs{Gas: gas(30_000_001)}, code: errCodeBlockGasLimitReached},
+		{name: "sender is not EOA", validate: true,
+			setup: func(b *testBackend) { b.statedb.SetCode(from, storageCode) },
+			code:  errCodeSenderIsNotEOA},
+		{name: "sender is not EOA without validation",
+			setup: func(b *testBackend) { b.statedb.SetCode(from, storageCode) }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			b := newTestBackend(t)
+			if tt.setup != nil {
+				tt.setup(b)
+			}
+			call := tt.call
+			call.From, call.To = &from, &to
+			_, err := NewPublicBlockChainAPI(b).SimulateV1(context.Background(), SimOpts{
+				BlockStateCalls: []SimBlock{{BlockOverrides: tt.overrides, Calls: []TransactionArgs{call}}},
+				Validation:      tt.validate,
+			}, nil)
+			if tt.code == 0 {
+				require.NoError(err)
+				return
+			}
+			require.Error(err)
+			require.Equal(tt.code, err.(*simulateError).ErrorCode(), err.Error())
+		})
+	}
+}
+
+func TestSimulateV1StateBetweenBlocks(t *testing.T) {
+	require := require.New(t)
+
+	b := newTestBackend(t)
+	b.statedb.SetCode(sfc.ContractAddress, []byte{1})
+	b.sfcState.SetCode(sfc.ContractAddress, []byte{1})
+	api := NewPublicBlockChainAPI(b)
+
+	var (
+		from     = common.Address{1}
+		contract = common.Address{0xcc}
+		code     = hexutil.Bytes(storageCode)
+		value    = common.Hash{31: 42}
+		input    = hexutil.Bytes(value.Bytes())
+	)
+	sfcOverrides := func(slot common.Hash) *StateOverride {
+		diff := map[common.Hash]common.Hash{slot: value}
+		return &StateOverride{sfc.ContractAddress: OverrideAccount{StateDiff: &diff}}
+	}
+	res, err := api.SimulateV1(context.Background(), SimOpts{
+		BlockStateCalls: []SimBlock{
+			{
+				StateOverrides: &StateOverride{contract: OverrideAccount{Code: &code}},
+				Calls:          []TransactionArgs{{From: &from, To: &contract, Data: &input}},
+			},
+			{StateOverrides: sfcOverrides(common.Hash{1})},
+			{
+				StateOverrides: sfcOverrides(common.Hash{2}),
+				Calls:          []TransactionArgs{{From: &from, To: &contract}},
+			},
+		},
+		Validation: true,
+	}, nil)
+	require.NoError(err)
+	require.Len(res, 3)
+
+	// the call of the last block reads the value written in the first one
+	calls := res[2]["calls"].([]simCallResult)
+	require.Len(calls, 1)
+	require.Equal(hexutil.Bytes(value.Bytes()), calls[0].ReturnValue)
+	require.Equal(uint64(2), b.statedb.GetNonce(from))
+
+	// the SFC state overrides of the blocks are accumulated
+	require.Equal(value, b.sfcState.GetState(sfc.ContractAddress, common.Hash{1}))
+	require.Equal(value, b.sfcState.GetState(sfc.ContractAddress, common.Hash{2}))
+	require.Equal(value, b.statedb.GetState(sfc.ContractAddress, common.Hash{2}))
+	require.NotEqual(res[1]["stateRoot"], res[2]["stateRoot"])
+}
@@ -0,0 +1,223 @@
+package ethapi
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/common/hexutil"
+	"github.com/unicornultrafoundation/go-u2u/core/state"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/eth/tracers"
+	"github.com/unicornultrafoundation/go-u2u/evmcore"
+	"github.com/unicornultrafoundation/go-u2u/log"
+	"github.com/unicornultrafoundation/go-u2u/rpc"
+)
+
+// blockTraceTask represents a single block trace task when an entire chain is being traced.
+type blockTraceTask struct {
+	block      *evmcore.EvmBlock
+	statedb    *state.StateDB // Intermediate state prepped for tracing
+	sfcStatedb *state.StateDB // Intermediate SFC state prepped for tracing
+	release    func()         // Releases the intermediate state once traced
+	results    []*txTraceResult
+	done       chan struct{} // Closed once the block is traced
+}
+
+// blockTraceResult represents the results of tracing a single block when an entire chain is being traced.
+type blockTraceResult struct {
+	Block  hexutil.Uint64   `json:"block"`  // Block number corresponding to this trace
+	Hash   common.Hash      `json:"hash"`   // Block hash corresponding to this trace
+	Traces []*txTraceResult `json:"traces"` // Trace results produced by the task
+}
+
+// TraceChain returns the structured logs created during the execution of EVM
+// between two blocks (excluding start) and streams them over the subscription
+// in the order of the blocks.
+func (api *PublicDebugAPI) TraceChain(ctx context.Context, start, end rpc.BlockNumber, config *TraceConfig) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	from, err := api.blockByNumber(ctx, start)
+	if err != nil {
+		return nil, err
+	}
+	to, err := api.blockByNumber(ctx, end)
+	if err != nil {
+		return nil, err
+	}
+	if from.NumberU64() >= to.NumberU64() {
+		return nil, fmt.Errorf("end block (#%d) needs to come after start block (#%d)", to.NumberU64(), from.NumberU64())
+	}
+	reexec := defaultTraceReexec
+	if config != nil && config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	statedb, sfcStatedb, err := api.b.StateAtBlock(ctx, from, reexec, nil, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	sub := notifier.CreateSubscription()
+	api.traceChain(notifier, sub, from, to, statedb, sfcStatedb, reexec, config)
+	return sub, nil
+}
+
+// traceChain traces the blocks after the from block up to the to block in parallel, starting
+// from the state of the from block. The state of each block is derived from the state of the
+// previous one. The results are notified in order and the number of the blocks waiting for
+// the delivery is bounded, so a slow subscriber slows the tracing down.
+func (api *PublicDebugAPI) traceChain(notifier *rpc.Notifier, sub *rpc.Subscription, from, to *evmcore.EvmBlock,
+	statedb, sfcStatedb *state.StateDB, reexec uint64, config *TraceConfig) {
+	blocks := int(to.NumberU64() - from.NumberU64())
+	threads := runtime.NumCPU()
+	if threads > blocks {
+		threads = blocks
+	}
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		jobs        = make(chan *blockTraceTask, threads)
+		ordered     = make(chan *blockTraceTask, 2*threads)
+	)
+	// Stop the tracing once the subscriber is gone
+	go func() {
+		select {
+		case <-sub.Err():
+		case <-notifier.Closed():
+		case <-ctx.Done():
+		}
+		cancel()
+	}()
+
+	// Trace the blocks concurrently
+	for th := 0; th < threads; th++ {
+		go func() {
+			for task := range jobs {
+				if ctx.Err() == nil {
+					task.results = api.traceBlockTxs(ctx, task.block, task.statedb, task.sfcStatedb, config)
+				}
+				task.release()
+				close(task.done)
+			}
+		}()
+	}
+
+	// Feed the blocks with their parent states, advancing the state block by block
+	go func() {
+		defer close(ordered)
+		defer close(jobs)
+
+		var (
+			triedb = statedb.Database().TrieDB()
+			root   common.Hash // state root referenced by the chain tracing, empty if none
+		)
+		defer func() {
+			if root != (common.Hash{}) {
+				triedb.Dereference(root)
+			}
+		}()
+		for n := from.NumberU64() + 1; n <= to.NumberU64(); n++ {
+			block, err := api.blockByNumber(ctx, rpc.BlockNumber(n))
+			if err != nil {
+				log.Warn("Chain tracing failed", "block", n, "err", err)
+				return
+			}
+			task := &blockTraceTask{
+				block:   block,
+				statedb: statedb.Copy(),
+				release: func() {},
+				done:    make(chan struct{}),
+			}
+			if sfcStatedb != nil {
+				task.sfcStatedb = sfcStatedb.Copy()
+			}
+			// Reference the parent state once more, so it outlives the chain state until traced
+			if root != (common.Hash{}) {
+				parentRoot := root
+				triedb.Reference(parentRoot, common.Hash{})
+				task.release = func() { triedb.Dereference(parentRoot) }
+			}
+			select {
+			case ordered <- task:
+			case <-ctx.Done():
+				task.release()
+				return
+			}
+			jobs <- task
+
+			if n == to.NumberU64() {
+				break
+			}
+			// Generate the state of the block on top of the parent state
+			next, nextSfc, err := api.b.StateAtBlock(ctx, block, reexec, statedb, sfcStatedb, false)
+			if err != nil {
+				log.Warn("Chain tracing failed", "block", n, "err", err)
+				return
+			}
+			if sfcStatedb != nil && nextSfc == nil {
+				log.Warn("Chain tracing failed", "block", n, "err", errSfcStateUnavailable)
+				return
+			}
+			if root != (common.Hash{}) {
+				triedb.Dereference(root)
+			}
+			statedb, sfcStatedb, root = next, nextSfc, block.Root
+		}
+	}()
+
+	// Deliver the results in the order of the blocks
+	go func() {
+		defer cancel()
+		for task := range ordered {
+			select {
+			case <-task.done:
+			case <-ctx.Done():
+				return
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			res := &blockTraceResult{
+				Block:  hexutil.Uint64(task.block.NumberU64()),
+				Hash:   task.block.Hash,
+				Traces: task.results,
+			}
+			if err := notifier.Notify(sub.ID, res); err != nil {
+				log.Warn("Chain tracing notification failed", "block", task.block.NumberU64(), "err", err)
+				return
+			}
+		}
+	}()
+}
+
+// traceBlockTxs traces the block transactions one by one on top of the parent state.
+// The tracing stops at the first failed transaction, as the subsequent state is unreliable.
+func (api *PublicDebugAPI) traceBlockTxs(ctx context.Context, block *evmcore.EvmBlock, statedb, sfcStatedb *state.StateDB, config *TraceConfig) []*txTraceResult {
+	var (
+		signer   = types.MakeSigner(api.b.ChainConfig(), block.Number)
+		blockCtx = api.b.GetBlockContext(block.Header())
+		results  = make([]*txTraceResult, len(block.Transactions))
+	)
+	for i, tx := range block.Transactions {
+		msg, _ := tx.AsMessage(signer, block.BaseFee)
+		txctx := &tracers.Context{
+			BlockHash: block.Hash,
+			TxIndex:   i,
+			TxHash:    tx.Hash(),
+		}
+		res, err := api.traceTx(ctx, msg, txctx, blockCtx, statedb, sfcStatedb, config)
+		if err != nil {
+			results[i] = &txTraceResult{Error: err.Error()}
+			log.Warn("Tracing failed", "hash", tx.Hash(), "block", block.NumberU64(), "err", err)
+			break
+		}
+		// Only delete empty objects if EIP158/161 (a.k.a Spurious Dragon) is in effect
+		statedb.Finalise(api.b.ChainConfig().IsEIP158(block.Number))
+		if sfcStatedb != nil {
+			sfcStatedb.Finalise(true)
+		}
+		results[i] = &txTraceResult{Result: res}
+	}
+	return results
+}
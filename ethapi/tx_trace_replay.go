@@ -0,0 +1,318 @@
+package ethapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/common/hexutil"
+	"github.com/unicornultrafoundation/go-u2u/core/state"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+	"github.com/unicornultrafoundation/go-u2u/crypto"
+	"github.com/unicornultrafoundation/go-u2u/evmcore"
+	"github.com/unicornultrafoundation/go-u2u/evmcore/txtracer"
+	"github.com/unicornultrafoundation/go-u2u/log"
+	"github.com/unicornultrafoundation/go-u2u/rpc"
+	"github.com/unicornultrafoundation/go-u2u/u2u"
+	"github.com/unicornultrafoundation/go-u2u/utils/signers/gsignercache"
+)
+
+// TraceCallParam is a single call of the trace_callMany request,
+// encoded as [callArgs, traceTypes]
+type TraceCallParam struct {
+	Args       TransactionArgs
+	TraceTypes []string
+}
+
+// UnmarshalJSON decodes the call from the two-element JSON array
+func (p *TraceCallParam) UnmarshalJSON(input []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(input, &raw); err != nil {
+		return err
+	}
+	if len(raw) != 2 {
+		return fmt.Errorf("expected [callArgs, traceTypes], got %d elements", len(raw))
+	}
+	if err := json.Unmarshal(raw[0], &p.Args); err != nil {
+		return err
+	}
+	return json.Unmarshal(raw[1], &p.TraceTypes)
+}
+
+// replayTxContext identifies the replayed message within the chain
+type replayTxContext struct {
+	blockHash   common.Hash
+	blockNumber *big.Int
+	txHash      common.Hash
+	txIndex     int
+	// callHash files the logs of a call, which has no transaction hash
+	callHash common.Hash
+}
+
+// replayMessage executes the message on top of the given state with the tracers
+// of the requested trace types. The state is left modified by the message,
+// so that subsequent messages may be replayed on top of it.
+func (s *PublicTxTraceAPI) replayMessage(ctx context.Context, blockCtx vm.BlockContext, msg evmcore.Message,
+	statedb *state.StateDB, sfcState *state.StateDB, txCtx replayTxContext, traceTypes []string) (*txtracer.TraceResults, error) {
+
+	tracer, err := txtracer.NewReplayTracer(traceTypes)
+	if err != nil {
+		return nil, err
+	}
+	if tracer.Trace != nil {
+		tracer.Trace.SetTx(txCtx.txHash)
+		tracer.Trace.SetFrom(msg.From())
+		tracer.Trace.SetTo(msg.To())
+		tracer.Trace.SetValue(*msg.Value())
+		tracer.Trace.SetBlockHash(txCtx.blockHash)
+		tracer.Trace.SetBlockNumber(txCtx.blockNumber)
+		tracer.Trace.SetTxIndex(uint(txCtx.txIndex))
+		tracer.Trace.SetGasUsed(msg.Gas())
+	}
+	var pre *state.StateDB
+	if tracer.StateDiff != nil {
+		// Delegations of the authorities are applied before the execution starts
+		for _, auth := range msg.SetCodeAuthorizations() {
+			if authority, err := auth.Authority(); err == nil {
+				tracer.StateDiff.Touch(authority)
+			}
+		}
+		pre = statedb.Copy()
+	}
+
+	cfg := u2u.DefaultVMConfig
+	cfg.Debug = true
+	cfg.Tracer = tracer
+	cfg.NoBaseFee = true
+
+	// Setup context so it may be cancelled the call has completed
+	// or, in case of unmetered gas, setup a context with a timeout.
+	var timeout time.Duration = 5 * time.Second
+	if s.b.RPCTimeout() > 0 {
+		timeout = s.b.RPCTimeout()
+	}
+	var cancel context.CancelFunc
+	ctx, cancel = context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	vmenv := vm.NewEVM(blockCtx, evmcore.NewEVMTxContext(msg), statedb, sfcState, s.b.ChainConfig(), cfg)
+
+	// Wait for the context to be done and cancel the evm. Even if the
+	// EVM has finished, cancelling may be done (repeatedly)
+	go func() {
+		<-ctx.Done()
+		vmenv.Cancel()
+	}()
+
+	logsHash := txCtx.txHash
+	if logsHash == (common.Hash{}) {
+		logsHash = txCtx.callHash
+	}
+	statedb.Prepare(logsHash, txCtx.txIndex)
+	result, err := evmcore.ApplyMessage(vmenv, msg, new(evmcore.GasPool).AddGas(math.MaxUint64))
+	if err != nil {
+		return nil, fmt.Errorf("tracing failed: %w", err)
+	}
+	// If the timer caused an abort, return an appropriate error message
+	if vmenv.Cancelled() {
+		return nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
+	}
+	// Finalize the state so any modifications are visible to the state diff
+	statedb.Finalise(true)
+	if tracer.Trace != nil {
+		tracer.Trace.SetGasUsed(result.UsedGas)
+	}
+	return tracer.Results(result.ReturnData, pre, statedb), nil
+}
+
+// callState returns the state and the block context, on top of which the calls are executed
+func (s *PublicTxTraceAPI) callState(ctx context.Context, blockNrOrHash *rpc.BlockNumberOrHash) (*state.StateDB, *state.StateDB, *evmcore.EvmHeader, error) {
+	if blockNrOrHash == nil {
+		latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		blockNrOrHash = &latest
+	}
+	statedb, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, *blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, nil, nil, err
+	}
+	sfcState, _, err := s.b.SfcStateAndHeaderByNumberOrHash(ctx, *blockNrOrHash)
+	if err != nil {
+		log.Warn("Failed to get SFC state", "height", header.Number, "hash", header.Hash.Hex(), "err", err)
+	}
+	return statedb, sfcState, header, nil
+}
+
+// Call trace_call function executes a call on top of the given block and returns its traces
+func (s *PublicTxTraceAPI) Call(ctx context.Context, args TransactionArgs, traceTypes []string, blockNrOrHash *rpc.BlockNumberOrHash) (*txtracer.TraceResults, error) {
+	defer func(start time.Time) {
+		log.Info("Executing trace_call call finished", "runtime", time.Since(start))
+	}(time.Now())
+
+	statedb, sfcState, header, err := s.callState(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := args.ToMessage(s.b.RPCGasCap(), header.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+	txCtx := replayTxContext{blockHash: header.Hash, blockNumber: header.Number}
+	return s.replayMessage(ctx, s.b.GetBlockContext(header), msg, statedb, sfcState, txCtx, traceTypes)
+}
+
+// CallMany trace_callMany function executes a sequence of calls on top of the given block
+// and returns their traces. Each call is executed on top of the state modified by the previous ones.
+func (s *PublicTxTraceAPI) CallMany(ctx context.Context, calls []TraceCallParam, blockNrOrHash *rpc.BlockNumberOrHash) ([]*txtracer.TraceResults, error) {
+	defer func(start time.Time) {
+		log.Info("Executing trace_callMany call finished", "calls", len(calls), "runtime", time.Since(start))
+	}(time.Now())
+
+	statedb, sfcState, header, err := s.callState(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	blockCtx := s.b.GetBlockContext(header)
+	results := make([]*txtracer.TraceResults, 0, len(calls))
+	for i, call := range calls {
+		msg, err := call.Args.ToMessage(s.b.RPCGasCap(), header.BaseFee)
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %w", i, err)
+		}
+		txCtx := replayTxContext{blockHash: header.Hash, blockNumber: header.Number, txIndex: i, callHash: callManyHash(header.Hash, i)}
+		res, err := s.replayMessage(ctx, blockCtx, msg, statedb, sfcState, txCtx, call.TraceTypes)
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %w", i, err)
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// callManyHash returns the synthetic hash of the call of a trace_callMany request,
+// so the logs of the calls aren't mixed up in the shared state
+func callManyHash(blockHash common.Hash, i int) common.Hash {
+	return crypto.Keccak256Hash(blockHash.Bytes(), big.NewInt(int64(i)).Bytes())
+}
+
+// RawTransaction trace_rawTransaction function executes a signed transaction on top
+// of the given block and returns its traces
+func (s *PublicTxTraceAPI) RawTransaction(ctx context.Context, input hexutil.Bytes, traceTypes []string, blockNrOrHash *rpc.BlockNumberOrHash) (*txtracer.TraceResults, error) {
+	defer func(start time.Time) {
+		log.Info("Executing trace_rawTransaction call finished", "runtime", time.Since(start))
+	}(time.Now())
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return nil, err
+	}
+	statedb, sfcState, header, err := s.callState(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	signer := gsignercache.Wrap(types.MakeSigner(s.b.ChainConfig(), header.Number))
+	msg, err := evmcore.TxAsMessage(tx, signer, header.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+	txCtx := replayTxContext{blockHash: header.Hash, blockNumber: header.Number, txHash: tx.Hash()}
+	return s.replayMessage(ctx, s.b.GetBlockContext(header), msg, statedb, sfcState, txCtx, traceTypes)
+}
+
+// ReplayTransaction trace_replayTransaction function replays a transaction
+// of the chain and returns its traces
+func (s *PublicTxTraceAPI) ReplayTransaction(ctx context.Context, hash common.Hash, traceTypes []string) (*txtracer.TraceResults, error) {
+	defer func(start time.Time) {
+		log.Info("Executing trace_replayTransaction call finished", "txHash", hash.String(), "runtime", time.Since(start))
+	}(time.Now())
+
+	tx, blockNumber, index, err := s.b.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, fmt.Errorf("transaction %s not found", hash.String())
+	}
+	block, err := s.b.BlockByNumber(ctx, rpc.BlockNumber(blockNumber))
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %d not found", blockNumber)
+	}
+	msg, blockCtx, statedb, sfcState, err := NewPublicDebugAPI(s.b).stateAtTransaction(ctx, block, int(index))
+	if err != nil {
+		return nil, err
+	}
+	txCtx := replayTxContext{blockHash: block.Hash, blockNumber: block.Number, txHash: hash, txIndex: int(index)}
+	res, err := s.replayMessage(ctx, blockCtx, msg, statedb, sfcState, txCtx, traceTypes)
+	if err != nil {
+		return nil, err
+	}
+	res.TransactionHash = &hash
+	return res, nil
+}
+
+// ReplayBlockTransactions trace_replayBlockTransactions function replays all
+// transactions of the block and returns their traces
+func (s *PublicTxTraceAPI) ReplayBlockTransactions(ctx context.Context, numberOrHash rpc.BlockNumberOrHash, traceTypes []string) ([]*txtracer.TraceResults, error) {
+	defer func(start time.Time) {
+		log.Info("Executing trace_replayBlockTransactions call finished", "runtime", time.Since(start))
+	}(time.Now())
+
+	var (
+		block *evmcore.EvmBlock
+		err   error
+	)
+	if hash, ok := numberOrHash.Hash(); ok {
+		block, err = s.b.BlockByHash(ctx, hash)
+	} else if number, ok := numberOrHash.Number(); ok {
+		block, err = s.b.BlockByNumber(ctx, number)
+	} else {
+		return nil, errors.New("invalid arguments; neither block nor hash specified")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, errors.New("block not found")
+	}
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+
+	// get state from block parent, to be able to recreate correct nonces
+	parentBlockNr := rpc.BlockNumber(block.NumberU64() - 1)
+	statedb, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHash{BlockNumber: &parentBlockNr})
+	if err != nil {
+		return nil, fmt.Errorf("cannot get state for block %v, error: %v", block.NumberU64(), err.Error())
+	}
+	sfcState, _, err := s.b.SfcStateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHash{BlockNumber: &parentBlockNr})
+	if err != nil {
+		log.Warn("Failed to get SFC state", "height", block.NumberU64(), "hash", block.Hash.Hex(), "err", err)
+	}
+
+	signer := gsignercache.Wrap(types.MakeSigner(s.b.ChainConfig(), block.Number))
+	blockCtx := s.b.GetBlockContext(block.Header())
+	results := make([]*txtracer.TraceResults, 0, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		msg, err := evmcore.TxAsMessage(tx, signer, block.BaseFee)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get message from transaction %s, error %s", tx.Hash().String(), err)
+		}
+		txHash := tx.Hash()
+		txCtx := replayTxContext{blockHash: block.Hash, blockNumber: block.Number, txHash: txHash, txIndex: i}
+		res, err := s.replayMessage(ctx, blockCtx, msg, statedb, sfcState, txCtx, traceTypes)
+		if err != nil {
+			return nil, fmt.Errorf("cannot replay transaction %s, error %s", txHash.String(), err)
+		}
+		res.TransactionHash = &txHash
+		results = append(results, res)
+	}
+	return results, nil
+}
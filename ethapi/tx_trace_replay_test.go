@@ -0,0 +1,46 @@
+package ethapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/common/hexutil"
+)
+
+func TestTraceCallMany(t *testing.T) {
+	require := require.New(t)
+
+	b := newTestBackend(t)
+	contract := common.Address{0xcc}
+	b.statedb.SetCode(contract, storageCode)
+	api := NewPublicTxTraceAPI(b)
+
+	call := func(input []byte) TraceCallParam {
+		data := hexutil.Bytes(input)
+		return TraceCallParam{
+			Args:       TransactionArgs{From: &common.Address{1}, To: &contract, Data: &data},
+			TraceTypes: []string{"trace"},
+		}
+	}
+	value := common.Hash{31: 42}
+	res, err := api.CallMany(context.Background(), []TraceCallParam{
+		call(value.Bytes()),
+		call(nil),
+		call(common.Hash{31: 7}.Bytes()),
+	}, nil)
+	require.NoError(err)
+	require.Len(res, 3)
+
+	// the second call reads the value written by the first one
+	require.Equal(hexutil.Bytes(value.Bytes()), res[1].Output)
+	require.Equal(common.Hash{31: 7}, b.statedb.GetState(contract, common.Hash{}))
+
+	// the logs of every call are kept apart
+	require.Len(b.statedb.GetLogs(callManyHash(b.header.Hash, 0), b.header.Hash), 1)
+	require.Empty(b.statedb.GetLogs(callManyHash(b.header.Hash, 1), b.header.Hash))
+	require.Len(b.statedb.GetLogs(callManyHash(b.header.Hash, 2), b.header.Hash), 1)
+	require.NotEqual(callManyHash(b.header.Hash, 0), callManyHash(b.header.Hash, 2))
+}
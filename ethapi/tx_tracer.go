@@ -18,10 +18,12 @@ package ethapi
 
 import (
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"runtime"
+	"sort"
 	"sync"
 	"time"
 
@@ -440,6 +442,14 @@ func (s *PublicTxTraceAPI) Filter(ctx context.Context, args FilterArgs) (*[]txtr
 		}
 	}
 
+	// bounded scan of the address index of the stored traces
+	if (len(fromAddresses) > 0 || len(toAddresses) > 0) && s.b.TxTraceIndexed(ctx) {
+		if fromBlock < 0 {
+			fromBlock = rpc.BlockNumber(s.b.CurrentBlock().NumberU64())
+		}
+		return s.filterIndexed(ctx, uint64(fromBlock), uint64(toBlock), fromAddresses, toAddresses, args.After, args.Count)
+	}
+
 	// check for context timeout
 	contextDone := false
 	go func() {
@@ -455,6 +465,9 @@ func (s *PublicTxTraceAPI) Filter(ctx context.Context, args FilterArgs) (*[]txtr
 	// count of traces doesn't matter so use parallel workers
 	if args.Count == 0 {
 		workerCount := runtime.NumCPU() / 2
+		if workerCount == 0 {
+			workerCount = 1
+		}
 		blocks := make(chan rpc.BlockNumber, 10000)
 		results := make(chan txtracer.ActionTrace, 100000)
 
@@ -513,27 +526,7 @@ func (s *PublicTxTraceAPI) Filter(ctx context.Context, args FilterArgs) (*[]txtr
 				for _, trace := range *traces {
 
 					if args.Count == 0 || traceAdded < args.Count {
-						addTrace := true
-
-						if args.FromAddress != nil || args.ToAddress != nil {
-							if args.FromAddress != nil {
-								if trace.Action.From == nil {
-									addTrace = false
-								} else {
-									if _, ok := fromAddresses[*trace.Action.From]; !ok {
-										addTrace = false
-									}
-								}
-							}
-							if args.ToAddress != nil {
-								if trace.Action.To == nil {
-									addTrace = false
-								} else if _, ok := toAddresses[*trace.Action.To]; !ok {
-									addTrace = false
-								}
-							}
-						}
-						if addTrace {
+						if matchTrace(&trace, fromAddresses, toAddresses) {
 							if traceCount >= args.After {
 								trace := trace
 								callTrace.AddTrace(&trace)
@@ -564,6 +557,156 @@ func (s *PublicTxTraceAPI) Filter(ctx context.Context, args FilterArgs) (*[]txtr
 	return &callTrace.Actions, nil
 }
 
+// matchTrace checks the trace against the from and to addresses of the filter by the same
+// rule as the address index of the stored traces: the sender is the caller, the contract creator
+// or the self-destructed contract, and the recipient is the callee, the refund address
+// of a self-destruct or the created contract.
+func matchTrace(trace *txtracer.ActionTrace, fromAddresses, toAddresses map[common.Address]struct{}) bool {
+	contains := func(addresses map[common.Address]struct{}, addr *common.Address) bool {
+		if addr == nil {
+			return false
+		}
+		_, ok := addresses[*addr]
+		return ok
+	}
+	if len(fromAddresses) > 0 {
+		from := trace.Action.From
+		if from == nil {
+			from = trace.Action.Address
+		}
+		if !contains(fromAddresses, from) {
+			return false
+		}
+	}
+	if len(toAddresses) > 0 {
+		to := trace.Action.To
+		if to == nil {
+			to = trace.Action.RefundAddress
+		}
+		var created *common.Address
+		if trace.Result != nil {
+			created = trace.Result.Address
+		}
+		if !contains(toAddresses, to) && !contains(toAddresses, created) {
+			return false
+		}
+	}
+	return true
+}
+
+// traceRefKey returns a key of the trace reference, which sorts in the order of traces in the chain
+func traceRefKey(ref TraceRef) string {
+	key := make([]byte, 12+4*len(ref.TraceAddress))
+	binary.BigEndian.PutUint64(key, ref.Block)
+	binary.BigEndian.PutUint32(key[8:], ref.TxPosition)
+	for i, pos := range ref.TraceAddress {
+		binary.BigEndian.PutUint32(key[12+4*i:], pos)
+	}
+	return string(key)
+}
+
+// filterIndexed looks up the traces of the specified addresses in the address index
+// of the stored traces. When both from and to addresses are specified, a trace has
+// to match both of them.
+func (s *PublicTxTraceAPI) filterIndexed(ctx context.Context, fromBlock, toBlock uint64,
+	fromAddresses, toAddresses map[common.Address]struct{}, after, count uint) (*[]txtracer.ActionTrace, error) {
+
+	collect := func(addresses map[common.Address]struct{}, roles ...TraceAddressRole) (map[string]TraceRef, error) {
+		refs := make(map[string]TraceRef)
+		for addr := range addresses {
+			for _, role := range roles {
+				err := s.b.TxTraceRefs(ctx, role, addr, fromBlock, toBlock, func(ref TraceRef) bool {
+					refs[traceRefKey(ref)] = ref
+					return true
+				})
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		return refs, nil
+	}
+
+	var refs map[string]TraceRef
+	if len(fromAddresses) > 0 {
+		fromRefs, err := collect(fromAddresses, TraceRoleFrom)
+		if err != nil {
+			return nil, err
+		}
+		refs = fromRefs
+	}
+	if len(toAddresses) > 0 {
+		toRefs, err := collect(toAddresses, TraceRoleTo, TraceRoleCreated)
+		if err != nil {
+			return nil, err
+		}
+		if refs == nil {
+			refs = toRefs
+		} else {
+			for key := range refs {
+				if _, ok := toRefs[key]; !ok {
+					delete(refs, key)
+				}
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(refs))
+	for key := range refs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if uint(len(keys)) <= after {
+		keys = keys[:0]
+	} else {
+		keys = keys[after:]
+	}
+	if count > 0 && uint(len(keys)) > count {
+		keys = keys[:count]
+	}
+
+	callTrace := txtracer.CallTrace{
+		Actions: make([]txtracer.ActionTrace, 0, len(keys)),
+	}
+	var (
+		txHash common.Hash
+		traces *[]txtracer.ActionTrace
+	)
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("timeout when loading traces")
+		}
+		ref := refs[key]
+		if traces == nil || txHash != ref.TxHash {
+			var err error
+			if traces, err = s.b.TxTraceByHash(ctx, ref.TxHash); err != nil {
+				return nil, err
+			}
+			txHash = ref.TxHash
+		}
+		for i := range *traces {
+			trace := (*traces)[i]
+			if equalTraceAddress(trace.TraceAddress, ref.TraceAddress) {
+				callTrace.AddTrace(&trace)
+				break
+			}
+		}
+	}
+	return &callTrace.Actions, nil
+}
+
+func equalTraceAddress(a, b []uint32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func worker(id int,
 	s *PublicTxTraceAPI,
 	ctx context.Context,
@@ -585,26 +728,7 @@ func worker(id int,
 				break
 			}
 			for _, trace := range *traces {
-				addTrace := true
-
-				if len(fromAddresses) > 0 {
-
-					if trace.Action.From == nil {
-						addTrace = false
-					} else {
-						if _, ok := fromAddresses[*trace.Action.From]; !ok {
-							addTrace = false
-						}
-					}
-				}
-				if len(toAddresses) > 0 {
-					if trace.Action.To == nil {
-						addTrace = false
-					} else if _, ok := toAddresses[*trace.Action.To]; !ok {
-						addTrace = false
-					}
-				}
-				if addTrace {
+				if matchTrace(&trace, fromAddresses, toAddresses) {
 					results <- trace
 				}
 			}
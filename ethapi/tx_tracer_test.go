@@ -0,0 +1,156 @@
+package ethapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unicornultrafoundation/go-helios/u2udb/memorydb"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/evmcore"
+	"github.com/unicornultrafoundation/go-u2u/evmcore/txtracer"
+	txtrace "github.com/unicornultrafoundation/go-u2u/gossip/txtracer"
+	"github.com/unicornultrafoundation/go-u2u/native"
+	"github.com/unicornultrafoundation/go-u2u/rpc"
+)
+
+// traceTestBackend serves the stored traces of a short chain, with or without the address index
+type traceTestBackend struct {
+	*testBackend
+	store   *txtrace.Store
+	blocks  []*evmcore.EvmBlock
+	indexed bool
+}
+
+func (b *traceTestBackend) CurrentBlock() *evmcore.EvmBlock {
+	return b.blocks[len(b.blocks)-1]
+}
+
+func (b *traceTestBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmBlock, error) {
+	if number < 0 || int(number) >= len(b.blocks) {
+		return nil, nil
+	}
+	return b.blocks[number], nil
+}
+
+func (b *traceTestBackend) TxTraceByHash(ctx context.Context, h common.Hash) (*[]txtracer.ActionTrace, error) {
+	traces := make([]txtracer.ActionTrace, 0)
+	if err := json.Unmarshal(b.store.GetTx(h), &traces); err != nil {
+		return nil, err
+	}
+	if len(traces) == 0 {
+		return nil, errors.New("no trace")
+	}
+	return &traces, nil
+}
+
+func (b *traceTestBackend) TxTraceIndexed(ctx context.Context) bool {
+	return b.indexed
+}
+
+func (b *traceTestBackend) TxTraceRefs(ctx context.Context, role TraceAddressRole, addr common.Address, from, to uint64, onRef func(ref TraceRef) bool) error {
+	roles := map[TraceAddressRole]txtrace.AddressRole{
+		TraceRoleFrom:    txtrace.RoleFrom,
+		TraceRoleTo:      txtrace.RoleTo,
+		TraceRoleCreated: txtrace.RoleCreated,
+	}
+	b.store.ForEachAddressTrace(roles[role], addr, from, to, func(ref txtrace.TraceRef) bool {
+		return onRef(TraceRef(ref))
+	})
+	return nil
+}
+
+func TestFilterWithAndWithoutIndex(t *testing.T) {
+	require := require.New(t)
+
+	a, b, c := common.Address{0xa}, common.Address{0xb}, common.Address{0xc}
+	call := func(from, to common.Address, traceAddress ...uint32) txtracer.ActionTrace {
+		return txtracer.ActionTrace{
+			Action:       txtracer.AddressAction{From: &from, To: &to},
+			TraceAddress: traceAddress,
+			TraceType:    "call",
+		}
+	}
+	create := func(from, created common.Address, traceAddress ...uint32) txtracer.ActionTrace {
+		return txtracer.ActionTrace{
+			Action:       txtracer.AddressAction{From: &from},
+			Result:       &txtracer.TraceActionResult{Address: &created},
+			TraceAddress: traceAddress,
+			TraceType:    "create",
+		}
+	}
+	suicide := func(contract, refund common.Address, traceAddress ...uint32) txtracer.ActionTrace {
+		return txtracer.ActionTrace{
+			Action:       txtracer.AddressAction{Address: &contract, RefundAddress: &refund},
+			TraceAddress: traceAddress,
+			TraceType:    "suicide",
+		}
+	}
+	chain := [][]txtracer.ActionTrace{
+		nil,
+		{call(a, b), create(b, c, 0)},
+		{call(b, c), suicide(c, a, 0)},
+		{call(a, c)},
+	}
+
+	backend := &traceTestBackend{
+		testBackend: newTestBackend(t),
+		store:       txtrace.NewStore(memorydb.New(), memorydb.New()),
+	}
+	for n, traces := range chain {
+		var txs types.Transactions
+		if len(traces) != 0 {
+			tx := types.NewTx(&types.LegacyTx{Nonce: uint64(n), GasPrice: big.NewInt(0)})
+			for i := range traces {
+				traces[i].BlockNumber = *big.NewInt(int64(n))
+				traces[i].TransactionHash = tx.Hash()
+			}
+			raw, err := json.Marshal(traces)
+			require.NoError(err)
+			require.NoError(backend.store.SetTxTrace(tx.Hash(), raw))
+			// expect the traces as they are loaded from the store
+			stored, err := backend.TxTraceByHash(context.Background(), tx.Hash())
+			require.NoError(err)
+			chain[n] = *stored
+			txs = types.Transactions{tx}
+		}
+		backend.blocks = append(backend.blocks, evmcore.NewEvmBlock(&evmcore.EvmHeader{
+			Number: big.NewInt(int64(n)),
+			Time:   native.FromUnix(int64(n)),
+		}, txs))
+	}
+	api := NewPublicTxTraceAPI(backend)
+
+	filter := func(indexed bool, count uint, from, to []common.Address) []txtracer.ActionTrace {
+		backend.indexed = indexed
+		args := FilterArgs{Count: count}
+		if from != nil {
+			args.FromAddress = &from
+		}
+		if to != nil {
+			args.ToAddress = &to
+		}
+		res, err := api.Filter(context.Background(), args)
+		require.NoError(err)
+		return *res
+	}
+	for _, query := range []struct {
+		from, to []common.Address
+		expected []txtracer.ActionTrace
+	}{
+		{from: []common.Address{a}, expected: []txtracer.ActionTrace{chain[1][0], chain[3][0]}},
+		{from: []common.Address{c}, expected: []txtracer.ActionTrace{chain[2][1]}},
+		{to: []common.Address{a}, expected: []txtracer.ActionTrace{chain[2][1]}},
+		{to: []common.Address{c}, expected: []txtracer.ActionTrace{chain[1][1], chain[2][0], chain[3][0]}},
+		{from: []common.Address{b}, to: []common.Address{c}, expected: []txtracer.ActionTrace{chain[1][1], chain[2][0]}},
+	} {
+		require.Equal(query.expected, filter(true, 0, query.from, query.to), query)
+		require.Equal(query.expected, filter(false, 0, query.from, query.to), query)
+		require.Equal(query.expected, filter(false, 10, query.from, query.to), query)
+	}
+}
@@ -60,7 +60,8 @@ func validateTx(tx *types.Transaction) error {
 		return ErrNegativeValue
 	}
 	// Ensure the transaction has more gas than the basic tx fee.
-	intrGas, err := evmcore.IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil)
+	// Network rules aren't known here, so only the pre-Shanghai lower bound is checked.
+	intrGas, err := evmcore.IntrinsicGas(tx.Data(), tx.AccessList(), tx.SetCodeAuthorizations(), tx.To() == nil, false)
 	if err != nil {
 		return err
 	}
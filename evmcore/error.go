@@ -62,6 +62,10 @@ var (
 	// than required to start the invocation.
 	ErrIntrinsicGas = errors.New("intrinsic gas too low")
 
+	// ErrMaxInitCodeSizeExceeded is returned if creation transaction provides the init code bigger
+	// than init code size limit.
+	ErrMaxInitCodeSizeExceeded = errors.New("max initcode size exceeded")
+
 	// ErrTxTypeNotSupported is returned if a transaction is not supported in the
 	// current network configuration.
 	ErrTxTypeNotSupported = types.ErrTxTypeNotSupported
@@ -89,6 +93,22 @@ var (
 	// allowed by a pool for a single account.
 	ErrAccountLimitExceeded = errors.New("account limit exceeded")
 
+	// ErrSetCodeTxCreate is returned if a set code transaction has no recipient.
+	ErrSetCodeTxCreate = errors.New("SetCode transaction must not be a create transaction")
+
+	// ErrEmptyAuthList is returned if a set code transaction has an empty authorization list.
+	ErrEmptyAuthList = errors.New("EIP-7702 transaction with empty auth list")
+
 	// ErrInvalidPaymasterParams is returned if the paymaster params is malformed.
 	ErrInvalidPaymasterParams = errors.New("invalid paymaster params")
 )
+
+// EIP-7702 state transition errors.
+// Note these are just informational, and do not cause tx execution abort.
+var (
+	ErrAuthorizationWrongChainID       = errors.New("EIP-7702 authorization chain ID mismatch")
+	ErrAuthorizationNonceOverflow      = errors.New("EIP-7702 authorization nonce > 64 bit")
+	ErrAuthorizationInvalidSignature   = errors.New("EIP-7702 authorization has invalid signature")
+	ErrAuthorizationDestinationHasCode = errors.New("EIP-7702 authorization destination is a contract")
+	ErrAuthorizationNonceMismatch      = errors.New("EIP-7702 authorization nonce does not match current account nonce")
+)
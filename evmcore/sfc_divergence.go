@@ -0,0 +1,77 @@
+package evmcore
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/state"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+)
+
+// SfcSlotDiff is a storage slot of a SFC contract, which has different values
+// in the main state and in the SFC state.
+type SfcSlotDiff struct {
+	Key      common.Hash
+	Value    common.Hash // value in the main state
+	SfcValue common.Hash // value in the SFC state
+}
+
+// SfcDivergence is a divergence of the SFC state from the main state, caused
+// by a single transaction in a single contract.
+type SfcDivergence struct {
+	BlockNumber uint64
+	TxHash      common.Hash
+	Address     common.Address
+	Slots       []SfcSlotDiff
+}
+
+// OnSfcDivergenceFn is called for every divergence found during block processing.
+type OnSfcDivergenceFn func(*SfcDivergence)
+
+// FindSfcDivergences compares the storage slots of the SFC contracts, which were
+// modified in either state since the states were finalised. The divergences are
+// returned in the order of the contract addresses.
+func FindSfcDivergences(statedb, sfcStatedb *state.StateDB, contracts map[common.Address]vm.PrecompiledStateContract) []*SfcDivergence {
+	addrs := make([]common.Address, 0, len(contracts))
+	for addr := range contracts {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i].Bytes(), addrs[j].Bytes()) < 0
+	})
+
+	var divergences []*SfcDivergence
+	for _, addr := range addrs {
+		keys := make(map[common.Hash]struct{})
+		for _, key := range statedb.GetDirtyStorageKeys(addr) {
+			keys[key] = struct{}{}
+		}
+		for _, key := range sfcStatedb.GetDirtyStorageKeys(addr) {
+			keys[key] = struct{}{}
+		}
+		var slots []SfcSlotDiff
+		for key := range keys {
+			value := statedb.GetState(addr, key)
+			sfcValue := sfcStatedb.GetState(addr, key)
+			if value != sfcValue {
+				slots = append(slots, SfcSlotDiff{
+					Key:      key,
+					Value:    value,
+					SfcValue: sfcValue,
+				})
+			}
+		}
+		if len(slots) == 0 {
+			continue
+		}
+		sort.Slice(slots, func(i, j int) bool {
+			return bytes.Compare(slots[i].Key.Bytes(), slots[j].Key.Bytes()) < 0
+		})
+		divergences = append(divergences, &SfcDivergence{
+			Address: addr,
+			Slots:   slots,
+		})
+	}
+	return divergences
+}
@@ -0,0 +1,56 @@
+package evmcore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/core/state"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+)
+
+func TestFindSfcDivergences(t *testing.T) {
+	require := require.New(t)
+
+	newState := func() *state.StateDB {
+		statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		require.NoError(err)
+		return statedb
+	}
+	statedb, sfcStatedb := newState(), newState()
+	sfc, other := common.Address{0xfc}, common.Address{0x01}
+	contracts := map[common.Address]vm.PrecompiledStateContract{sfc: nil}
+	// keep the accounts from being deleted as empty ones
+	statedb.SetNonce(sfc, 1)
+	sfcStatedb.SetNonce(sfc, 1)
+
+	// same writes
+	statedb.SetState(sfc, common.Hash{1}, common.Hash{1})
+	sfcStatedb.SetState(sfc, common.Hash{1}, common.Hash{1})
+	// the writes of other contracts aren't compared
+	statedb.SetState(other, common.Hash{1}, common.Hash{1})
+	require.Empty(FindSfcDivergences(statedb, sfcStatedb, contracts))
+
+	// a write missing in either state
+	statedb.SetState(sfc, common.Hash{2}, common.Hash{2})
+	sfcStatedb.SetState(sfc, common.Hash{3}, common.Hash{3})
+	require.Equal([]*SfcDivergence{{
+		Address: sfc,
+		Slots: []SfcSlotDiff{
+			{Key: common.Hash{2}, Value: common.Hash{2}},
+			{Key: common.Hash{3}, SfcValue: common.Hash{3}},
+		},
+	}}, FindSfcDivergences(statedb, sfcStatedb, contracts))
+
+	// only the slots modified since the last finalisation are compared
+	statedb.Finalise(true)
+	sfcStatedb.Finalise(true)
+	require.Empty(FindSfcDivergences(statedb, sfcStatedb, contracts))
+	sfcStatedb.SetState(sfc, common.Hash{1}, common.Hash{4})
+	require.Equal([]*SfcDivergence{{
+		Address: sfc,
+		Slots:   []SfcSlotDiff{{Key: common.Hash{1}, Value: common.Hash{1}, SfcValue: common.Hash{4}}},
+	}}, FindSfcDivergences(statedb, sfcStatedb, contracts))
+}
@@ -55,9 +55,12 @@ func NewStateProcessor(config *params.ChainConfig, bc DummyChain) *StateProcesso
 // Process returns the receipts and logs accumulated during the process and
 // returns the amount of gas that was used in the process. If any of the
 // transactions failed to execute due to insufficient gas, it will return an error.
+//
+// If onSfcDivergence isn't nil, the SFC state is compared with the main state
+// after every transaction.
 func (p *StateProcessor) Process(
 	block *EvmBlock, statedb *state.StateDB, sfcStatedb *state.StateDB, cfg vm.Config,
-	usedGas *uint64, onNewLog func(*types.Log, *state.StateDB),
+	usedGas *uint64, onNewLog func(*types.Log, *state.StateDB), onSfcDivergence OnSfcDivergenceFn,
 ) (
 	receipts types.Receipts, allLogs []*types.Log, skipped []uint32, err error,
 ) {
@@ -81,7 +84,10 @@ func (p *StateProcessor) Process(
 		}
 
 		statedb.Prepare(tx.Hash(), i)
-		receipt, _, skip, err = ApplyTransaction(msg, p.config, gp, statedb, blockNumber, blockHash, tx, usedGas, vmenv, cfg, onNewLog)
+		if sfcStatedb != nil {
+			sfcStatedb.Prepare(tx.Hash(), i)
+		}
+		receipt, _, skip, err = applyTransaction(msg, p.config, gp, statedb, sfcStatedb, blockNumber, blockHash, tx, usedGas, vmenv, cfg, onNewLog, onSfcDivergence)
 		if skip {
 			skipped = append(skipped, uint32(i))
 			err = nil
@@ -113,6 +119,29 @@ func ApplyTransaction(
 	uint64,
 	bool,
 	error,
+) {
+	return applyTransaction(msg, config, gp, statedb, nil, blockNumber, blockHash, tx, usedGas, evm, cfg, onNewLog, nil)
+}
+
+func applyTransaction(
+	msg types.Message,
+	config *params.ChainConfig,
+	gp *GasPool,
+	statedb *state.StateDB,
+	sfcStatedb *state.StateDB,
+	blockNumber *big.Int,
+	blockHash common.Hash,
+	tx *types.Transaction,
+	usedGas *uint64,
+	evm *vm.EVM,
+	cfg vm.Config,
+	onNewLog func(*types.Log, *state.StateDB),
+	onSfcDivergence OnSfcDivergenceFn,
+) (
+	*types.Receipt,
+	uint64,
+	bool,
+	error,
 ) {
 	// Create a new context to be used in the EVM environment.
 	txContext := NewEVMTxContext(msg)
@@ -143,6 +172,18 @@ func ApplyTransaction(
 		onNewLog(l, statedb)
 	}
 
+	// Compare the SFC state with the main state before the changes are finalised
+	if sfcStatedb != nil {
+		if onSfcDivergence != nil {
+			for _, d := range FindSfcDivergences(statedb, sfcStatedb, cfg.SfcPrecompiles) {
+				d.BlockNumber = blockNumber.Uint64()
+				d.TxHash = tx.Hash()
+				onSfcDivergence(d)
+			}
+		}
+		sfcStatedb.Finalise(true)
+	}
+
 	// Update the state with pending changes.
 	var root []byte
 	if config.IsByzantium(blockNumber) {
@@ -0,0 +1,60 @@
+package evmcore
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/core/state"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+	"github.com/unicornultrafoundation/go-u2u/crypto"
+	"github.com/unicornultrafoundation/go-u2u/params"
+)
+
+func TestApplySetCodeMessageBeforePrague(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	tx := setCodeTx(0, key, []types.SetCodeAuthorization{setCodeAuth(1, key)})
+	msg, err := tx.AsMessage(types.LatestSignerForChainID(params.TestChainConfig.ChainID), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apply := func(config *params.ChainConfig) (*state.StateDB, error) {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		statedb.AddBalance(from, big.NewInt(1000000000))
+		blockCtx := vm.BlockContext{
+			CanTransfer: CanTransfer,
+			Transfer:    Transfer,
+			BlockNumber: big.NewInt(1),
+			Time:        big.NewInt(1),
+			Difficulty:  big.NewInt(1),
+			BaseFee:     big.NewInt(0),
+			GasLimit:    msg.Gas(),
+		}
+		evm := vm.NewEVM(blockCtx, NewEVMTxContext(msg), statedb, nil, config, vm.Config{})
+		_, err := ApplyMessage(evm, msg, new(GasPool).AddGas(msg.Gas()))
+		return statedb, err
+	}
+
+	prePrague := *params.TestChainConfig
+	prePrague.PragueBlock = nil
+	statedb, err := apply(&prePrague)
+	if !errors.Is(err, ErrTxTypeNotSupported) {
+		t.Fatal("expected", ErrTxTypeNotSupported, "got", err)
+	}
+	if code := statedb.GetCode(from); len(code) != 0 {
+		t.Fatal("delegation applied before Prague", code)
+	}
+
+	statedb, err = apply(params.TestChainConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address, ok := types.ParseDelegation(statedb.GetCode(from)); !ok || address != (common.Address{0xaa}) {
+		t.Fatal("delegation isn't applied after Prague", address)
+	}
+}
@@ -87,6 +87,15 @@ var (
 	// than some meaningful limit a user might use. This is not a consensus error
 	// making the transaction invalid, rather a DOS protection.
 	ErrOversizedData = errors.New("oversized data")
+
+	// ErrInflightTxLimitReached is returned when the maximum number of in-flight
+	// transactions is reached for specific accounts, which are delegated or
+	// authorized by a pooled set code transaction.
+	ErrInflightTxLimitReached = errors.New("in-flight transaction limit reached for delegated accounts")
+
+	// ErrAuthorityReserved is returned if a set code transaction authorizes an
+	// account, which already has pooled transactions.
+	ErrAuthorityReserved = errors.New("authority already reserved")
 )
 
 var (
@@ -607,7 +616,8 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 		Accept: 0 |
 			1<<types.LegacyTxType |
 			1<<types.AccessListTxType |
-			1<<types.DynamicFeeTxType,
+			1<<types.DynamicFeeTxType |
+			1<<types.SetCodeTxType,
 		MaxSize:      txMaxSize,
 		MinTip:       pool.chain.EffectiveMinTip(),
 		MinGasPrice:  pool.chain.MinGasPrice(),
@@ -650,6 +660,47 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if err := ValidateTransactionWithState(tx, pool.signer, optsWithState); err != nil {
 		return err
 	}
+	return pool.validateAuth(tx)
+}
+
+// validateAuth verifies that the transaction complies with the code authorization
+// restrictions brought by the set code transaction type. The pooled transactions
+// of a delegated account may be invalidated by the delegated code at any moment,
+// so such accounts are limited to a single in-flight transaction.
+func (pool *TxPool) validateAuth(tx *types.Transaction) error {
+	from, _ := types.Sender(pool.signer, tx) // already validated
+	// Allow at most one in-flight tx for delegated accounts
+	if _, delegated := types.ParseDelegation(pool.currentState.GetCode(from)); delegated {
+		var (
+			count  int
+			exists bool
+		)
+		if pending := pool.pending[from]; pending != nil {
+			count += pending.Len()
+			exists = pending.txs.Get(tx.Nonce()) != nil
+		}
+		if queue := pool.queue[from]; queue != nil {
+			count += queue.Len()
+			exists = exists || queue.txs.Get(tx.Nonce()) != nil
+		}
+		// Replacing the in-flight transaction of a delegated account is still supported
+		if count >= 1 && !exists {
+			return ErrInflightTxLimitReached
+		}
+	}
+	// Authorities cannot conflict with any pending or queued transactions
+	for _, auth := range tx.SetCodeAuthorities() {
+		var count int
+		if pending := pool.pending[auth]; pending != nil {
+			count += pending.Len()
+		}
+		if queue := pool.queue[auth]; queue != nil {
+			count += queue.Len()
+		}
+		if count > 1 {
+			return ErrAuthorityReserved
+		}
+	}
 	return nil
 }
 
@@ -136,6 +136,29 @@ func dynamicFeeTx(nonce uint64, gaslimit uint64, gasFee *big.Int, tip *big.Int,
 	return tx
 }
 
+func setCodeTx(nonce uint64, key *ecdsa.PrivateKey, auths []types.SetCodeAuthorization) *types.Transaction {
+	tx, _ := types.SignNewTx(key, types.LatestSignerForChainID(params.TestChainConfig.ChainID), &types.SetCodeTx{
+		ChainID:   params.TestChainConfig.ChainID,
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(2),
+		Gas:       100000,
+		To:        common.Address{},
+		Value:     big.NewInt(100),
+		AuthList:  auths,
+	})
+	return tx
+}
+
+func setCodeAuth(nonce uint64, key *ecdsa.PrivateKey) types.SetCodeAuthorization {
+	auth, _ := types.SignSetCode(key, types.SetCodeAuthorization{
+		ChainID: params.TestChainConfig.ChainID,
+		Address: common.Address{0xaa},
+		Nonce:   nonce,
+	})
+	return auth
+}
+
 func setupTxPool() (*TxPool, *ecdsa.PrivateKey) {
 	return setupTxPoolWithConfig(params.TestChainConfig)
 }
@@ -428,6 +451,60 @@ func TestTransactionTipAboveFeeCap(t *testing.T) {
 	}
 }
 
+func TestSetCodeTransactions(t *testing.T) {
+	t.Parallel()
+
+	// Set code transactions are rejected before Prague
+	cancunConfig := *params.TestChainConfig
+	cancunConfig.PragueBlock = nil
+	pool, key := setupTxPoolWithConfig(&cancunConfig)
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+	if err := pool.AddRemote(setCodeTx(0, key, []types.SetCodeAuthorization{setCodeAuth(0, key)})); !errors.Is(err, ErrTxTypeNotSupported) {
+		t.Error("expected", ErrTxTypeNotSupported, "got", err)
+	}
+	pool.Stop()
+
+	pool, key = setupTxPool()
+	defer pool.Stop()
+	var (
+		delegatedKey, _ = crypto.GenerateKey()
+		authorityKey, _ = crypto.GenerateKey()
+		delegated       = crypto.PubkeyToAddress(delegatedKey.PublicKey)
+	)
+	for _, k := range []*ecdsa.PrivateKey{key, delegatedKey, authorityKey} {
+		testAddBalance(pool, crypto.PubkeyToAddress(k.PublicKey), big.NewInt(1000000000))
+	}
+
+	// The authorization list must not be empty
+	if err := pool.AddRemote(setCodeTx(0, key, nil)); err != ErrEmptyAuthList {
+		t.Error("expected", ErrEmptyAuthList, "got", err)
+	}
+
+	// A delegated account may have a single in-flight transaction only
+	pool.mu.Lock()
+	pool.currentState.SetCode(delegated, types.AddressToDelegation(common.Address{0xaa}))
+	pool.mu.Unlock()
+	if err := pool.AddRemote(dynamicFeeTx(0, 100000, big.NewInt(2), big.NewInt(1), delegatedKey)); err != nil {
+		t.Error("expected nil, got", err)
+	}
+	if err := pool.AddRemote(dynamicFeeTx(1, 100000, big.NewInt(2), big.NewInt(1), delegatedKey)); err != ErrInflightTxLimitReached {
+		t.Error("expected", ErrInflightTxLimitReached, "got", err)
+	}
+
+	// The authorities must not have pooled transactions
+	for nonce := uint64(0); nonce < 2; nonce++ {
+		if err := pool.AddRemote(dynamicFeeTx(nonce, 100000, big.NewInt(2), big.NewInt(1), authorityKey)); err != nil {
+			t.Error("expected nil, got", err)
+		}
+	}
+	if err := pool.AddRemote(setCodeTx(0, key, []types.SetCodeAuthorization{setCodeAuth(2, authorityKey)})); err != ErrAuthorityReserved {
+		t.Error("expected", ErrAuthorityReserved, "got", err)
+	}
+	if err := pool.AddRemote(setCodeTx(0, key, []types.SetCodeAuthorization{setCodeAuth(0, delegatedKey)})); err != nil {
+		t.Error("expected nil, got", err)
+	}
+}
+
 func TestTransactionVeryHighValues(t *testing.T) {
 	t.Parallel()
 
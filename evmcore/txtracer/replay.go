@@ -0,0 +1,128 @@
+package txtracer
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/common/hexutil"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+)
+
+// Trace types which may be requested from the replay tracer
+const (
+	TraceTypeTrace     = "trace"
+	TraceTypeStateDiff = "stateDiff"
+	TraceTypeVmTrace   = "vmTrace"
+)
+
+// TraceResults is the OpenEthereum-compatible result of a replayed transaction or call
+type TraceResults struct {
+	Output          hexutil.Bytes `json:"output"`
+	StateDiff       StateDiff     `json:"stateDiff"`
+	Trace           []ActionTrace `json:"trace"`
+	VmTrace         *VmTrace      `json:"vmTrace"`
+	TransactionHash *common.Hash  `json:"transactionHash,omitempty"`
+}
+
+// ReplayTracer dispatches the EVM events to the loggers of the requested trace types
+type ReplayTracer struct {
+	Trace     *TraceStructLogger
+	StateDiff *StateDiffLogger
+	VmTrace   *VmTraceLogger
+
+	tracers []vm.Tracer
+}
+
+// NewReplayTracer creates new instance of replay tracer for the given trace types
+func NewReplayTracer(traceTypes []string) (*ReplayTracer, error) {
+	rt := &ReplayTracer{}
+	for _, traceType := range traceTypes {
+		switch traceType {
+		case TraceTypeTrace:
+			if rt.Trace == nil {
+				rt.Trace = NewTraceStructLogger(nil)
+				rt.tracers = append(rt.tracers, rt.Trace)
+			}
+		case TraceTypeStateDiff:
+			if rt.StateDiff == nil {
+				rt.StateDiff = NewStateDiffLogger()
+				rt.tracers = append(rt.tracers, rt.StateDiff)
+			}
+		case TraceTypeVmTrace:
+			if rt.VmTrace == nil {
+				rt.VmTrace = NewVmTraceLogger()
+				rt.tracers = append(rt.tracers, rt.VmTrace)
+			}
+		default:
+			return nil, fmt.Errorf("unrecognized trace type: %s", traceType)
+		}
+	}
+	return rt, nil
+}
+
+// Results assembles the results of the requested trace types. The pre state is
+// the state before the transaction, the post state must be finalised.
+func (rt *ReplayTracer) Results(output []byte, pre, post vm.StateDB) *TraceResults {
+	res := &TraceResults{
+		Output: common.CopyBytes(output),
+		Trace:  make([]ActionTrace, 0),
+	}
+	if res.Output == nil {
+		res.Output = hexutil.Bytes{}
+	}
+	if rt.Trace != nil {
+		rt.Trace.ProcessTx()
+		res.Trace = *rt.Trace.GetTraceActions()
+	}
+	if rt.StateDiff != nil {
+		res.StateDiff = rt.StateDiff.StateDiff(pre, post)
+	}
+	if rt.VmTrace != nil {
+		res.VmTrace = rt.VmTrace.VmTrace()
+	}
+	return res
+}
+
+// CaptureStart implements the tracer interface to initialize the tracing operation.
+func (rt *ReplayTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	for _, t := range rt.tracers {
+		t.CaptureStart(env, from, to, create, input, gas, value)
+	}
+}
+
+// CaptureState implements the tracer interface to trace a single step of VM execution.
+func (rt *ReplayTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	for _, t := range rt.tracers {
+		t.CaptureState(env, pc, op, gas, cost, scope, rData, depth, err)
+	}
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (rt *ReplayTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	for _, t := range rt.tracers {
+		t.CaptureEnter(typ, from, to, input, gas, value)
+	}
+}
+
+// CaptureExit is called when returning from an inner call
+func (rt *ReplayTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	for _, t := range rt.tracers {
+		t.CaptureExit(output, gasUsed, err)
+	}
+}
+
+// CaptureFault implements the Tracer interface to trace an execution fault
+func (rt *ReplayTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+	for _, t := range rt.tracers {
+		t.CaptureFault(env, pc, op, gas, cost, scope, depth, err)
+	}
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (rt *ReplayTracer) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) {
+	for _, tracer := range rt.tracers {
+		tracer.CaptureEnd(output, gasUsed, t, err)
+	}
+}
@@ -0,0 +1,126 @@
+package txtracer_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+	"github.com/unicornultrafoundation/go-u2u/crypto"
+	"github.com/unicornultrafoundation/go-u2u/evmcore"
+	"github.com/unicornultrafoundation/go-u2u/evmcore/txtracer"
+	"github.com/unicornultrafoundation/go-u2u/params"
+	"github.com/unicornultrafoundation/go-u2u/tests"
+)
+
+func TestReplayTracer(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		from     = crypto.PubkeyToAddress(key.PublicKey)
+		to       = common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+		signer   = types.LatestSignerForChainID(params.TestChainConfig.ChainID)
+		alloc    = core.GenesisAlloc{}
+		contract = []byte{
+			byte(vm.PUSH1), 0x2a,
+			byte(vm.PUSH1), 0x01,
+			byte(vm.SSTORE),
+		}
+	)
+	alloc[to] = core.GenesisAccount{Nonce: 1, Code: contract, Balance: big.NewInt(0)}
+	alloc[from] = core.GenesisAccount{Nonce: 1, Balance: big.NewInt(500000000000000)}
+
+	tx, err := types.SignNewTx(key, signer, &types.LegacyTx{
+		Nonce:    1,
+		GasPrice: big.NewInt(500),
+		Gas:      100000,
+		To:       &to,
+		Value:    big.NewInt(1000),
+	})
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	msg, err := tx.AsMessage(signer, nil)
+	if err != nil {
+		t.Fatalf("failed to prepare transaction: %v", err)
+	}
+	_, statedb := tests.MakePreState(rawdb.NewMemoryDatabase(), alloc, false)
+	pre := statedb.Copy()
+
+	tracer, err := txtracer.NewReplayTracer([]string{txtracer.TraceTypeTrace, txtracer.TraceTypeStateDiff, txtracer.TraceTypeVmTrace})
+	if err != nil {
+		t.Fatalf("failed to create replay tracer: %v", err)
+	}
+	tracer.Trace.SetTx(tx.Hash())
+	tracer.Trace.SetFrom(from)
+	tracer.Trace.SetTo(&to)
+	tracer.Trace.SetValue(*tx.Value())
+	tracer.Trace.SetBlockNumber(big.NewInt(5))
+
+	context := vm.BlockContext{
+		CanTransfer: evmcore.CanTransfer,
+		Transfer:    evmcore.Transfer,
+		BlockNumber: big.NewInt(5),
+		Time:        big.NewInt(5),
+		Difficulty:  big.NewInt(0),
+		GasLimit:    tx.Gas(),
+		BaseFee:     big.NewInt(8),
+	}
+	txContext := vm.TxContext{Origin: from, GasPrice: tx.GasPrice()}
+	evm := vm.NewEVM(context, txContext, statedb, nil, params.TestChainConfig, vm.Config{Debug: true, Tracer: tracer})
+	result, err := evmcore.ApplyMessage(evm, msg, new(evmcore.GasPool).AddGas(tx.Gas()))
+	if err != nil {
+		t.Fatalf("failed to execute transaction: %v", err)
+	}
+	statedb.Finalise(true)
+	tracer.Trace.SetGasUsed(result.UsedGas)
+	res := tracer.Results(result.ReturnData, pre, statedb)
+
+	if len(res.Trace) != 1 || res.Trace[0].TraceType != txtracer.CALL {
+		t.Fatalf("unexpected trace: %+v", res.Trace)
+	}
+
+	// Check the state diff
+	if have := res.StateDiff[to]; have == nil || have.Storage[common.HexToHash("0x01")] == nil {
+		t.Fatalf("missing storage diff of the contract: %+v", have)
+	}
+	blob, err := json.Marshal(res.StateDiff[to].Storage[common.HexToHash("0x01")])
+	if err != nil {
+		t.Fatalf("failed to marshal storage diff: %v", err)
+	}
+	want := `{"*":{"from":"0x0000000000000000000000000000000000000000000000000000000000000000","to":"0x000000000000000000000000000000000000000000000000000000000000002a"}}`
+	if string(blob) != want {
+		t.Fatalf("storage diff mismatch: have %s, want %s", blob, want)
+	}
+	blob, err = json.Marshal(res.StateDiff[to].Nonce)
+	if err != nil {
+		t.Fatalf("failed to marshal nonce diff: %v", err)
+	}
+	if string(blob) != `"="` {
+		t.Fatalf("nonce diff mismatch: have %s, want \"=\"", blob)
+	}
+	if res.StateDiff[from] == nil {
+		t.Fatalf("sender diff is missing")
+	}
+	blob, err = json.Marshal(res.StateDiff[from].Nonce)
+	if err != nil {
+		t.Fatalf("failed to marshal nonce diff: %v", err)
+	}
+	if want := `{"*":{"from":"0x1","to":"0x2"}}`; string(blob) != want {
+		t.Fatalf("sender nonce diff mismatch: have %s, want %s", blob, want)
+	}
+
+	// Check the vm trace
+	if res.VmTrace == nil || len(res.VmTrace.Ops) != 3 {
+		t.Fatalf("unexpected vm trace: %+v", res.VmTrace)
+	}
+	if have := res.VmTrace.Ops[0].Ex.Push; len(have) != 1 || have[0].ToInt().Uint64() != 0x2a {
+		t.Fatalf("unexpected push of the first opcode: %v", have)
+	}
+	if have := res.VmTrace.Ops[2].Ex.Store; have == nil || have.Key.ToInt().Uint64() != 1 || have.Val.ToInt().Uint64() != 0x2a {
+		t.Fatalf("unexpected store of the last opcode: %+v", have)
+	}
+}
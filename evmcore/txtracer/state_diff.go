@@ -0,0 +1,180 @@
+package txtracer
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/common/hexutil"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+)
+
+// diffKind is a kind of change of a single state field
+type diffKind int
+
+const (
+	diffSame diffKind = iota
+	diffBorn
+	diffDied
+	diffChanged
+)
+
+// Diff represents a change of a single state field in the OpenEthereum format:
+// "=" when unchanged, {"+": new} when created, {"-": old} when removed
+// and {"*": {"from": old, "to": new}} when modified.
+type Diff struct {
+	kind diffKind
+	from interface{}
+	to   interface{}
+}
+
+// MarshalJSON marshals the diff as JSON.
+func (d *Diff) MarshalJSON() ([]byte, error) {
+	switch d.kind {
+	case diffBorn:
+		return json.Marshal(map[string]interface{}{"+": d.to})
+	case diffDied:
+		return json.Marshal(map[string]interface{}{"-": d.from})
+	case diffChanged:
+		return json.Marshal(map[string]interface{}{"*": map[string]interface{}{"from": d.from, "to": d.to}})
+	}
+	return json.Marshal("=")
+}
+
+// AccountDiff holds the changes of a single account made by a transaction
+type AccountDiff struct {
+	Balance *Diff                 `json:"balance"`
+	Code    *Diff                 `json:"code"`
+	Nonce   *Diff                 `json:"nonce"`
+	Storage map[common.Hash]*Diff `json:"storage"`
+}
+
+// StateDiff holds the changes of all the accounts modified by a transaction
+type StateDiff map[common.Address]*AccountDiff
+
+// StateDiffLogger collects accounts and storage slots touched by a transaction,
+// so that their values before and after the transaction can be compared
+type StateDiffLogger struct {
+	accounts map[common.Address]map[common.Hash]struct{}
+}
+
+// NewStateDiffLogger creates new instance of state diff collector
+func NewStateDiffLogger() *StateDiffLogger {
+	return &StateDiffLogger{
+		accounts: make(map[common.Address]map[common.Hash]struct{}),
+	}
+}
+
+// Touch marks an account as possibly modified by the transaction
+func (l *StateDiffLogger) Touch(addr common.Address) {
+	if _, ok := l.accounts[addr]; !ok {
+		l.accounts[addr] = make(map[common.Hash]struct{})
+	}
+}
+
+// touchSlot marks a storage slot as possibly modified by the transaction
+func (l *StateDiffLogger) touchSlot(addr common.Address, slot common.Hash) {
+	l.Touch(addr)
+	l.accounts[addr][slot] = struct{}{}
+}
+
+// CaptureStart implements the tracer interface to initialize the tracing operation.
+func (l *StateDiffLogger) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	l.Touch(from)
+	l.Touch(to)
+}
+
+// CaptureState records storage slots written by the executed opcodes
+func (l *StateDiffLogger) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if err != nil || op != vm.SSTORE || len(scope.Stack.Data()) < 1 {
+		return
+	}
+	l.touchSlot(scope.Contract.Address(), common.Hash(scope.Stack.Back(0).Bytes32()))
+}
+
+// CaptureEnter records accounts entered by inner calls, creations and self-destructs
+func (l *StateDiffLogger) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	l.Touch(from)
+	l.Touch(to)
+}
+
+// CaptureExit is called when returning from an inner call
+func (l *StateDiffLogger) CaptureExit(output []byte, gasUsed uint64, err error) {}
+
+// CaptureFault implements the Tracer interface to trace an execution fault
+func (l *StateDiffLogger) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (l *StateDiffLogger) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) {}
+
+// StateDiff compares the touched accounts between the state before and after
+// the transaction. The post state is expected to be finalised.
+func (l *StateDiffLogger) StateDiff(pre, post vm.StateDB) StateDiff {
+	diff := make(StateDiff)
+	for addr, slots := range l.accounts {
+		preExist, postExist := pre.Exist(addr) && !pre.Empty(addr), post.Exist(addr) && !post.Empty(addr)
+		switch {
+		case !preExist && !postExist:
+			continue
+		case !preExist:
+			account := &AccountDiff{
+				Balance: &Diff{kind: diffBorn, to: (*hexutil.Big)(post.GetBalance(addr))},
+				Code:    &Diff{kind: diffBorn, to: hexutil.Bytes(post.GetCode(addr))},
+				Nonce:   &Diff{kind: diffBorn, to: hexutil.Uint64(post.GetNonce(addr))},
+				Storage: make(map[common.Hash]*Diff),
+			}
+			for slot := range slots {
+				if val := post.GetState(addr, slot); val != (common.Hash{}) {
+					account.Storage[slot] = &Diff{kind: diffBorn, to: val}
+				}
+			}
+			diff[addr] = account
+		case !postExist:
+			account := &AccountDiff{
+				Balance: &Diff{kind: diffDied, from: (*hexutil.Big)(pre.GetBalance(addr))},
+				Code:    &Diff{kind: diffDied, from: hexutil.Bytes(pre.GetCode(addr))},
+				Nonce:   &Diff{kind: diffDied, from: hexutil.Uint64(pre.GetNonce(addr))},
+				Storage: make(map[common.Hash]*Diff),
+			}
+			for slot := range slots {
+				if val := pre.GetState(addr, slot); val != (common.Hash{}) {
+					account.Storage[slot] = &Diff{kind: diffDied, from: val}
+				}
+			}
+			diff[addr] = account
+		default:
+			account := &AccountDiff{
+				Balance: &Diff{kind: diffSame},
+				Code:    &Diff{kind: diffSame},
+				Nonce:   &Diff{kind: diffSame},
+				Storage: make(map[common.Hash]*Diff),
+			}
+			changed := false
+			if from, to := pre.GetBalance(addr), post.GetBalance(addr); from.Cmp(to) != 0 {
+				account.Balance = &Diff{kind: diffChanged, from: (*hexutil.Big)(from), to: (*hexutil.Big)(to)}
+				changed = true
+			}
+			if from, to := pre.GetCode(addr), post.GetCode(addr); !bytes.Equal(from, to) {
+				account.Code = &Diff{kind: diffChanged, from: hexutil.Bytes(from), to: hexutil.Bytes(to)}
+				changed = true
+			}
+			if from, to := pre.GetNonce(addr), post.GetNonce(addr); from != to {
+				account.Nonce = &Diff{kind: diffChanged, from: hexutil.Uint64(from), to: hexutil.Uint64(to)}
+				changed = true
+			}
+			for slot := range slots {
+				if from, to := pre.GetState(addr, slot), post.GetState(addr, slot); from != to {
+					account.Storage[slot] = &Diff{kind: diffChanged, from: from, to: to}
+					changed = true
+				}
+			}
+			if changed {
+				diff[addr] = account
+			}
+		}
+	}
+	return diff
+}
@@ -0,0 +1,251 @@
+package txtracer
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/common/hexutil"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+)
+
+// VmTrace is an OpenEthereum-style trace of the executed opcodes of a single
+// call frame
+type VmTrace struct {
+	Code hexutil.Bytes `json:"code"`
+	Ops  []*VmTraceOp  `json:"ops"`
+}
+
+// VmTraceOp represents a single executed opcode
+type VmTraceOp struct {
+	Cost uint64     `json:"cost"`
+	Ex   *VmTraceEx `json:"ex"`
+	Pc   uint64     `json:"pc"`
+	Sub  *VmTrace   `json:"sub"`
+}
+
+// VmTraceEx holds the effects of an executed opcode
+type VmTraceEx struct {
+	Mem   *VmTraceMem    `json:"mem"`
+	Push  []*hexutil.Big `json:"push"`
+	Store *VmTraceStore  `json:"store"`
+	Used  uint64         `json:"used"`
+}
+
+// VmTraceMem represents a memory region written by an opcode
+type VmTraceMem struct {
+	Data hexutil.Bytes `json:"data"`
+	Off  uint64        `json:"off"`
+}
+
+// VmTraceStore represents a storage slot written by an opcode
+type VmTraceStore struct {
+	Key *hexutil.Big `json:"key"`
+	Val *hexutil.Big `json:"val"`
+}
+
+// vmTraceFrame is the state of processing of a single call frame
+type vmTraceFrame struct {
+	trace *VmTrace
+
+	// the last opcode, which effects aren't known yet
+	pending     *VmTraceOp
+	pendingGas  uint64
+	pendingPush int
+	memOff      uint64
+	memSize     uint64
+}
+
+// VmTraceLogger is a transaction vmTrace creator
+type VmTraceLogger struct {
+	root         *VmTrace
+	frames       []*vmTraceFrame
+	selfdestruct bool
+}
+
+// NewVmTraceLogger creates new instance of vmTrace creator
+func NewVmTraceLogger() *VmTraceLogger {
+	return &VmTraceLogger{}
+}
+
+// VmTrace returns the recorded trace of the executed opcodes
+func (l *VmTraceLogger) VmTrace() *VmTrace {
+	return l.root
+}
+
+// CaptureStart implements the tracer interface to initialize the tracing operation.
+func (l *VmTraceLogger) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	l.root = &VmTrace{Code: []byte{}, Ops: make([]*VmTraceOp, 0)}
+	l.frames = []*vmTraceFrame{{trace: l.root}}
+}
+
+// CaptureState records the executed opcode and the effects of the previous one
+func (l *VmTraceLogger) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if len(l.frames) == 0 {
+		return
+	}
+	frame := l.frames[len(l.frames)-1]
+	if frame.pending != nil {
+		frame.finish(scope, gas)
+	}
+	// The implicit STOP past the end of the code isn't reported
+	if err != nil || pc >= uint64(len(scope.Contract.Code)) {
+		return
+	}
+	if len(frame.trace.Ops) == 0 {
+		frame.trace.Code = common.CopyBytes(scope.Contract.Code)
+	}
+	frame.pending = &VmTraceOp{Cost: cost, Pc: pc}
+	frame.pendingGas = gas
+	frame.pendingPush = stackPushes(op)
+	frame.memOff, frame.memSize = memoryWritten(op, scope.Stack)
+	frame.trace.Ops = append(frame.trace.Ops, frame.pending)
+
+	if op == vm.SSTORE && len(scope.Stack.Data()) >= 2 {
+		frame.pending.Ex = &VmTraceEx{
+			Store: &VmTraceStore{
+				Key: (*hexutil.Big)(scope.Stack.Back(0).ToBig()),
+				Val: (*hexutil.Big)(scope.Stack.Back(1).ToBig()),
+			},
+		}
+	}
+}
+
+// CaptureEnter opens a sub-trace for the opcode which caused the call
+func (l *VmTraceLogger) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if typ == vm.SELFDESTRUCT {
+		l.selfdestruct = true
+		return
+	}
+	if len(l.frames) == 0 {
+		return
+	}
+	sub := &VmTrace{Code: []byte{}, Ops: make([]*VmTraceOp, 0)}
+	if parent := l.frames[len(l.frames)-1]; parent.pending != nil {
+		parent.pending.Sub = sub
+	}
+	l.frames = append(l.frames, &vmTraceFrame{trace: sub})
+}
+
+// CaptureExit closes the sub-trace of an inner call
+func (l *VmTraceLogger) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if l.selfdestruct {
+		l.selfdestruct = false
+		return
+	}
+	if len(l.frames) <= 1 {
+		return
+	}
+	l.frames[len(l.frames)-1].finish(nil, 0)
+	l.frames = l.frames[:len(l.frames)-1]
+}
+
+// CaptureFault finishes the opcode which failed during its execution
+func (l *VmTraceLogger) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+	if len(l.frames) == 0 {
+		return
+	}
+	l.frames[len(l.frames)-1].finish(nil, 0)
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (l *VmTraceLogger) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) {
+	if len(l.frames) == 0 {
+		return
+	}
+	l.frames[0].finish(nil, 0)
+	l.frames = nil
+}
+
+// finish fills in the effects of the pending opcode. If the scope is nil,
+// the opcode was the last one in the frame and has no visible effects.
+func (f *vmTraceFrame) finish(scope *vm.ScopeContext, gas uint64) {
+	op := f.pending
+	if op == nil {
+		return
+	}
+	f.pending = nil
+	if op.Ex == nil {
+		op.Ex = &VmTraceEx{}
+	}
+	op.Ex.Push = make([]*hexutil.Big, 0)
+	if scope == nil {
+		if f.pendingGas >= op.Cost {
+			op.Ex.Used = f.pendingGas - op.Cost
+		}
+		return
+	}
+	op.Ex.Used = gas
+
+	stack := scope.Stack.Data()
+	for i := f.pendingPush; i > 0 && i <= len(stack); i-- {
+		op.Ex.Push = append(op.Ex.Push, (*hexutil.Big)(stack[len(stack)-i].ToBig()))
+	}
+	if f.memSize > 0 {
+		mem := scope.Memory.Data()
+		if f.memOff < uint64(len(mem)) {
+			end := f.memOff + f.memSize
+			if end > uint64(len(mem)) || end < f.memOff {
+				end = uint64(len(mem))
+			}
+			op.Ex.Mem = &VmTraceMem{
+				Data: common.CopyBytes(mem[f.memOff:end]),
+				Off:  f.memOff,
+			}
+		}
+	}
+}
+
+// stackPushes returns the number of the stack items, which are reported
+// as pushed by the opcode
+func stackPushes(op vm.OpCode) int {
+	switch {
+	case op >= vm.PUSH0 && op <= vm.PUSH32:
+		return 1
+	case op >= vm.DUP1 && op <= vm.DUP16:
+		return int(op-vm.DUP1) + 2
+	case op >= vm.SWAP1 && op <= vm.SWAP16:
+		return int(op-vm.SWAP1) + 2
+	case op >= vm.LOG0 && op <= vm.LOG4:
+		return 0
+	}
+	switch op {
+	case vm.STOP, vm.POP, vm.JUMP, vm.JUMPI, vm.JUMPDEST,
+		vm.MSTORE, vm.MSTORE8, vm.SSTORE, vm.TSTORE, vm.MCOPY,
+		vm.CALLDATACOPY, vm.CODECOPY, vm.EXTCODECOPY, vm.RETURNDATACOPY,
+		vm.RETURN, vm.REVERT, vm.SELFDESTRUCT:
+		return 0
+	}
+	return 1
+}
+
+// memoryWritten returns the memory region written by the opcode
+func memoryWritten(op vm.OpCode, stack *vm.Stack) (offset uint64, size uint64) {
+	var offPos, sizePos int
+	switch op {
+	case vm.MSTORE:
+		offPos, sizePos = 0, -1
+		size = 32
+	case vm.MSTORE8:
+		offPos, sizePos = 0, -1
+		size = 1
+	case vm.CALLDATACOPY, vm.CODECOPY, vm.RETURNDATACOPY, vm.MCOPY:
+		offPos, sizePos = 0, 2
+	case vm.EXTCODECOPY:
+		offPos, sizePos = 1, 3
+	case vm.CALL, vm.CALLCODE:
+		offPos, sizePos = 5, 6
+	case vm.DELEGATECALL, vm.STATICCALL:
+		offPos, sizePos = 4, 5
+	default:
+		return 0, 0
+	}
+	data := stack.Data()
+	if len(data) <= offPos || len(data) <= sizePos {
+		return 0, 0
+	}
+	if sizePos >= 0 {
+		size = stack.Back(sizePos).Uint64()
+	}
+	return stack.Back(offPos).Uint64(), size
+}
@@ -15,6 +15,7 @@ require (
 	github.com/evalphobia/logrus_sentry v0.8.2
 	github.com/golang/mock v1.6.0
 	github.com/google/gofuzz v1.2.0
+	github.com/graph-gophers/graphql-go v1.3.0
 	github.com/hashicorp/golang-lru v1.0.2
 	github.com/holiman/bloomfilter/v2 v2.0.3
 	github.com/holiman/uint256 v1.2.4
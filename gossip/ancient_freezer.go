@@ -0,0 +1,227 @@
+package gossip
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/unicornultrafoundation/go-helios/common/bigendian"
+	"github.com/unicornultrafoundation/go-helios/hash"
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/native"
+	"github.com/unicornultrafoundation/go-u2u/rlp"
+)
+
+// ancientFreezer periodically moves the finalized events, blocks and receipts,
+// which are older than the configured number of epochs, into the ancient store
+type ancientFreezer struct {
+	store *Store
+	cfg   AncientConfig
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+func newAncientFreezer(store *Store, cfg AncientConfig) *ancientFreezer {
+	return &ancientFreezer{
+		store: store,
+		cfg:   cfg,
+		quit:  make(chan struct{}),
+	}
+}
+
+// enabled returns true if the ancient store is opened and the number of kept epochs is limited
+func (f *ancientFreezer) enabled() bool {
+	return f.store.HasAncient() && f.cfg.Epochs != 0
+}
+
+func (f *ancientFreezer) Start() {
+	if !f.enabled() {
+		return
+	}
+	f.wg.Add(1)
+	go f.loop()
+}
+
+func (f *ancientFreezer) Stop() {
+	if !f.enabled() {
+		return
+	}
+	close(f.quit)
+	f.wg.Wait()
+}
+
+func (f *ancientFreezer) loop() {
+	defer f.wg.Done()
+	period := f.cfg.FreezePeriod
+	if period <= 0 {
+		period = time.Minute
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			f.store.freezeAncient(f.cfg, f.quit)
+		case <-f.quit:
+			return
+		}
+	}
+}
+
+// freezeAncient moves the events and blocks of the epochs below the retention window,
+// along with the blocks receipts, into the ancient store. At most MaxItemsPerRound
+// events and blocks are moved at once.
+func (s *Store) freezeAncient(cfg AncientConfig, quit <-chan struct{}) {
+	s.mutex.History.Lock()
+	defer s.mutex.History.Unlock()
+
+	epoch := s.GetEpoch()
+	if cfg.Epochs == 0 || epoch <= cfg.Epochs {
+		return
+	}
+	limit := epoch - cfg.Epochs
+	start := time.Now()
+
+	events, err := s.freezeAncientEvents(limit, cfg.MaxItemsPerRound, quit)
+	if err != nil {
+		s.Log.Warn("Failed to freeze ancient events", "err", err)
+		return
+	}
+	blocks, err := s.freezeAncientBlocks(limit, cfg.MaxItemsPerRound, quit)
+	if err != nil {
+		s.Log.Warn("Failed to freeze ancient blocks", "err", err)
+		return
+	}
+	if events != 0 || blocks != 0 {
+		s.Log.Info("Froze ancient data", "epoch", limit, "events", events, "blocks", blocks, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+}
+
+// freezeAncientEvents moves the events of the epochs up to the limit epoch into the ancient store
+func (s *Store) freezeAncientEvents(limit idx.Epoch, max int, quit <-chan struct{}) (int, error) {
+	first := s.ancient.Events.Items()
+	frozen := make(hash.Events, 0, 1024)
+
+	it := s.table.Events.NewIterator(nil, nil)
+	for it.Next() && (max == 0 || len(frozen) < max) {
+		id := hash.BytesToEvent(it.Key())
+		if id.Epoch() > limit || isClosed(quit) {
+			break
+		}
+		err := s.ancient.Events.Append(first+uint64(len(frozen)), map[string][]byte{ancientEventsTable: it.Value()})
+		if err != nil {
+			it.Release()
+			return 0, err
+		}
+		frozen = append(frozen, id)
+	}
+	it.Release()
+	if len(frozen) == 0 {
+		return 0, nil
+	}
+	if err := s.ancient.Events.Sync(); err != nil {
+		return 0, err
+	}
+
+	// the counter goes first, so the frozen events are never lost on a partial flush
+	s.setAncientCounter(ancientEventsKey, s.ancient.Events.Items())
+	for i, id := range frozen {
+		if err := s.table.AncientEvents.Put(id.Bytes(), bigendian.Uint64ToBytes(first+uint64(i))); err != nil {
+			s.Log.Crit("Failed to put key-value", "err", err)
+		}
+	}
+	for _, id := range frozen {
+		if err := s.table.Events.Delete(id.Bytes()); err != nil {
+			s.Log.Crit("Failed to delete key", "err", err)
+		}
+	}
+	return len(frozen), nil
+}
+
+// freezeAncientBlocks moves the blocks of the epochs up to the limit epoch, along
+// with the blocks receipts, into the ancient store
+func (s *Store) freezeAncientBlocks(limit idx.Epoch, max int, quit <-chan struct{}) (int, error) {
+	var (
+		latest   = s.GetLatestBlockIndex()
+		items    = s.ancient.Blocks.Items()
+		first    = idx.Block(atomic.LoadUint64(&s.ancient.FirstBlock))
+		from, to idx.Block
+		count    int
+	)
+	it := s.table.Blocks.NewIterator(nil, nil)
+	for it.Next() && (max == 0 || count < max) {
+		n := idx.BytesToBlock(it.Key())
+		if n > latest || isClosed(quit) {
+			break
+		}
+		block := native.Block{}
+		if err := rlp.DecodeBytes(it.Value(), &block); err != nil {
+			s.Log.Crit("Failed to decode block", "err", err)
+		}
+		if block.Atropos.Epoch() > limit {
+			break
+		}
+		if items == 0 && count == 0 {
+			// the ancient blocks start from the first block in the key-value database
+			first = n
+			atomic.StoreUint64(&s.ancient.FirstBlock, uint64(first))
+		}
+		if n != first+idx.Block(items)+idx.Block(count) {
+			it.Release()
+			s.rollbackAncientBlocks(items)
+			return 0, fmt.Errorf("block %d doesn't follow the last frozen block %d", n, first+idx.Block(items)+idx.Block(count)-1)
+		}
+		if err := s.ancient.Blocks.Append(uint64(n-first), map[string][]byte{ancientBlocksTable: it.Value()}); err != nil {
+			it.Release()
+			s.rollbackAncientBlocks(items)
+			return 0, err
+		}
+		if count == 0 {
+			from = n
+		}
+		to = n
+		count++
+	}
+	it.Release()
+	if count == 0 {
+		return 0, nil
+	}
+	if err := s.ancient.Blocks.Sync(); err != nil {
+		s.rollbackAncientBlocks(items)
+		return 0, err
+	}
+	if err := s.evm.FreezeReceipts(from, to); err != nil {
+		s.rollbackAncientBlocks(items)
+		return 0, err
+	}
+
+	// the counters go first, so the frozen blocks are never lost on a partial flush
+	s.setAncientCounter(ancientFirstBlockKey, uint64(first))
+	s.setAncientCounter(ancientBlocksKey, s.ancient.Blocks.Items())
+	for n := from; n <= to; n++ {
+		if err := s.table.Blocks.Delete(n.Bytes()); err != nil {
+			s.Log.Crit("Failed to delete key", "err", err)
+		}
+	}
+	return count, nil
+}
+
+// rollbackAncientBlocks discards the ancient blocks appended during a failed migration
+func (s *Store) rollbackAncientBlocks(items uint64) {
+	if err := s.ancient.Blocks.Truncate(items); err != nil {
+		s.Log.Crit("Failed to truncate ancient blocks", "err", err)
+	}
+}
+
+func isClosed(quit <-chan struct{}) bool {
+	select {
+	case <-quit:
+		return true
+	default:
+		return false
+	}
+}
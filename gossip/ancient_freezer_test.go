@@ -0,0 +1,114 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unicornultrafoundation/go-helios/hash"
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+	"github.com/unicornultrafoundation/go-helios/u2udb/flushable"
+	"github.com/unicornultrafoundation/go-helios/u2udb/memorydb"
+
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/native"
+	"github.com/unicornultrafoundation/go-u2u/native/iblockproc"
+	"github.com/unicornultrafoundation/go-u2u/rlp"
+)
+
+func TestStoreFreezeAncient(t *testing.T) {
+	require := require.New(t)
+
+	dbs := flushable.NewSyncedPool(memorydb.NewProducer(""), []byte{0})
+	cfg := LiteStoreConfig()
+	cfg.Ancient.Dir = t.TempDir()
+	store := NewStore(dbs, cfg)
+	require.True(store.HasAncient())
+
+	// two events and a block with a receipt per epoch
+	var events hash.Events
+	for epoch := idx.Epoch(1); epoch <= 4; epoch++ {
+		for lamport := idx.Lamport(1); lamport <= 2; lamport++ {
+			e := &native.MutableEventPayload{}
+			e.SetVersion(1)
+			e.SetEpoch(epoch)
+			e.SetLamport(lamport)
+			e.SetCreator(1)
+			e.SetExtra([]byte{byte(epoch), byte(lamport)})
+			e.SetPayloadHash(native.CalcPayloadHash(e))
+			event := e.Build()
+			store.SetEvent(event)
+			events = append(events, event.ID())
+		}
+		n := idx.Block(epoch)
+		store.SetBlock(n, &native.Block{Atropos: events[len(events)-1], GasUsed: uint64(n)})
+		store.evm.SetRawReceipts(n, []*types.ReceiptForStorage{{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: uint64(n), Logs: []*types.Log{}}})
+	}
+	store.SetBlockEpochState(iblockproc.BlockState{LastBlock: iblockproc.BlockCtx{Idx: 4}}, iblockproc.EpochState{Epoch: 5})
+	store.setLlrState(LlrState{})
+
+	// the migration is limited by the number of items per round
+	ancient := AncientConfig{Epochs: 2, MaxItemsPerRound: 3}
+	store.freezeAncient(ancient, nil)
+	require.Equal(uint64(3), store.ancient.Events.Items())
+	require.Equal(uint64(3), store.ancient.Blocks.Items())
+	store.freezeAncient(ancient, nil)
+	require.Equal(uint64(6), store.ancient.Events.Items())
+	require.Equal(uint64(3), store.ancient.Blocks.Items())
+	store.freezeAncient(ancient, nil)
+	require.Equal(uint64(6), store.ancient.Events.Items())
+
+	check := func(store *Store) {
+		for i, id := range events {
+			frozen := id.Epoch() <= 3
+			has, _ := store.table.Events.Has(id.Bytes())
+			require.Equal(!frozen, has, "event %d", i)
+			require.True(store.HasEvent(id))
+			require.Equal(id, store.GetEventPayload(id).ID())
+			require.Equal(id, store.GetEvent(id).ID())
+			require.NotNil(store.GetEventPayloadRLP(id))
+		}
+		for n := idx.Block(1); n <= 4; n++ {
+			has, _ := store.table.Blocks.Has(n.Bytes())
+			require.Equal(n == 4, has, "block %d", n)
+			require.True(store.HasBlock(n))
+			require.Equal(uint64(n), store.GetBlock(n).GasUsed)
+			receipts, _ := store.evm.GetRawReceipts(n)
+			require.Len(receipts, 1)
+			require.Equal(uint64(n), receipts[0].CumulativeGasUsed)
+		}
+		require.Len(store.FindEventHashes(2, 1, nil), 1)
+
+		// the frozen data is iterated before the data in the key-value database
+		var iterated hash.Events
+		store.ForEachEventRLP(nil, func(id hash.Event, _ rlp.RawValue) bool {
+			iterated = append(iterated, id)
+			return true
+		})
+		require.Equal(events, iterated)
+		var epochEvents hash.Events
+		store.ForEachEpochEvent(3, func(event *native.EventPayload) bool {
+			epochEvents = append(epochEvents, event.ID())
+			return true
+		})
+		require.Equal(events[4:6], epochEvents)
+		var blocks []idx.Block
+		store.IterateFullBlockRecordsRLP(2, func(n idx.Block, _ rlp.RawValue) bool {
+			blocks = append(blocks, n)
+			return true
+		})
+		require.Equal([]idx.Block{2, 3, 4}, blocks)
+	}
+	check(store)
+
+	// the ancient data is readable after the restart
+	require.NoError(store.Commit())
+	require.NoError(store.Close())
+	store = NewStore(dbs, cfg)
+	defer store.Close()
+	check(store)
+
+	// the deleted events are no longer reachable
+	store.DelEvent(events[0])
+	require.False(store.HasEvent(events[0]))
+	require.Nil(store.GetEventPayloadRLP(events[0]))
+}
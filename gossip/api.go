@@ -1,8 +1,14 @@
 package gossip
 
 import (
+	"errors"
+
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+
 	"github.com/unicornultrafoundation/go-u2u/common"
 	"github.com/unicornultrafoundation/go-u2u/common/hexutil"
+	"github.com/unicornultrafoundation/go-u2u/evmcore"
+	"github.com/unicornultrafoundation/go-u2u/rpc"
 )
 
 // PublicEthereumAPI provides an API to access Ethereum-like information.
@@ -35,3 +41,67 @@ func (api *PublicEthereumAPI) Hashrate() hexutil.Uint64 {
 func (api *PublicEthereumAPI) ChainId() hexutil.Uint64 {
 	return hexutil.Uint64(api.s.store.GetRules().NetworkID)
 }
+
+// maxSfcDivergences is the limit of records returned by a single debug_sfcDivergences call
+const maxSfcDivergences = 1000
+
+// PublicDebugAPI provides the debug methods of the gossip service.
+type PublicDebugAPI struct {
+	s *Service
+}
+
+// NewPublicDebugAPI creates a new debug API for gossip.
+func NewPublicDebugAPI(s *Service) *PublicDebugAPI {
+	return &PublicDebugAPI{s}
+}
+
+// RPCSfcSlotDiff is a storage slot which differs in the main state and in the SFC state
+type RPCSfcSlotDiff struct {
+	Key      common.Hash `json:"key"`
+	Value    common.Hash `json:"value"`
+	SfcValue common.Hash `json:"sfcValue"`
+}
+
+// RPCSfcDivergence is a recorded divergence of the SFC state from the main state
+type RPCSfcDivergence struct {
+	BlockNumber hexutil.Uint64   `json:"blockNumber"`
+	TxHash      common.Hash      `json:"transactionHash"`
+	Address     common.Address   `json:"address"`
+	Slots       []RPCSfcSlotDiff `json:"slots"`
+}
+
+// SfcDivergences returns the recorded divergences of the SFC state from the main
+// state in the given range of blocks. At most 1000 records are returned, the
+// rest may be requested starting from the block of the last record.
+func (api *PublicDebugAPI) SfcDivergences(from rpc.BlockNumber, to rpc.BlockNumber) ([]*RPCSfcDivergence, error) {
+	latest := api.s.store.GetLatestBlockIndex()
+	resolve := func(n rpc.BlockNumber) idx.Block {
+		if n < 0 || idx.Block(n) > latest {
+			return latest
+		}
+		return idx.Block(n)
+	}
+	fromBlock, toBlock := resolve(from), resolve(to)
+	if fromBlock > toBlock {
+		return nil, errors.New("invalid block range")
+	}
+
+	res := make([]*RPCSfcDivergence, 0)
+	api.s.store.evm.ForEachSfcDivergence(fromBlock, func(d *evmcore.SfcDivergence) bool {
+		if idx.Block(d.BlockNumber) > toBlock || len(res) >= maxSfcDivergences {
+			return false
+		}
+		slots := make([]RPCSfcSlotDiff, len(d.Slots))
+		for i, slot := range d.Slots {
+			slots[i] = RPCSfcSlotDiff(slot)
+		}
+		res = append(res, &RPCSfcDivergence{
+			BlockNumber: hexutil.Uint64(d.BlockNumber),
+			TxHash:      d.TxHash,
+			Address:     d.Address,
+			Slots:       slots,
+		})
+		return true
+	})
+	return res, nil
+}
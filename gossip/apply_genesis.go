@@ -6,6 +6,8 @@ import (
 	"github.com/unicornultrafoundation/go-helios/hash"
 	"github.com/unicornultrafoundation/go-helios/u2udb/batched"
 
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
 	"github.com/unicornultrafoundation/go-u2u/native/iblockproc"
 	"github.com/unicornultrafoundation/go-u2u/native/ibr"
 	"github.com/unicornultrafoundation/go-u2u/native/ier"
@@ -57,6 +59,13 @@ func (s *Store) ApplyGenesis(g genesis.Genesis) (genesisHash hash.Hash, err erro
 	if err != nil {
 		return genesisHash, err
 	}
+	if s.evm.StateScheme() == rawdb.PathScheme {
+		// the genesis state is written by the hash scheme
+		err = s.evm.MigrateStateScheme(common.Hash(topEr.BlockState.FinalizedStateRoot), func() error { return nil })
+		if err != nil {
+			return genesisHash, err
+		}
+	}
 
 	// write LLR state
 	s.setLlrState(LlrState{
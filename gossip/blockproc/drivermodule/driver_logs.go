@@ -0,0 +1,66 @@
+package drivermodule
+
+import (
+	"io"
+	"math/big"
+
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/driver"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/driver/driverpos"
+)
+
+// DriverEvent is a decoded NodeDriver event, which the node reacts to.
+// Only the fields of the event Topic are set.
+type DriverEvent struct {
+	Topic       common.Hash
+	ValidatorID idx.ValidatorID
+	Weight      *big.Int
+	PubKey      []byte
+	RulesDiff   []byte
+	// EpochsNum is the number of epochs to advance, it's < 2^24 to avoid overflow
+	EpochsNum idx.Epoch
+}
+
+// DecodeDriverLog decodes a log of the NodeDriver contract. The NodeDriver bytecode
+// and the native NodeDriver precompile emit the same logs, so the log may come from
+// either of them. ok is false for the logs of other contracts and for the events
+// which aren't handled by the node.
+func DecodeDriverLog(l *types.Log) (ev DriverEvent, ok bool, err error) {
+	if l.Address != driver.ContractAddress || len(l.Topics) == 0 {
+		return ev, false, nil
+	}
+	ev.Topic = l.Topics[0]
+	switch {
+	case ev.Topic == driverpos.Topics.UpdateValidatorWeight && len(l.Topics) > 1 && len(l.Data) >= 32:
+		ev.ValidatorID = idx.ValidatorID(new(big.Int).SetBytes(l.Topics[1][:]).Uint64())
+		ev.Weight = new(big.Int).SetBytes(l.Data[0:32])
+	case ev.Topic == driverpos.Topics.UpdateValidatorPubkey && len(l.Topics) > 1:
+		ev.ValidatorID = idx.ValidatorID(new(big.Int).SetBytes(l.Topics[1][:]).Uint64())
+		ev.PubKey, err = decodeDataBytes(l)
+	case ev.Topic == driverpos.Topics.UpdateNetworkRules && len(l.Data) >= 64:
+		ev.RulesDiff, err = decodeDataBytes(l)
+	case ev.Topic == driverpos.Topics.AdvanceEpochs && len(l.Data) >= 32:
+		ev.EpochsNum = idx.Epoch(new(big.Int).SetBytes(l.Data[29:32]).Uint64())
+	default:
+		return ev, false, nil
+	}
+	return ev, true, err
+}
+
+func decodeDataBytes(l *types.Log) ([]byte, error) {
+	if len(l.Data) < 32 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	start := new(big.Int).SetBytes(l.Data[24:32]).Uint64()
+	if start+32 > uint64(len(l.Data)) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	size := new(big.Int).SetBytes(l.Data[start+24 : start+32]).Uint64()
+	if start+32+size > uint64(len(l.Data)) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return l.Data[start+32 : start+32+size], nil
+}
@@ -1,7 +1,6 @@
 package drivermodule
 
 import (
-	"io"
 	"math"
 	"math/big"
 
@@ -146,64 +145,43 @@ func (p *DriverTxListener) OnNewReceipt(tx *types.Transaction, r *types.Receipt,
 	}
 }
 
-func decodeDataBytes(l *types.Log) ([]byte, error) {
-	if len(l.Data) < 32 {
-		return nil, io.ErrUnexpectedEOF
-	}
-	start := new(big.Int).SetBytes(l.Data[24:32]).Uint64()
-	if start+32 > uint64(len(l.Data)) {
-		return nil, io.ErrUnexpectedEOF
-	}
-	size := new(big.Int).SetBytes(l.Data[start+24 : start+32]).Uint64()
-	if start+32+size > uint64(len(l.Data)) {
-		return nil, io.ErrUnexpectedEOF
-	}
-	return l.Data[start+32 : start+32+size], nil
-}
-
 func (p *DriverTxListener) OnNewLog(l *types.Log) {
-	if l.Address != driver.ContractAddress {
+	ev, ok, err := DecodeDriverLog(l)
+	if !ok {
 		return
 	}
+	switch ev.Topic {
 	// Track validator weight changes
-	if l.Topics[0] == driverpos.Topics.UpdateValidatorWeight && len(l.Topics) > 1 && len(l.Data) >= 32 {
-		validatorID := idx.ValidatorID(new(big.Int).SetBytes(l.Topics[1][:]).Uint64())
-		weight := new(big.Int).SetBytes(l.Data[0:32])
-
-		if weight.Sign() == 0 {
-			delete(p.bs.NextValidatorProfiles, validatorID)
+	case driverpos.Topics.UpdateValidatorWeight:
+		if ev.Weight.Sign() == 0 {
+			delete(p.bs.NextValidatorProfiles, ev.ValidatorID)
 		} else {
-			profile, ok := p.bs.NextValidatorProfiles[validatorID]
+			profile, ok := p.bs.NextValidatorProfiles[ev.ValidatorID]
 			if !ok {
 				profile.PubKey = validatorpk.PubKey{
 					Type: 0,
 					Raw:  []byte{},
 				}
 			}
-			profile.Weight = weight
-			p.bs.NextValidatorProfiles[validatorID] = profile
+			profile.Weight = ev.Weight
+			p.bs.NextValidatorProfiles[ev.ValidatorID] = profile
 		}
-	}
 	// Track validator pubkey changes
-	if l.Topics[0] == driverpos.Topics.UpdateValidatorPubkey && len(l.Topics) > 1 {
-		validatorID := idx.ValidatorID(new(big.Int).SetBytes(l.Topics[1][:]).Uint64())
-		pubkey, err := decodeDataBytes(l)
+	case driverpos.Topics.UpdateValidatorPubkey:
 		if err != nil {
 			log.Warn("Malformed UpdatedValidatorPubkey Driver event")
 			return
 		}
 
-		profile, ok := p.bs.NextValidatorProfiles[validatorID]
+		profile, ok := p.bs.NextValidatorProfiles[ev.ValidatorID]
 		if !ok {
 			log.Warn("Unexpected UpdatedValidatorPubkey Driver event")
 			return
 		}
-		profile.PubKey, _ = validatorpk.FromBytes(pubkey)
-		p.bs.NextValidatorProfiles[validatorID] = profile
-	}
+		profile.PubKey, _ = validatorpk.FromBytes(ev.PubKey)
+		p.bs.NextValidatorProfiles[ev.ValidatorID] = profile
 	// Update rules
-	if l.Topics[0] == driverpos.Topics.UpdateNetworkRules && len(l.Data) >= 64 {
-		diff, err := decodeDataBytes(l)
+	case driverpos.Topics.UpdateNetworkRules:
 		if err != nil {
 			log.Warn("Malformed UpdateNetworkRules Driver event")
 			return
@@ -213,19 +191,15 @@ func (p *DriverTxListener) OnNewLog(l *types.Log) {
 		if p.bs.DirtyRules != nil {
 			last = p.bs.DirtyRules
 		}
-		updated, err := u2u.UpdateRules(*last, diff)
+		updated, err := u2u.UpdateRules(*last, ev.RulesDiff)
 		if err != nil {
 			log.Warn("Network rules update error", "err", err)
 			return
 		}
 		p.bs.DirtyRules = &updated
-	}
 	// Advance epochs
-	if l.Topics[0] == driverpos.Topics.AdvanceEpochs && len(l.Data) >= 32 {
-		// epochsNum < 2^24 to avoid overflow
-		epochsNum := new(big.Int).SetBytes(l.Data[29:32]).Uint64()
-
-		p.bs.AdvanceEpochs += idx.Epoch(epochsNum)
+	case driverpos.Topics.AdvanceEpochs:
+		p.bs.AdvanceEpochs += ev.EpochsNum
 		if p.bs.AdvanceEpochs > maxAdvanceEpochs {
 			p.bs.AdvanceEpochs = maxAdvanceEpochs
 		}
@@ -8,6 +8,7 @@ import (
 	"github.com/unicornultrafoundation/go-u2u/common"
 	"github.com/unicornultrafoundation/go-u2u/core/state"
 	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
 	"github.com/unicornultrafoundation/go-u2u/log"
 	"github.com/unicornultrafoundation/go-u2u/params"
 
@@ -26,20 +27,22 @@ func New() *EVMModule {
 }
 
 func (p *EVMModule) Start(block iblockproc.BlockCtx, statedb *state.StateDB, sfcStatedb *state.StateDB, reader evmcore.DummyChain,
-	onNewLog func(*types.Log), net u2u.Rules, evmCfg *params.ChainConfig) blockproc.EVMProcessor {
+	onNewLog func(*types.Log), onSfcDivergence evmcore.OnSfcDivergenceFn, net u2u.Rules, evmCfg *params.ChainConfig, vmCfg vm.Config) blockproc.EVMProcessor {
 	var prevBlockHash common.Hash
 	if block.Idx != 0 {
 		prevBlockHash = reader.GetHeader(common.Hash{}, uint64(block.Idx-1)).Hash
 	}
 	processor := &U2UEVMProcessor{
-		block:         block,
-		reader:        reader,
-		statedb:       statedb,
-		onNewLog:      onNewLog,
-		net:           net,
-		evmCfg:        evmCfg,
-		blockIdx:      utils.U64toBig(uint64(block.Idx)),
-		prevBlockHash: prevBlockHash,
+		block:           block,
+		reader:          reader,
+		statedb:         statedb,
+		onNewLog:        onNewLog,
+		onSfcDivergence: onSfcDivergence,
+		net:             net,
+		evmCfg:          evmCfg,
+		vmCfg:           vmCfg,
+		blockIdx:        utils.U64toBig(uint64(block.Idx)),
+		prevBlockHash:   prevBlockHash,
 	}
 	if !isNilInterface(sfcStatedb) {
 		processor.sfcStateDb = sfcStatedb
@@ -53,8 +56,11 @@ type U2UEVMProcessor struct {
 	statedb    *state.StateDB
 	sfcStateDb *state.StateDB
 	onNewLog   func(*types.Log)
-	net        u2u.Rules
-	evmCfg     *params.ChainConfig
+	// onSfcDivergence is called for every divergence of the SFC state, nil disables the comparison
+	onSfcDivergence evmcore.OnSfcDivergenceFn
+	net             u2u.Rules
+	evmCfg          *params.ChainConfig
+	vmCfg           vm.Config
 
 	blockIdx      *big.Int
 	prevBlockHash common.Hash
@@ -93,11 +99,11 @@ func (p *U2UEVMProcessor) Execute(txs types.Transactions) types.Receipts {
 
 	// Process txs
 	evmBlock := p.evmBlockWith(txs)
-	receipts, _, skipped, err := evmProcessor.Process(evmBlock, p.statedb, p.sfcStateDb, u2u.DefaultVMConfig, &p.gasUsed, func(l *types.Log, _ *state.StateDB) {
+	receipts, _, skipped, err := evmProcessor.Process(evmBlock, p.statedb, p.sfcStateDb, p.vmCfg, &p.gasUsed, func(l *types.Log, _ *state.StateDB) {
 		// Note: l.Index is properly set before
 		l.TxIndex += txsOffset
 		p.onNewLog(l)
-	})
+	}, p.onSfcDivergence)
 	if err != nil {
 		log.Crit("EVM internal error", "err", err)
 	}
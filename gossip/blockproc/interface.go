@@ -3,6 +3,7 @@ package blockproc
 import (
 	"github.com/unicornultrafoundation/go-u2u/core/state"
 	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
 	"github.com/unicornultrafoundation/go-u2u/params"
 
 	"github.com/unicornultrafoundation/go-helios/native/idx"
@@ -53,5 +54,5 @@ type EVMProcessor interface {
 
 type EVM interface {
 	Start(block iblockproc.BlockCtx, statedb *state.StateDB, sfcStatedb *state.StateDB, reader evmcore.DummyChain,
-		onNewLog func(*types.Log), net u2u.Rules, evmCfg *params.ChainConfig) EVMProcessor
+		onNewLog func(*types.Log), onSfcDivergence evmcore.OnSfcDivergenceFn, net u2u.Rules, evmCfg *params.ChainConfig, vmCfg vm.Config) EVMProcessor
 }
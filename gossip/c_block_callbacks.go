@@ -17,7 +17,6 @@ import (
 	"github.com/unicornultrafoundation/go-u2u/common"
 	"github.com/unicornultrafoundation/go-u2u/core/types"
 	"github.com/unicornultrafoundation/go-u2u/evmcore"
-	"github.com/unicornultrafoundation/go-u2u/evmcore/txtracer"
 	"github.com/unicornultrafoundation/go-u2u/gossip/blockproc/verwatcher"
 	"github.com/unicornultrafoundation/go-u2u/gossip/emitter"
 	"github.com/unicornultrafoundation/go-u2u/gossip/evmstore"
@@ -79,10 +78,21 @@ func (s *Service) GetConsensusCallbacks() utypes.ConsensusCallbacks {
 			&s.emitters,
 			s.verWatcher,
 			&s.bootstrapping,
+			s.onSfcDivergence,
 		),
 	}
 }
 
+// onSfcDivergence records a divergence of the SFC state from the main state,
+// and halts the events processing if it's configured so.
+func (s *Service) onSfcDivergence(d *evmcore.SfcDivergence) {
+	s.Log.Error("SFC state diverged", "block", d.BlockNumber, "tx", d.TxHash.String(), "contract", d.Address.String(), "slots", len(d.Slots))
+	s.store.evm.SetSfcDivergence(d)
+	if s.config.HaltOnSfcDivergence && atomic.CompareAndSwapUint32(&s.sfcDivergedFlag, 0, 1) {
+		s.Log.Warn("Events processing is halted on SFC state divergence, the node may be stopped to snapshot the datadir")
+	}
+}
+
 // consensusCallbackBeginBlockFn takes only necessaries for block processing and
 // makes types.BeginBlockFn.
 func consensusCallbackBeginBlockFn(
@@ -96,6 +106,7 @@ func consensusCallbackBeginBlockFn(
 	emitters *[]*emitter.Emitter,
 	verWatcher *verwatcher.VerWarcher,
 	bootstrapping *bool,
+	onSfcDivergence evmcore.OnSfcDivergenceFn,
 ) utypes.BeginBlockFn {
 	return func(cBlock *utypes.Block) utypes.BlockCallbacks {
 		if *bootstrapping {
@@ -120,6 +131,7 @@ func consensusCallbackBeginBlockFn(
 		bs.EpochCheaters = mergeCheaters(bs.EpochCheaters, cBlock.Cheaters)
 
 		// Get stateDB
+		prevStateRoot := common.Hash(bs.FinalizedStateRoot)
 		statedb, err := store.evm.StateDB(bs.FinalizedStateRoot)
 		if err != nil {
 			log.Crit("Failed to open StateDB", "err", err)
@@ -269,15 +281,20 @@ func consensusCallbackBeginBlockFn(
 				}
 
 				// Providing default config
-				// In case of trace transaction node, this config is changed
-				evmCfg := u2u.DefaultVMConfig
-				if store.txtracer != nil {
-					evmCfg.Debug = true
-					evmCfg.Tracer = txtracer.NewTraceStructLogger(store.txtracer)
+				// The transaction traces aren't recorded here, as the positions of the transactions
+				// in the block aren't final yet, the trace API records them while replaying the block.
+				// The internal calls are collected for the address index, the tracer needs the debug
+				// mode to be notified about the call frames and ignores the opcodes.
+				vmCfg := u2u.DefaultVMConfig
+				var internalCalls *evmstore.InternalCallsTracer
+				if txIndex && store.evm.AddressIndexEnabled() {
+					internalCalls = evmstore.NewInternalCallsTracer()
+					vmCfg.Debug = true
+					vmCfg.Tracer = internalCalls
 				}
 
-				evmProcessor := blockProc.EVMModule.Start(blockCtx, statedb, sfcStatedb, evmStateReader, onNewLogAll,
-					es.Rules, es.Rules.EvmChainConfig(store.GetUpgradeHeights()))
+				evmProcessor := blockProc.EVMModule.Start(blockCtx, statedb, sfcStatedb, evmStateReader, onNewLogAll, onSfcDivergence,
+					es.Rules, es.Rules.EvmChainConfig(store.GetUpgradeHeights()), vmCfg)
 				executionStart := time.Now()
 
 				// Execute pre-internal transactions
@@ -344,6 +361,11 @@ func consensusCallbackBeginBlockFn(
 					block.SfcStateRoot = hash.Hash(evmBlock.SfcStateRoot)
 					block.GasUsed = evmBlock.GasUsed
 
+					// record the state history before the block becomes visible
+					if err := store.evm.WriteStateHistory(blockCtx.Idx, prevStateRoot, evmBlock.Root); err != nil {
+						log.Crit("Failed to write state history", "block", blockCtx.Idx, "err", err)
+					}
+
 					// memorize event position of each tx
 					txPositions := make(map[common.Hash]ExtendedTxPosition)
 					for _, e := range blockEvents {
@@ -397,6 +419,11 @@ func consensusCallbackBeginBlockFn(
 							for _, r := range allReceipts {
 								store.evm.IndexLogs(r.Logs...)
 							}
+							var calls map[common.Hash]*evmstore.InternalCalls
+							if internalCalls != nil {
+								calls = internalCalls.Calls()
+							}
+							store.evm.IndexAddresses(blockCtx.Idx, evmBlock.Transactions, allReceipts, store.txSigner(), calls)
 						}
 					}
 					for _, tx := range append(preInternalTxs, internalTxs...) {
@@ -509,18 +536,14 @@ func (s *Service) ReexecuteBlocks(from, to idx.Block) {
 			log.Warn("Failed to get SFC state", "event hash", prev.Atropos.Hex(), "err", err)
 		}
 		es := s.store.GetHistoryEpochState(s.store.FindBlockEpoch(b))
-		// Providing default config
-		// In case of trace transaction node, this config is changed
-		evmCfg := u2u.DefaultVMConfig
-		if s.store.txtracer != nil {
-			evmCfg.Debug = true
-			evmCfg.Tracer = txtracer.NewTraceStructLogger(s.store.txtracer)
-		}
-		evmProcessor := blockProc.EVMModule.Start(blockCtx, statedb, sfcStatedb, evmStateReader, func(t *types.Log) {},
-			es.Rules, es.Rules.EvmChainConfig(upgradeHeights))
+		evmProcessor := blockProc.EVMModule.Start(blockCtx, statedb, sfcStatedb, evmStateReader, func(t *types.Log) {}, nil,
+			es.Rules, es.Rules.EvmChainConfig(upgradeHeights), u2u.DefaultVMConfig)
 		txs := s.store.GetBlockTxs(b, block)
 		evmProcessor.Execute(txs)
 		evmProcessor.Finalize()
+		if err := s.store.evm.WriteStateHistory(b, common.Hash(prev.Root), common.Hash(block.Root)); err != nil {
+			log.Crit("Failed to write state history", "block", b, "err", err)
+		}
 		_ = s.store.evm.Commit(b, block.Root, false)
 		s.store.evm.Cap()
 		s.mayCommit(false)
@@ -209,7 +209,7 @@ func (s *Service) ReprocessEpochEvents() {
 // processEvent extends the engine.Process with gossip-specific actions on each event processing
 func (s *Service) processEvent(e *native.EventPayload) error {
 	// s.engineMu is locked here
-	if s.stopped {
+	if s.stopped || atomic.LoadUint32(&s.sfcDivergedFlag) != 0 {
 		return errStopped
 	}
 	if err := s.verWatcher.Pause(); err != nil {
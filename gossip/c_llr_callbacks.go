@@ -105,6 +105,10 @@ func indexRawReceipts(s *Store, receiptsForStorage []*types.ReceiptForStorage, t
 	for _, r := range receipts {
 		s.evm.IndexLogs(r.Logs...)
 	}
+	// The transactions aren't executed here, so only the addresses of the transactions and
+	// the receipts are indexed. The internal calls are indexed once the block is processed,
+	// they are missing for the blocks which are only imported from the full block records.
+	s.evm.IndexAddresses(blockIdx, txs, receipts, s.txSigner(), nil)
 }
 
 func (s *Store) WriteFullBlockRecord(br ibr.LlrIdxFullBlockRecord) {
@@ -1,19 +1,24 @@
 package gossip
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"errors"
 	"fmt"
 	"math"
 	"math/big"
+	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/unicornultrafoundation/go-helios/consensus"
 	"github.com/unicornultrafoundation/go-helios/hash"
 	"github.com/unicornultrafoundation/go-helios/native/dag"
 	"github.com/unicornultrafoundation/go-helios/native/idx"
+	"github.com/unicornultrafoundation/go-helios/u2udb/flushable"
+	"github.com/unicornultrafoundation/go-helios/u2udb/memorydb"
 	"github.com/unicornultrafoundation/go-helios/utils/cachescale"
 	go_u2u "github.com/unicornultrafoundation/go-u2u"
 	"github.com/unicornultrafoundation/go-u2u/accounts/abi/bind"
@@ -24,6 +29,9 @@ import (
 	"github.com/unicornultrafoundation/go-u2u/core/vm"
 	"github.com/unicornultrafoundation/go-u2u/crypto"
 	"github.com/unicornultrafoundation/go-u2u/log"
+	"github.com/unicornultrafoundation/go-u2u/params"
+	"github.com/unicornultrafoundation/go-u2u/rlp"
+	"github.com/unicornultrafoundation/go-u2u/trie"
 
 	"github.com/unicornultrafoundation/go-u2u/evmcore"
 	"github.com/unicornultrafoundation/go-u2u/gossip/blockproc"
@@ -62,6 +70,9 @@ type testEnv struct {
 	*Service
 	signer  valkeystore.SignerI
 	pubkeys []validatorpk.PubKey
+
+	// sfcDivergence is the first divergence of the native system contracts from the bytecode
+	sfcDivergence atomic.Value
 }
 
 func panics(name string) func(error) {
@@ -133,7 +144,146 @@ func (m testConfirmedEventsModule) Start(bs iblockproc.BlockState, es iblockproc
 	return testConfirmedEventsProcessor{p, m.env}
 }
 
+// testEVMModule runs the native system contracts alongside the bytecode and checks that they agree
+type testEVMModule struct {
+	blockproc.EVM
+	env *testEnv
+}
+
+func (m testEVMModule) Start(block iblockproc.BlockCtx, statedb *state.StateDB, sfcStatedb *state.StateDB, reader evmcore.DummyChain,
+	onNewLog func(*types.Log), onSfcDivergence evmcore.OnSfcDivergenceFn, net u2u.Rules, evmCfg *params.ChainConfig, vmCfg vm.Config) blockproc.EVMProcessor {
+	p := m.EVM.Start(block, statedb, sfcStatedb, reader, onNewLog, onSfcDivergence, net, evmCfg, vmCfg)
+	return &testEVMProcessor{p, m.env, block, statedb, sfcStatedb}
+}
+
+type testEVMProcessor struct {
+	blockproc.EVMProcessor
+	env        *testEnv
+	block      iblockproc.BlockCtx
+	statedb    *state.StateDB
+	sfcStatedb *state.StateDB
+}
+
+func (p *testEVMProcessor) Execute(txs types.Transactions) types.Receipts {
+	receipts := p.EVMProcessor.Execute(txs)
+	if p.sfcStatedb == nil {
+		return receipts
+	}
+	for _, r := range receipts {
+		expected := sfcLogs(r.Logs)
+		got := sfcLogs(p.sfcStatedb.GetLogs(r.TxHash, common.Hash(p.block.Atropos)))
+		if len(expected) != len(got) {
+			p.env.reportSfcDivergence(fmt.Errorf("block %d, tx %s: %d logs of system contracts, native %d", p.block.Idx, r.TxHash.Hex(), len(expected), len(got)))
+			continue
+		}
+		for i := range expected {
+			if !equalLogs(expected[i], got[i]) {
+				p.env.reportSfcDivergence(fmt.Errorf("block %d, tx %s: log %d of %s differs", p.block.Idx, r.TxHash.Hex(), i, expected[i].Address.Hex()))
+				break
+			}
+		}
+	}
+	return receipts
+}
+
+func (p *testEVMProcessor) Finalize() (*evmcore.EvmBlock, []uint32, types.Receipts) {
+	evmBlock, skippedTxs, receipts := p.EVMProcessor.Finalize()
+	if p.sfcStatedb == nil {
+		return evmBlock, skippedTxs, receipts
+	}
+	for addr := range u2u.DefaultVMConfig.SfcPrecompiles {
+		if p.statedb.GetStorageRoot(addr) != p.sfcStatedb.GetStorageRoot(addr) {
+			p.env.reportSfcDivergence(fmt.Errorf("block %d: storage of %s differs", p.block.Idx, addr.Hex()))
+		}
+	}
+	return evmBlock, skippedTxs, receipts
+}
+
+// sfcLogs filters the logs emitted by the system contracts which have native implementations
+func sfcLogs(logs []*types.Log) []*types.Log {
+	res := make([]*types.Log, 0, len(logs))
+	for _, l := range logs {
+		if _, ok := u2u.DefaultVMConfig.SfcPrecompiles[l.Address]; ok {
+			res = append(res, l)
+		}
+	}
+	return res
+}
+
+func equalLogs(a, b *types.Log) bool {
+	return a.Address == b.Address && reflect.DeepEqual(a.Topics, b.Topics) && bytes.Equal(a.Data, b.Data)
+}
+
+func (env *testEnv) reportSfcDivergence(err error) {
+	if env.sfcDivergence.Load() == nil {
+		env.sfcDivergence.Store(err)
+	}
+}
+
+// SfcDivergence returns the first divergence of the native system contracts from the bytecode, if any
+func (env *testEnv) SfcDivergence() error {
+	err, _ := env.sfcDivergence.Load().(error)
+	return err
+}
+
+// seedSfcState copies the storage of the system contracts from the main state into the SFC state.
+// The storage tries are copied under the hashed keys, so no preimages are needed.
+func seedSfcState(store *Store) {
+	bs, es := store.GetBlockState(), store.GetEpochState()
+	statedb, err := store.evm.StateDB(bs.FinalizedStateRoot)
+	if err != nil {
+		panic(err)
+	}
+	triedb := store.evm.SfcState.TrieDB()
+	accounts, err := trie.NewSecure(common.Hash{}, triedb)
+	if err != nil {
+		panic(err)
+	}
+	for addr := range u2u.DefaultVMConfig.SfcPrecompiles {
+		if !statedb.Exist(addr) {
+			continue
+		}
+		storage, err := trie.New(common.Hash{}, triedb)
+		if err != nil {
+			panic(err)
+		}
+		it := trie.NewIterator(statedb.StorageTrie(addr).NodeIterator(nil))
+		for it.Next() {
+			if err := storage.TryUpdate(it.Key, it.Value); err != nil {
+				panic(err)
+			}
+		}
+		root, err := storage.Commit(nil)
+		if err != nil {
+			panic(err)
+		}
+		// the precompiles have no code in the SFC state, so the nonce keeps the accounts from being empty
+		account, err := rlp.EncodeToBytes(state.Account{
+			Nonce:    1,
+			Balance:  new(big.Int),
+			Root:     root,
+			CodeHash: crypto.Keccak256(nil),
+		})
+		if err != nil {
+			panic(err)
+		}
+		if err := accounts.TryUpdate(addr.Bytes(), account); err != nil {
+			panic(err)
+		}
+	}
+	root, err := accounts.Commit(nil)
+	if err != nil {
+		panic(err)
+	}
+	bs.SfcStateRoot = hash.Hash(root)
+	store.SetBlockEpochState(bs, es)
+}
+
 func newTestEnv(firstEpoch idx.Epoch, validatorsNum idx.Validator) *testEnv {
+	return newTestEnvWithStoreConfig(firstEpoch, validatorsNum, LiteStoreConfig())
+}
+
+func newTestEnvWithStoreConfig(firstEpoch idx.Epoch, validatorsNum idx.Validator, storeCfg StoreConfig) *testEnv {
 	rules := u2u.FakeNetRules()
 	rules.Epochs.MaxEpochDuration = native.Timestamp(maxEpochDuration)
 	rules.Blocks.MaxEmptyBlockSkipPeriod = 0
@@ -141,11 +291,13 @@ func newTestEnv(firstEpoch idx.Epoch, validatorsNum idx.Validator) *testEnv {
 	genStore := makefakegenesis.FakeGenesisStoreWithRulesAndStart(validatorsNum, utils.ToU2U(genesisBalance), utils.ToU2U(genesisStake), rules, firstEpoch, 2)
 	genesis := genStore.Genesis()
 
-	store := NewMemStore()
+	storeCfg.EVM.SfcEnabled = true
+	store := NewStore(flushable.NewSyncedPool(memorydb.NewProducer(""), []byte{0}), storeCfg)
 	_, err := store.ApplyGenesis(genesis)
 	if err != nil {
 		panic(err)
 	}
+	seedSfcState(store)
 
 	// install blockProc callbacks
 	env := &testEnv{
@@ -154,6 +306,7 @@ func newTestEnv(firstEpoch idx.Epoch, validatorsNum idx.Validator) *testEnv {
 	}
 	blockProc := DefaultBlockProc()
 	blockProc.EventsModule = testConfirmedEventsModule{blockProc.EventsModule, env}
+	blockProc.EVMModule = testEVMModule{blockProc.EVMModule, env}
 
 	engine, vecClock := makeTestEngine(store)
 
@@ -299,6 +452,9 @@ func (env *testEnv) EmitUntil(stop func() bool) error {
 			}
 		}
 		env.WaitBlockEnd()
+		if err := env.SfcDivergence(); err != nil {
+			return err
+		}
 		env.t = env.t.Add(time.Second)
 		if time.Since(t) > 30*time.Second {
 			panic("block doesn't get processed")
@@ -545,3 +701,6 @@ func (m callmsg) Nonce() uint64                { return 0 }
 func (m callmsg) IsFake() bool                 { return true }
 func (m callmsg) Data() []byte                 { return m.CallMsg.Data }
 func (m callmsg) AccessList() types.AccessList { return nil }
+func (m callmsg) SetCodeAuthorizations() []types.SetCodeAuthorization {
+	return nil
+}
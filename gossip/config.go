@@ -104,6 +104,10 @@ type (
 		AllowUnprotectedTxs bool
 
 		RPCBlockExt bool
+
+		// HaltOnSfcDivergence stops the events processing after the first
+		// divergence of the SFC state from the main state.
+		HaltOnSfcDivergence bool
 	}
 
 	StoreCacheConfig struct {
@@ -122,6 +126,69 @@ type (
 		LlrEpochVotesIndexes int
 	}
 
+	// TxTraceRetentionConfig is a config for pruning of stored transaction traces.
+	// Traces are kept forever if both limits are zero. If both limits are set,
+	// traces are kept for the longer of the two windows.
+	TxTraceRetentionConfig struct {
+		// Blocks is the number of latest blocks to keep transaction traces for
+		Blocks idx.Block
+		// Epochs is the number of latest epochs to keep transaction traces for
+		Epochs idx.Epoch
+		// PrunePeriod is the period of the background pruning
+		PrunePeriod time.Duration
+		// MaxBlocksPerRound limits the number of blocks pruned at once
+		MaxBlocksPerRound idx.Block
+	}
+
+	// AncientConfig is a config for the ancient store, which keeps the finalized events,
+	// blocks and receipts of the old epochs in append-only compressed flat files.
+	AncientConfig struct {
+		// Dir is the directory of the ancient store, the ancient store is disabled if empty
+		Dir string
+		// Epochs is the number of latest epochs to keep in the key-value database.
+		// The data isn't moved into the ancient store if zero
+		Epochs idx.Epoch
+		// FreezePeriod is the period of the background migration
+		FreezePeriod time.Duration
+		// MaxItemsPerRound limits the number of events and blocks migrated at once
+		MaxItemsPerRound int
+	}
+
+	// EventsHistoryConfig is a config for pruning of the DAG events of the old epochs.
+	// The blocks, epoch records and LLR votes are kept, so only the DAG sync of the
+	// pruned epochs isn't served to the peers.
+	EventsHistoryConfig struct {
+		// Epochs is the number of latest epochs to keep the events for.
+		// The events are kept forever if zero
+		Epochs idx.Epoch
+		// PrunePeriod is the period of the background pruning
+		PrunePeriod time.Duration
+	}
+
+	// ReceiptsRetentionConfig is a config for pruning of the receipts, transaction positions
+	// and logs index of the old blocks. The history is kept forever if the limit is zero.
+	ReceiptsRetentionConfig struct {
+		// Blocks is the number of latest blocks to keep the receipts for
+		Blocks idx.Block
+		// PrunePeriod is the period of the background pruning
+		PrunePeriod time.Duration
+		// MaxBlocksPerRound limits the number of blocks pruned at once
+		MaxBlocksPerRound idx.Block
+	}
+
+	// StatePruningConfig is a config for the online pruning of the EVM state. The trie nodes
+	// and contract codes, which don't belong to the states of the latest blocks, are removed
+	// in background while the node keeps processing the blocks.
+	StatePruningConfig struct {
+		// Blocks is the number of latest blocks to keep the states for.
+		// The pruning is disabled if zero
+		Blocks idx.Block
+		// PrunePeriod is the period of the background pruning
+		PrunePeriod time.Duration
+		// BloomSize is the size of the bloom filter of the retained state entries (in megabytes)
+		BloomSize uint64
+	}
+
 	// StoreConfig is a config for store db.
 	StoreConfig struct {
 		Cache StoreCacheConfig
@@ -130,6 +197,11 @@ type (
 		MaxNonFlushedSize   int
 		MaxNonFlushedPeriod time.Duration
 		TraceTransactions   bool
+		TxTraceRetention    TxTraceRetentionConfig
+		Ancient             AncientConfig
+		EventsHistory       EventsHistoryConfig
+		ReceiptsRetention   ReceiptsRetentionConfig
+		StatePruning        StatePruningConfig
 	}
 )
 
@@ -277,6 +349,25 @@ func DefaultStoreConfig(scale cachescale.Func) StoreConfig {
 		EVM:                 evmstore.DefaultStoreConfig(scale),
 		MaxNonFlushedSize:   21*opt.MiB + scale.I(2*opt.MiB),
 		MaxNonFlushedPeriod: 30 * time.Minute,
+		TxTraceRetention: TxTraceRetentionConfig{
+			PrunePeriod:       time.Minute,
+			MaxBlocksPerRound: 10000,
+		},
+		Ancient: AncientConfig{
+			FreezePeriod:     time.Minute,
+			MaxItemsPerRound: 30000,
+		},
+		EventsHistory: EventsHistoryConfig{
+			PrunePeriod: time.Minute,
+		},
+		ReceiptsRetention: ReceiptsRetentionConfig{
+			PrunePeriod:       time.Minute,
+			MaxBlocksPerRound: 10000,
+		},
+		StatePruning: StatePruningConfig{
+			PrunePeriod: 6 * time.Hour,
+			BloomSize:   2048,
+		},
 	}
 }
 
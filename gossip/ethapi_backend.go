@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -24,6 +25,7 @@ import (
 	"github.com/unicornultrafoundation/go-u2u/evmcore"
 	"github.com/unicornultrafoundation/go-u2u/evmcore/txtracer"
 	"github.com/unicornultrafoundation/go-u2u/gossip/evmstore"
+	txtrace "github.com/unicornultrafoundation/go-u2u/gossip/txtracer"
 	"github.com/unicornultrafoundation/go-u2u/native"
 	"github.com/unicornultrafoundation/go-u2u/native/iblockproc"
 	"github.com/unicornultrafoundation/go-u2u/params"
@@ -40,6 +42,7 @@ type EthAPIBackend struct {
 	state               *EvmStateReader
 	signer              types.Signer
 	allowUnprotectedTxs bool
+	traceDir            string
 }
 
 // SetExtRPCEnabled updates extRPCEnabled
@@ -47,6 +50,19 @@ func (b *EthAPIBackend) SetExtRPCEnabled(v bool) {
 	b.extRPCEnabled = v
 }
 
+// SetTraceDir updates traceDir
+func (b *EthAPIBackend) SetTraceDir(dir string) {
+	b.traceDir = dir
+}
+
+// TraceDir returns the directory for the trace files, the system temporary directory if it isn't set.
+func (b *EthAPIBackend) TraceDir() string {
+	if b.traceDir == "" {
+		return os.TempDir()
+	}
+	return b.traceDir
+}
+
 // ChainConfig returns the active chain configuration.
 func (b *EthAPIBackend) ChainConfig() *params.ChainConfig {
 	return b.svc.store.GetEvmChainConfig()
@@ -121,6 +137,82 @@ func (b *EthAPIBackend) TxTraceSave(ctx context.Context, h common.Hash, traces [
 	return errors.New("Transaction trace key-value store db is not initialized")
 }
 
+// TxTraceIndexed returns true if the stored transaction traces are indexed by addresses
+func (b *EthAPIBackend) TxTraceIndexed(ctx context.Context) bool {
+	return b.state.store.txtracer != nil && b.state.store.txtracer.IsIndexed()
+}
+
+// traceRoles maps the address roles of the API onto the roles of the trace index
+var traceRoles = map[ethapi.TraceAddressRole]txtrace.AddressRole{
+	ethapi.TraceRoleFrom:    txtrace.RoleFrom,
+	ethapi.TraceRoleTo:      txtrace.RoleTo,
+	ethapi.TraceRoleCreated: txtrace.RoleCreated,
+}
+
+// TxTraceRefs iterates over the stored traces in which the address has the specified role
+func (b *EthAPIBackend) TxTraceRefs(ctx context.Context, role ethapi.TraceAddressRole, addr common.Address, from, to uint64, onRef func(ref ethapi.TraceRef) bool) error {
+	if b.state.store.txtracer == nil {
+		return errors.New("Transaction trace key-value store db is not initialized")
+	}
+	indexRole, ok := traceRoles[role]
+	if !ok {
+		return fmt.Errorf("unknown trace address role %d", role)
+	}
+	b.state.store.txtracer.ForEachAddressTrace(indexRole, addr, from, to, func(ref txtrace.TraceRef) bool {
+		return ctx.Err() == nil && onRef(ethapi.TraceRef{
+			Block:        ref.Block,
+			TxPosition:   ref.TxPosition,
+			TraceAddress: ref.TraceAddress,
+			TxHash:       ref.TxHash,
+		})
+	})
+	return ctx.Err()
+}
+
+// AddressTxs iterates over the indexed transactions in which the address appears.
+// If reverse is set, the transactions below the from block are iterated in descending order.
+func (b *EthAPIBackend) AddressTxs(ctx context.Context, addr common.Address, from idx.Block, reverse bool, onTx func(tx ethapi.AddressTx) bool) error {
+	if !b.state.store.evm.AddressIndexEnabled() {
+		return ethapi.ErrAddressIndexDisabled
+	}
+	onIndexed := func(tx evmstore.AddressTx) bool {
+		return ctx.Err() == nil && onTx(ethapi.AddressTx{
+			Block:       tx.Block,
+			BlockOffset: tx.BlockOffset,
+			TxHash:      tx.TxHash,
+		})
+	}
+	if reverse {
+		b.state.store.evm.ForEachAddressTxBackward(addr, from, onIndexed)
+	} else {
+		b.state.store.evm.ForEachAddressTx(addr, from, onIndexed)
+	}
+	return ctx.Err()
+}
+
+// TxBySenderAndNonce returns the hash of the indexed transaction with the sender and nonce, or nil if not exists.
+func (b *EthAPIBackend) TxBySenderAndNonce(ctx context.Context, addr common.Address, nonce uint64) (*common.Hash, error) {
+	if !b.state.store.evm.AddressIndexEnabled() {
+		return nil, ethapi.ErrAddressIndexDisabled
+	}
+	return b.state.store.evm.GetTxBySenderAndNonce(addr, nonce), nil
+}
+
+// ContractCreator returns the indexed transaction which created the contract, or nil if not exists.
+func (b *EthAPIBackend) ContractCreator(ctx context.Context, addr common.Address) (*ethapi.ContractCreator, error) {
+	if !b.state.store.evm.AddressIndexEnabled() {
+		return nil, ethapi.ErrAddressIndexDisabled
+	}
+	creator := b.state.store.evm.GetContractCreator(addr)
+	if creator == nil {
+		return nil, nil
+	}
+	return &ethapi.ContractCreator{
+		TxHash:  creator.TxHash,
+		Creator: creator.Creator,
+	}, nil
+}
+
 // BlockByNumber returns evm block by its number, or nil if not exists.
 func (b *EthAPIBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmBlock, error) {
 	if number == rpc.PendingBlockNumber {
@@ -166,6 +258,10 @@ func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 		return nil, nil, errors.New("header not found")
 	}
 	stateDb, err := b.svc.store.evm.StateDB(hash.Hash(header.Root))
+	if err != nil && b.svc.store.evm.StateHistoryEnabled() {
+		// the state of an old block is read from the head state and the state history
+		stateDb, err = b.svc.store.evm.HistoricalStateDB(idx.Block(header.Number.Uint64()), hash.Hash(header.Root))
+	}
 	if err != nil {
 		return nil, nil, err
 	}
@@ -282,6 +378,18 @@ func (b *EthAPIBackend) GetHeads(ctx context.Context, epoch rpc.BlockNumber) (he
 	return
 }
 
+// GetBlockEvents returns IDs of the events confirmed by the block, or nil if the block doesn't exist.
+func (b *EthAPIBackend) GetBlockEvents(ctx context.Context, number rpc.BlockNumber) (hash.Events, error) {
+	if isLatestBlockNumber(number) {
+		number = rpc.BlockNumber(b.svc.store.GetLatestBlockIndex())
+	}
+	block := b.svc.store.GetBlock(idx.Block(number))
+	if block == nil {
+		return nil, nil
+	}
+	return block.Events, nil
+}
+
 func (b *EthAPIBackend) epochWithDefault(ctx context.Context, epoch rpc.BlockNumber) (requested idx.Epoch, err error) {
 	current := b.svc.store.GetEpoch()
 
@@ -342,6 +450,9 @@ func (b *EthAPIBackend) GetReceiptsByNumber(ctx context.Context, number rpc.Bloc
 		header := b.state.CurrentHeader()
 		number = rpc.BlockNumber(header.Number.Uint64())
 	}
+	if idx.Block(number) < b.svc.store.evm.GetReceiptsTail() {
+		return nil, evmstore.ErrPrunedHistory
+	}
 
 	block := b.state.GetBlock(common.Hash{}, uint64(number))
 	receipts := b.svc.store.evm.GetReceipts(idx.Block(number), b.signer, block.Hash, block.Transactions)
@@ -533,6 +644,11 @@ func (b *EthAPIBackend) EvmLogIndex() topicsdb.Index {
 	return b.svc.store.evm.EvmLogs
 }
 
+// ReceiptsTail returns the lowest block, which receipts and logs are retained, or zero if the history isn't pruned.
+func (b *EthAPIBackend) ReceiptsTail() idx.Block {
+	return b.svc.store.evm.GetReceiptsTail()
+}
+
 // CurrentEpoch returns current epoch number.
 func (b *EthAPIBackend) CurrentEpoch(ctx context.Context) idx.Epoch {
 	return b.svc.store.GetEpoch()
@@ -605,8 +721,19 @@ func (b *EthAPIBackend) SealedEpochTiming(ctx context.Context) (start native.Tim
 	return es.PrevEpochStart, es.EpochStart
 }
 
-func (b *EthAPIBackend) StateAtBlock(ctx context.Context, block *evmcore.EvmBlock, reexec uint64, base *state.StateDB, checkLive bool) (*state.StateDB, *state.StateDB, error) {
-	return b.svc.stateAtBlock(block, reexec, base, checkLive)
+func (b *EthAPIBackend) StateAtBlock(ctx context.Context, block *evmcore.EvmBlock, reexec uint64, base, baseSfc *state.StateDB, checkLive bool) (*state.StateDB, *state.StateDB, error) {
+	return b.svc.stateAtBlock(block, reexec, base, baseSfc, checkLive)
+}
+
+// ReplayBlock re-executes the block transactions one by one on top of the given states of the parent block.
+func (b *EthAPIBackend) ReplayBlock(ctx context.Context, block *evmcore.EvmBlock, statedb, sfcStatedb *state.StateDB, vmCfg vm.Config,
+	onTx func(i int, tx *types.Transaction, receipt *types.Receipt) error) error {
+	return b.svc.replayBlock(block, statedb, sfcStatedb, vmCfg, func(i int, tx *types.Transaction, receipt *types.Receipt) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return onTx(i, tx, receipt)
+	})
 }
 
 func (b *EthAPIBackend) StateAtTransaction(ctx context.Context, block *evmcore.EvmBlock, txIndex int, reexec uint64) (evmcore.Message, vm.BlockContext, *state.StateDB, error) {
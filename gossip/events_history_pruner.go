@@ -0,0 +1,216 @@
+package gossip
+
+import (
+	"sync"
+	"time"
+
+	"github.com/unicornultrafoundation/go-helios/hash"
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+	"github.com/unicornultrafoundation/go-helios/u2udb"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/native"
+)
+
+var (
+	prunedEpochKey   = []byte("e")
+	detachedBlockKey = []byte("b")
+)
+
+// eventsHistoryPruner periodically removes the DAG events of the epochs,
+// which are older than the configured history horizon
+type eventsHistoryPruner struct {
+	store *Store
+	cfg   EventsHistoryConfig
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+func newEventsHistoryPruner(store *Store, cfg EventsHistoryConfig) *eventsHistoryPruner {
+	return &eventsHistoryPruner{
+		store: store,
+		cfg:   cfg,
+		quit:  make(chan struct{}),
+	}
+}
+
+// enabled returns true if the events history is limited
+func (p *eventsHistoryPruner) enabled() bool {
+	return p.cfg.Epochs != 0
+}
+
+func (p *eventsHistoryPruner) Start() {
+	if !p.enabled() {
+		return
+	}
+	p.wg.Add(1)
+	go p.loop()
+}
+
+func (p *eventsHistoryPruner) Stop() {
+	if !p.enabled() {
+		return
+	}
+	close(p.quit)
+	p.wg.Wait()
+}
+
+func (p *eventsHistoryPruner) loop() {
+	defer p.wg.Done()
+	period := p.cfg.PrunePeriod
+	if period <= 0 {
+		period = time.Minute
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.store.pruneEventsHistory(p.cfg, p.quit)
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// GetPrunedEpoch returns the highest epoch, which events are pruned, or zero if no events are pruned.
+func (s *Store) GetPrunedEpoch() idx.Epoch {
+	if v := s.cache.PrunedEpoch.Load(); v != nil {
+		return v.(idx.Epoch)
+	}
+	b, err := s.table.EventsHistory.Get(prunedEpochKey)
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	var epoch idx.Epoch
+	if b != nil {
+		epoch = idx.BytesToEpoch(b)
+	}
+	s.cache.PrunedEpoch.Store(epoch)
+	return epoch
+}
+
+func (s *Store) setPrunedEpoch(epoch idx.Epoch) {
+	if err := s.table.EventsHistory.Put(prunedEpochKey, epoch.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+	s.cache.PrunedEpoch.Store(epoch)
+}
+
+// getDetachedBlock returns the last block, which transactions don't refer to the block events
+func (s *Store) getDetachedBlock() idx.Block {
+	b, err := s.table.EventsHistory.Get(detachedBlockKey)
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if b == nil {
+		return 0
+	}
+	return idx.BytesToBlock(b)
+}
+
+func (s *Store) setDetachedBlock(n idx.Block) {
+	if err := s.table.EventsHistory.Put(detachedBlockKey, n.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// pruneEventsHistory removes the events of the epochs below the history horizon.
+// The vector clock of an epoch is kept in the epoch DB, which is dropped once the
+// epoch is sealed, so only the event payloads are left to prune.
+func (s *Store) pruneEventsHistory(cfg EventsHistoryConfig, quit <-chan struct{}) {
+	s.mutex.History.Lock()
+	defer s.mutex.History.Unlock()
+
+	epoch := s.GetEpoch()
+	if cfg.Epochs == 0 || epoch <= cfg.Epochs {
+		return
+	}
+	horizon := epoch - cfg.Epochs
+	start := time.Now()
+
+	from := s.GetPrunedEpoch() + 1
+	to := from - 1
+	var events int
+	for e := from; e <= horizon && !isClosed(quit); e++ {
+		n, ok := s.pruneEpochEvents(e)
+		if !ok {
+			break
+		}
+		events += n
+		to = e
+	}
+	if to >= from {
+		s.Log.Info("Pruned events history", "from", from, "to", to, "events", events, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+}
+
+// pruneEpochEvents removes the events of the sealed epoch, after the transactions of the epoch
+// blocks are detached from the events. Returns false if the epoch blocks aren't complete yet.
+func (s *Store) pruneEpochEvents(epoch idx.Epoch) (int, bool) {
+	// events of the frozen blocks, which can't be detached
+	keep := make(map[hash.Event]bool)
+
+	latest := s.GetLatestBlockIndex()
+	last := s.getDetachedBlock()
+	for n := last + 1; n <= latest; n++ {
+		block := s.GetBlock(n)
+		if block == nil {
+			// the block isn't filled yet
+			return 0, false
+		}
+		if block.Atropos.Epoch() > epoch {
+			break
+		}
+		if len(block.Events) != 0 {
+			if has, _ := s.table.Blocks.Has(n.Bytes()); has {
+				s.detachBlockTxs(n, block)
+			} else {
+				for _, id := range block.Events {
+					keep[id] = true
+				}
+			}
+		}
+		last = n
+	}
+
+	ids := make(hash.Events, 0, 1024)
+	for _, table := range []u2udb.Store{s.table.Events, s.table.AncientEvents} {
+		it := table.NewIterator(epoch.Bytes(), nil)
+		for it.Next() {
+			if id := hash.BytesToEvent(it.Key()); !keep[id] {
+				ids = append(ids, id)
+			}
+		}
+		it.Release()
+	}
+	for _, id := range ids {
+		s.DelEvent(id)
+	}
+	s.setDetachedBlock(last)
+	s.setPrunedEpoch(epoch)
+	return len(ids), true
+}
+
+// detachBlockTxs stores the block transactions separately from the block events,
+// so the block stays complete after the events are pruned
+func (s *Store) detachBlockTxs(n idx.Block, block *native.Block) {
+	txs := s.GetBlockTxs(n, block)
+	txHashes := make([]common.Hash, 0, len(txs))
+	for _, tx := range txs {
+		s.evm.SetTx(tx.Hash(), tx)
+		if position := s.evm.GetTxPosition(tx.Hash()); position != nil && !position.Event.IsZero() {
+			position.Event = hash.ZeroEvent
+			position.EventOffset = 0
+			s.evm.SetTxPosition(tx.Hash(), *position)
+		}
+		txHashes = append(txHashes, tx.Hash())
+	}
+	detached := *block
+	detached.Events = hash.Events{}
+	detached.Txs = txHashes
+	detached.InternalTxs = []common.Hash{}
+	detached.SkippedTxs = []uint32{}
+	s.SetBlock(n, &detached)
+}
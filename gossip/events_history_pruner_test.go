@@ -0,0 +1,134 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unicornultrafoundation/go-helios/hash"
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+	"github.com/unicornultrafoundation/go-helios/u2udb/flushable"
+	"github.com/unicornultrafoundation/go-helios/u2udb/memorydb"
+	"github.com/unicornultrafoundation/go-helios/utils/cachescale"
+
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/gossip/evmstore"
+	"github.com/unicornultrafoundation/go-u2u/native"
+	"github.com/unicornultrafoundation/go-u2u/native/iblockproc"
+	"github.com/unicornultrafoundation/go-u2u/p2p"
+	"github.com/unicornultrafoundation/go-u2u/p2p/enode"
+)
+
+func TestStorePruneEventsHistory(t *testing.T) {
+	require := require.New(t)
+
+	dbs := flushable.NewSyncedPool(memorydb.NewProducer(""), []byte{0})
+	cfg := LiteStoreConfig()
+	cfg.Ancient.Dir = t.TempDir()
+	store := NewStore(dbs, cfg)
+	defer store.Close()
+
+	// two events with a transaction each and a block per epoch
+	var (
+		events hash.Events
+		txs    = map[idx.Block]types.Transactions{}
+	)
+	for epoch := idx.Epoch(1); epoch <= 4; epoch++ {
+		n := idx.Block(epoch)
+		block := &native.Block{Events: hash.Events{}}
+		for lamport := idx.Lamport(1); lamport <= 2; lamport++ {
+			tx := types.NewTx(&types.LegacyTx{Nonce: uint64(epoch)*10 + uint64(lamport)})
+			e := &native.MutableEventPayload{}
+			e.SetVersion(1)
+			e.SetEpoch(epoch)
+			e.SetLamport(lamport)
+			e.SetCreator(1)
+			e.SetTxs(types.Transactions{tx})
+			e.SetPayloadHash(native.CalcPayloadHash(e))
+			event := e.Build()
+			store.SetEvent(event)
+			store.evm.SetTxPosition(tx.Hash(), evmstore.TxPosition{
+				Block:       n,
+				Event:       event.ID(),
+				BlockOffset: uint32(len(txs[n])),
+			})
+			events = append(events, event.ID())
+			block.Events = append(block.Events, event.ID())
+			txs[n] = append(txs[n], tx)
+		}
+		block.Atropos = events[len(events)-1]
+		store.SetBlock(n, block)
+	}
+	store.SetBlockEpochState(iblockproc.BlockState{LastBlock: iblockproc.BlockCtx{Idx: 4}}, iblockproc.EpochState{Epoch: 5})
+
+	// the first block is frozen along with its events
+	store.freezeAncient(AncientConfig{Epochs: 4}, nil)
+	require.Equal(uint64(1), store.ancient.Blocks.Items())
+
+	store.pruneEventsHistory(EventsHistoryConfig{Epochs: 2}, nil)
+	require.Equal(idx.Epoch(3), store.GetPrunedEpoch())
+	for i, id := range events {
+		// the events of the frozen block can't be pruned
+		kept := id.Epoch() == 1 || id.Epoch() > 3
+		require.Equal(kept, store.HasEvent(id), "event %d", i)
+		require.Equal(kept, store.GetEventPayload(id) != nil, "event %d", i)
+	}
+	for n := idx.Block(1); n <= 4; n++ {
+		block := store.GetBlock(n)
+		require.NotNil(block)
+		require.Equal(n == 2 || n == 3, len(block.Events) == 0, "block %d", n)
+		blockTxs := store.GetBlockTxs(n, block)
+		require.Len(blockTxs, len(txs[n]))
+		for i, tx := range txs[n] {
+			require.Equal(tx.Hash(), blockTxs[i].Hash())
+			position := store.evm.GetTxPosition(tx.Hash())
+			require.Equal(n == 2 || n == 3, position.Event.IsZero())
+			require.Equal(uint32(i), position.BlockOffset)
+		}
+	}
+
+	// the pruning progresses with the epochs
+	store.pruneEventsHistory(EventsHistoryConfig{Epochs: 2}, nil)
+	require.Equal(idx.Epoch(3), store.GetPrunedEpoch())
+	store.SetBlockEpochState(iblockproc.BlockState{LastBlock: iblockproc.BlockCtx{Idx: 4}}, iblockproc.EpochState{Epoch: 6})
+	store.pruneEventsHistory(EventsHistoryConfig{Epochs: 2}, nil)
+	require.Equal(idx.Epoch(4), store.GetPrunedEpoch())
+	require.False(store.HasEvent(events[6]))
+	require.Len(store.GetBlockTxs(4, store.GetBlock(4)), 2)
+}
+
+func TestPeerProgressPrunedEpoch(t *testing.T) {
+	require := require.New(t)
+
+	// the progress as it's decoded by UP01 peers
+	type up01Progress struct {
+		Epoch            idx.Epoch
+		LastBlockIdx     idx.Block
+		LastBlockAtropos hash.Event
+		HighestLamport   idx.Lamport
+	}
+	progress := PeerProgress{Epoch: 5, LastBlockIdx: 10, PrunedEpoch: 3}
+
+	for _, version := range ProtocolVersions {
+		local, remote := p2p.MsgPipe()
+		p := newPeer(version, p2p.NewPeer(enode.ID{}, "", nil), local, DefaultPeerCacheConfig(cachescale.Identity))
+		go func() {
+			_ = p.SendProgress(progress)
+		}()
+		msg, err := remote.ReadMsg()
+		require.NoError(err)
+		require.Equal(uint64(ProgressMsg), msg.Code)
+
+		if version < UP02 {
+			var received up01Progress
+			require.NoError(msg.Decode(&received))
+			require.Equal(progress.Epoch, received.Epoch)
+			require.Equal(progress.LastBlockIdx, received.LastBlockIdx)
+		} else {
+			var received PeerProgress
+			require.NoError(msg.Decode(&received))
+			require.Equal(progress, received)
+		}
+		p.Close()
+		_ = local.Close()
+	}
+}
@@ -0,0 +1,110 @@
+package evmstore
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+)
+
+// InternalCalls holds the addresses which appear in the internal calls of a transaction
+type InternalCalls struct {
+	Addresses []common.Address
+	// Created maps the contracts created by the internal calls to their creators
+	Created map[common.Address]common.Address
+}
+
+func (c *InternalCalls) add(addr common.Address) {
+	for _, a := range c.Addresses {
+		if a == addr {
+			return
+		}
+	}
+	c.Addresses = append(c.Addresses, addr)
+}
+
+// internalFrame is an internal call which hasn't exited yet
+type internalFrame struct {
+	typ     vm.OpCode
+	from    common.Address
+	to      common.Address
+	created map[common.Address]common.Address
+}
+
+// InternalCallsTracer collects the internal calls of the executed transactions.
+// It relies on the state to provide the hash of the current transaction.
+type InternalCallsTracer struct {
+	txs   map[common.Hash]*InternalCalls
+	tx    *InternalCalls
+	stack []*internalFrame
+}
+
+// NewInternalCallsTracer creates a tracer for the transactions of a block.
+func NewInternalCallsTracer() *InternalCallsTracer {
+	return &InternalCallsTracer{
+		txs: make(map[common.Hash]*InternalCalls),
+	}
+}
+
+// Calls returns the collected internal calls by the transaction hashes.
+func (t *InternalCallsTracer) Calls() map[common.Hash]*InternalCalls {
+	return t.txs
+}
+
+func (t *InternalCallsTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.tx = nil
+	t.stack = append(t.stack[:0], &internalFrame{})
+	if statedb, ok := env.StateDB.(interface{ TxHash() common.Hash }); ok {
+		t.tx = &InternalCalls{}
+		t.txs[statedb.TxHash()] = t.tx
+	}
+}
+
+func (t *InternalCallsTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+func (t *InternalCallsTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.tx == nil {
+		return
+	}
+	t.tx.add(from)
+	t.tx.add(to)
+	t.stack = append(t.stack, &internalFrame{typ: typ, from: from, to: to})
+}
+
+func (t *InternalCallsTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if t.tx == nil || len(t.stack) < 2 {
+		return
+	}
+	frame := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+	if err != nil {
+		// the contracts created by a reverted frame don't exist
+		return
+	}
+	parent := t.stack[len(t.stack)-1]
+	if parent.created == nil {
+		parent.created = make(map[common.Address]common.Address, len(frame.created)+1)
+	}
+	for created, creator := range frame.created {
+		parent.created[created] = creator
+	}
+	if frame.typ == vm.CREATE || frame.typ == vm.CREATE2 {
+		parent.created[frame.to] = frame.from
+	}
+}
+
+func (t *InternalCallsTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+func (t *InternalCallsTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) {
+	if t.tx == nil || len(t.stack) == 0 {
+		return
+	}
+	if err == nil {
+		t.tx.Created = t.stack[0].created
+	}
+	t.stack = t.stack[:0]
+	t.tx = nil
+}
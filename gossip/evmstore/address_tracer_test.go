@@ -0,0 +1,58 @@
+package evmstore
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/core/state"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+)
+
+func TestInternalCallsTracer(t *testing.T) {
+	require := require.New(t)
+
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(err)
+	env := &vm.EVM{StateDB: statedb}
+
+	var (
+		sender   = common.HexToAddress("0x01")
+		contract = common.HexToAddress("0x02")
+		callee   = common.HexToAddress("0x03")
+		created  = common.HexToAddress("0x04")
+		reverted = common.HexToAddress("0x05")
+		txHash1  = common.HexToHash("0xaa")
+		txHash2  = common.HexToHash("0xbb")
+	)
+	tracer := NewInternalCallsTracer()
+
+	statedb.Prepare(txHash1, 0)
+	tracer.CaptureStart(env, sender, contract, false, nil, 0, big.NewInt(0))
+	tracer.CaptureEnter(vm.CALL, contract, callee, nil, 0, big.NewInt(1))
+	tracer.CaptureEnter(vm.CREATE, callee, created, nil, 0, big.NewInt(0))
+	tracer.CaptureExit(nil, 0, nil)
+	tracer.CaptureExit(nil, 0, nil)
+	tracer.CaptureEnter(vm.STATICCALL, contract, callee, nil, 0, nil)
+	tracer.CaptureEnter(vm.CREATE2, callee, reverted, nil, 0, big.NewInt(0))
+	tracer.CaptureExit(nil, 0, nil)
+	tracer.CaptureExit(nil, 0, errors.New("reverted"))
+	tracer.CaptureEnd(nil, 0, 0, nil)
+
+	statedb.Prepare(txHash2, 1)
+	tracer.CaptureStart(env, sender, contract, false, nil, 0, big.NewInt(0))
+	tracer.CaptureEnter(vm.CREATE, contract, created, nil, 0, big.NewInt(0))
+	tracer.CaptureExit(nil, 0, nil)
+	tracer.CaptureEnd(nil, 0, 0, errors.New("reverted"))
+
+	calls := tracer.Calls()
+	require.Len(calls, 2)
+	require.Equal([]common.Address{contract, callee, created, reverted}, calls[txHash1].Addresses)
+	require.Equal(map[common.Address]common.Address{created: callee}, calls[txHash1].Created)
+	require.Equal([]common.Address{contract, created}, calls[txHash2].Addresses)
+	require.Empty(calls[txHash2].Created)
+}
@@ -37,6 +37,13 @@ type (
 		Cache      StoreCacheConfig
 		// Enables tracking of SHA3 preimages in the VM
 		EnablePreimageRecording bool
+		// Enables indexing of the transactions by the addresses appearing in them
+		AddressIndex bool
+		// Directory of the ancient store of the old receipts, disabled if empty
+		AncientDir string
+		// Scheme of the EVM state trie nodes, the hash scheme if empty. The path scheme keeps only the
+		// latest state and records the reverse state diffs, which serve the state of the old blocks
+		StateScheme string
 	}
 )
 
@@ -0,0 +1,97 @@
+package evmstore
+
+import (
+	"sync"
+
+	"github.com/unicornultrafoundation/go-helios/u2udb"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+)
+
+// KeySet is a set of the trie nodes and contract codes retained by the state pruning.
+// Keys of the contract codes are accepted in both legacy and prefixed schemes.
+type KeySet interface {
+	Put(key []byte, value []byte) error
+	Contain(key []byte) (bool, error)
+}
+
+// stateWrites records the trie nodes and contract codes written into the EVM DB while the
+// state is being pruned, so the state pruning doesn't delete the entries written after the
+// retained state is marked
+type stateWrites struct {
+	mu  sync.Mutex
+	set KeySet // nil if the writes aren't tracked
+}
+
+func (w *stateWrites) start(set KeySet) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.set = set
+}
+
+func (w *stateWrites) stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.set = nil
+}
+
+// Put adds the key into the set, it's safe to call concurrently with the tracked writes
+func (w *stateWrites) Put(key []byte, value []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.set.Put(key, value)
+}
+
+// Contain reports whether the key may be in the set
+func (w *stateWrites) Contain(key []byte) (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.set.Contain(key)
+}
+
+func (w *stateWrites) record(key []byte) {
+	if len(key) != common.HashLength {
+		if isCode, _ := rawdb.IsCodeKey(key); !isCode {
+			return
+		}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.set != nil {
+		_ = w.set.Put(key, nil)
+	}
+}
+
+// trackedStore is the EVM DB wrapper, which records the written state entries
+type trackedStore struct {
+	u2udb.Store
+	writes *stateWrites
+}
+
+type trackedBatch struct {
+	u2udb.Batch
+	writes *stateWrites
+}
+
+func (s *trackedStore) Put(key []byte, value []byte) error {
+	// the key is recorded before it's written, see Store.deleteUnmarked
+	s.writes.record(key)
+	return s.Store.Put(key, value)
+}
+
+func (s *trackedStore) NewBatch() u2udb.Batch {
+	batch := s.Store.NewBatch()
+	if batch == nil {
+		return nil
+	}
+	return &trackedBatch{
+		Batch:  batch,
+		writes: s.writes,
+	}
+}
+
+func (b *trackedBatch) Put(key []byte, value []byte) error {
+	b.writes.record(key)
+	return b.Batch.Put(key, value)
+}
@@ -2,6 +2,8 @@ package evmstore
 
 import (
 	"errors"
+	"sync"
+	"sync/atomic"
 
 	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/unicornultrafoundation/go-helios/hash"
@@ -35,12 +37,25 @@ type Store struct {
 	table struct {
 		Evm u2udb.Store `table:"M"`
 		// SFC-only tables
-		SfcEvm     u2udb.Store `table:"A"`
-		StateRoots u2udb.Store `table:"C"`
+		SfcEvm         u2udb.Store `table:"A"`
+		StateRoots     u2udb.Store `table:"C"`
+		SfcDivergences u2udb.Store `table:"K"`
 		// API-only tables
 		Receipts    u2udb.Store `table:"r"`
 		TxPositions u2udb.Store `table:"x"`
 		Txs         u2udb.Store `table:"X"`
+		// Address appearance indexes
+		AddressTxs       u2udb.Store `table:"y"`
+		SenderNonces     u2udb.Store `table:"n"`
+		ContractCreators u2udb.Store `table:"c"`
+		// Ancient store state
+		AncientState u2udb.Store `table:"j"`
+		// History expiry state
+		History u2udb.Store `table:"o"`
+		// State history
+		AccountHistory u2udb.Store `table:"p"`
+		StorageHistory u2udb.Store `table:"q"`
+		CodeHistory    u2udb.Store `table:"w"`
 	}
 
 	EvmDb    ethdb.Database
@@ -51,14 +66,23 @@ type Store struct {
 	Snaps    *snapshot.Tree
 
 	cache struct {
-		TxPositions *wlru.Cache `cache:"-"` // store by pointer
-		Receipts    *wlru.Cache `cache:"-"` // store by value
-		EvmBlocks   *wlru.Cache `cache:"-"` // store by pointer
+		TxPositions  *wlru.Cache  `cache:"-"` // store by pointer
+		Receipts     *wlru.Cache  `cache:"-"` // store by value
+		EvmBlocks    *wlru.Cache  `cache:"-"` // store by pointer
+		ReceiptsTail atomic.Value // store by value
+	}
+
+	ancient struct {
+		Receipts   *rawdb.ItemsFreezer
+		FirstBlock uint64 // accessed atomically
 	}
 
 	rlp rlpstore.Helper
 
-	triegc *prque.Prque // Priority queue mapping block numbers to tries to gc
+	triegc   *prque.Prque // Priority queue mapping block numbers to tries to gc
+	triedbMu *sync.Mutex  // Serializes the trie DB mutators with the state pruning
+
+	stateWrites *stateWrites
 
 	logger.Instance
 }
@@ -74,6 +98,9 @@ func NewStore(dbs u2udb.DBProducer, cfg StoreConfig) *Store {
 		Instance: logger.New("evm-store"),
 		rlp:      rlpstore.Helper{logger.New("rlp")},
 		triegc:   prque.New(nil),
+		triedbMu: new(sync.Mutex),
+
+		stateWrites: new(stateWrites),
 	}
 
 	err := table.OpenTables(&s.table, dbs, "evm")
@@ -81,10 +108,17 @@ func NewStore(dbs u2udb.DBProducer, cfg StoreConfig) *Store {
 		s.Log.Crit("Failed to open tables", "err", err)
 	}
 
+	s.initStateScheme()
 	s.initEVMDB()
 	s.EvmLogs = topicsdb.NewWithThreadPool(dbs)
 	s.initCache()
 
+	if cfg.AncientDir != "" {
+		if err := s.openAncient(); err != nil {
+			s.Log.Crit("Failed to open ancient store", "err", err)
+		}
+	}
+
 	return s
 }
 
@@ -100,6 +134,11 @@ func (s *Store) Close() error {
 	table.MigrateTables(&s.table, nil)
 	table.MigrateCaches(&s.cache, setnil)
 	s.EvmLogs.Close()
+	if s.ancient.Receipts != nil {
+		if err := s.ancient.Receipts.Close(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -113,12 +152,14 @@ func (s *Store) initEVMDB() {
 	s.EvmDb = rawdb.NewDatabase(
 		udb2ethdb.Wrap(
 			nokeyiserr.Wrap(
-				s.table.Evm)))
+				&trackedStore{s.table.Evm, s.stateWrites})))
 	s.EvmState = state.NewDatabaseWithConfig(s.EvmDb, &trie.Config{
 		Cache:     s.cfg.Cache.EvmDatabase / opt.MiB,
 		Journal:   s.cfg.Cache.TrieCleanJournal,
 		Preimages: s.cfg.EnablePreimageRecording,
 		GreedyGC:  s.cfg.Cache.GreedyGC,
+		// the path scheme overwrites the nodes of the latest state, keep them for the concurrent readers
+		StaleCommits: TriesInMemory,
 	})
 	if s.cfg.SfcEnabled {
 		s.SfcDb = rawdb.NewDatabase(
@@ -172,6 +213,8 @@ func (s *Store) RebuildEvmSnapshot(root common.Hash) {
 func (s *Store) CleanCommit(block iblockproc.BlockState) error {
 	// Don't need to reference the current state root
 	// due to it already be referenced on `Commit()` function
+	s.triedbMu.Lock()
+	defer s.triedbMu.Unlock()
 	triedb := s.EvmState.TrieDB()
 	stateRoot := common.Hash(block.FinalizedStateRoot)
 	if current := uint64(block.LastBlock.Idx); current > TriesInMemory {
@@ -207,21 +250,24 @@ func (s *Store) IsEvmSnapshotPaused() bool {
 func (s *Store) Commit(block idx.Block, root hash.Hash, flush bool) error {
 	triedb := s.EvmState.TrieDB()
 	stateRoot := common.Hash(root)
-	// If we're applying genesis or running an archive node, always flush
-	if flush || s.cfg.Cache.TrieDirtyDisabled {
+	// If we're applying genesis or running an archive node, always flush.
+	// The path scheme keeps only the latest state, which is always flushed too.
+	if flush || s.cfg.Cache.TrieDirtyDisabled || triedb.Scheme() == rawdb.PathScheme {
 		err := triedb.Commit(stateRoot, false, nil)
 		if err != nil {
 			s.Log.Error("Failed to flush trie DB into main DB", "err", err)
 		}
 		return err
 	} else {
+		s.triedbMu.Lock()
+		defer s.triedbMu.Unlock()
 		// Full but not archive node, do proper garbage collection
 		triedb.Reference(stateRoot, common.Hash{}) // metadata reference to keep trie alive
 		s.triegc.Push(stateRoot, -int64(block))
 
 		if current := uint64(block); current > TriesInMemory {
 			// If we exceeded our memory allowance, flush matured singleton nodes to disk
-			s.Cap()
+			s.cap()
 
 			// Find the next state trie we need to commit
 			chosen := current - TriesInMemory
@@ -313,6 +359,12 @@ func (s *Store) Flush(block iblockproc.BlockState) {
 
 // Cap flush matured singleton nodes to disk
 func (s *Store) Cap() {
+	s.triedbMu.Lock()
+	defer s.triedbMu.Unlock()
+	s.cap()
+}
+
+func (s *Store) cap() {
 	triedb := s.EvmState.TrieDB()
 	var (
 		nodes, imgs = triedb.Size()
@@ -339,6 +391,10 @@ func (s *Store) CapSfcState() {
 
 // StateDB returns state database.
 func (s *Store) StateDB(from hash.Hash) (*state.StateDB, error) {
+	if triedb := s.EvmState.TrieDB(); triedb.Scheme() == rawdb.PathScheme && !triedb.Readable(common.Hash(from)) {
+		// the nodes of an old state may be still cached, but the state isn't complete
+		return nil, &trie.MissingNodeError{NodeHash: common.Hash(from)}
+	}
 	return state.NewWithSnapLayers(common.Hash(from), s.EvmState, s.Snaps, 0)
 }
 
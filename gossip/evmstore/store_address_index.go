@@ -0,0 +1,216 @@
+package evmstore
+
+import (
+	"encoding/binary"
+
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+	"github.com/unicornultrafoundation/go-helios/u2udb"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/crypto"
+	"github.com/unicornultrafoundation/go-u2u/utils/signers/internaltx"
+)
+
+const (
+	addressTxKeySize = common.AddressLength + 8 + 4
+	// maxBackwardWindow limits the number of blocks scanned at once by the backward iteration
+	maxBackwardWindow = idx.Block(1 << 20)
+)
+
+// AddressTx locates a transaction in which an address appears
+type AddressTx struct {
+	Block       idx.Block
+	BlockOffset uint32
+	TxHash      common.Hash
+}
+
+// ContractCreator is the transaction which created a contract
+type ContractCreator struct {
+	TxHash  common.Hash
+	Creator common.Address
+}
+
+func addressTxKey(addr common.Address, n idx.Block, offset uint32) []byte {
+	key := make([]byte, addressTxKeySize)
+	copy(key, addr.Bytes())
+	binary.BigEndian.PutUint64(key[common.AddressLength:], uint64(n))
+	binary.BigEndian.PutUint32(key[common.AddressLength+8:], offset)
+	return key
+}
+
+func parseAddressTx(key, value []byte) (AddressTx, bool) {
+	if len(key) != addressTxKeySize || len(value) != common.HashLength {
+		return AddressTx{}, false
+	}
+	return AddressTx{
+		Block:       idx.Block(binary.BigEndian.Uint64(key[common.AddressLength:])),
+		BlockOffset: binary.BigEndian.Uint32(key[common.AddressLength+8:]),
+		TxHash:      common.BytesToHash(value),
+	}, true
+}
+
+func senderNonceKey(addr common.Address, nonce uint64) []byte {
+	key := make([]byte, common.AddressLength+8)
+	copy(key, addr.Bytes())
+	binary.BigEndian.PutUint64(key[common.AddressLength:], nonce)
+	return key
+}
+
+// txAppearances returns the addresses which appear in the transaction: the sender, the recipient,
+// the created contract, the participants of the internal calls and the emitters of the logs
+func txAppearances(from common.Address, tx *types.Transaction, r *types.Receipt, calls *InternalCalls) []common.Address {
+	addrs := make([]common.Address, 0, 3+len(r.Logs))
+	seen := make(map[common.Address]bool, cap(addrs))
+	add := func(addr common.Address) {
+		if !seen[addr] {
+			seen[addr] = true
+			addrs = append(addrs, addr)
+		}
+	}
+	add(from)
+	if tx.To() != nil {
+		add(*tx.To())
+	} else {
+		add(crypto.CreateAddress(from, tx.Nonce()))
+	}
+	if calls != nil {
+		for _, addr := range calls.Addresses {
+			add(addr)
+		}
+	}
+	for _, l := range r.Logs {
+		add(l.Address)
+	}
+	return addrs
+}
+
+// AddressIndexEnabled returns true if the transactions are indexed by the appearing addresses.
+func (s *Store) AddressIndexEnabled() bool {
+	return s.cfg.AddressIndex
+}
+
+// IndexAddresses indexes the appearances of the addresses in the block transactions,
+// the transactions by their sender and nonce and the creators of the contracts.
+// The internal calls are optional, they are indexed for the transactions present in the map.
+func (s *Store) IndexAddresses(n idx.Block, txs types.Transactions, receipts types.Receipts, signer types.Signer, calls map[common.Hash]*InternalCalls) {
+	if !s.cfg.AddressIndex {
+		return
+	}
+	if len(txs) != len(receipts) {
+		s.Log.Error("Transaction and receipt count mismatch", "block", n, "txs", len(txs), "receipts", len(receipts))
+		return
+	}
+	addressTxs := s.table.AddressTxs.NewBatch()
+	senderNonces := s.table.SenderNonces.NewBatch()
+	creators := s.table.ContractCreators.NewBatch()
+	for i, tx := range txs {
+		from, err := internaltx.Sender(signer, tx)
+		if err != nil {
+			s.Log.Warn("Failed to derive transaction sender", "tx", tx.Hash(), "err", err)
+			continue
+		}
+		r := receipts[i]
+		txCalls := calls[tx.Hash()]
+		for _, addr := range txAppearances(from, tx, r, txCalls) {
+			if err := addressTxs.Put(addressTxKey(addr, n, uint32(i)), tx.Hash().Bytes()); err != nil {
+				s.Log.Crit("Failed to put key-value", "err", err)
+			}
+		}
+		if err := senderNonces.Put(senderNonceKey(from, tx.Nonce()), tx.Hash().Bytes()); err != nil {
+			s.Log.Crit("Failed to put key-value", "err", err)
+		}
+		if tx.To() == nil && r.Status == types.ReceiptStatusSuccessful {
+			contract := crypto.CreateAddress(from, tx.Nonce())
+			if err := creators.Put(contract.Bytes(), append(tx.Hash().Bytes(), from.Bytes()...)); err != nil {
+				s.Log.Crit("Failed to put key-value", "err", err)
+			}
+		}
+		if txCalls != nil && r.Status == types.ReceiptStatusSuccessful {
+			for contract, creator := range txCalls.Created {
+				if err := creators.Put(contract.Bytes(), append(tx.Hash().Bytes(), creator.Bytes()...)); err != nil {
+					s.Log.Crit("Failed to put key-value", "err", err)
+				}
+			}
+		}
+	}
+	for _, batch := range []u2udb.Batch{addressTxs, senderNonces, creators} {
+		if err := batch.Write(); err != nil {
+			s.Log.Crit("Failed to write batch", "err", err)
+		}
+	}
+}
+
+// ForEachAddressTx iterates over the transactions in which the address appears,
+// starting from the block in ascending order.
+func (s *Store) ForEachAddressTx(addr common.Address, from idx.Block, onTx func(AddressTx) bool) {
+	it := s.table.AddressTxs.NewIterator(addr.Bytes(), from.Bytes())
+	defer it.Release()
+	for it.Next() {
+		tx, ok := parseAddressTx(it.Key(), it.Value())
+		if !ok {
+			continue
+		}
+		if !onTx(tx) {
+			return
+		}
+	}
+}
+
+// ForEachAddressTxBackward iterates over the transactions in which the address appears,
+// in the blocks below the given one in descending order.
+func (s *Store) ForEachAddressTxBackward(addr common.Address, before idx.Block, onTx func(AddressTx) bool) {
+	// the index is ordered by blocks ascending, so it's scanned by the growing windows
+	window := idx.Block(1024)
+	for hi := before; hi > 0; {
+		lo := idx.Block(0)
+		if hi > window {
+			lo = hi - window
+		}
+		txs := make([]AddressTx, 0)
+		s.ForEachAddressTx(addr, lo, func(tx AddressTx) bool {
+			if tx.Block >= hi {
+				return false
+			}
+			txs = append(txs, tx)
+			return true
+		})
+		for i := len(txs) - 1; i >= 0; i-- {
+			if !onTx(txs[i]) {
+				return
+			}
+		}
+		hi = lo
+		if window < maxBackwardWindow {
+			window *= 2
+		}
+	}
+}
+
+// GetTxBySenderAndNonce returns the hash of the transaction with the sender and nonce.
+func (s *Store) GetTxBySenderAndNonce(addr common.Address, nonce uint64) *common.Hash {
+	buf, err := s.table.SenderNonces.Get(senderNonceKey(addr, nonce))
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if len(buf) != common.HashLength {
+		return nil
+	}
+	h := common.BytesToHash(buf)
+	return &h
+}
+
+// GetContractCreator returns the transaction which created the contract.
+func (s *Store) GetContractCreator(addr common.Address) *ContractCreator {
+	buf, err := s.table.ContractCreators.Get(addr.Bytes())
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if len(buf) != common.HashLength+common.AddressLength {
+		return nil
+	}
+	return &ContractCreator{
+		TxHash:  common.BytesToHash(buf[:common.HashLength]),
+		Creator: common.BytesToAddress(buf[common.HashLength:]),
+	}
+}
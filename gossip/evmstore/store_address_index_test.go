@@ -0,0 +1,103 @@
+package evmstore
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+	"github.com/unicornultrafoundation/go-helios/u2udb/memorydb"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/crypto"
+	"github.com/unicornultrafoundation/go-u2u/logger"
+)
+
+func TestStoreIndexAddresses(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	recipient := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	emitter := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	callee := common.HexToAddress("0x3000000000000000000000000000000000000003")
+	internalContract := common.HexToAddress("0x4000000000000000000000000000000000000004")
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+
+	cfg := LiteStoreConfig()
+	cfg.AddressIndex = true
+	store := NewStore(memorydb.NewProducer(""), cfg)
+	disabled := cachedStore()
+	hashes := make([]common.Hash, 0)
+	for n := idx.Block(1); n <= 3000; n += 1000 {
+		var to *common.Address
+		if n != 1 {
+			to = &recipient
+		}
+		tx, err := types.SignNewTx(key, signer, &types.LegacyTx{Nonce: uint64(n / 1000), To: to, GasPrice: big.NewInt(1)})
+		require.NoError(err)
+		receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{{Address: emitter}}}
+		var calls map[common.Hash]*InternalCalls
+		if n == 1001 {
+			calls = map[common.Hash]*InternalCalls{
+				tx.Hash(): {
+					Addresses: []common.Address{recipient, callee, internalContract},
+					Created:   map[common.Address]common.Address{internalContract: callee},
+				},
+			}
+		}
+		store.IndexAddresses(n, types.Transactions{tx}, types.Receipts{receipt}, signer, calls)
+		disabled.IndexAddresses(n, types.Transactions{tx}, types.Receipts{receipt}, signer, calls)
+		hashes = append(hashes, tx.Hash())
+	}
+
+	collect := func(addr common.Address, from idx.Block, reverse bool) []common.Hash {
+		res := make([]common.Hash, 0)
+		onTx := func(tx AddressTx) bool {
+			res = append(res, tx.TxHash)
+			return true
+		}
+		if reverse {
+			store.ForEachAddressTxBackward(addr, from, onTx)
+		} else {
+			store.ForEachAddressTx(addr, from, onTx)
+		}
+		return res
+	}
+	require.Equal(hashes, collect(sender, 0, false))
+	require.Equal(hashes[1:], collect(sender, 2, false))
+	require.Equal(hashes[1:], collect(recipient, 0, false))
+	require.Equal(hashes, collect(emitter, 0, false))
+	require.Equal([]common.Hash{hashes[1], hashes[0]}, collect(sender, 2001, true))
+	require.Equal([]common.Hash{hashes[2], hashes[1], hashes[0]}, collect(emitter, 1<<30, true))
+
+	// the created contract appears in the creation transaction
+	contract := crypto.CreateAddress(sender, 0)
+	require.Equal(hashes[:1], collect(contract, 0, false))
+	creator := store.GetContractCreator(contract)
+	require.NotNil(creator)
+	require.Equal(hashes[0], creator.TxHash)
+	require.Equal(sender, creator.Creator)
+	require.Nil(store.GetContractCreator(recipient))
+
+	// the participants of the internal calls appear in the transaction
+	require.Equal(hashes[1:2], collect(callee, 0, false))
+	require.Equal(hashes[1:2], collect(internalContract, 0, false))
+	creator = store.GetContractCreator(internalContract)
+	require.NotNil(creator)
+	require.Equal(hashes[1], creator.TxHash)
+	require.Equal(callee, creator.Creator)
+
+	require.Equal(&hashes[2], store.GetTxBySenderAndNonce(sender, 2))
+	require.Nil(store.GetTxBySenderAndNonce(sender, 3))
+
+	// nothing is indexed if the index is disabled
+	disabled.ForEachAddressTx(sender, 0, func(AddressTx) bool {
+		t.Fatal("unexpected indexed transaction")
+		return false
+	})
+	require.Nil(disabled.GetTxBySenderAndNonce(sender, 0))
+	require.Nil(disabled.GetContractCreator(contract))
+}
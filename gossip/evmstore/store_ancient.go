@@ -0,0 +1,132 @@
+package evmstore
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/unicornultrafoundation/go-helios/common/bigendian"
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/rlp"
+)
+
+const ancientReceiptsTable = "receipts"
+
+var (
+	ancientFirstBlockKey = []byte("f")
+	ancientItemsKey      = []byte("n")
+)
+
+// openAncient opens the ancient store of the receipts and discards the items,
+// which were appended after the last flush of the key-value database
+func (s *Store) openAncient() error {
+	receipts, err := rawdb.NewItemsFreezer(s.cfg.AncientDir, "u2u/evm/", map[string]bool{
+		ancientReceiptsTable: false,
+	})
+	if err != nil {
+		return err
+	}
+	items := s.getAncientCounter(ancientItemsKey)
+	if receipts.Items() < items {
+		_ = receipts.Close()
+		return fmt.Errorf("ancient receipts are missing, stored %d, expected %d", receipts.Items(), items)
+	}
+	if err := receipts.Truncate(items); err != nil {
+		_ = receipts.Close()
+		return err
+	}
+	s.ancient.Receipts = receipts
+	atomic.StoreUint64(&s.ancient.FirstBlock, s.getAncientCounter(ancientFirstBlockKey))
+	return nil
+}
+
+func (s *Store) getAncientCounter(key []byte) uint64 {
+	b, err := s.table.AncientState.Get(key)
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if b == nil {
+		return 0
+	}
+	return bigendian.BytesToUint64(b)
+}
+
+func (s *Store) setAncientCounter(key []byte, v uint64) {
+	if err := s.table.AncientState.Put(key, bigendian.Uint64ToBytes(v)); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// HasAncient returns true if the ancient store is enabled.
+func (s *Store) HasAncient() bool {
+	return s.ancient.Receipts != nil
+}
+
+// getAncientReceiptsRLP returns the receipts of the block from the ancient store, or nil if they aren't frozen.
+func (s *Store) getAncientReceiptsRLP(n idx.Block) rlp.RawValue {
+	if s.ancient.Receipts == nil {
+		return nil
+	}
+	first := atomic.LoadUint64(&s.ancient.FirstBlock)
+	if uint64(n) < first || !s.ancient.Receipts.Has(uint64(n)-first) {
+		return nil
+	}
+	buf, err := s.ancient.Receipts.Retrieve(ancientReceiptsTable, uint64(n)-first)
+	if err != nil {
+		s.Log.Crit("Failed to read ancient receipts", "block", n, "err", err)
+	}
+	if len(buf) == 0 {
+		return nil
+	}
+	return buf
+}
+
+// FreezeReceipts moves the receipts of the blocks [from, to] into the ancient store.
+// The blocks have to follow the last frozen block.
+func (s *Store) FreezeReceipts(from, to idx.Block) error {
+	if s.ancient.Receipts == nil {
+		return nil
+	}
+	first := atomic.LoadUint64(&s.ancient.FirstBlock)
+	items := s.ancient.Receipts.Items()
+	if items == 0 {
+		first = uint64(from)
+		atomic.StoreUint64(&s.ancient.FirstBlock, first)
+	} else if uint64(from) != first+items {
+		return fmt.Errorf("block %d doesn't follow the last frozen block %d", from, first+items-1)
+	}
+	err := s.appendAncientReceipts(first, from, to)
+	if err == nil {
+		err = s.ancient.Receipts.Sync()
+	}
+	if err != nil {
+		// discard the receipts appended during the failed migration
+		if terr := s.ancient.Receipts.Truncate(items); terr != nil {
+			s.Log.Crit("Failed to truncate ancient receipts", "err", terr)
+		}
+		return err
+	}
+	// the counters go first, so the frozen receipts are never lost on a partial flush
+	s.setAncientCounter(ancientFirstBlockKey, first)
+	s.setAncientCounter(ancientItemsKey, s.ancient.Receipts.Items())
+	for n := from; n <= to; n++ {
+		if err := s.table.Receipts.Delete(n.Bytes()); err != nil {
+			s.Log.Crit("Failed to delete key", "err", err)
+		}
+	}
+	return nil
+}
+
+func (s *Store) appendAncientReceipts(first uint64, from, to idx.Block) error {
+	for n := from; n <= to; n++ {
+		buf, err := s.table.Receipts.Get(n.Bytes())
+		if err != nil {
+			return err
+		}
+		if err := s.ancient.Receipts.Append(uint64(n)-first, map[string][]byte{ancientReceiptsTable: buf}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package evmstore
+
+import (
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+)
+
+var receiptsTailKey = []byte("r")
+
+// prunedHistoryError is returned for the requests of the pruned receipts and logs.
+type prunedHistoryError struct{}
+
+func (e *prunedHistoryError) Error() string  { return "pruned history unavailable" }
+func (e *prunedHistoryError) ErrorCode() int { return 4444 }
+
+// ErrPrunedHistory is returned if the requested receipts or logs are below the retained history tail.
+var ErrPrunedHistory error = &prunedHistoryError{}
+
+// GetReceiptsTail returns the lowest block, which receipts are retained, or zero if no receipts are pruned.
+func (s *Store) GetReceiptsTail() idx.Block {
+	if v := s.cache.ReceiptsTail.Load(); v != nil {
+		return v.(idx.Block)
+	}
+	b, err := s.table.History.Get(receiptsTailKey)
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	var tail idx.Block
+	if b != nil {
+		tail = idx.BytesToBlock(b)
+	}
+	s.cache.ReceiptsTail.Store(tail)
+	return tail
+}
+
+// SetReceiptsTail stores the lowest block, which receipts are retained.
+func (s *Store) SetReceiptsTail(n idx.Block) {
+	if err := s.table.History.Put(receiptsTailKey, n.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+	s.cache.ReceiptsTail.Store(n)
+}
@@ -41,11 +41,24 @@ func (s *Store) SetRawReceipts(n idx.Block, receipts []*types.ReceiptForStorage)
 	return len(buf)
 }
 
+// DelReceipts removes transaction receipts of the block.
+func (s *Store) DelReceipts(n idx.Block) {
+	if err := s.table.Receipts.Delete(n.Bytes()); err != nil {
+		s.Log.Crit("Failed to delete key", "err", err)
+	}
+
+	// Remove from LRU cache.
+	s.cache.Receipts.Remove(n)
+}
+
 func (s *Store) GetRawReceiptsRLP(n idx.Block) rlp.RawValue {
 	buf, err := s.table.Receipts.Get(n.Bytes())
 	if err != nil {
 		s.Log.Crit("Failed to get key-value", "err", err)
 	}
+	if buf == nil {
+		return s.getAncientReceiptsRLP(n)
+	}
 	return buf
 }
 
@@ -4,8 +4,10 @@ import (
 	"encoding/binary"
 
 	"github.com/unicornultrafoundation/go-helios/native/idx"
-	
+
 	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/evmcore"
+	"github.com/unicornultrafoundation/go-u2u/rlp"
 )
 
 var (
@@ -33,3 +35,33 @@ func encodeBlockNumber(number uint64) []byte {
 func SfcStateRootKey(n idx.Block, hash []byte) []byte {
 	return append(append(sfcStateRootPrefix, encodeBlockNumber(uint64(n))...), hash...)
 }
+
+// SetSfcDivergence stores a divergence of the SFC state from the main state.
+func (s *Store) SetSfcDivergence(d *evmcore.SfcDivergence) {
+	s.rlp.Set(s.table.SfcDivergences, sfcDivergenceKey(d), d)
+}
+
+// ForEachSfcDivergence iterates the stored divergences of the SFC state in the
+// order of blocks, starting from the given block, until fn returns false.
+func (s *Store) ForEachSfcDivergence(from idx.Block, fn func(d *evmcore.SfcDivergence) bool) {
+	it := s.table.SfcDivergences.NewIterator(nil, encodeBlockNumber(uint64(from)))
+	defer it.Release()
+	for it.Next() {
+		var d evmcore.SfcDivergence
+		err := rlp.DecodeBytes(it.Value(), &d)
+		if err != nil {
+			s.Log.Crit("Failed to decode SFC divergence", "err", err)
+		}
+		if !fn(&d) {
+			break
+		}
+	}
+}
+
+// sfcDivergenceKey is num (uint64 big endian) + tx hash + contract address
+func sfcDivergenceKey(d *evmcore.SfcDivergence) []byte {
+	key := make([]byte, 0, 8+common.HashLength+common.AddressLength)
+	key = append(key, encodeBlockNumber(d.BlockNumber)...)
+	key = append(key, d.TxHash.Bytes()...)
+	return append(key, d.Address.Bytes()...)
+}
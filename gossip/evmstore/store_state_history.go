@@ -0,0 +1,473 @@
+package evmstore
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/unicornultrafoundation/go-helios/hash"
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+	"github.com/unicornultrafoundation/go-helios/u2udb"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/core/state"
+	"github.com/unicornultrafoundation/go-u2u/core/state/snapshot"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/rlp"
+	"github.com/unicornultrafoundation/go-u2u/trie"
+)
+
+// The state history is a list of the reverse diffs of the EVM state: the values of the accounts,
+// storage slots and contract codes as they were before a block changed them. Together with the
+// head state, it's enough to read the state of an old block, which trie nodes are already
+// overwritten by the path scheme.
+//
+// An account entry is keyed by account hash + block, a storage entry is keyed by account hash +
+// slot hash + block. The value of a key at the block N is the previous value of the first entry
+// above N, or the head value if the key wasn't changed since N.
+
+var (
+	stateHistoryFirstKey = []byte("hf")
+	stateHistoryLastKey  = []byte("hl")
+)
+
+// ErrStateHistoryUnavailable is returned if the state history doesn't cover the requested block.
+var ErrStateHistoryUnavailable = errors.New("state history unavailable")
+
+// StateHistoryEnabled returns true if the state history is recorded, which is done along with the path scheme
+func (s *Store) StateHistoryEnabled() bool {
+	return s.StateScheme() == rawdb.PathScheme
+}
+
+// GetStateHistoryRange returns the first and the last blocks recorded into the state history,
+// or zeros if nothing is recorded. The states of the blocks [first-1, last] can be read.
+func (s *Store) GetStateHistoryRange() (first, last idx.Block) {
+	return s.getBlockKey(stateHistoryFirstKey), s.getBlockKey(stateHistoryLastKey)
+}
+
+func (s *Store) setStateHistoryRange(first, last idx.Block) {
+	if err := s.table.History.Put(stateHistoryFirstKey, first.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+	if err := s.table.History.Put(stateHistoryLastKey, last.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+func (s *Store) getBlockKey(key []byte) idx.Block {
+	b, err := s.table.History.Get(key)
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if b == nil {
+		return 0
+	}
+	return idx.BytesToBlock(b)
+}
+
+// WriteStateHistory records the reverse diff of the block n, which changed the state from parentRoot to root.
+// Both states must be available. Does nothing if the state history is disabled.
+func (s *Store) WriteStateHistory(n idx.Block, parentRoot, root common.Hash) error {
+	if !s.StateHistoryEnabled() {
+		return nil
+	}
+	triedb := s.EvmState.TrieDB()
+	prevAccounts, err := diffLeaves(triedb, common.Hash{}, root, parentRoot)
+	if err != nil {
+		return err
+	}
+	newAccounts, err := diffLeaves(triedb, common.Hash{}, parentRoot, root)
+	if err != nil {
+		return err
+	}
+
+	accounts := s.table.AccountHistory.NewBatch()
+	storage := s.table.StorageHistory.NewBatch()
+	codes := s.table.CodeHistory.NewBatch()
+	writeAccount := func(addrHash common.Hash, prevEnc, newEnc []byte) error {
+		if err := putStateHistory(accounts, addrHash.Bytes(), n, prevEnc); err != nil {
+			return err
+		}
+		prevAcc, err := decodeAccount(prevEnc)
+		if err != nil {
+			return err
+		}
+		newAcc, err := decodeAccount(newEnc)
+		if err != nil {
+			return err
+		}
+		// storage slots
+		if prevAcc.Root != newAcc.Root {
+			prevSlots, err := diffLeaves(triedb, addrHash, newAcc.Root, prevAcc.Root)
+			if err != nil {
+				return err
+			}
+			newSlots, err := diffLeaves(triedb, addrHash, prevAcc.Root, newAcc.Root)
+			if err != nil {
+				return err
+			}
+			for slot, prev := range prevSlots {
+				if err := putStateHistory(storage, append(addrHash.Bytes(), slot.Bytes()...), n, prev); err != nil {
+					return err
+				}
+			}
+			for slot := range newSlots {
+				if _, ok := prevSlots[slot]; ok {
+					continue
+				}
+				if err := putStateHistory(storage, append(addrHash.Bytes(), slot.Bytes()...), n, nil); err != nil {
+					return err
+				}
+			}
+		}
+		// the code of a removed contract may be pruned from the state
+		if len(prevEnc) != 0 && prevAcc.CodeHash != newAcc.CodeHash && prevAcc.CodeHash != common.BytesToHash(EmptyCode) {
+			code := rawdb.ReadCode(s.EvmDb, prevAcc.CodeHash)
+			if len(code) != 0 {
+				if err := codes.Put(prevAcc.CodeHash.Bytes(), code); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	for addrHash, prev := range prevAccounts {
+		if err := writeAccount(addrHash, prev, newAccounts[addrHash]); err != nil {
+			return err
+		}
+	}
+	for addrHash, enc := range newAccounts {
+		if _, ok := prevAccounts[addrHash]; ok {
+			continue
+		}
+		if err := writeAccount(addrHash, nil, enc); err != nil {
+			return err
+		}
+	}
+	for _, batch := range []u2udb.Batch{codes, storage, accounts} {
+		if err := batch.Write(); err != nil {
+			return err
+		}
+	}
+
+	s.triedbMu.Lock()
+	defer s.triedbMu.Unlock()
+	first, last := s.GetStateHistoryRange()
+	if first == 0 || n < first || n > last+1 {
+		// the history is interrupted, the older entries aren't usable anymore
+		first = n
+	}
+	s.setStateHistoryRange(first, n)
+	return nil
+}
+
+// HistoricalStateDB returns the read-only state of the block n with the given root. The state is read from
+// the persisted head state and the state history of the blocks after n. The block n must be older than the
+// persisted head state.
+func (s *Store) HistoricalStateDB(n idx.Block, root hash.Hash) (*state.StateDB, error) {
+	first, last := s.GetStateHistoryRange()
+	if !s.StateHistoryEnabled() || first == 0 || n+1 < first || n >= last {
+		return nil, ErrStateHistoryUnavailable
+	}
+	snap := &historicalState{
+		store: s,
+		block: n,
+		root:  common.Hash(root),
+	}
+	if err := snap.openHead(); err != nil {
+		return nil, err
+	}
+	db := &historicalCodes{
+		Database: s.EvmState,
+		store:    s,
+	}
+	return state.NewWithSnapshot(snap.head.Hash(), db, snap)
+}
+
+// PruneStateHistory deletes the state history of the blocks up to the given one, so the states of the older
+// blocks can't be read anymore. The history of the last block is retained.
+func (s *Store) PruneStateHistory(until idx.Block) (int, error) {
+	s.triedbMu.Lock()
+	first, last := s.GetStateHistoryRange()
+	if first == 0 || until < first || until >= last {
+		s.triedbMu.Unlock()
+		return 0, nil
+	}
+	// the state history is cut before the entries are deleted
+	s.setStateHistoryRange(until+1, last)
+	s.triedbMu.Unlock()
+
+	count := 0
+	for _, table := range []u2udb.Store{s.table.AccountHistory, s.table.StorageHistory} {
+		deleted, err := pruneStateHistory(table, until)
+		if err != nil {
+			return count, err
+		}
+		count += deleted
+	}
+	return count, nil
+}
+
+func pruneStateHistory(table u2udb.Store, until idx.Block) (int, error) {
+	var (
+		count int
+		batch = table.NewBatch()
+		it    = table.NewIterator(nil, nil)
+	)
+	defer func() {
+		it.Release()
+	}()
+	for it.Next() {
+		key := it.Key()
+		if idx.BytesToBlock(key[len(key)-8:]) > until {
+			continue
+		}
+		if err := batch.Delete(common.CopyBytes(key)); err != nil {
+			return count, err
+		}
+		count++
+		if count%10000 == 0 {
+			if err := batch.Write(); err != nil {
+				return count, err
+			}
+			batch.Reset()
+			// Recreate the iterator after every batch commit in order
+			// to allow the underlying compactor to delete the entries.
+			start := common.CopyBytes(key)
+			it.Release()
+			it = table.NewIterator(nil, start)
+		}
+	}
+	if err := it.Error(); err != nil {
+		return count, err
+	}
+	return count, batch.Write()
+}
+
+// getStateHistory returns the previous value of the first entry of the key above the block n
+func (s *Store) getStateHistory(table u2udb.Store, key []byte, n idx.Block) ([]byte, bool) {
+	it := table.NewIterator(key, (n + 1).Bytes())
+	defer it.Release()
+	if !it.Next() {
+		return nil, false
+	}
+	var prev []byte
+	if err := rlp.DecodeBytes(it.Value(), &prev); err != nil {
+		s.Log.Crit("Failed to decode rlp", "err", err)
+	}
+	return prev, true
+}
+
+func putStateHistory(batch u2udb.Batch, key []byte, n idx.Block, prev []byte) error {
+	// an absent value is encoded as an empty string
+	enc, err := rlp.EncodeToBytes(prev)
+	if err != nil {
+		return err
+	}
+	return batch.Put(append(common.CopyBytes(key), n.Bytes()...), enc)
+}
+
+// decodeAccount decodes the account of the trie, an absent account has the empty storage and code
+func decodeAccount(enc []byte) (acc struct {
+	Root     common.Hash
+	CodeHash common.Hash
+}, err error) {
+	acc.Root = types.EmptyRootHash
+	acc.CodeHash = common.BytesToHash(EmptyCode)
+	if len(enc) == 0 {
+		return acc, nil
+	}
+	var data state.Account
+	if err := rlp.DecodeBytes(enc, &data); err != nil {
+		return acc, err
+	}
+	acc.Root = data.Root
+	acc.CodeHash = common.BytesToHash(data.CodeHash)
+	return acc, nil
+}
+
+func isEmptyRoot(root common.Hash) bool {
+	return root == types.EmptyRootHash || root == common.Hash{}
+}
+
+// diffLeaves returns the leaves of the trie b, which aren't in the trie a. Both tries belong to the owner,
+// which is the zero hash for the account trie.
+func diffLeaves(triedb *trie.Database, owner, a, b common.Hash) (map[common.Hash][]byte, error) {
+	leaves := make(map[common.Hash][]byte)
+	if isEmptyRoot(b) {
+		return leaves, nil
+	}
+	tb, err := trie.NewWithOwner(owner, b, triedb)
+	if err != nil {
+		return nil, err
+	}
+	it := tb.NodeIterator(nil)
+	if !isEmptyRoot(a) {
+		ta, err := trie.NewWithOwner(owner, a, triedb)
+		if err != nil {
+			return nil, err
+		}
+		it, _ = trie.NewDifferenceIterator(ta.NodeIterator(nil), it)
+	}
+	for it.Next(true) {
+		if it.Leaf() {
+			leaves[common.BytesToHash(it.LeafKey())] = common.CopyBytes(it.LeafBlob())
+		}
+	}
+	return leaves, it.Error()
+}
+
+// historicalState is the snapshot layer of an old block, which is read from the head state and the state history
+type historicalState struct {
+	store *Store
+	block idx.Block
+	root  common.Hash
+
+	mu      sync.Mutex
+	head    *trie.Trie
+	storage map[common.Hash]*trie.Trie // storage tries of the head state
+}
+
+// openHead opens the trie of the persisted head state
+func (hs *historicalState) openHead() error {
+	head, err := trie.New(hs.store.diskStateRoot(), hs.store.EvmState.TrieDB())
+	if err != nil {
+		return err
+	}
+	hs.head = head
+	hs.storage = make(map[common.Hash]*trie.Trie)
+	return nil
+}
+
+// withHead runs the read of the state history and of the head state. The head state moves on with
+// the new blocks, which are recorded into the state history beforehand, so the whole read is repeated
+// at the new head if the nodes of the old one are overwritten. The state history is looked up again,
+// because it records the changes of the blocks up to the new head.
+func (hs *historicalState) withHead(read func() error) error {
+	err := read()
+	if _, ok := err.(*trie.MissingNodeError); !ok {
+		return err
+	}
+	if err := hs.openHead(); err != nil {
+		return err
+	}
+	return read()
+}
+
+// Root returns the state root of the block.
+func (hs *historicalState) Root() common.Hash {
+	return hs.root
+}
+
+func (hs *historicalState) account(addrHash common.Hash) (*state.Account, error) {
+	var enc []byte
+	err := hs.withHead(func() (err error) {
+		var ok bool
+		if enc, ok = hs.store.getStateHistory(hs.store.table.AccountHistory, addrHash.Bytes(), hs.block); ok {
+			return nil
+		}
+		enc, err = hs.head.TryGet(addrHash.Bytes())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(enc) == 0 {
+		return nil, nil
+	}
+	acc := new(state.Account)
+	if err := rlp.DecodeBytes(enc, acc); err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+// Account retrieves the account of the block in the snapshot slim data format.
+func (hs *historicalState) Account(addrHash common.Hash) (*snapshot.Account, error) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	acc, err := hs.account(addrHash)
+	if acc == nil || err != nil {
+		return nil, err
+	}
+	return &snapshot.Account{
+		Nonce:    acc.Nonce,
+		Balance:  acc.Balance,
+		Root:     acc.Root.Bytes(),
+		CodeHash: acc.CodeHash,
+	}, nil
+}
+
+// AccountRLP retrieves the account RLP of the block in the snapshot slim data format.
+func (hs *historicalState) AccountRLP(addrHash common.Hash) ([]byte, error) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	acc, err := hs.account(addrHash)
+	if acc == nil || err != nil {
+		return nil, err
+	}
+	return snapshot.SlimAccountRLP(acc.Nonce, acc.Balance, acc.Root, acc.CodeHash), nil
+}
+
+// Storage retrieves the storage slot of the block.
+func (hs *historicalState) Storage(addrHash, slotHash common.Hash) ([]byte, error) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	var value []byte
+	err := hs.withHead(func() error {
+		if prev, ok := hs.store.getStateHistory(hs.store.table.StorageHistory, append(addrHash.Bytes(), slotHash.Bytes()...), hs.block); ok {
+			value = prev
+			return nil
+		}
+		// the slot wasn't changed since the block, read it from the head state
+		t, ok := hs.storage[addrHash]
+		if !ok {
+			enc, err := hs.head.TryGet(addrHash.Bytes())
+			if err != nil {
+				return err
+			}
+			acc, err := decodeAccount(enc)
+			if err != nil {
+				return err
+			}
+			if !isEmptyRoot(acc.Root) {
+				if t, err = trie.NewWithOwner(addrHash, acc.Root, hs.store.EvmState.TrieDB()); err != nil {
+					return err
+				}
+			}
+			hs.storage[addrHash] = t
+		}
+		if t == nil {
+			value = nil
+			return nil
+		}
+		var err error
+		value, err = t.TryGet(slotHash.Bytes())
+		return err
+	})
+	return value, err
+}
+
+// historicalCodes is the state database, which reads the codes of the removed contracts from the state history
+type historicalCodes struct {
+	state.Database
+	store *Store
+}
+
+// ContractCode retrieves the contract code from the state or the state history.
+func (db *historicalCodes) ContractCode(addrHash, codeHash common.Hash) ([]byte, error) {
+	code, err := db.Database.ContractCode(addrHash, codeHash)
+	if err == nil {
+		return code, nil
+	}
+	if code, _ := db.store.table.CodeHistory.Get(codeHash.Bytes()); len(code) != 0 {
+		return code, nil
+	}
+	return nil, err
+}
+
+// ContractCodeSize retrieves the contract code size from the state or the state history.
+func (db *historicalCodes) ContractCodeSize(addrHash, codeHash common.Hash) (int, error) {
+	code, err := db.ContractCode(addrHash, codeHash)
+	return len(code), err
+}
@@ -0,0 +1,197 @@
+package evmstore
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unicornultrafoundation/go-helios/hash"
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+	"github.com/unicornultrafoundation/go-helios/u2udb/memorydb"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/core/state"
+	"github.com/unicornultrafoundation/go-u2u/trie"
+)
+
+func TestStoreStateHistory(t *testing.T) {
+	require := require.New(t)
+
+	cfg := LiteStoreConfig()
+	cfg.StateScheme = rawdb.PathScheme
+	store := NewStore(memorydb.NewProducer(""), cfg)
+	defer store.Close()
+
+	var (
+		addrs = []common.Address{{1}, {2}, {3}}
+		slot  = common.Hash{1}
+		roots = []common.Hash{{}}
+	)
+	changes := []func(statedb *state.StateDB){
+		func(statedb *state.StateDB) {
+			statedb.SetBalance(addrs[0], big.NewInt(1))
+			statedb.SetState(addrs[0], slot, common.Hash{1})
+			statedb.SetCode(addrs[0], []byte{1})
+			statedb.SetBalance(addrs[1], big.NewInt(100))
+			statedb.SetState(addrs[1], slot, common.Hash{0xff})
+			statedb.SetCode(addrs[1], []byte{0xaa})
+		},
+		func(statedb *state.StateDB) {
+			statedb.SetBalance(addrs[0], big.NewInt(2))
+			statedb.SetState(addrs[0], slot, common.Hash{2})
+		},
+		func(statedb *state.StateDB) {
+			statedb.Suicide(addrs[1])
+			statedb.SetBalance(addrs[0], big.NewInt(3))
+		},
+		func(statedb *state.StateDB) {
+			statedb.SetBalance(addrs[0], big.NewInt(4))
+			statedb.SetState(addrs[0], slot, common.Hash{})
+		},
+		func(statedb *state.StateDB) {
+			statedb.SetBalance(addrs[2], big.NewInt(5))
+		},
+	}
+	type account struct {
+		balance *big.Int
+		value   common.Hash
+		code    []byte
+	}
+	read := func(statedb *state.StateDB) []account {
+		accounts := make([]account, len(addrs))
+		for i, addr := range addrs {
+			accounts[i] = account{
+				balance: statedb.GetBalance(addr),
+				value:   statedb.GetState(addr, slot),
+				code:    statedb.GetCode(addr),
+			}
+		}
+		require.NoError(statedb.Error())
+		return accounts
+	}
+	statedb, err := store.StateDB(hash.Hash(roots[0]))
+	require.NoError(err)
+	expected := [][]account{read(statedb)}
+
+	for i, change := range changes {
+		n := idx.Block(i + 1)
+		statedb, err := store.StateDB(hash.Hash(roots[i]))
+		require.NoError(err)
+		change(statedb)
+		root, err := statedb.Commit(true)
+		require.NoError(err)
+		require.NoError(store.WriteStateHistory(n, roots[i], root))
+		require.NoError(store.Commit(n, hash.Hash(root), false))
+		roots = append(roots, root)
+
+		statedb, err = store.StateDB(hash.Hash(root))
+		require.NoError(err)
+		expected = append(expected, read(statedb))
+	}
+	first, last := store.GetStateHistoryRange()
+	require.Equal(idx.Block(1), first)
+	require.Equal(idx.Block(5), last)
+
+	// the overwritten nodes of the recent states are kept in memory only
+	head := idx.Block(len(roots) - 1)
+	statedb, err = store.StateDB(hash.Hash(roots[1]))
+	require.NoError(err)
+	require.Equal(expected[1], read(statedb))
+	store.initEVMDB()
+	_, err = store.StateDB(hash.Hash(roots[1]))
+	require.IsType(&trie.MissingNodeError{}, err)
+
+	for i, root := range roots[:head] {
+		statedb, err := store.HistoricalStateDB(idx.Block(i), hash.Hash(root))
+		require.NoError(err, "block %d", i)
+		require.Equal(expected[i], read(statedb), "block %d", i)
+	}
+
+	_, err = store.HistoricalStateDB(head, hash.Hash(roots[head]))
+	require.Equal(ErrStateHistoryUnavailable, err)
+
+	// the pruned history isn't used
+	_, err = store.PruneStateHistory(2)
+	require.NoError(err)
+	_, err = store.HistoricalStateDB(1, hash.Hash(roots[1]))
+	require.Equal(ErrStateHistoryUnavailable, err)
+	statedb, err = store.HistoricalStateDB(2, hash.Hash(roots[2]))
+	require.NoError(err)
+	require.Equal(expected[2], read(statedb))
+
+	// the interrupted history isn't used
+	require.NoError(store.WriteStateHistory(head+2, roots[head], roots[head]))
+	_, err = store.HistoricalStateDB(head-1, hash.Hash(roots[head-1]))
+	require.Equal(ErrStateHistoryUnavailable, err)
+}
+
+func TestStoreStateConcurrentReader(t *testing.T) {
+	require := require.New(t)
+
+	cfg := LiteStoreConfig()
+	cfg.StateScheme = rawdb.PathScheme
+	store := NewStore(memorydb.NewProducer(""), cfg)
+	defer store.Close()
+
+	addrs := make([]common.Address, 100)
+	for i := range addrs {
+		addrs[i] = common.Address{byte(i), 1}
+	}
+	root := common.Hash{}
+	commit := func(n idx.Block) common.Hash {
+		statedb, err := store.StateDB(hash.Hash(root))
+		require.NoError(err)
+		for _, addr := range addrs {
+			statedb.SetBalance(addr, big.NewInt(int64(n)))
+			statedb.SetState(addr, common.Hash{}, common.Hash{byte(n)})
+		}
+		next, err := statedb.Commit(true)
+		require.NoError(err)
+		require.NoError(store.WriteStateHistory(n, root, next))
+		require.NoError(store.Commit(n, hash.Hash(next), false))
+		root = next
+		return next
+	}
+	readRoot := commit(1)
+
+	// the state of the first block is read while the next blocks overwrite its nodes
+	var (
+		wg   sync.WaitGroup
+		stop = make(chan struct{})
+		errs = make(chan error, 1)
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			statedb, err := store.StateDB(hash.Hash(readRoot))
+			if err != nil {
+				errs <- err
+				return
+			}
+			for _, addr := range addrs {
+				if statedb.GetBalance(addr).Int64() != 1 || statedb.GetState(addr, common.Hash{}) != (common.Hash{1}) {
+					errs <- statedb.Error()
+					return
+				}
+			}
+			select {
+			case <-stop:
+				return
+			default:
+			}
+		}
+	}()
+	for n := idx.Block(2); n < TriesInMemory; n++ {
+		commit(n)
+	}
+	close(stop)
+	wg.Wait()
+	select {
+	case err := <-errs:
+		require.Fail("failed to read the state concurrently", "%v", err)
+	default:
+	}
+}
@@ -0,0 +1,258 @@
+package evmstore
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/core/state"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/ethdb"
+	"github.com/unicornultrafoundation/go-u2u/rlp"
+	"github.com/unicornultrafoundation/go-u2u/trie"
+)
+
+// markedDepth is the depth of the account trie nodes, which are remembered exactly
+// during the marking, so the subtrees shared by the retained states are marked once.
+// The roots of the storage tries are remembered exactly too.
+const markedDepth = 4
+
+var (
+	errStatePruningInterrupted = errors.New("state pruning is interrupted")
+	errSnapshotGenerating      = errors.New("EVM snapshot is being generated")
+	errPathSchemePruning       = errors.New("EVM state of the path scheme has no old states to prune")
+)
+
+// StatePruningStats is the result of the state pruning round
+type StatePruningStats struct {
+	Roots int
+	Nodes int
+	Size  common.StorageSize
+}
+
+// PruneState removes the trie nodes and contract codes, which don't belong to the given state roots,
+// while the node keeps writing the new states. The first root is the head state, which must be available.
+// The rest roots are retained if they're available, otherwise skipped.
+func (s *Store) PruneState(roots []common.Hash, set KeySet, quit <-chan struct{}) (StatePruningStats, error) {
+	var stats StatePruningStats
+	if len(roots) == 0 {
+		return stats, errors.New("no state roots to retain")
+	}
+	if s.StateScheme() == rawdb.PathScheme {
+		// only the latest state is kept, the sweeping would delete it
+		return stats, errPathSchemePruning
+	}
+	if s.Snaps != nil {
+		if generating, _ := s.Snaps.Generating(); generating {
+			// the snapshot generator reads the trie of the snapshot disk layer
+			return stats, errSnapshotGenerating
+		}
+	}
+	// the entries written since now are retained
+	s.stateWrites.start(set)
+	defer s.stateWrites.stop()
+
+	// pin the in-memory states, so they aren't garbage collected while marked
+	triedb := s.EvmState.TrieDB()
+	s.triedbMu.Lock()
+	for _, root := range roots {
+		triedb.Reference(root, common.Hash{})
+	}
+	s.triedbMu.Unlock()
+	defer func() {
+		s.triedbMu.Lock()
+		defer s.triedbMu.Unlock()
+		for _, root := range roots {
+			triedb.Dereference(root)
+		}
+	}()
+
+	marked := make(map[common.Hash]struct{})
+	for i, root := range roots {
+		err := markState(triedb, root, s.stateWrites, marked, quit)
+		if err == errStatePruningInterrupted {
+			return stats, err
+		}
+		if err != nil {
+			if i == 0 {
+				return stats, err
+			}
+			s.Log.Debug("Skipped unavailable state", "root", root, "err", err)
+			continue
+		}
+		stats.Roots++
+	}
+
+	var err error
+	stats.Nodes, stats.Size, err = s.sweepState(quit)
+	return stats, err
+}
+
+// markState adds the trie nodes and contract codes of the state into the set. Nodes remembered
+// in the marked map are skipped along with their subtrees. The map is updated only if the
+// whole state is marked, so the subtrees of an unavailable state aren't skipped later.
+func markState(triedb *trie.Database, root common.Hash, set KeySet, marked map[common.Hash]struct{}, quit <-chan struct{}) error {
+	marking := make(map[common.Hash]struct{})
+	seen := func(h common.Hash) bool {
+		_, ok := marked[h]
+		if !ok {
+			_, ok = marking[h]
+		}
+		return ok
+	}
+
+	err := markTrie(triedb, root, set, markedDepth, seen, marking, quit, func(leaf []byte) error {
+		var acc state.Account
+		if err := rlp.DecodeBytes(leaf, &acc); err != nil {
+			return err
+		}
+		if acc.Root != types.EmptyRootHash {
+			err := markTrie(triedb, acc.Root, set, 0, seen, marking, quit, nil)
+			if err != nil {
+				return err
+			}
+		}
+		if !bytes.Equal(acc.CodeHash, EmptyCode) {
+			_ = set.Put(acc.CodeHash, nil)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for h := range marking {
+		marked[h] = struct{}{}
+	}
+	return nil
+}
+
+func markTrie(triedb *trie.Database, root common.Hash, set KeySet, depth int, seen func(common.Hash) bool, marking map[common.Hash]struct{}, quit <-chan struct{}, onLeaf func([]byte) error) error {
+	if seen(root) {
+		return nil
+	}
+	t, err := trie.NewSecure(root, triedb)
+	if err != nil {
+		return err
+	}
+	it := t.NodeIterator(nil)
+	for descend := true; it.Next(descend); {
+		descend = true
+		h := it.Hash()
+		if h == (common.Hash{}) {
+			// embedded node or value
+			if it.Leaf() && onLeaf != nil {
+				if err := onLeaf(it.LeafBlob()); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if len(it.Path()) <= depth {
+			if seen(h) {
+				descend = false
+				continue
+			}
+			if isClosed(quit) {
+				return errStatePruningInterrupted
+			}
+			marking[h] = struct{}{}
+		}
+		_ = set.Put(h.Bytes(), nil)
+	}
+	return it.Error()
+}
+
+// sweepState deletes the trie nodes and contract codes, which aren't in the tracked set
+func (s *Store) sweepState(quit <-chan struct{}) (count int, size common.StorageSize, err error) {
+	var (
+		keys   [][]byte
+		batch  int
+		logged = time.Now()
+		it     = s.table.Evm.NewIterator(nil, nil)
+	)
+	defer func() {
+		it.Release()
+	}()
+	for it.Next() {
+		key := it.Key()
+		isCode, codeKey := rawdb.IsCodeKey(key)
+		if len(key) != common.HashLength && !isCode {
+			continue
+		}
+		checkKey := key
+		if isCode {
+			checkKey = codeKey
+		}
+		if ok, err := s.stateWrites.Contain(checkKey); err != nil {
+			return count, size, err
+		} else if ok {
+			continue
+		}
+		keys = append(keys, common.CopyBytes(key))
+		batch += len(key)
+		size += common.StorageSize(len(key) + len(it.Value()))
+
+		if batch >= ethdb.IdealBatchSize {
+			if isClosed(quit) {
+				return count, size, errStatePruningInterrupted
+			}
+			deleted, err := s.deleteUnmarked(keys)
+			if err != nil {
+				return count, size, err
+			}
+			count += deleted
+			keys, batch = keys[:0], 0
+			if time.Since(logged) > 8*time.Second {
+				s.Log.Info("Pruning state data", "nodes", count, "size", size)
+				logged = time.Now()
+			}
+			// Recreate the iterator after every batch commit in order
+			// to allow the underlying compactor to delete the entries.
+			start := common.CopyBytes(key)
+			it.Release()
+			it = s.table.Evm.NewIterator(nil, start)
+		}
+	}
+	deleted, err := s.deleteUnmarked(keys)
+	count += deleted
+	return count, size, err
+}
+
+// deleteUnmarked deletes the keys, which aren't in the tracked set. The set is checked and the keys are
+// deleted under the lock of the tracked writes, so a key written concurrently is either retained or
+// written after it's deleted.
+func (s *Store) deleteUnmarked(keys [][]byte) (int, error) {
+	s.stateWrites.mu.Lock()
+	defer s.stateWrites.mu.Unlock()
+	set := s.stateWrites.set
+
+	batch := s.table.Evm.NewBatch()
+	deleted := 0
+	for _, key := range keys {
+		checkKey := key
+		if isCode, codeKey := rawdb.IsCodeKey(key); isCode {
+			checkKey = codeKey
+		}
+		if ok, err := set.Contain(checkKey); err != nil {
+			return 0, err
+		} else if ok {
+			continue
+		}
+		if err := batch.Delete(key); err != nil {
+			return 0, err
+		}
+		deleted++
+	}
+	return deleted, batch.Write()
+}
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,119 @@
+package evmstore
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unicornultrafoundation/go-helios/hash"
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+	"github.com/unicornultrafoundation/go-helios/u2udb/memorydb"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/core/state"
+	"github.com/unicornultrafoundation/go-u2u/crypto"
+)
+
+type testKeySet map[string]bool
+
+func (set testKeySet) Put(key []byte, _ []byte) error {
+	if isCode, codeKey := rawdb.IsCodeKey(key); isCode {
+		key = codeKey
+	}
+	set[string(key)] = true
+	return nil
+}
+
+func (set testKeySet) Contain(key []byte) (bool, error) {
+	return set[string(key)], nil
+}
+
+func TestStorePruneState(t *testing.T) {
+	require := require.New(t)
+
+	store := NewStore(memorydb.NewProducer(""), LiteStoreConfig())
+	defer store.Close()
+
+	var (
+		addr1 = common.Address{1}
+		addr2 = common.Address{2}
+		slot  = common.Hash{1}
+		roots []common.Hash
+	)
+	root := common.Hash{}
+	for i := int64(1); i <= 4; i++ {
+		statedb, err := state.New(root, store.EvmState, nil)
+		require.NoError(err)
+		statedb.SetBalance(addr1, big.NewInt(i))
+		statedb.SetState(addr1, slot, common.BigToHash(big.NewInt(i)))
+		statedb.SetCode(addr1, []byte{byte(i)})
+		statedb.SetBalance(addr2, big.NewInt(100))
+		statedb.SetState(addr2, slot, common.Hash{0xff})
+		root, err = statedb.Commit(true)
+		require.NoError(err)
+		require.NoError(store.Commit(idx.Block(i), hash.Hash(root), true))
+		roots = append(roots, root)
+	}
+	// an unrelated entry of the same DB
+	require.NoError(store.EvmDb.Put([]byte("unrelated"), []byte{1}))
+
+	// the unavailable state is skipped
+	stats, err := store.PruneState([]common.Hash{roots[3], roots[2], {0xaa}}, testKeySet{}, nil)
+	require.NoError(err)
+	require.Equal(2, stats.Roots)
+	require.NotZero(stats.Nodes)
+
+	// read the states from the disk only
+	db := state.NewDatabase(store.EvmDb)
+	for i, root := range roots {
+		if i < 2 {
+			require.Empty(rawdb.ReadTrieNode(store.EvmDb, root), "state %d", i)
+			continue
+		}
+		statedb, err := state.New(root, db, nil)
+		require.NoError(err, "state %d", i)
+		require.Equal(big.NewInt(int64(i+1)), statedb.GetBalance(addr1))
+		require.Equal(common.BigToHash(big.NewInt(int64(i+1))), statedb.GetState(addr1, slot))
+		require.Equal([]byte{byte(i + 1)}, statedb.GetCode(addr1))
+		require.Equal(big.NewInt(100), statedb.GetBalance(addr2))
+		require.Equal(common.Hash{0xff}, statedb.GetState(addr2, slot))
+		require.NoError(statedb.Error())
+	}
+	require.Empty(rawdb.ReadCodeWithPrefix(store.EvmDb, common.BytesToHash(crypto.Keccak256([]byte{1}))))
+	require.NotEmpty(rawdb.ReadCodeWithPrefix(store.EvmDb, common.BytesToHash(crypto.Keccak256([]byte{4}))))
+	value, err := store.EvmDb.Get([]byte("unrelated"))
+	require.NoError(err)
+	require.Equal([]byte{1}, value)
+
+	// the head state must be available
+	_, err = store.PruneState([]common.Hash{{0xaa}, roots[3]}, testKeySet{}, nil)
+	require.Error(err)
+}
+
+// TestStorePruneStateWrites checks that the entries written during the pruning are retained
+func TestStorePruneStateWrites(t *testing.T) {
+	require := require.New(t)
+
+	store := NewStore(memorydb.NewProducer(""), LiteStoreConfig())
+	defer store.Close()
+
+	set := testKeySet{}
+	store.stateWrites.start(set)
+	node := common.Hash{0xbb}
+	batch := store.EvmDb.NewBatch()
+	rawdb.WriteTrieNode(batch, node, []byte{1})
+	rawdb.WriteCode(batch, common.Hash{0xcc}, []byte{2})
+	require.NoError(batch.Write())
+	rawdb.WriteTrieNode(store.EvmDb, common.Hash{0xdd}, []byte{3})
+	rawdb.WriteSnapshotRoot(store.EvmDb, common.Hash{0xee})
+
+	count, _, err := store.sweepState(nil)
+	store.stateWrites.stop()
+	require.NoError(err)
+	require.Zero(count)
+	require.Len(set, 3)
+	require.NotEmpty(rawdb.ReadTrieNode(store.EvmDb, node))
+	require.NotEmpty(rawdb.ReadCodeWithPrefix(store.EvmDb, common.Hash{0xcc}))
+	require.NotEmpty(rawdb.ReadTrieNode(store.EvmDb, common.Hash{0xdd}))
+}
@@ -0,0 +1,222 @@
+package evmstore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/core/state"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/crypto"
+	"github.com/unicornultrafoundation/go-u2u/ethdb"
+	"github.com/unicornultrafoundation/go-u2u/rlp"
+	"github.com/unicornultrafoundation/go-u2u/trie"
+)
+
+// The path scheme keeps only the latest EVM state, so the state history is recorded along with it.
+// A DB of the hash scheme is migrated by copying the latest state into the path keys and deleting
+// the trie nodes keyed by hashes afterwards.
+
+// stateSchemeCleanupKey marks the migrated DB, which still has the trie nodes of the hash scheme
+var stateSchemeCleanupKey = []byte("sc")
+
+var errPathSchemeDisabled = errors.New("path scheme isn't configured for EVM state")
+
+// StateScheme returns the scheme of the persisted EVM state, see rawdb.HashScheme and rawdb.PathScheme
+func (s *Store) StateScheme() string {
+	return s.EvmState.TrieDB().Scheme()
+}
+
+// StateSchemeMigrationNeeded returns true if the config requires the path scheme, while the EVM state is
+// stored by the hash scheme.
+func (s *Store) StateSchemeMigrationNeeded() bool {
+	return s.cfg.StateScheme == rawdb.PathScheme && s.StateScheme() != rawdb.PathScheme
+}
+
+// initStateScheme records the scheme of the config into an empty DB. The scheme of a non-empty DB is
+// changed only by the migration.
+func (s *Store) initStateScheme() {
+	if s.cfg.StateScheme == "" || s.cfg.StateScheme == rawdb.HashScheme {
+		return
+	}
+	if s.cfg.StateScheme != rawdb.PathScheme {
+		s.Log.Crit("Unknown EVM state scheme", "scheme", s.cfg.StateScheme)
+	}
+	it := s.table.Evm.NewIterator(nil, nil)
+	empty := !it.Next()
+	it.Release()
+	if empty {
+		rawdb.WriteStateScheme(s.table.Evm, rawdb.PathScheme)
+	}
+}
+
+// diskStateRoot returns the root of the persisted EVM state of the path scheme
+func (s *Store) diskStateRoot() common.Hash {
+	enc := rawdb.ReadAccountTrieNode(s.EvmDb, nil)
+	if len(enc) == 0 {
+		return types.EmptyRootHash
+	}
+	return crypto.Keccak256Hash(enc)
+}
+
+// MigrateStateScheme copies the EVM state of the given root from the hash scheme into the path scheme,
+// and deletes the trie nodes of the hash scheme. The other states are lost. The flush callback is called
+// after every written batch, so the caller may flush the DB. The migration may be repeated if interrupted.
+// The state written by the hash scheme into a DB of the path scheme (e.g. the genesis state) is migrated too.
+func (s *Store) MigrateStateScheme(root common.Hash, flush func() error) error {
+	if s.cfg.StateScheme != rawdb.PathScheme {
+		return errPathSchemeDisabled
+	}
+	s.Log.Info("Migrating EVM state to the path scheme", "root", root)
+	start := time.Now()
+	triedb := trie.NewDatabase(s.EvmDb)
+	batch := s.EvmDb.NewBatch()
+	write := func() error {
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+		return flush()
+	}
+	var accounts, nodes int
+	logged := time.Now()
+	copyTrie := func(owner, root common.Hash, onLeaf func(key, blob []byte) error) error {
+		t, err := trie.New(root, triedb)
+		if err != nil {
+			return err
+		}
+		it := t.NodeIterator(nil)
+		for it.Next(true) {
+			if it.Leaf() && onLeaf != nil {
+				if err := onLeaf(it.LeafKey(), it.LeafBlob()); err != nil {
+					return err
+				}
+			}
+			if it.Hash() == (common.Hash{}) {
+				// embedded node or value
+				continue
+			}
+			enc, err := triedb.Node(it.Hash())
+			if err != nil {
+				return err
+			}
+			rawdb.WriteTrieNodeByPath(batch, owner, it.Path(), enc)
+			nodes++
+			if batch.ValueSize() >= ethdb.IdealBatchSize {
+				if err := write(); err != nil {
+					return err
+				}
+			}
+		}
+		return it.Error()
+	}
+	err := copyTrie(common.Hash{}, root, func(key, blob []byte) error {
+		var acc state.Account
+		if err := rlp.DecodeBytes(blob, &acc); err != nil {
+			return err
+		}
+		accounts++
+		if time.Since(logged) > 8*time.Second {
+			s.Log.Info("Migrating EVM state", "accounts", accounts, "nodes", nodes, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+		// the legacy codes are keyed by hashes and would be deleted along with the trie nodes
+		if !bytes.Equal(acc.CodeHash, EmptyCode) {
+			codeHash := common.BytesToHash(acc.CodeHash)
+			if len(rawdb.ReadCodeWithPrefix(s.EvmDb, codeHash)) == 0 {
+				code := rawdb.ReadCode(s.EvmDb, codeHash)
+				if len(code) == 0 {
+					return fmt.Errorf("missing contract code %s", codeHash.Hex())
+				}
+				rawdb.WriteCode(batch, codeHash, code)
+			}
+		}
+		if isEmptyRoot(acc.Root) {
+			return nil
+		}
+		return copyTrie(common.BytesToHash(key), acc.Root, nil)
+	})
+	if err != nil {
+		return err
+	}
+	// the trie nodes of the hash scheme are deleted after the scheme is switched
+	if err := s.table.History.Put(stateSchemeCleanupKey, []byte{1}); err != nil {
+		return err
+	}
+	rawdb.WriteStateScheme(batch, rawdb.PathScheme)
+	if err := write(); err != nil {
+		return err
+	}
+	s.initEVMDB()
+	s.Log.Info("Migrated EVM state to the path scheme", "accounts", accounts, "nodes", nodes, "elapsed", common.PrettyDuration(time.Since(start)))
+	return s.CleanupStateScheme(flush)
+}
+
+// StateSchemeCleanupNeeded returns true if the trie nodes of the hash scheme are left after the migration.
+func (s *Store) StateSchemeCleanupNeeded() bool {
+	pending, _ := s.table.History.Get(stateSchemeCleanupKey)
+	return len(pending) != 0
+}
+
+// CleanupStateScheme deletes the trie nodes of the hash scheme left after the migration to the path scheme.
+// Does nothing if the migration isn't finished or the nodes are already deleted.
+func (s *Store) CleanupStateScheme(flush func() error) error {
+	if !s.StateSchemeCleanupNeeded() {
+		return nil
+	}
+	s.Log.Info("Deleting EVM state of the hash scheme")
+	start := time.Now()
+	var (
+		keys  [][]byte
+		size  int
+		count int
+		it    = s.table.Evm.NewIterator(nil, nil)
+	)
+	deleteKeys := func() error {
+		batch := s.table.Evm.NewBatch()
+		for _, key := range keys {
+			if err := batch.Delete(key); err != nil {
+				return err
+			}
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		count += len(keys)
+		keys, size = keys[:0], 0
+		return flush()
+	}
+	for it.Next() {
+		if !rawdb.IsLegacyTrieNode(it.Key(), it.Value()) {
+			continue
+		}
+		keys = append(keys, common.CopyBytes(it.Key()))
+		size += len(it.Key())
+		if size >= ethdb.IdealBatchSize {
+			next := common.CopyBytes(it.Key())
+			it.Release()
+			if err := deleteKeys(); err != nil {
+				return err
+			}
+			// Recreate the iterator after every batch commit in order
+			// to allow the underlying compactor to delete the entries.
+			it = s.table.Evm.NewIterator(nil, next)
+		}
+	}
+	err := it.Error()
+	it.Release()
+	if err != nil {
+		return err
+	}
+	if err := deleteKeys(); err != nil {
+		return err
+	}
+	if err := s.table.History.Delete(stateSchemeCleanupKey); err != nil {
+		return err
+	}
+	s.Log.Info("Deleted EVM state of the hash scheme", "nodes", count, "elapsed", common.PrettyDuration(time.Since(start)))
+	return flush()
+}
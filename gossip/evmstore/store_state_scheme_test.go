@@ -0,0 +1,74 @@
+package evmstore
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unicornultrafoundation/go-helios/hash"
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+	"github.com/unicornultrafoundation/go-helios/u2udb/memorydb"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+)
+
+func TestStoreMigrateStateScheme(t *testing.T) {
+	require := require.New(t)
+
+	store := NewStore(memorydb.NewProducer(""), LiteStoreConfig())
+	defer store.Close()
+	require.Equal(rawdb.HashScheme, store.StateScheme())
+
+	addrs := []common.Address{{1}, {2}}
+	root := common.Hash{}
+	for i := 1; i <= 3; i++ {
+		statedb, err := store.StateDB(hash.Hash(root))
+		require.NoError(err)
+		for _, addr := range addrs {
+			statedb.SetBalance(addr, big.NewInt(int64(i)))
+			statedb.SetState(addr, common.Hash{byte(i)}, common.Hash{byte(i)})
+			statedb.SetCode(addr, []byte{byte(i), addr[0]})
+		}
+		root, err = statedb.Commit(true)
+		require.NoError(err)
+		require.NoError(store.Commit(idx.Block(i), hash.Hash(root), true))
+	}
+
+	store.cfg.StateScheme = rawdb.PathScheme
+	require.True(store.StateSchemeMigrationNeeded())
+	flushes := 0
+	require.NoError(store.MigrateStateScheme(root, func() error {
+		flushes++
+		return nil
+	}))
+	require.NotZero(flushes)
+	require.Equal(rawdb.PathScheme, store.StateScheme())
+	require.False(store.StateSchemeMigrationNeeded())
+
+	// no trie nodes of the hash scheme are left
+	it := store.table.Evm.NewIterator(nil, nil)
+	for it.Next() {
+		require.False(rawdb.IsLegacyTrieNode(it.Key(), it.Value()), "key %x", it.Key())
+	}
+	it.Release()
+
+	// the latest state is migrated and may be changed further
+	statedb, err := store.StateDB(hash.Hash(root))
+	require.NoError(err)
+	for _, addr := range addrs {
+		require.Equal(big.NewInt(3), statedb.GetBalance(addr))
+		require.Equal(common.Hash{1}, statedb.GetState(addr, common.Hash{1}))
+		require.Equal(common.Hash{3}, statedb.GetState(addr, common.Hash{3}))
+		require.Equal([]byte{3, addr[0]}, statedb.GetCode(addr))
+	}
+	statedb.SetBalance(addrs[0], big.NewInt(4))
+	root, err = statedb.Commit(true)
+	require.NoError(err)
+	require.NoError(store.Commit(4, hash.Hash(root), false))
+
+	statedb, err = store.StateDB(hash.Hash(root))
+	require.NoError(err)
+	require.Equal(big.NewInt(4), statedb.GetBalance(addrs[0]))
+	require.Equal(common.Hash{2}, statedb.GetState(addrs[1], common.Hash{2}))
+}
@@ -6,7 +6,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/unicornultrafoundation/go-helios/u2udb/memorydb"
+	"github.com/unicornultrafoundation/go-u2u/common"
 	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/evmcore"
 )
 
 func cachedStore() *Store {
@@ -38,3 +40,38 @@ func TestStoreSetTx(t *testing.T) {
 	require.Equal(t, tx.Gas(), txFromStore.Gas())
 	require.Equal(t, tx.GasPrice(), txFromStore.GasPrice())
 }
+
+func TestStoreSfcDivergences(t *testing.T) {
+	store := cachedStore()
+
+	divergence := func(block uint64, tx byte) *evmcore.SfcDivergence {
+		return &evmcore.SfcDivergence{
+			BlockNumber: block,
+			TxHash:      common.Hash{tx},
+			Address:     common.Address{0xfc},
+			Slots: []evmcore.SfcSlotDiff{{
+				Key:      common.Hash{1},
+				Value:    common.Hash{2},
+				SfcValue: common.Hash{3},
+			}},
+		}
+	}
+	store.SetSfcDivergence(divergence(300, 1))
+	store.SetSfcDivergence(divergence(2, 2))
+	store.SetSfcDivergence(divergence(2, 1))
+	store.SetSfcDivergence(divergence(1, 1))
+
+	var got []*evmcore.SfcDivergence
+	store.ForEachSfcDivergence(2, func(d *evmcore.SfcDivergence) bool {
+		got = append(got, d)
+		return d.BlockNumber < 2
+	})
+	require.Equal(t, []*evmcore.SfcDivergence{divergence(2, 1)}, got)
+
+	got = nil
+	store.ForEachSfcDivergence(2, func(d *evmcore.SfcDivergence) bool {
+		got = append(got, d)
+		return true
+	})
+	require.Equal(t, []*evmcore.SfcDivergence{divergence(2, 1), divergence(2, 2), divergence(300, 1)}, got)
+}
@@ -43,3 +43,13 @@ func (s *Store) GetTxPosition(txid common.Hash) *TxPosition {
 
 	return txPosition
 }
+
+// DelTxPosition removes transaction block and position.
+func (s *Store) DelTxPosition(txid common.Hash) {
+	if err := s.table.TxPositions.Delete(txid.Bytes()); err != nil {
+		s.Log.Crit("Failed to delete key", "err", err)
+	}
+
+	// Remove from LRU cache.
+	s.cache.TxPositions.Remove(txid.String())
+}
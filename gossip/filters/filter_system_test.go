@@ -23,6 +23,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/unicornultrafoundation/go-helios/native/idx"
 	"github.com/unicornultrafoundation/go-helios/u2udb/memorydb"
 	"github.com/unicornultrafoundation/go-u2u/common"
 	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
@@ -44,6 +45,8 @@ type testBackend struct {
 	blocksFeed *notify.Feed
 	txsFeed    *notify.Feed
 	logsFeed   *notify.Feed
+
+	receiptsTail idx.Block
 }
 
 func newTestBackend() *testBackend {
@@ -148,6 +151,10 @@ func (b *testBackend) GetTxPosition(txid common.Hash) *evmstore.TxPosition {
 	return nil
 }
 
+func (b *testBackend) ReceiptsTail() idx.Block {
+	return b.receiptsTail
+}
+
 func (b *testBackend) CalcBlockExtApi() bool {
 	return true
 }
@@ -33,6 +33,7 @@ import (
 	"github.com/unicornultrafoundation/go-u2u/core/types"
 	"github.com/unicornultrafoundation/go-u2u/crypto"
 	"github.com/unicornultrafoundation/go-u2u/evmcore"
+	"github.com/unicornultrafoundation/go-u2u/gossip/evmstore"
 	"github.com/unicornultrafoundation/go-u2u/params"
 	"github.com/unicornultrafoundation/go-u2u/topicsdb"
 )
@@ -274,4 +275,17 @@ func TestFilters(t *testing.T) {
 		t.Error("expected 0 log, got", len(logs))
 	}
 
+	backend.receiptsTail = 3
+	filter = NewRangeFilter(backend, testConfig(), 1, 10, nil, [][]common.Hash{{hash1, hash2}})
+	if _, err = filter.Logs(context.Background()); err != evmstore.ErrPrunedHistory {
+		t.Error("expected pruned history error, got", err)
+	}
+	filter = NewRangeFilter(backend, testConfig(), 3, -1, nil, [][]common.Hash{{hash3}})
+	logs, err = filter.Logs(context.Background())
+	if err != nil {
+		t.Error(err)
+	}
+	if len(logs) != 1 {
+		t.Error("expected 1 log, got", len(logs))
+	}
 }
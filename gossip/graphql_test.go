@@ -0,0 +1,116 @@
+package gossip
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/common/hexutil"
+	"github.com/unicornultrafoundation/go-u2u/gossip/filters"
+	"github.com/unicornultrafoundation/go-u2u/graphql"
+	"github.com/unicornultrafoundation/go-u2u/logger"
+	"github.com/unicornultrafoundation/go-u2u/utils"
+)
+
+func TestGraphQL(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	env := newTestEnv(2, 3)
+	defer env.Close()
+
+	receipts, err := env.ApplyTxs(sameEpoch,
+		env.Transfer(1, 2, utils.ToU2U(10)),
+	)
+	require.NoError(err)
+	receipt := receipts[0]
+
+	handler, err := graphql.NewHandler(env.EthAPI, filters.DefaultConfig())
+	require.NoError(err)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	query := func(q string, result interface{}) {
+		body, err := json.Marshal(map[string]string{"query": q})
+		require.NoError(err)
+		resp, err := http.Post(server.URL, "application/json", bytes.NewReader(body))
+		require.NoError(err)
+		defer resp.Body.Close()
+		var res struct {
+			Data   json.RawMessage
+			Errors []interface{}
+		}
+		require.NoError(json.NewDecoder(resp.Body).Decode(&res))
+		require.Empty(res.Errors)
+		require.Equal(http.StatusOK, resp.StatusCode)
+		require.NoError(json.Unmarshal(res.Data, result))
+	}
+
+	var blockRes struct {
+		Block struct {
+			Number       hexutil.Uint64
+			Hash         common.Hash
+			Epoch        hexutil.Uint64
+			Transactions []struct {
+				Hash   common.Hash
+				Status hexutil.Uint64
+				Event  struct {
+					ID common.Hash
+				}
+			}
+			Atropos struct {
+				ID      common.Hash
+				Creator struct {
+					ID     hexutil.Uint64
+					Weight *hexutil.Big
+				}
+			}
+			EventCount int32
+			Events     []struct {
+				ID common.Hash
+			}
+		}
+	}
+	query(fmt.Sprintf(`{ block(number: %d) { number hash epoch transactions { hash status event { id } }
+		atropos { id creator { id weight } } eventCount events { id } } }`, receipt.BlockNumber.Uint64()), &blockRes)
+
+	block := blockRes.Block
+	require.Equal(receipt.BlockNumber.Uint64(), uint64(block.Number))
+	require.Equal(receipt.BlockHash, block.Hash)
+	require.Equal(receipt.BlockHash, block.Atropos.ID)
+	require.NotZero(block.Epoch)
+	require.NotZero(block.Atropos.Creator.ID)
+	require.NotNil(block.Atropos.Creator.Weight)
+	require.Len(block.Transactions, 1)
+	require.Equal(receipt.TxHash, block.Transactions[0].Hash)
+	require.Equal(hexutil.Uint64(receipt.Status), block.Transactions[0].Status)
+	require.Equal(int(block.EventCount), len(block.Events))
+
+	// the event which brought the transaction is confirmed by the block
+	confirmed := false
+	for _, e := range block.Events {
+		if e.ID == block.Transactions[0].Event.ID {
+			confirmed = true
+		}
+	}
+	require.True(confirmed)
+
+	var eventRes struct {
+		Event struct {
+			Epoch        hexutil.Uint64
+			Transactions []struct {
+				Hash common.Hash
+			}
+		}
+	}
+	query(fmt.Sprintf(`{ event(id: "%s") { epoch transactions { hash } } }`, block.Transactions[0].Event.ID.Hex()), &eventRes)
+	require.Equal(block.Epoch, eventRes.Event.Epoch)
+	require.Len(eventRes.Event.Transactions, 1)
+	require.Equal(receipt.TxHash, eventRes.Event.Transactions[0].Hash)
+}
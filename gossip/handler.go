@@ -290,9 +290,17 @@ func newHandler(
 			}
 			return p.progress.Epoch
 		},
+		PeerPrunedEpoch: func(peer string) idx.Epoch {
+			p := h.peers.Peer(peer)
+			if p == nil {
+				return 0
+			}
+			return p.progress.PrunedEpoch
+		},
 	})
 	h.dagSeeder = dagstreamseeder.New(h.config.Protocol.DagStreamSeeder, dagstreamseeder.Callbacks{
 		ForEachEvent: c.s.ForEachEventRLP,
+		PrunedEpoch:  c.s.GetPrunedEpoch,
 	})
 
 	h.bvProcessor = h.makeBvProcessor(c.checkers)
@@ -760,6 +768,7 @@ func (h *handler) myProgress() PeerProgress {
 		Epoch:            epoch,
 		LastBlockIdx:     bs.LastBlock.Idx,
 		LastBlockAtropos: bs.LastBlock.Atropos,
+		PrunedEpoch:      h.store.GetPrunedEpoch(),
 	}
 }
 
@@ -828,7 +837,7 @@ func (h *handler) handle(p *peer) error {
 		p.Log().Warn("Leecher peer registration failed", "err", err)
 		return err
 	}
-	if p.RunningCap(ProtocolName, []uint{UP01}) {
+	if p.RunningCap(ProtocolName, ProtocolVersions) {
 		if err := h.epLeecher.RegisterPeer(p.id); err != nil {
 			p.Log().Warn("Leecher peer registration failed", "err", err)
 			return err
@@ -1148,7 +1157,7 @@ func (h *handler) handleMsg(p *peer) error {
 		}
 
 		pid := p.id
-		_, peerErr := h.dagSeeder.NotifyRequestReceived(dagstreamseeder.Peer{
+		err, peerErr := h.dagSeeder.NotifyRequestReceived(dagstreamseeder.Peer{
 			ID:        pid,
 			SendChunk: p.SendEventsStream,
 			Misbehaviour: func(err error) {
@@ -1158,6 +1167,10 @@ func (h *handler) handleMsg(p *peer) error {
 		if peerErr != nil {
 			return peerErr
 		}
+		if err == dagstreamseeder.ErrPrunedEpoch {
+			// the session is ended by the seeder, let the peer know which epochs aren't served anymore
+			p.AsyncSendProgress(h.myProgress(), p.queue)
+		}
 
 	case msg.Code == EventsStreamResponse:
 		if !h.syncStatus.AcceptEvents() {
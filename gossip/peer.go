@@ -407,7 +407,7 @@ func (p *peer) EnqueueSendEventsRLP(events []rlp.RawValue, ids []hash.Event, que
 // AsyncSendProgress queues a progress propagation to a remote peer.
 // If the peer's broadcast queue is full, the progress is silently dropped.
 func (p *peer) AsyncSendProgress(progress PeerProgress, queue chan broadcastItem) {
-	if !p.asyncSendNonEncodedItem(progress, ProgressMsg, queue) {
+	if !p.asyncSendNonEncodedItem(p.versionedProgress(progress), ProgressMsg, queue) {
 		p.Log().Debug("Dropping peer progress propagation")
 	}
 }
@@ -522,7 +522,16 @@ func (p *peer) Handshake(network uint64, progress PeerProgress, genesis common.H
 }
 
 func (p *peer) SendProgress(progress PeerProgress) error {
-	return p2p.Send(p.rw, ProgressMsg, progress)
+	return p2p.Send(p.rw, ProgressMsg, p.versionedProgress(progress))
+}
+
+// versionedProgress drops the progress fields which the peer's protocol version doesn't support.
+// The optional fields are omitted from the encoding if they are zero.
+func (p *peer) versionedProgress(progress PeerProgress) PeerProgress {
+	if p.version < UP02 {
+		progress.PrunedEpoch = 0
+	}
+	return progress
 }
 
 func (p *peer) readStatus(network uint64, handshake *handshakeData, genesis common.Hash) (err error) {
@@ -585,5 +594,5 @@ func (p *snapPeer) info() *snapPeerInfo {
 
 // eligibleForSnap checks eligibility of a peer for a snap protocol. A peer is eligible for a snap if it advertises `snap` sattelite protocol along with `u2u` protocol.
 func eligibleForSnap(p *p2p.Peer) bool {
-	return p.RunningCap(ProtocolName, []uint{UP01}) && p.RunningCap(snap.ProtocolName, snap.ProtocolVersions)
+	return p.RunningCap(ProtocolName, ProtocolVersions) && p.RunningCap(snap.ProtocolName, snap.ProtocolVersions)
 }
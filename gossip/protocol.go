@@ -18,18 +18,20 @@ import (
 
 // Constants to match up U2U Protocol versions and messages
 const (
-	UP01            = 1
-	ProtocolVersion = UP01
+	UP01 = 1
+	// UP02 advertises the pruned epoch of the peer in PeerProgress
+	UP02            = 2
+	ProtocolVersion = UP02
 )
 
 // ProtocolName is the official short name of the protocol used during capability negotiation.
 const ProtocolName = "u2u"
 
 // ProtocolVersions are the supported versions of the protocol (first is primary).
-var ProtocolVersions = []uint{UP01}
+var ProtocolVersions = []uint{UP02, UP01}
 
 // protocolLengths are the number of implemented message corresponding to different protocol versions.
-var protocolLengths = map[uint]uint64{UP01: EventsStreamResponse + 1}
+var protocolLengths = map[uint]uint64{UP02: EventsStreamResponse + 1, UP01: EventsStreamResponse + 1}
 
 const protocolMaxMsgSize = native.ProtocolMaxMsgSize // Maximum cap on the size of a protocol message
 
@@ -138,6 +140,9 @@ type PeerProgress struct {
 	LastBlockAtropos hash.Event
 	// Currently unused
 	HighestLamport idx.Lamport
+	// PrunedEpoch is the highest epoch, which events are pruned by the peer.
+	// It's sent only to UP02 peers, because UP01 peers fail to decode the extra field.
+	PrunedEpoch idx.Epoch `rlp:"optional"`
 }
 
 type dagChunk struct {
@@ -60,6 +60,8 @@ type Callbacks struct {
 	RequestChunk func(peer string, r dagstream.Request) error
 	Suspend      func(peer string) bool
 	PeerEpoch    func(peer string) idx.Epoch
+	// PeerPrunedEpoch returns the highest epoch, which events are pruned by the peer
+	PeerPrunedEpoch func(peer string) idx.Epoch
 }
 
 type sessionState struct {
@@ -111,6 +113,10 @@ func (d *Leecher) selectSessionPeerCandidates() []string {
 	currentEpochPeers := make([]string, 0, len(d.Peers))
 	futureEpochPeers := make([]string, 0, len(d.Peers))
 	for p := range d.Peers {
+		if d.callback.PeerPrunedEpoch(p) >= d.epoch {
+			// the peer doesn't have the events of the epoch
+			continue
+		}
 		epoch := d.callback.PeerEpoch(p)
 		if epoch == d.epoch {
 			currentEpochPeers = append(currentEpochPeers, p)
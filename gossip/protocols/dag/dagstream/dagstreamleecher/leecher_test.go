@@ -48,6 +48,9 @@ func testLeecherNoDeadlocks(t *testing.T, maxPeers int) {
 		PeerEpoch: func(peer string) idx.Epoch {
 			return 1 + epoch/2 + idx.Epoch(rand.Intn(int(epoch*2)))
 		},
+		PeerPrunedEpoch: func(peer string) idx.Epoch {
+			return idx.Epoch(rand.Intn(int(epoch)))
+		},
 	})
 	terminated := false
 	for i := 0; i < maxPeers*2; i++ {
@@ -8,21 +8,28 @@ import (
 	"github.com/unicornultrafoundation/go-helios/gossip/basestream"
 	"github.com/unicornultrafoundation/go-helios/gossip/basestream/basestreamseeder"
 	"github.com/unicornultrafoundation/go-helios/hash"
+	"github.com/unicornultrafoundation/go-helios/native/idx"
 
+	"github.com/unicornultrafoundation/go-u2u/common"
 	"github.com/unicornultrafoundation/go-u2u/gossip/protocols/dag/dagstream"
 )
 
 var (
 	ErrWrongType        = errors.New("wrong request type")
 	ErrWrongSelectorLen = errors.New("wrong event selector length")
+	ErrPrunedEpoch      = errors.New("events of the epoch are pruned")
 )
 
 type Seeder struct {
 	*basestreamseeder.BaseSeeder
+
+	callbacks Callbacks
 }
 
 type Callbacks struct {
 	ForEachEvent func(start []byte, onEvent func(key hash.Event, eventB rlp.RawValue) bool)
+	// PrunedEpoch returns the highest epoch, which events are pruned
+	PrunedEpoch func() idx.Epoch
 }
 
 type Peer struct {
@@ -54,6 +61,7 @@ func New(cfg Config, callbacks Callbacks) *Seeder {
 				return res
 			},
 		}),
+		callbacks: callbacks,
 	}
 }
 
@@ -64,6 +72,18 @@ func (s *Seeder) NotifyRequestReceived(peer Peer, r dagstream.Request) (err erro
 	if r.Type != dagstream.RequestIDs && r.Type != dagstream.RequestEvents {
 		return nil, ErrWrongType
 	}
+	if pruned := s.callbacks.PrunedEpoch(); pruned != 0 {
+		// the start locator begins with the epoch of the requested events
+		start := common.RightPadBytes(r.Session.Start, 4)
+		if idx.BytesToEpoch(start[:4]) <= pruned {
+			// end the session explicitly, so the leecher requests the epoch from another peer
+			// instead of waiting for the chunks until the session timeout
+			if err := peer.SendChunk(dagstream.Response{SessionID: r.Session.ID, Done: true}, nil); err != nil {
+				return err, nil
+			}
+			return ErrPrunedEpoch, nil
+		}
+	}
 	rType := r.Type
 	return s.BaseSeeder.NotifyRequestReceived(basestreamseeder.Peer{
 		ID: peer.ID,
@@ -0,0 +1,115 @@
+package gossip
+
+import (
+	"sync"
+	"time"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+)
+
+// receiptsPruner periodically removes the receipts, transaction positions
+// and logs index of the blocks, which are older than the configured retention window
+type receiptsPruner struct {
+	store *Store
+	cfg   ReceiptsRetentionConfig
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+func newReceiptsPruner(store *Store, cfg ReceiptsRetentionConfig) *receiptsPruner {
+	return &receiptsPruner{
+		store: store,
+		cfg:   cfg,
+		quit:  make(chan struct{}),
+	}
+}
+
+// enabled returns true if the retention window is limited
+func (p *receiptsPruner) enabled() bool {
+	return p.cfg.Blocks != 0
+}
+
+func (p *receiptsPruner) Start() {
+	if !p.enabled() {
+		return
+	}
+	p.wg.Add(1)
+	go p.loop()
+}
+
+func (p *receiptsPruner) Stop() {
+	if !p.enabled() {
+		return
+	}
+	close(p.quit)
+	p.wg.Wait()
+}
+
+func (p *receiptsPruner) loop() {
+	defer p.wg.Done()
+	period := p.cfg.PrunePeriod
+	if period <= 0 {
+		period = time.Minute
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.store.pruneReceipts(p.cfg, p.quit)
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// pruneReceipts removes the receipts, transaction positions and logs index of the blocks
+// below the retention window, at most MaxBlocksPerRound blocks at once
+func (s *Store) pruneReceipts(cfg ReceiptsRetentionConfig, quit <-chan struct{}) {
+	s.mutex.History.Lock()
+	defer s.mutex.History.Unlock()
+
+	latest := s.GetLatestBlockIndex()
+	if cfg.Blocks == 0 || latest <= cfg.Blocks {
+		return
+	}
+	start := time.Now()
+	from := s.evm.GetReceiptsTail()
+	if from == 0 {
+		from = 1
+	}
+	to := latest - cfg.Blocks
+	if cfg.MaxBlocksPerRound != 0 && to >= from+cfg.MaxBlocksPerRound {
+		to = from + cfg.MaxBlocksPerRound - 1
+	}
+	if from > to {
+		return
+	}
+
+	// the tail goes first, so the API doesn't serve the partially pruned blocks
+	s.evm.SetReceiptsTail(to + 1)
+	var txs int
+	for n := from; n <= to; n++ {
+		if isClosed(quit) {
+			// the remaining blocks are left for the next round
+			s.evm.SetReceiptsTail(n)
+			to = n - 1
+			break
+		}
+		if block := s.GetBlock(n); block != nil {
+			for _, tx := range s.GetBlockTxs(n, block) {
+				s.evm.DelTxPosition(tx.Hash())
+				txs++
+			}
+		}
+		s.evm.DelReceipts(n)
+	}
+	if to < from {
+		return
+	}
+	if err := s.evm.EvmLogs.DeleteInBlocks(from, to); err != nil {
+		s.Log.Warn("Failed to prune logs index", "from", from, "to", to, "err", err)
+	}
+	s.Log.Debug("Pruned receipts", "from", from, "to", to, "txs", txs, "elapsed", common.PrettyDuration(time.Since(start)))
+}
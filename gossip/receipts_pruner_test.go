@@ -0,0 +1,75 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unicornultrafoundation/go-helios/hash"
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+	"github.com/unicornultrafoundation/go-helios/u2udb/flushable"
+	"github.com/unicornultrafoundation/go-helios/u2udb/memorydb"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/gossip/evmstore"
+	"github.com/unicornultrafoundation/go-u2u/native"
+	"github.com/unicornultrafoundation/go-u2u/native/iblockproc"
+)
+
+func TestStorePruneReceipts(t *testing.T) {
+	require := require.New(t)
+
+	dbs := flushable.NewSyncedPool(memorydb.NewProducer(""), []byte{0})
+	store := NewStore(dbs, LiteStoreConfig())
+	defer store.Close()
+
+	// a transaction with a single log per block
+	addr := common.HexToAddress("0x1")
+	txs := make(map[idx.Block]*types.Transaction)
+	for n := idx.Block(1); n <= 5; n++ {
+		tx := types.NewTx(&types.LegacyTx{Nonce: uint64(n)})
+		store.evm.SetTx(tx.Hash(), tx)
+		store.evm.SetTxPosition(tx.Hash(), evmstore.TxPosition{Block: n})
+		store.SetBlock(n, &native.Block{
+			Atropos:     hash.FakeEvent(),
+			Events:      hash.Events{},
+			Txs:         []common.Hash{tx.Hash()},
+			InternalTxs: []common.Hash{},
+			SkippedTxs:  []uint32{},
+		})
+		l := &types.Log{
+			Address:     addr,
+			Topics:      []common.Hash{common.BytesToHash([]byte("topic"))},
+			BlockNumber: uint64(n),
+			TxHash:      tx.Hash(),
+		}
+		store.evm.SetReceipts(n, types.Receipts{{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{l}}})
+		require.NoError(store.evm.EvmLogs.Push(l))
+		txs[n] = tx
+	}
+	store.SetBlockEpochState(iblockproc.BlockState{LastBlock: iblockproc.BlockCtx{Idx: 5}}, iblockproc.EpochState{Epoch: 1})
+
+	store.pruneReceipts(ReceiptsRetentionConfig{Blocks: 2, MaxBlocksPerRound: 2}, nil)
+	require.Equal(idx.Block(3), store.evm.GetReceiptsTail())
+	store.pruneReceipts(ReceiptsRetentionConfig{Blocks: 2, MaxBlocksPerRound: 2}, nil)
+	require.Equal(idx.Block(4), store.evm.GetReceiptsTail())
+
+	for n := idx.Block(1); n <= 5; n++ {
+		kept := n > 3
+		receipts, _ := store.evm.GetRawReceipts(n)
+		require.Equal(kept, receipts != nil, "block %d", n)
+		require.Equal(kept, store.evm.GetTxPosition(txs[n].Hash()) != nil, "block %d", n)
+		// the blocks are kept
+		require.Len(store.GetBlockTxs(n, store.GetBlock(n)), 1)
+	}
+	logs, err := store.evm.EvmLogs.FindInBlocks(nil, 0, 5, [][]common.Hash{{addr.Hash()}})
+	require.NoError(err)
+	require.Len(logs, 2)
+	for _, l := range logs {
+		require.Greater(l.BlockNumber, uint64(3))
+	}
+
+	// nothing to prune within the retention window
+	store.pruneReceipts(ReceiptsRetentionConfig{Blocks: 2}, nil)
+	require.Equal(idx.Block(4), store.evm.GetReceiptsTail())
+}
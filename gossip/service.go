@@ -131,8 +131,9 @@ type Service struct {
 	blockProcTasksDone chan struct{}
 	blockProcModules   BlockProc
 
-	blockBusyFlag uint32
-	eventBusyFlag uint32
+	blockBusyFlag   uint32
+	eventBusyFlag   uint32
+	sfcDivergedFlag uint32
 
 	feed     ServiceFeed
 	eventMux *event.TypeMux
@@ -155,6 +156,12 @@ type Service struct {
 
 	tflusher PeriodicFlusher
 
+	txTracePruner       *txTracePruner
+	ancientFreezer      *ancientFreezer
+	eventsHistoryPruner *eventsHistoryPruner
+	receiptsPruner      *receiptsPruner
+	statePruner         *statePruner
+
 	bootstrapping bool
 
 	logger.Instance
@@ -176,6 +183,7 @@ func NewService(stack *node.Node, config Config, store *Store, blockProc BlockPr
 	svc.accountManager = stack.AccountManager()
 	svc.eventMux = stack.EventMux()
 	svc.EthAPI.SetExtRPCEnabled(stack.Config().ExtRPCEnabled())
+	svc.EthAPI.SetTraceDir(stack.ResolvePath("traces"))
 	// Create the net API service
 	svc.netRPCService = ethapi.NewPublicNetAPI(svc.p2pServer, store.GetRules().NetworkID)
 	svc.haltCheck = haltCheck
@@ -275,13 +283,18 @@ func newService(config Config, store *Store, blockProc BlockProc, engine utypes.
 	rpc.SetExecutionTimeLimit(config.RPCTimeout)
 
 	// create API backend
-	svc.EthAPI = &EthAPIBackend{false, svc, stateReader, txSigner, config.AllowUnprotectedTxs}
+	svc.EthAPI = &EthAPIBackend{false, svc, stateReader, txSigner, config.AllowUnprotectedTxs, ""}
 	if svc.EthAPI.allowUnprotectedTxs {
 		log.Info("Unprotected transactions allowed")
 	}
 
 	svc.verWatcher = verwatcher.New(netVerStore)
 	svc.tflusher = svc.makePeriodicFlusher()
+	svc.txTracePruner = newTxTracePruner(svc.store, svc.store.cfg.TxTraceRetention)
+	svc.ancientFreezer = newAncientFreezer(svc.store, svc.store.cfg.Ancient)
+	svc.eventsHistoryPruner = newEventsHistoryPruner(svc.store, svc.store.cfg.EventsHistory)
+	svc.receiptsPruner = newReceiptsPruner(svc.store, svc.store.cfg.ReceiptsRetention)
+	svc.statePruner = newStatePruner(svc.store, svc.store.cfg.StatePruning)
 
 	return svc, nil
 }
@@ -427,6 +440,11 @@ func (s *Service) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   s.netRPCService,
 			Public:    true,
+		}, {
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   NewPublicDebugAPI(s),
+			Public:    true,
 		},
 	}...)
 
@@ -438,6 +456,11 @@ func (s *Service) Start() error {
 	s.gpo.Start(&GPOBackend{s.store, s.txpool})
 	// start tflusher before starting snapshots generation
 	s.tflusher.Start()
+	s.txTracePruner.Start()
+	s.ancientFreezer.Start()
+	s.eventsHistoryPruner.Start()
+	s.receiptsPruner.Start()
+	s.statePruner.Start()
 	// start snapshots generation
 	if s.store.evm.IsEvmSnapshotPaused() && !s.config.AllowSnapsync {
 		return errors.New("cannot halt snapsync and start fullsync")
@@ -497,6 +520,11 @@ func (s *Service) Stop() error {
 	s.gpo.Stop()
 	// it's safe to stop tflusher only before locking engineMu
 	s.tflusher.Stop()
+	s.txTracePruner.Stop()
+	s.ancientFreezer.Stop()
+	s.eventsHistoryPruner.Stop()
+	s.receiptsPruner.Stop()
+	s.statePruner.Stop()
 
 	// flush the state at exit, after all the routines stopped
 	s.engineMu.Lock()
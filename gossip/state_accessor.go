@@ -21,21 +21,26 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/unicornultrafoundation/go-helios/hash"
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+
 	"github.com/unicornultrafoundation/go-u2u/common"
 	"github.com/unicornultrafoundation/go-u2u/core/state"
 	"github.com/unicornultrafoundation/go-u2u/core/types"
 	"github.com/unicornultrafoundation/go-u2u/core/vm"
 	"github.com/unicornultrafoundation/go-u2u/evmcore"
 	"github.com/unicornultrafoundation/go-u2u/log"
+	"github.com/unicornultrafoundation/go-u2u/native/iblockproc"
 	"github.com/unicornultrafoundation/go-u2u/trie"
+	"github.com/unicornultrafoundation/go-u2u/u2u"
 )
 
 // stateAtBlock retrieves the state database associated with a certain block.
 // If no state is locally available for the given block, a number of blocks
 // are attempted to be reexecuted to generate the desired state. The optional
-// base layer statedb can be passed then it's regarded as the statedb of the
-// parent block.
-func (eth *Service) stateAtBlock(evmblock *evmcore.EvmBlock, reexec uint64, base *state.StateDB,
+// base layer statedb and SFC statedb can be passed then they're regarded as the
+// statedbs of the parent block.
+func (eth *Service) stateAtBlock(evmblock *evmcore.EvmBlock, reexec uint64, base, baseSfc *state.StateDB,
 	checkLive bool) (statedb *state.StateDB, sfcStatedb *state.StateDB, err error) {
 	block := evmblock.EthBlock()
 	var (
@@ -55,9 +60,24 @@ func (eth *Service) stateAtBlock(evmblock *evmcore.EvmBlock, reexec uint64, base
 		}
 		// TODO(trinhdn97): implement diff layer for SFC state and re-execute SFC state at block as well
 	}
+	// The path scheme keeps only the latest state, the old states are read from the state history
+	// rather than regenerated
+	if eth.store.evm.StateHistoryEnabled() {
+		statedb, err = eth.store.evm.StateDB(hash.Hash(block.Root()))
+		if err != nil {
+			statedb, err = eth.store.evm.HistoricalStateDB(idx.Block(origin), hash.Hash(block.Root()))
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if sfcStatedb, err = eth.store.evm.SfcStateDB(hash.Hash(block.SfcStateRoot())); err != nil {
+			log.Warn("Failed to get SFC state", "sfcStateRoot", block.SfcStateRoot(), "err", err)
+		}
+		return statedb, sfcStatedb, nil
+	}
 	if base != nil {
-		// The optional base statedb is given, mark the start point as parent block
-		statedb, database, report = base, base.Database(), false
+		// The optional base statedbs are given, mark the start point as parent block
+		statedb, sfcStatedb, database, report = base, baseSfc, base.Database(), false
 		current = eth.EthAPI.state.GetBlock(block.ParentHash(), block.NumberU64()-1)
 	} else {
 		// Otherwise try to reexec blocks until we find a state or reach our limit
@@ -120,7 +140,8 @@ func (eth *Service) stateAtBlock(evmblock *evmcore.EvmBlock, reexec uint64, base
 		}
 		evmProcessor := evmcore.NewStateProcessor(eth.EthAPI.ChainConfig(), eth.EthAPI.state)
 		var gasUsed uint64 = 0
-		_, _, _, err := evmProcessor.Process(current, statedb, sfcStatedb, vm.Config{}, &gasUsed, func(l *types.Log, _ *state.StateDB) {})
+		// The precompiled contracts of the block processing are needed to regenerate the SFC state
+		_, _, _, err := evmProcessor.Process(current, statedb, sfcStatedb, u2u.DefaultVMConfig, &gasUsed, func(l *types.Log, _ *state.StateDB) {}, nil)
 		if err != nil {
 			return nil, nil, fmt.Errorf("processing block %d failed: %v", current.NumberU64(), err)
 		}
@@ -161,7 +182,7 @@ func (eth *Service) stateAtTransaction(evmblock *evmcore.EvmBlock, txIndex int,
 	}
 	// Lookup the statedb of parent block from the live database,
 	// otherwise regenerate it on the flight.
-	statedb, sfcStatedb, err := eth.stateAtBlock(parent, reexec, nil, true)
+	statedb, sfcStatedb, err := eth.stateAtBlock(parent, reexec, nil, nil, true)
 	if err != nil {
 		return nil, vm.BlockContext{}, nil, err
 	}
@@ -190,3 +211,36 @@ func (eth *Service) stateAtTransaction(evmblock *evmcore.EvmBlock, txIndex int,
 	}
 	return nil, vm.BlockContext{}, nil, fmt.Errorf("transaction index %d out of range for block %#x", txIndex, block.Hash())
 }
+
+// replayBlock re-executes the block transactions on top of the states of the parent block
+// through the EVM module, one transaction at a time. The onTx callback is called after
+// each transaction with its receipt, which is nil if the transaction is skipped.
+func (eth *Service) replayBlock(evmblock *evmcore.EvmBlock, statedb, sfcStatedb *state.StateDB, vmCfg vm.Config,
+	onTx func(i int, tx *types.Transaction, receipt *types.Receipt) error) error {
+	n := idx.Block(evmblock.NumberU64())
+	block := eth.store.GetBlock(n)
+	if block == nil {
+		return fmt.Errorf("block #%d not found", n)
+	}
+	es := eth.store.GetHistoryEpochState(eth.store.FindBlockEpoch(n))
+	if es == nil {
+		return fmt.Errorf("epoch state of block #%d not found", n)
+	}
+	blockCtx := iblockproc.BlockCtx{
+		Idx:     n,
+		Time:    block.Time,
+		Atropos: block.Atropos,
+	}
+	evmProcessor := eth.blockProcModules.EVMModule.Start(blockCtx, statedb, sfcStatedb, eth.GetEvmStateReader(), func(*types.Log) {}, nil,
+		es.Rules, es.Rules.EvmChainConfig(eth.store.GetUpgradeHeights()), vmCfg)
+	for i, tx := range evmblock.Transactions {
+		var receipt *types.Receipt
+		if receipts := evmProcessor.Execute(types.Transactions{tx}); len(receipts) != 0 {
+			receipt = receipts[0]
+		}
+		if err := onTx(i, tx, receipt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package gossip
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unicornultrafoundation/go-helios/hash"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/common/hexutil"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/ethapi"
+	"github.com/unicornultrafoundation/go-u2u/gossip/evmstore"
+	"github.com/unicornultrafoundation/go-u2u/logger"
+	"github.com/unicornultrafoundation/go-u2u/rpc"
+	"github.com/unicornultrafoundation/go-u2u/u2u"
+	"github.com/unicornultrafoundation/go-u2u/utils"
+)
+
+func TestReplayBlock(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	env := newTestEnv(2, 3)
+	defer env.Close()
+
+	receipts, err := env.ApplyTxs(nextEpoch,
+		env.Transfer(1, 2, utils.ToU2U(10)),
+		env.Transfer(2, 3, utils.ToU2U(5)),
+	)
+	require.NoError(err)
+
+	n := receipts[1].BlockNumber.Int64()
+	block, err := env.EthAPI.BlockByNumber(ctx, rpc.BlockNumber(n))
+	require.NoError(err)
+	parent, err := env.EthAPI.BlockByNumber(ctx, rpc.BlockNumber(n-1))
+	require.NoError(err)
+	statedb, sfcStatedb, err := env.EthAPI.StateAtBlock(ctx, parent, 0, nil, nil, true)
+	require.NoError(err)
+
+	executed := make(map[int]bool)
+	err = env.EthAPI.ReplayBlock(ctx, block, statedb, sfcStatedb, u2u.DefaultVMConfig, func(i int, tx *types.Transaction, receipt *types.Receipt) error {
+		require.Equal(block.Transactions[i].Hash(), tx.Hash())
+		require.NotNil(receipt)
+		require.Equal(tx.Hash(), receipt.TxHash)
+		executed[i] = true
+		return nil
+	})
+	require.NoError(err)
+	require.Len(executed, len(block.Transactions))
+
+	// the state after the last transaction is the state of the block
+	require.Equal(block.Root, statedb.IntermediateRoot(true))
+	if sfcStatedb != nil {
+		require.Equal(block.SfcStateRoot, sfcStatedb.IntermediateRoot(true))
+	}
+}
+
+func TestStateAtBlockWithBase(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	env := newTestEnv(2, 3)
+	defer env.Close()
+
+	var receipts types.Receipts
+	for i := 0; i < 2; i++ {
+		var err error
+		receipts, err = env.ApplyTxs(nextEpoch,
+			env.Transfer(1, 2, utils.ToU2U(10)),
+		)
+		require.NoError(err)
+	}
+
+	n := receipts[0].BlockNumber.Int64()
+	block, err := env.EthAPI.BlockByNumber(ctx, rpc.BlockNumber(n))
+	require.NoError(err)
+	parent, err := env.EthAPI.BlockByNumber(ctx, rpc.BlockNumber(n-1))
+	require.NoError(err)
+	statedb, sfcStatedb, err := env.EthAPI.StateAtBlock(ctx, parent, 0, nil, nil, true)
+	require.NoError(err)
+	require.NotNil(sfcStatedb)
+
+	// both the states are advanced on top of the given parent states
+	statedb, sfcStatedb, err = env.EthAPI.StateAtBlock(ctx, block, 0, statedb, sfcStatedb, false)
+	require.NoError(err)
+	require.Equal(block.Root, statedb.IntermediateRoot(true))
+	require.NotNil(sfcStatedb)
+	require.Equal(block.SfcStateRoot, sfcStatedb.IntermediateRoot(true))
+}
+
+func TestTraceChain(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	env := newTestEnv(2, 3)
+	defer env.Close()
+
+	var blocks []int64
+	for i := 0; i < 3; i++ {
+		receipts, err := env.ApplyTxs(sameEpoch,
+			env.Transfer(1, 2, utils.ToU2U(10)),
+		)
+		require.NoError(err)
+		blocks = append(blocks, receipts[0].BlockNumber.Int64())
+	}
+	start, end := blocks[0]-1, blocks[len(blocks)-1]
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	require.NoError(server.RegisterName("debug", ethapi.NewPublicDebugAPI(env.EthAPI)))
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	type blockTraceResult struct {
+		Block  hexutil.Uint64 `json:"block"`
+		Hash   common.Hash    `json:"hash"`
+		Traces []struct {
+			Result json.RawMessage `json:"result"`
+			Error  string          `json:"error"`
+		} `json:"traces"`
+	}
+	results := make(chan *blockTraceResult)
+	sub, err := client.Subscribe(ctx, "debug", results, "traceChain", hexutil.Uint64(start), hexutil.Uint64(end), nil)
+	require.NoError(err)
+	defer sub.Unsubscribe()
+
+	// the blocks after the start block are delivered in order
+	for n := start + 1; n <= end; n++ {
+		select {
+		case res := <-results:
+			require.Equal(uint64(n), uint64(res.Block))
+			block, err := env.EthAPI.BlockByNumber(ctx, rpc.BlockNumber(n))
+			require.NoError(err)
+			require.Equal(block.Hash, res.Hash)
+			require.Len(res.Traces, len(block.Transactions))
+			for _, trace := range res.Traces {
+				require.Empty(trace.Error)
+				require.NotEmpty(trace.Result)
+			}
+		case err := <-sub.Err():
+			t.Fatal(err)
+		case <-time.After(10 * time.Second):
+			t.Fatal("chain trace timeout")
+		}
+	}
+}
+
+func TestPathSchemeStateHistory(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	storeCfg := LiteStoreConfig()
+	storeCfg.EVM.StateScheme = rawdb.PathScheme
+	env := newTestEnvWithStoreConfig(2, 3, storeCfg)
+	defer env.Close()
+	require.Equal(rawdb.PathScheme, env.store.evm.StateScheme())
+
+	addr := env.Address(2)
+	balances := make(map[int64]*big.Int)
+	for i := 0; i < 3; i++ {
+		receipts, err := env.ApplyTxs(sameEpoch,
+			env.Transfer(1, 2, utils.ToU2U(10)),
+		)
+		require.NoError(err)
+		n := receipts[0].BlockNumber.Int64()
+		statedb, _, err := env.EthAPI.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(n)))
+		require.NoError(err)
+		balances[n] = statedb.GetBalance(addr)
+	}
+	// the nodes of the recent states are kept in memory, move the states out of them
+	for i := 0; i < evmstore.TriesInMemory; i++ {
+		_, err := env.ApplyTxs(sameEpoch,
+			env.Transfer(1, 3, utils.ToU2U(10)),
+		)
+		require.NoError(err)
+	}
+
+	// the old states are overwritten, but served from the state history
+	head := env.store.GetBlock(env.store.GetLatestBlockIndex())
+	for n, balance := range balances {
+		block, err := env.EthAPI.BlockByNumber(ctx, rpc.BlockNumber(n))
+		require.NoError(err)
+		if block.Root != common.Hash(head.Root) {
+			require.False(env.store.evm.HasStateDB(hash.Hash(block.Root)))
+		}
+		statedb, _, err := env.EthAPI.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(n)))
+		require.NoError(err, "block %d", n)
+		require.Equal(balance, statedb.GetBalance(addr), "block %d", n)
+
+		statedb, _, err = env.EthAPI.StateAtBlock(ctx, block, 0, nil, nil, true)
+		require.NoError(err, "block %d", n)
+		require.Equal(balance, statedb.GetBalance(addr), "block %d", n)
+	}
+}
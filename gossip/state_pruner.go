@@ -0,0 +1,118 @@
+package gossip
+
+import (
+	"sync"
+	"time"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/gossip/evmstore/evmpruner"
+)
+
+// statePruner periodically removes the EVM state, which doesn't belong to the latest blocks,
+// while the node is running. It's an online alternative to the `snapshot prune-state` command.
+type statePruner struct {
+	store *Store
+	cfg   StatePruningConfig
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+func newStatePruner(store *Store, cfg StatePruningConfig) *statePruner {
+	return &statePruner{
+		store: store,
+		cfg:   cfg,
+		quit:  make(chan struct{}),
+	}
+}
+
+// enabled returns true if the state history is limited
+func (p *statePruner) enabled() bool {
+	return p.cfg.Blocks != 0
+}
+
+func (p *statePruner) Start() {
+	if !p.enabled() {
+		return
+	}
+	p.wg.Add(1)
+	go p.loop()
+}
+
+func (p *statePruner) Stop() {
+	if !p.enabled() {
+		return
+	}
+	close(p.quit)
+	p.wg.Wait()
+}
+
+func (p *statePruner) loop() {
+	defer p.wg.Done()
+	period := p.cfg.PrunePeriod
+	if period <= 0 {
+		period = time.Hour
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.store.pruneState(p.cfg, p.quit)
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// pruneState removes the trie nodes and contract codes, which don't belong to the states of the latest blocks
+func (s *Store) pruneState(cfg StatePruningConfig, quit <-chan struct{}) {
+	latest := s.GetLatestBlockIndex()
+	if cfg.Blocks == 0 || latest <= cfg.Blocks {
+		return
+	}
+	start := time.Now()
+
+	if s.evm.StateScheme() == rawdb.PathScheme {
+		// only the latest state is kept, the state history of the old blocks is pruned instead
+		deleted, err := s.evm.PruneStateHistory(latest - cfg.Blocks)
+		if err != nil {
+			s.Log.Warn("State history pruning failed", "err", err, "elapsed", common.PrettyDuration(time.Since(start)))
+			return
+		}
+		s.Log.Info("Pruned EVM state history", "block", latest, "entries", deleted, "elapsed", common.PrettyDuration(time.Since(start)))
+		return
+	}
+
+	// the head state goes first as it must be retained
+	roots := make([]common.Hash, 0, cfg.Blocks)
+	known := make(map[common.Hash]bool, cfg.Blocks)
+	for n := latest; n > latest-cfg.Blocks; n-- {
+		block := s.GetBlock(n)
+		if block == nil {
+			continue
+		}
+		root := common.Hash(block.Root)
+		if !known[root] {
+			known[root] = true
+			roots = append(roots, root)
+		}
+	}
+	if len(roots) == 0 {
+		return
+	}
+
+	set, err := evmpruner.NewProbabilisticSet(cfg.BloomSize)
+	if err != nil {
+		s.Log.Error("Failed to create state bloom", "err", err)
+		return
+	}
+	s.Log.Info("Pruning EVM state", "block", latest, "roots", len(roots))
+	stats, err := s.evm.PruneState(roots, set, quit)
+	if err != nil {
+		s.Log.Warn("State pruning failed", "err", err, "elapsed", common.PrettyDuration(time.Since(start)))
+		return
+	}
+	s.Log.Info("Pruned EVM state", "block", latest, "retained", stats.Roots, "nodes", stats.Nodes, "size", stats.Size, "elapsed", common.PrettyDuration(time.Since(start)))
+}
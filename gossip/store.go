@@ -1,11 +1,13 @@
 package gossip
 
 import (
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
 	"github.com/unicornultrafoundation/go-u2u/log"
 
 	"github.com/unicornultrafoundation/go-helios/common/bigendian"
@@ -45,7 +47,8 @@ type Store struct {
 		UpgradeHeights         u2udb.Store `table:"U"`
 
 		// Transaction traces
-		TransactionTraces u2udb.Store `table:"t"`
+		TransactionTraces      u2udb.Store `table:"t"`
+		TransactionTracesIndex u2udb.Store `table:"a"`
 
 		// P2P-only
 		HighestLamport u2udb.Store `table:"l"`
@@ -65,6 +68,13 @@ type Store struct {
 		LlrEpochVoteIndex  u2udb.Store `table:"I"`
 		LlrLastBlockVotes  u2udb.Store `table:"G"`
 		LlrLastEpochVote   u2udb.Store `table:"F"`
+
+		// Ancient store
+		AncientEvents u2udb.Store `table:"f"`
+		AncientState  u2udb.Store `table:"i"`
+
+		// History expiry
+		EventsHistory u2udb.Store `table:"k"`
 	}
 
 	prevFlushTime time.Time
@@ -90,10 +100,19 @@ type Store struct {
 		Genesis                atomic.Value // store by value
 		LlrBlockVotesIndex     *VotesCache  // store by pointer
 		LlrEpochVoteIndex      *VotesCache  // store by pointer
+		PrunedEpoch            atomic.Value // store by value
+	}
+
+	ancient struct {
+		Events     *rawdb.ItemsFreezer
+		Blocks     *rawdb.ItemsFreezer
+		FirstBlock uint64 // accessed atomically
 	}
 
 	mutex struct {
 		WriteLlrState sync.Mutex
+		// History serializes the background migrations of the old events and blocks
+		History sync.Mutex
 	}
 
 	rlp rlpstore.Helper
@@ -126,15 +145,26 @@ func NewStore(dbs u2udb.FlushableDBProducer, cfg StoreConfig) *Store {
 	}
 
 	s.initCache()
-	s.evm = evmstore.NewStore(dbs, cfg.EVM)
+	if cfg.Ancient.Dir != "" {
+		if err := s.openAncient(); err != nil {
+			s.Log.Crit("Failed to open ancient store", "err", err)
+		}
+		if s.cfg.EVM.AncientDir == "" {
+			s.cfg.EVM.AncientDir = filepath.Join(cfg.Ancient.Dir, "receipts")
+		}
+	}
+	s.evm = evmstore.NewStore(dbs, s.cfg.EVM)
 
 	if cfg.TraceTransactions {
-		s.txtracer = txtracer.NewStore(s.table.TransactionTraces)
+		s.txtracer = txtracer.NewStore(s.table.TransactionTraces, s.table.TransactionTracesIndex)
 	}
 
 	if err := s.migrateData(); err != nil {
 		s.Log.Crit("Failed to migrate Gossip DB", "err", err)
 	}
+	if err := s.migrateStateScheme(); err != nil {
+		s.Log.Crit("Failed to migrate EVM state scheme", "err", err)
+	}
 
 	return s
 }
@@ -188,6 +218,9 @@ func (s *Store) Close() error {
 	if err := s.evm.Close(); err != nil {
 		return err
 	}
+	if err := s.closeAncient(); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -0,0 +1,210 @@
+package gossip
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/unicornultrafoundation/go-helios/common/bigendian"
+	"github.com/unicornultrafoundation/go-helios/hash"
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/native"
+	"github.com/unicornultrafoundation/go-u2u/rlp"
+)
+
+const (
+	ancientEventsTable = "events"
+	ancientBlocksTable = "blocks"
+)
+
+var (
+	ancientEventsKey     = []byte("e")
+	ancientFirstBlockKey = []byte("f")
+	ancientBlocksKey     = []byte("b")
+)
+
+// openAncientFreezer opens the freezer of a single table and discards the items,
+// which were appended after the last flush of the key-value database
+func openAncientFreezer(dir, table string, items uint64) (*rawdb.ItemsFreezer, error) {
+	f, err := rawdb.NewItemsFreezer(filepath.Join(dir, table), "u2u/gossip/"+table+"/", map[string]bool{
+		table: false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if f.Items() < items {
+		_ = f.Close()
+		return nil, fmt.Errorf("ancient %s are missing, stored %d, expected %d", table, f.Items(), items)
+	}
+	if err := f.Truncate(items); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// openAncient opens the ancient store of the events and blocks
+func (s *Store) openAncient() error {
+	events, err := openAncientFreezer(s.cfg.Ancient.Dir, ancientEventsTable, s.getAncientCounter(ancientEventsKey))
+	if err != nil {
+		return err
+	}
+	blocks, err := openAncientFreezer(s.cfg.Ancient.Dir, ancientBlocksTable, s.getAncientCounter(ancientBlocksKey))
+	if err != nil {
+		_ = events.Close()
+		return err
+	}
+	s.ancient.Events = events
+	s.ancient.Blocks = blocks
+	atomic.StoreUint64(&s.ancient.FirstBlock, s.getAncientCounter(ancientFirstBlockKey))
+	return nil
+}
+
+func (s *Store) closeAncient() error {
+	if s.ancient.Events == nil {
+		return nil
+	}
+	if err := s.ancient.Events.Close(); err != nil {
+		return err
+	}
+	return s.ancient.Blocks.Close()
+}
+
+func (s *Store) getAncientCounter(key []byte) uint64 {
+	b, err := s.table.AncientState.Get(key)
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if b == nil {
+		return 0
+	}
+	return bigendian.BytesToUint64(b)
+}
+
+func (s *Store) setAncientCounter(key []byte, v uint64) {
+	if err := s.table.AncientState.Put(key, bigendian.Uint64ToBytes(v)); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// HasAncient returns true if the ancient store is enabled.
+func (s *Store) HasAncient() bool {
+	return s.ancient.Events != nil
+}
+
+func (s *Store) getAncientItem(f *rawdb.ItemsFreezer, table string, item uint64) []byte {
+	if !f.Has(item) {
+		return nil
+	}
+	buf, err := f.Retrieve(table, item)
+	if err != nil {
+		s.Log.Crit("Failed to read ancient item", "table", table, "item", item, "err", err)
+	}
+	return buf
+}
+
+// getAncientEventRLP returns the event from the ancient store, or nil if it isn't frozen.
+func (s *Store) getAncientEventRLP(id hash.Event) rlp.RawValue {
+	if s.ancient.Events == nil {
+		return nil
+	}
+	b, err := s.table.AncientEvents.Get(id.Bytes())
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if b == nil {
+		return nil
+	}
+	return s.getAncientItem(s.ancient.Events, ancientEventsTable, bigendian.BytesToUint64(b))
+}
+
+// getAncientEventPayload returns the event from the ancient store, or nil if it isn't frozen.
+func (s *Store) getAncientEventPayload(id hash.Event) *native.EventPayload {
+	buf := s.getAncientEventRLP(id)
+	if buf == nil {
+		return nil
+	}
+	e := &native.EventPayload{}
+	if err := rlp.DecodeBytes(buf, e); err != nil {
+		s.Log.Crit("Failed to decode rlp", "err", err, "size", len(buf))
+	}
+	return e
+}
+
+// hasAncientEvent returns true if the event is frozen.
+func (s *Store) hasAncientEvent(id hash.Event) bool {
+	if s.ancient.Events == nil {
+		return false
+	}
+	has, _ := s.table.AncientEvents.Has(id.Bytes())
+	return has
+}
+
+// forEachAncientEventRLP iterates the frozen events in the order of their IDs.
+// Returns false if the iteration is stopped by the callback.
+// The frozen events precede all the events in the key-value database.
+func (s *Store) forEachAncientEventRLP(prefix, start []byte, onEvent func(key hash.Event, event rlp.RawValue) bool) bool {
+	if s.ancient.Events == nil {
+		return true
+	}
+	it := s.table.AncientEvents.NewIterator(prefix, start)
+	defer it.Release()
+	for it.Next() {
+		buf := s.getAncientItem(s.ancient.Events, ancientEventsTable, bigendian.BytesToUint64(it.Value()))
+		if buf == nil {
+			continue
+		}
+		if !onEvent(hash.BytesToEvent(it.Key()), buf) {
+			return false
+		}
+	}
+	return true
+}
+
+// getAncientBlockRLP returns the block from the ancient store, or nil if it isn't frozen.
+func (s *Store) getAncientBlockRLP(n idx.Block) rlp.RawValue {
+	if s.ancient.Blocks == nil {
+		return nil
+	}
+	first := atomic.LoadUint64(&s.ancient.FirstBlock)
+	if uint64(n) < first {
+		return nil
+	}
+	return s.getAncientItem(s.ancient.Blocks, ancientBlocksTable, uint64(n)-first)
+}
+
+// getAncientBlock returns the block from the ancient store, or nil if it isn't frozen.
+func (s *Store) getAncientBlock(n idx.Block) *native.Block {
+	buf := s.getAncientBlockRLP(n)
+	if buf == nil {
+		return nil
+	}
+	block := &native.Block{}
+	if err := rlp.DecodeBytes(buf, block); err != nil {
+		s.Log.Crit("Failed to decode block", "err", err)
+	}
+	return block
+}
+
+// forEachAncientBlockRLP iterates the frozen blocks starting from the start block.
+// Returns the block to continue the iteration from, and false if the iteration
+// is stopped by the callback.
+// The frozen blocks precede all the blocks in the key-value database.
+func (s *Store) forEachAncientBlockRLP(start idx.Block, fn func(n idx.Block, block rlp.RawValue) bool) (idx.Block, bool) {
+	if s.ancient.Blocks == nil || s.ancient.Blocks.Items() == 0 {
+		return start, true
+	}
+	first := idx.Block(atomic.LoadUint64(&s.ancient.FirstBlock))
+	n := start
+	if n < first {
+		n = first
+	}
+	for ; s.ancient.Blocks.Has(uint64(n - first)); n++ {
+		if !fn(n, s.getAncientBlockRLP(n)) {
+			return n, false
+		}
+	}
+	return n, true
+}
@@ -69,6 +69,9 @@ func (s *Store) GetBlock(n idx.Block) *native.Block {
 	}
 
 	block, _ := s.rlp.Get(s.table.Blocks, n.Bytes(), &native.Block{}).(*native.Block)
+	if block == nil {
+		block = s.getAncientBlock(n)
+	}
 
 	// Add to LRU cache.
 	if block != nil {
@@ -80,20 +83,34 @@ func (s *Store) GetBlock(n idx.Block) *native.Block {
 
 func (s *Store) HasBlock(n idx.Block) bool {
 	has, _ := s.table.Blocks.Has(n.Bytes())
-	return has
+	return has || s.getAncientBlockRLP(n) != nil
 }
 
-func (s *Store) ForEachBlock(fn func(index idx.Block, block *native.Block)) {
-	it := s.table.Blocks.NewIterator(nil, nil)
+// forEachBlockRLP iterates the frozen blocks and the blocks in the key-value database, starting from the start block.
+func (s *Store) forEachBlockRLP(start idx.Block, fn func(n idx.Block, block rlp.RawValue) bool) {
+	start, ok := s.forEachAncientBlockRLP(start, fn)
+	if !ok {
+		return
+	}
+	it := s.table.Blocks.NewIterator(nil, start.Bytes())
 	defer it.Release()
 	for it.Next() {
+		if !fn(idx.BytesToBlock(it.Key()), it.Value()) {
+			return
+		}
+	}
+}
+
+func (s *Store) ForEachBlock(fn func(index idx.Block, block *native.Block)) {
+	s.forEachBlockRLP(0, func(n idx.Block, blockB rlp.RawValue) bool {
 		var block native.Block
-		err := rlp.DecodeBytes(it.Value(), &block)
+		err := rlp.DecodeBytes(blockB, &block)
 		if err != nil {
 			s.Log.Crit("Failed to decode block", "err", err)
 		}
-		fn(idx.BytesToBlock(it.Key()), &block)
-	}
+		fn(n, &block)
+		return true
+	})
 }
 
 // SetBlockIndex stores chain block index.
@@ -3,12 +3,14 @@ package gossip
 import (
 	"github.com/unicornultrafoundation/go-helios/native/idx"
 	"github.com/unicornultrafoundation/go-helios/native/pos"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
 	"github.com/unicornultrafoundation/go-u2u/log"
 	ethparams "github.com/unicornultrafoundation/go-u2u/params"
 	"github.com/unicornultrafoundation/go-u2u/rlp"
 
 	"github.com/unicornultrafoundation/go-u2u/native/iblockproc"
 	"github.com/unicornultrafoundation/go-u2u/u2u"
+	"github.com/unicornultrafoundation/go-u2u/utils/signers/gsignercache"
 )
 
 const sKey = "s"
@@ -160,6 +162,11 @@ func (s *Store) GetEvmChainConfig() *ethparams.ChainConfig {
 	return s.GetRules().EvmChainConfig(s.GetUpgradeHeights())
 }
 
+// txSigner returns the transactions signer of the current EVM chain config
+func (s *Store) txSigner() types.Signer {
+	return gsignercache.Wrap(types.LatestSignerForChainID(s.GetEvmChainConfig().ChainID))
+}
+
 // GetEpochRules retrieves current network rules and epoch atomically
 func (s *Store) GetEpochRules() (u2u.Rules, idx.Epoch) {
 	es := s.GetEpochState()
@@ -9,7 +9,6 @@ import (
 
 	"github.com/unicornultrafoundation/go-helios/hash"
 	"github.com/unicornultrafoundation/go-helios/native/idx"
-	"github.com/unicornultrafoundation/go-u2u/ethdb"
 	"github.com/unicornultrafoundation/go-u2u/rlp"
 
 	"github.com/unicornultrafoundation/go-u2u/native"
@@ -23,6 +22,13 @@ func (s *Store) DelEvent(id hash.Event) {
 	if err != nil {
 		s.Log.Crit("Failed to delete key", "err", err)
 	}
+	if s.ancient.Events != nil {
+		// the frozen data stays in the flat files, but is no longer reachable
+		err = s.table.AncientEvents.Delete(key)
+		if err != nil {
+			s.Log.Crit("Failed to delete key", "err", err)
+		}
+	}
 
 	// Remove from LRU cache.
 	s.cache.Events.Remove(id)
@@ -50,12 +56,7 @@ func (s *Store) GetEventPayload(id hash.Event) *native.EventPayload {
 		return ev.(*native.EventPayload)
 	}
 
-	key := id.Bytes()
-	w, _ := s.rlp.Get(s.table.Events, key, &native.EventPayload{}).(*native.EventPayload)
-
-	if w != nil {
-		fixEventTxHashes(w)
-	}
+	w := s.loadEventPayload(id)
 
 	// Put event to LRU cache.
 	if w != nil {
@@ -74,12 +75,10 @@ func (s *Store) GetEvent(id hash.Event) *native.Event {
 		return ev.(*native.Event)
 	}
 
-	key := id.Bytes()
-	w, _ := s.rlp.Get(s.table.Events, key, &native.EventPayload{}).(*native.EventPayload)
+	w := s.loadEventPayload(id)
 	if w == nil {
 		return nil
 	}
-	fixEventTxHashes(w)
 
 	eh := w.Event
 
@@ -90,40 +89,60 @@ func (s *Store) GetEvent(id hash.Event) *native.Event {
 	return &eh
 }
 
-func (s *Store) forEachEvent(it ethdb.Iterator, onEvent func(event *native.EventPayload) bool) {
-	for it.Next() {
+// loadEventPayload reads the event from the key-value database or from the ancient store.
+func (s *Store) loadEventPayload(id hash.Event) *native.EventPayload {
+	w, _ := s.rlp.Get(s.table.Events, id.Bytes(), &native.EventPayload{}).(*native.EventPayload)
+	if w == nil {
+		w = s.getAncientEventPayload(id)
+	}
+	if w != nil {
+		fixEventTxHashes(w)
+	}
+	return w
+}
+
+func (s *Store) forEachEvent(prefix, start []byte, onEvent func(event *native.EventPayload) bool) {
+	s.forEachEventRLP(prefix, start, func(_ hash.Event, eventB rlp.RawValue) bool {
 		event := &native.EventPayload{}
-		err := rlp.DecodeBytes(it.Value(), event)
+		err := rlp.DecodeBytes(eventB, event)
 		if err != nil {
 			s.Log.Crit("Failed to decode event", "err", err)
 		}
+		return onEvent(event)
+	})
+}
 
-		if !onEvent(event) {
+func (s *Store) forEachEventRLP(prefix, start []byte, onEvent func(key hash.Event, event rlp.RawValue) bool) {
+	var lastFrozen []byte
+	if !s.forEachAncientEventRLP(prefix, start, func(key hash.Event, event rlp.RawValue) bool {
+		lastFrozen = key.Bytes()
+		return onEvent(key, event)
+	}) {
+		return
+	}
+	it := s.table.Events.NewIterator(prefix, start)
+	defer it.Release()
+	for it.Next() {
+		if lastFrozen != nil && bytes.Compare(it.Key(), lastFrozen) <= 0 {
+			// the event is frozen, but not deleted from the key-value database yet
+			continue
+		}
+		if !onEvent(hash.BytesToEvent(it.Key()), it.Value()) {
 			return
 		}
 	}
 }
 
 func (s *Store) ForEachEpochEvent(epoch idx.Epoch, onEvent func(event *native.EventPayload) bool) {
-	it := s.table.Events.NewIterator(epoch.Bytes(), nil)
-	defer it.Release()
-	s.forEachEvent(it, onEvent)
+	s.forEachEvent(epoch.Bytes(), nil, onEvent)
 }
 
 func (s *Store) ForEachEvent(start idx.Epoch, onEvent func(event *native.EventPayload) bool) {
-	it := s.table.Events.NewIterator(nil, start.Bytes())
-	defer it.Release()
-	s.forEachEvent(it, onEvent)
+	s.forEachEvent(nil, start.Bytes(), onEvent)
 }
 
 func (s *Store) ForEachEventRLP(start []byte, onEvent func(key hash.Event, event rlp.RawValue) bool) {
-	it := s.table.Events.NewIterator(nil, start)
-	defer it.Release()
-	for it.Next() {
-		if !onEvent(hash.BytesToEvent(it.Key()), it.Value()) {
-			return
-		}
-	}
+	s.forEachEventRLP(nil, start, onEvent)
 }
 
 func (s *Store) FindEventHashes(epoch idx.Epoch, lamport idx.Lamport, hashPrefix []byte) hash.Events {
@@ -132,6 +151,14 @@ func (s *Store) FindEventHashes(epoch idx.Epoch, lamport idx.Lamport, hashPrefix
 	prefix.Write(hashPrefix)
 	res := make(hash.Events, 0, 10)
 
+	if s.ancient.Events != nil {
+		it := s.table.AncientEvents.NewIterator(prefix.Bytes(), nil)
+		for it.Next() {
+			res = append(res, hash.BytesToEvent(it.Key()))
+		}
+		it.Release()
+	}
+
 	it := s.table.Events.NewIterator(prefix.Bytes(), nil)
 	defer it.Release()
 	for it.Next() {
@@ -149,6 +176,9 @@ func (s *Store) GetEventPayloadRLP(id hash.Event) rlp.RawValue {
 	if err != nil {
 		s.Log.Crit("Failed to get key-value", "err", err)
 	}
+	if data == nil {
+		return s.getAncientEventRLP(id)
+	}
 	return data
 }
 
@@ -158,7 +188,7 @@ func (s *Store) HasEvent(h hash.Event) bool {
 		return has
 	}
 	has, _ := s.table.Events.Has(h.Bytes())
-	return has
+	return has || s.hasAncientEvent(h)
 }
 
 func (s *Store) loadHighestLamport() idx.Lamport {
@@ -183,15 +183,16 @@ type LlrIdxFullBlockRecordRLP struct {
 var emptyReceiptsRLP, _ = rlp.EncodeToBytes([]*types.ReceiptForStorage{})
 
 func (s *Store) IterateFullBlockRecordsRLP(start idx.Block, f func(b idx.Block, br rlp.RawValue) bool) {
-	it := s.table.Blocks.NewIterator(nil, start.Bytes())
-	defer it.Release()
-	for it.Next() {
+	s.forEachBlockRLP(start, func(n idx.Block, blockB rlp.RawValue) bool {
+		if n < s.evm.GetReceiptsTail() {
+			// the full block records of the pruned receipts can't be served
+			return false
+		}
 		block := &native.Block{}
-		err := rlp.DecodeBytes(it.Value(), block)
+		err := rlp.DecodeBytes(blockB, block)
 		if err != nil {
 			s.Log.Crit("Failed to decode block", "err", err)
 		}
-		n := idx.BytesToBlock(it.Key())
 		txs := s.GetBlockTxs(n, block)
 		receiptsRLP := s.EvmStore().GetRawReceiptsRLP(n)
 		if receiptsRLP == nil {
@@ -213,10 +214,8 @@ func (s *Store) IterateFullBlockRecordsRLP(start idx.Block, f func(b idx.Block,
 			s.Log.Crit("Failed to encode BR", "err", err)
 		}
 
-		if !f(n, encoded) {
-			break
-		}
-	}
+		return f(n, encoded)
+	})
 }
 
 type VotesCacheID struct {
@@ -32,6 +32,43 @@ func (s *Store) migrateData() error {
 	return err
 }
 
+// migrateStateScheme moves the EVM state to the path scheme if it's required by the config. Only the
+// state of the latest block, which has it, is migrated.
+func (s *Store) migrateStateScheme() error {
+	if !s.evm.StateSchemeMigrationNeeded() && !s.evm.StateSchemeCleanupNeeded() {
+		return nil
+	}
+	flush := func() error {
+		if !s.isCommitNeeded(1000, 1000) {
+			return nil
+		}
+		return s.flushDBs()
+	}
+	if s.evm.StateSchemeMigrationNeeded() && s.HasBlockEpochState() {
+		latest := s.GetLatestBlockIndex()
+		for n := latest; ; n-- {
+			block := s.GetBlock(n)
+			if block != nil && s.evm.HasStateDB(block.Root) {
+				if n != latest {
+					s.Log.Warn("EVM state of the latest blocks is missing, they will be re-executed", "from", n, "to", latest)
+				}
+				if err := s.evm.MigrateStateScheme(common.Hash(block.Root), flush); err != nil {
+					return err
+				}
+				break
+			}
+			if n == 0 {
+				return errors.New("no EVM state to migrate")
+			}
+		}
+	}
+	// the cleanup may be resumed after the interrupted one
+	if err := s.evm.CleanupStateScheme(flush); err != nil {
+		return err
+	}
+	return s.flushDBs()
+}
+
 func (s *Store) migrations() *migration.Migration {
 	return migration.
 		Begin("u2u-gossip-store").
@@ -7,6 +7,7 @@ import (
 
 	"github.com/unicornultrafoundation/go-helios/native/idx"
 	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
 	"github.com/unicornultrafoundation/go-u2u/p2p/enode"
 )
 
@@ -164,7 +165,9 @@ func (h *handler) updateSnapsyncStage() {
 	fullsyncPossibleEver := h.store.evm.HasStateDB(h.store.GetBlockState().FinalizedStateRoot)
 	fullsyncPossibleNow := fullsyncPossibleEver && !snapGenOngoing
 	// never allow to stop fullsync as it may lead to a race condition due to overwritten EVM snapshot by snapsync
-	snapsyncPossible := h.config.AllowSnapsync && (h.syncStatus.Is(ssUnknown) || h.syncStatus.Is(ssSnaps))
+	// the state sync writes the trie nodes by hashes, so it's not possible with the path scheme
+	snapsyncPossible := h.config.AllowSnapsync && h.store.evm.StateScheme() != rawdb.PathScheme &&
+		(h.syncStatus.Is(ssUnknown) || h.syncStatus.Is(ssSnaps))
 	snapsyncNeeded := !fullsyncPossibleEver || time.Since(h.store.GetEpochState().EpochStart.Time()) > snapsyncMinEndAge
 
 	if snapsyncPossible && snapsyncNeeded {
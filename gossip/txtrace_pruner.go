@@ -0,0 +1,139 @@
+package gossip
+
+import (
+	"sync"
+	"time"
+
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+)
+
+// txTracePruner periodically removes stored transaction traces,
+// which are older than the configured retention window
+type txTracePruner struct {
+	store *Store
+	cfg   TxTraceRetentionConfig
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+func newTxTracePruner(store *Store, cfg TxTraceRetentionConfig) *txTracePruner {
+	return &txTracePruner{
+		store: store,
+		cfg:   cfg,
+		quit:  make(chan struct{}),
+	}
+}
+
+// enabled returns true if the traces are stored and the retention window is limited
+func (p *txTracePruner) enabled() bool {
+	return p.store.txtracer != nil && (p.cfg.Blocks != 0 || p.cfg.Epochs != 0)
+}
+
+func (p *txTracePruner) Start() {
+	if !p.enabled() {
+		return
+	}
+	p.wg.Add(1)
+	go p.loop()
+}
+
+func (p *txTracePruner) Stop() {
+	if !p.enabled() {
+		return
+	}
+	close(p.quit)
+	p.wg.Wait()
+}
+
+func (p *txTracePruner) loop() {
+	defer p.wg.Done()
+	period := p.cfg.PrunePeriod
+	if period <= 0 {
+		period = time.Minute
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.store.pruneTxTraces(p.cfg, p.quit)
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// txTracesRetainedFrom returns the lowest block, which traces have to be kept
+func (s *Store) txTracesRetainedFrom(cfg TxTraceRetentionConfig) idx.Block {
+	var (
+		fromBlocks, fromEpochs idx.Block
+		latest                 = s.GetLatestBlockIndex()
+	)
+	if cfg.Blocks != 0 && latest > cfg.Blocks {
+		fromBlocks = latest - cfg.Blocks + 1
+	}
+	if cfg.Epochs != 0 {
+		if epoch := s.GetEpoch(); epoch > cfg.Epochs {
+			// the history state of an epoch holds the last block of the previous epoch
+			if bs, _ := s.GetHistoryBlockEpochState(epoch - cfg.Epochs + 1); bs != nil {
+				fromEpochs = bs.LastBlock.Idx + 1
+			}
+		}
+	}
+	// keep traces within the longer of the windows
+	switch {
+	case cfg.Blocks == 0:
+		return fromEpochs
+	case cfg.Epochs == 0:
+		return fromBlocks
+	case fromBlocks < fromEpochs:
+		return fromBlocks
+	}
+	return fromEpochs
+}
+
+// pruneTxTraces removes the stored transaction traces of the blocks
+// below the retention window, at most MaxBlocksPerRound blocks at once
+func (s *Store) pruneTxTraces(cfg TxTraceRetentionConfig, quit <-chan struct{}) {
+	retainFrom := s.txTracesRetainedFrom(cfg)
+	if retainFrom == 0 {
+		return
+	}
+	start := time.Now()
+	from := idx.Block(s.txtracer.GetPrunedBlock()) + 1
+	to := retainFrom - 1
+	if cfg.MaxBlocksPerRound != 0 && to >= from+cfg.MaxBlocksPerRound {
+		to = from + cfg.MaxBlocksPerRound - 1
+	}
+	if from > to {
+		return
+	}
+
+	var counter int
+	for n := from; n <= to; n++ {
+		select {
+		case <-quit:
+			to = n - 1
+			continue
+		default:
+		}
+		if block := s.GetBlock(n); block != nil {
+			for _, tx := range s.GetBlockTxs(n, block) {
+				if err := s.txtracer.PruneTxTrace(tx.Hash()); err != nil {
+					s.Log.Warn("Failed to prune transaction traces", "block", n, "tx", tx.Hash(), "err", err)
+					continue
+				}
+				counter++
+			}
+		}
+	}
+	if to < from {
+		return
+	}
+	if err := s.txtracer.SetPrunedBlock(uint64(to)); err != nil {
+		s.Log.Warn("Failed to store pruned traces block", "block", to, "err", err)
+		return
+	}
+	s.Log.Debug("Pruned transaction traces", "from", from, "to", to, "txs", counter, "elapsed", time.Since(start))
+}
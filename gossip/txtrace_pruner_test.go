@@ -0,0 +1,63 @@
+package gossip
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+	"github.com/unicornultrafoundation/go-helios/u2udb/flushable"
+	"github.com/unicornultrafoundation/go-helios/u2udb/memorydb"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/native"
+	"github.com/unicornultrafoundation/go-u2u/native/iblockproc"
+)
+
+func TestStorePruneTxTraces(t *testing.T) {
+	require := require.New(t)
+
+	cfg := LiteStoreConfig()
+	cfg.TraceTransactions = true
+	store := NewStore(flushable.NewSyncedPool(memorydb.NewProducer(""), []byte{0}), cfg)
+	defer store.Close()
+
+	// one transaction with traces per block
+	txs := make([]common.Hash, 0)
+	for n := idx.Block(1); n <= 10; n++ {
+		tx := types.NewTx(&types.LegacyTx{Nonce: uint64(n), GasPrice: big.NewInt(1)})
+		store.evm.SetTx(tx.Hash(), tx)
+		store.SetBlock(n, &native.Block{Txs: []common.Hash{tx.Hash()}})
+		require.NoError(store.txtracer.SetTxTrace(tx.Hash(), []byte(`[]`)))
+		txs = append(txs, tx.Hash())
+	}
+	store.SetBlockEpochState(iblockproc.BlockState{LastBlock: iblockproc.BlockCtx{Idx: 10}}, iblockproc.EpochState{Epoch: 3})
+	require.Equal(uint64(10), store.txtracer.GetStats().Traces)
+
+	retention := TxTraceRetentionConfig{Blocks: 4, MaxBlocksPerRound: 4}
+	require.Equal(idx.Block(7), store.txTracesRetainedFrom(retention))
+
+	// pruning is limited by the number of blocks per round
+	store.pruneTxTraces(retention, nil)
+	require.Equal(uint64(4), store.txtracer.GetPrunedBlock())
+	store.pruneTxTraces(retention, nil)
+	require.Equal(uint64(6), store.txtracer.GetPrunedBlock())
+	store.pruneTxTraces(retention, nil)
+	require.Equal(uint64(6), store.txtracer.GetPrunedBlock())
+
+	for i, tx := range txs {
+		ok, err := store.txtracer.HasTxTrace(tx)
+		require.NoError(err)
+		require.Equal(i >= 6, ok, "block %d", i+1)
+	}
+	require.Equal(uint64(4), store.txtracer.GetStats().Traces)
+	require.Equal(uint64(4*len(`[]`)), store.txtracer.GetStats().Size)
+
+	// the epoch window is applied only if it's known
+	require.Equal(idx.Block(0), store.txTracesRetainedFrom(TxTraceRetentionConfig{Epochs: 1}))
+	store.SetHistoryBlockEpochState(3, iblockproc.BlockState{LastBlock: iblockproc.BlockCtx{Idx: 8}}, iblockproc.EpochState{Epoch: 3})
+	require.Equal(idx.Block(9), store.txTracesRetainedFrom(TxTraceRetentionConfig{Epochs: 1}))
+	// the longer of the windows is kept
+	require.Equal(idx.Block(7), store.txTracesRetainedFrom(TxTraceRetentionConfig{Blocks: 4, Epochs: 1}))
+}
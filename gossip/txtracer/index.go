@@ -0,0 +1,209 @@
+package txtrace
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+)
+
+// AddressRole is a role of an address in an indexed trace
+type AddressRole byte
+
+const (
+	// RoleFrom is the sender of a call, the creator of a contract
+	// or the self-destructed contract
+	RoleFrom AddressRole = 'f'
+	// RoleTo is the recipient of a call or the refund address of a self-destruct
+	RoleTo AddressRole = 't'
+	// RoleCreated is the address of a created contract
+	RoleCreated AddressRole = 'c'
+)
+
+// indexedKey marks the index as covering all the stored traces
+var indexedKey = []byte("_indexed")
+
+const refPrefixSize = 1 + common.AddressLength
+
+// TraceRef locates a single trace of a transaction
+type TraceRef struct {
+	Block        uint64
+	TxPosition   uint32
+	TraceAddress []uint32
+	TxHash       common.Hash
+}
+
+// indexedTrace holds the fields of a stored trace which are needed for indexing
+type indexedTrace struct {
+	Action struct {
+		From          *common.Address `json:"from"`
+		To            *common.Address `json:"to"`
+		Address       *common.Address `json:"address"`
+		RefundAddress *common.Address `json:"refund_address"`
+	} `json:"action"`
+	Result *struct {
+		Address *common.Address `json:"address"`
+	} `json:"result"`
+	BlockNumber         *big.Int `json:"blockNumber"`
+	TraceAddress        []uint32 `json:"traceAddress"`
+	TransactionPosition uint64   `json:"transactionPosition"`
+}
+
+// addresses returns the addresses of the trace with their roles
+func (t *indexedTrace) addresses() map[AddressRole]*common.Address {
+	res := make(map[AddressRole]*common.Address, 3)
+	if t.Action.From != nil {
+		res[RoleFrom] = t.Action.From
+	} else if t.Action.Address != nil {
+		res[RoleFrom] = t.Action.Address
+	}
+	if t.Action.To != nil {
+		res[RoleTo] = t.Action.To
+	} else if t.Action.RefundAddress != nil {
+		res[RoleTo] = t.Action.RefundAddress
+	}
+	if t.Result != nil && t.Result.Address != nil {
+		res[RoleCreated] = t.Result.Address
+	}
+	return res
+}
+
+func refKey(role AddressRole, addr common.Address, block uint64, txPosition uint32, traceAddress []uint32) []byte {
+	key := make([]byte, refPrefixSize+8+4+4*len(traceAddress))
+	key[0] = byte(role)
+	copy(key[1:], addr.Bytes())
+	binary.BigEndian.PutUint64(key[refPrefixSize:], block)
+	binary.BigEndian.PutUint32(key[refPrefixSize+8:], txPosition)
+	for i, pos := range traceAddress {
+		binary.BigEndian.PutUint32(key[refPrefixSize+12+4*i:], pos)
+	}
+	return key
+}
+
+func parseRefKey(key, value []byte) (TraceRef, bool) {
+	if len(key) < refPrefixSize+12 || (len(key)-refPrefixSize-12)%4 != 0 {
+		return TraceRef{}, false
+	}
+	ref := TraceRef{
+		Block:        binary.BigEndian.Uint64(key[refPrefixSize:]),
+		TxPosition:   binary.BigEndian.Uint32(key[refPrefixSize+8:]),
+		TraceAddress: make([]uint32, (len(key)-refPrefixSize-12)/4),
+		TxHash:       common.BytesToHash(value),
+	}
+	for i := range ref.TraceAddress {
+		ref.TraceAddress[i] = binary.BigEndian.Uint32(key[refPrefixSize+12+4*i:])
+	}
+	return ref, true
+}
+
+// updateIndex adds or removes the index records of the transaction traces
+func (s *Store) updateIndex(txID common.Hash, txTraces []byte, remove bool) error {
+	if len(txTraces) == 0 {
+		return nil
+	}
+	traces := make([]indexedTrace, 0)
+	if err := json.Unmarshal(txTraces, &traces); err != nil {
+		return err
+	}
+	batch := s.indexDB.NewBatch()
+	for _, trace := range traces {
+		if trace.BlockNumber == nil {
+			continue
+		}
+		for role, addr := range trace.addresses() {
+			key := refKey(role, *addr, trace.BlockNumber.Uint64(), uint32(trace.TransactionPosition), trace.TraceAddress)
+			var err error
+			if remove {
+				err = batch.Delete(key)
+			} else {
+				err = batch.Put(key, txID.Bytes())
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return batch.Write()
+}
+
+// TraceAddresses returns the addresses which appear in the transaction traces
+// and the contracts created by the transaction mapped to their creators.
+func TraceAddresses(txTraces []byte) ([]common.Address, map[common.Address]common.Address, error) {
+	traces := make([]indexedTrace, 0)
+	if len(txTraces) != 0 {
+		if err := json.Unmarshal(txTraces, &traces); err != nil {
+			return nil, nil, err
+		}
+	}
+	var (
+		addrs   = make([]common.Address, 0, len(traces))
+		created = make(map[common.Address]common.Address)
+		seen    = make(map[common.Address]bool)
+	)
+	for _, trace := range traces {
+		roles := trace.addresses()
+		for _, role := range []AddressRole{RoleFrom, RoleTo, RoleCreated} {
+			if addr := roles[role]; addr != nil && !seen[*addr] {
+				seen[*addr] = true
+				addrs = append(addrs, *addr)
+			}
+		}
+		if roles[RoleCreated] != nil && roles[RoleFrom] != nil {
+			created[*roles[RoleCreated]] = *roles[RoleFrom]
+		}
+	}
+	return addrs, created, nil
+}
+
+// IndexTxTrace adds the address index records of already stored transaction traces.
+func (s *Store) IndexTxTrace(txID common.Hash, txTraces []byte) error {
+	return s.updateIndex(txID, txTraces, false)
+}
+
+// IsIndexed returns true if the address index covers all the stored traces.
+func (s *Store) IsIndexed() bool {
+	ok, err := s.indexDB.Has(indexedKey)
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	return ok
+}
+
+// SetIndexed marks the address index as covering all the stored traces.
+func (s *Store) SetIndexed() error {
+	return s.indexDB.Put(indexedKey, []byte{1})
+}
+
+// ForEachAddressTrace iterates over the references of the traces in which the address
+// has the specified role, within the [from, to] block range in ascending order.
+func (s *Store) ForEachAddressTrace(role AddressRole, addr common.Address, from, to uint64, onRef func(ref TraceRef) bool) {
+	prefix := refKey(role, addr, 0, 0, nil)[:refPrefixSize]
+	start := make([]byte, 8)
+	binary.BigEndian.PutUint64(start, from)
+
+	it := s.indexDB.NewIterator(prefix, start)
+	defer it.Release()
+	for it.Next() {
+		ref, ok := parseRefKey(it.Key(), it.Value())
+		if !ok {
+			continue
+		}
+		if ref.Block > to || !onRef(ref) {
+			return
+		}
+	}
+}
+
+// setIndexedIfEmpty marks the index of an empty store as complete,
+// as all the traces will be indexed while stored
+func (s *Store) setIndexedIfEmpty() {
+	it := s.mainDB.NewIterator(nil, nil)
+	empty := !it.Next()
+	it.Release()
+	if empty && !s.IsIndexed() {
+		if err := s.SetIndexed(); err != nil {
+			s.Log.Crit("Failed to put key-value", "err", err)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package txtrace
+
+import (
+	"testing"
+
+	"github.com/unicornultrafoundation/go-helios/u2udb/memorydb"
+	"github.com/unicornultrafoundation/go-u2u/common"
+)
+
+func TestStoreAddressIndex(t *testing.T) {
+	var (
+		sender   = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		contract = common.HexToAddress("0x2000000000000000000000000000000000000002")
+		created  = common.HexToAddress("0x3000000000000000000000000000000000000003")
+		tx1      = common.HexToHash("0x01")
+		tx2      = common.HexToHash("0x02")
+	)
+	s := NewStore(memorydb.New(), memorydb.New())
+	if !s.IsIndexed() {
+		t.Fatal("index of an empty store should be complete")
+	}
+
+	traces1 := []byte(`[
+		{"action":{"from":"` + sender.Hex() + `","to":"` + contract.Hex() + `"},"blockNumber":5,"traceAddress":[],"transactionPosition":1},
+		{"action":{"from":"` + contract.Hex() + `"},"result":{"address":"` + created.Hex() + `"},"blockNumber":5,"traceAddress":[0],"transactionPosition":1}
+	]`)
+	traces2 := []byte(`[
+		{"action":{"from":"` + sender.Hex() + `","to":"` + contract.Hex() + `"},"blockNumber":7,"traceAddress":[],"transactionPosition":0}
+	]`)
+	if err := s.SetTxTrace(tx1, traces1); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetTxTrace(tx2, traces2); err != nil {
+		t.Fatal(err)
+	}
+
+	lookup := func(role AddressRole, addr common.Address, from, to uint64) []TraceRef {
+		refs := make([]TraceRef, 0)
+		s.ForEachAddressTrace(role, addr, from, to, func(ref TraceRef) bool {
+			refs = append(refs, ref)
+			return true
+		})
+		return refs
+	}
+
+	if refs := lookup(RoleFrom, sender, 0, 10); len(refs) != 2 || refs[0].TxHash != tx1 || refs[1].TxHash != tx2 {
+		t.Fatalf("unexpected sender refs: %+v", refs)
+	}
+	if refs := lookup(RoleFrom, sender, 6, 10); len(refs) != 1 || refs[0].Block != 7 {
+		t.Fatalf("unexpected sender refs within block range: %+v", refs)
+	}
+	if refs := lookup(RoleTo, contract, 0, 5); len(refs) != 1 || refs[0].TxPosition != 1 {
+		t.Fatalf("unexpected recipient refs: %+v", refs)
+	}
+	refs := lookup(RoleCreated, created, 0, 10)
+	if len(refs) != 1 || len(refs[0].TraceAddress) != 1 || refs[0].TraceAddress[0] != 0 {
+		t.Fatalf("unexpected created refs: %+v", refs)
+	}
+
+	addrs, creators, err := TraceAddresses(traces1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addrs) != 3 || addrs[0] != sender || addrs[1] != contract || addrs[2] != created {
+		t.Fatalf("unexpected trace addresses: %v", addrs)
+	}
+	if len(creators) != 1 || creators[created] != contract {
+		t.Fatalf("unexpected trace creators: %v", creators)
+	}
+
+	// removed traces must disappear from the index
+	if err := s.RemoveTxTrace(tx1); err != nil {
+		t.Fatal(err)
+	}
+	if refs := lookup(RoleFrom, sender, 0, 10); len(refs) != 1 || refs[0].TxHash != tx2 {
+		t.Fatalf("unexpected sender refs after removal: %+v", refs)
+	}
+	if refs := lookup(RoleCreated, created, 0, 10); len(refs) != 0 {
+		t.Fatalf("unexpected created refs after removal: %+v", refs)
+	}
+}
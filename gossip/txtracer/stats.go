@@ -0,0 +1,129 @@
+package txtrace
+
+import (
+	"encoding/binary"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/metrics"
+)
+
+var (
+	statsKey  = []byte("_stats")
+	prunedKey = []byte("_pruned")
+
+	tracesGauge      = metrics.GetOrRegisterGauge("txtracer/traces", nil)
+	tracesSizeGauge  = metrics.GetOrRegisterGauge("txtracer/size", nil)
+	prunedTxsMeter   = metrics.GetOrRegisterMeter("txtracer/pruned/txs", nil)
+	prunedBlockGauge = metrics.GetOrRegisterGauge("txtracer/pruned/block", nil)
+)
+
+// Stats is the size accounting of the stored transaction traces
+type Stats struct {
+	Traces uint64
+	Size   uint64
+}
+
+func (st Stats) bytes() []byte {
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint64(b, st.Traces)
+	binary.BigEndian.PutUint64(b[8:], st.Size)
+	return b
+}
+
+// GetStats returns the size accounting of the stored transaction traces.
+func (s *Store) GetStats() Stats {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	return s.stats
+}
+
+// SetStats overrides the size accounting of the stored transaction traces.
+func (s *Store) SetStats(st Stats) error {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	s.stats = st
+	return s.flushStats()
+}
+
+// loadStats reads the size accounting from the db,
+// or calculates it for the traces stored before the accounting was introduced
+func (s *Store) loadStats() {
+	buf, err := s.indexDB.Get(statsKey)
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if len(buf) == 16 {
+		s.stats = Stats{
+			Traces: binary.BigEndian.Uint64(buf),
+			Size:   binary.BigEndian.Uint64(buf[8:]),
+		}
+	} else {
+		s.ForEachTxtrace(func(key common.Hash, traces []byte) bool {
+			s.stats.Traces++
+			s.stats.Size += uint64(len(traces))
+			return true
+		})
+		if err := s.flushStats(); err != nil {
+			s.Log.Crit("Failed to put key-value", "err", err)
+		}
+	}
+	s.updateMetrics()
+	prunedBlockGauge.Update(int64(s.GetPrunedBlock()))
+}
+
+// accountTrace updates the size accounting after a trace is replaced
+func (s *Store) accountTrace(prevSize, newSize int) {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	if prevSize > 0 {
+		s.stats.Traces--
+		s.stats.Size -= uint64(prevSize)
+	}
+	if newSize > 0 {
+		s.stats.Traces++
+		s.stats.Size += uint64(newSize)
+	}
+	if err := s.flushStats(); err != nil {
+		s.Log.Warn("Failed to store transaction traces stats", "err", err)
+	}
+}
+
+func (s *Store) flushStats() error {
+	s.updateMetrics()
+	return s.indexDB.Put(statsKey, s.stats.bytes())
+}
+
+func (s *Store) updateMetrics() {
+	tracesGauge.Update(int64(s.stats.Traces))
+	tracesSizeGauge.Update(int64(s.stats.Size))
+}
+
+// GetPrunedBlock returns the highest block, which traces were pruned.
+func (s *Store) GetPrunedBlock() uint64 {
+	buf, err := s.indexDB.Get(prunedKey)
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if len(buf) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(buf)
+}
+
+// SetPrunedBlock stores the highest block, which traces were pruned.
+func (s *Store) SetPrunedBlock(n uint64) error {
+	prunedBlockGauge.Update(int64(n))
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, n)
+	return s.indexDB.Put(prunedKey, buf)
+}
+
+// PruneTxTrace removes transaction traces as a part of the retention policy.
+func (s *Store) PruneTxTrace(txID common.Hash) error {
+	ok, err := s.HasTxTrace(txID)
+	if err != nil || !ok {
+		return err
+	}
+	prunedTxsMeter.Mark(1)
+	return s.RemoveTxTrace(txID)
+}
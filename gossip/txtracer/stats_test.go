@@ -0,0 +1,42 @@
+package txtrace
+
+import (
+	"testing"
+
+	"github.com/unicornultrafoundation/go-helios/u2udb/memorydb"
+	"github.com/unicornultrafoundation/go-u2u/common"
+)
+
+func TestStoreStatsOfExistingTraces(t *testing.T) {
+	var (
+		tx1 = common.HexToHash("0x01")
+		tx2 = common.HexToHash("0x02")
+	)
+	// the traces stored before the size accounting was introduced
+	mainDB, indexDB := memorydb.New(), memorydb.New()
+	if err := mainDB.Put(tx1.Bytes(), []byte(`[{}]`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := mainDB.Put(tx2.Bytes(), []byte(`[]`)); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewStore(mainDB, indexDB)
+	if got, exp := s.GetStats(), (Stats{Traces: 2, Size: 6}); got != exp {
+		t.Fatal("stats of the existing traces", "got", got, "exp", exp)
+	}
+	if err := s.RemoveTxTrace(tx1); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RemoveTxTrace(tx2); err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := s.GetStats(), (Stats{}); got != exp {
+		t.Fatal("stats after the traces are removed", "got", got, "exp", exp)
+	}
+
+	// the stats are persisted and not calculated again
+	if got, exp := NewStore(mainDB, indexDB).GetStats(), (Stats{}); got != exp {
+		t.Fatal("stats after reopening", "got", got, "exp", exp)
+	}
+}
@@ -1,6 +1,8 @@
 package txtrace
 
 import (
+	"sync"
+
 	"github.com/unicornultrafoundation/go-u2u/common"
 
 	"github.com/unicornultrafoundation/go-helios/u2udb"
@@ -8,28 +10,48 @@ import (
 )
 
 // Store is a transaction traces persistent storage working over physical key-value database.
+// The traces are indexed by the addresses involved, see ForEachAddressTrace.
 type Store struct {
-	mainDB u2udb.Store
+	mainDB  u2udb.Store
+	indexDB u2udb.Store
+
+	stats   Stats
+	statsMu sync.Mutex
+
 	logger.Instance
 }
 
-// NewStore creates store over key-value db.
-func NewStore(mainDB u2udb.Store) *Store {
+// NewStore creates store over key-value dbs.
+func NewStore(mainDB, indexDB u2udb.Store) *Store {
 	s := &Store{
 		mainDB:   mainDB,
+		indexDB:  indexDB,
 		Instance: logger.New("TxTrace Store"),
 	}
+	s.setIndexedIfEmpty()
+	s.loadStats()
 	return s
 }
 
-// Close closes underlying database.
+// Close closes underlying databases.
 func (s *Store) Close() error {
+	if err := s.indexDB.Close(); err != nil {
+		return err
+	}
 	return s.mainDB.Close()
 }
 
-// SetTxTrace stores []byte representation of transaction traces.
+// SetTxTrace stores []byte representation of transaction traces and indexes them.
 func (s *Store) SetTxTrace(txID common.Hash, txTraces []byte) error {
-	return s.mainDB.Put(txID.Bytes(), txTraces)
+	prev := s.GetTx(txID)
+	if err := s.updateIndex(txID, prev, true); err != nil {
+		s.Log.Warn("Failed to unindex transaction traces", "tx", txID, "err", err)
+	}
+	if err := s.mainDB.Put(txID.Bytes(), txTraces); err != nil {
+		return err
+	}
+	s.accountTrace(len(prev), len(txTraces))
+	return s.updateIndex(txID, txTraces, false)
 }
 
 // GetTx returns stored transaction traces.
@@ -45,9 +67,17 @@ func (s *Store) GetTx(txID common.Hash) []byte {
 	return buf
 }
 
-// RemoveTxTrace removes key and []byte representation of transaction traces.
+// RemoveTxTrace removes key and []byte representation of transaction traces along with their index.
 func (s *Store) RemoveTxTrace(txID common.Hash) error {
-	return s.mainDB.Delete(txID.Bytes())
+	prev := s.GetTx(txID)
+	if err := s.updateIndex(txID, prev, true); err != nil {
+		s.Log.Warn("Failed to unindex transaction traces", "tx", txID, "err", err)
+	}
+	if err := s.mainDB.Delete(txID.Bytes()); err != nil {
+		return err
+	}
+	s.accountTrace(len(prev), 0)
+	return nil
 }
 
 // HasTxTrace stores []byte representation of transaction traces.
@@ -0,0 +1,1571 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package graphql provides a GraphQL interface to Ethereum node data.
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/unicornultrafoundation/go-helios/hash"
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/common/hexutil"
+	"github.com/unicornultrafoundation/go-u2u/common/math"
+	"github.com/unicornultrafoundation/go-u2u/core/state"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/ethapi"
+	"github.com/unicornultrafoundation/go-u2u/evmcore"
+	"github.com/unicornultrafoundation/go-u2u/gossip/filters"
+	"github.com/unicornultrafoundation/go-u2u/gossip/gasprice"
+	"github.com/unicornultrafoundation/go-u2u/native"
+	"github.com/unicornultrafoundation/go-u2u/native/drivertype"
+	"github.com/unicornultrafoundation/go-u2u/rpc"
+	"github.com/unicornultrafoundation/go-u2u/trie"
+	"github.com/unicornultrafoundation/go-u2u/utils/signers/gsignercache"
+)
+
+var (
+	errBlockInvariant = errors.New("block objects must be instantiated with at least one of num or hash")
+)
+
+// Backend provides the chain data for the GraphQL service.
+type Backend interface {
+	ethapi.Backend
+	filters.Backend
+}
+
+type Long int64
+
+// ImplementsGraphQLType returns true if Long implements the provided GraphQL type.
+func (b Long) ImplementsGraphQLType(name string) bool { return name == "Long" }
+
+// UnmarshalGraphQL unmarshals the provided GraphQL query data.
+func (b *Long) UnmarshalGraphQL(input interface{}) error {
+	var err error
+	switch input := input.(type) {
+	case string:
+		// apply leniency and support hex representations of longs.
+		var value int64
+		if strings.HasPrefix(input, "0x") {
+			var u uint64
+			u, err = hexutil.DecodeUint64(input)
+			value = int64(u)
+		} else {
+			value, err = strconv.ParseInt(input, 10, 64)
+		}
+		*b = Long(value)
+	case int32:
+		*b = Long(input)
+	case int64:
+		*b = Long(input)
+	case float64:
+		*b = Long(input)
+	default:
+		err = fmt.Errorf("unexpected type %T for Long", input)
+	}
+	return err
+}
+
+// Account represents an Ethereum account at a particular block.
+type Account struct {
+	r             *Resolver
+	address       common.Address
+	blockNrOrHash rpc.BlockNumberOrHash
+}
+
+// getState fetches the StateDB object for an account.
+func (a *Account) getState(ctx context.Context) (*state.StateDB, error) {
+	statedb, _, err := a.r.backend.StateAndHeaderByNumberOrHash(ctx, a.blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if statedb == nil {
+		return nil, errors.New("state not found")
+	}
+	return statedb, nil
+}
+
+func (a *Account) Address(ctx context.Context) (common.Address, error) {
+	return a.address, nil
+}
+
+func (a *Account) Balance(ctx context.Context) (hexutil.Big, error) {
+	statedb, err := a.getState(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	balance := statedb.GetBalance(a.address)
+	if balance == nil {
+		return hexutil.Big{}, fmt.Errorf("failed to load balance %x", a.address)
+	}
+	return hexutil.Big(*balance), nil
+}
+
+func (a *Account) TransactionCount(ctx context.Context) (hexutil.Uint64, error) {
+	// Ask transaction pool for the nonce which includes pending transactions
+	if blockNr, ok := a.blockNrOrHash.Number(); ok && blockNr == rpc.PendingBlockNumber {
+		nonce, err := a.r.backend.GetPoolNonce(ctx, a.address)
+		if err != nil {
+			return 0, err
+		}
+		return hexutil.Uint64(nonce), nil
+	}
+	statedb, err := a.getState(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(statedb.GetNonce(a.address)), nil
+}
+
+func (a *Account) Code(ctx context.Context) (hexutil.Bytes, error) {
+	statedb, err := a.getState(ctx)
+	if err != nil {
+		return hexutil.Bytes{}, err
+	}
+	return statedb.GetCode(a.address), nil
+}
+
+func (a *Account) Storage(ctx context.Context, args struct{ Slot common.Hash }) (common.Hash, error) {
+	statedb, err := a.getState(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return statedb.GetState(a.address, args.Slot), nil
+}
+
+// Log represents an individual log message. All arguments are mandatory.
+type Log struct {
+	r           *Resolver
+	transaction *Transaction
+	log         *types.Log
+}
+
+func (l *Log) Transaction(ctx context.Context) *Transaction {
+	return l.transaction
+}
+
+func (l *Log) Account(ctx context.Context, args BlockNumberArgs) *Account {
+	return &Account{
+		r:             l.r,
+		address:       l.log.Address,
+		blockNrOrHash: args.NumberOrLatest(),
+	}
+}
+
+func (l *Log) Index(ctx context.Context) int32 {
+	return int32(l.log.Index)
+}
+
+func (l *Log) Topics(ctx context.Context) []common.Hash {
+	return l.log.Topics
+}
+
+func (l *Log) Data(ctx context.Context) hexutil.Bytes {
+	return l.log.Data
+}
+
+// AccessTuple represents EIP-2930
+type AccessTuple struct {
+	address     common.Address
+	storageKeys []common.Hash
+}
+
+func (at *AccessTuple) Address(ctx context.Context) common.Address {
+	return at.address
+}
+
+func (at *AccessTuple) StorageKeys(ctx context.Context) []common.Hash {
+	return at.storageKeys
+}
+
+// Transaction represents an Ethereum transaction.
+// backend and hash are mandatory; all others will be fetched when required.
+type Transaction struct {
+	r        *Resolver
+	mu       sync.Mutex
+	hash     common.Hash
+	tx       *types.Transaction
+	block    *Block
+	index    uint64
+	lookedUp bool // set once the transaction position is looked up
+}
+
+// resolve returns the internal transaction object and the block it's included into,
+// fetching them if needed. The block is nil if the transaction isn't included into a block.
+func (t *Transaction) resolve(ctx context.Context) (*types.Transaction, *Block, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.block != nil || t.lookedUp {
+		return t.tx, t.block, nil
+	}
+	t.lookedUp = true
+	// Try to return an already finalized transaction
+	tx, blockNumber, index, err := t.r.backend.GetTransaction(ctx, t.hash)
+	if err == nil && tx != nil {
+		t.tx = tx
+		blockNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(blockNumber))
+		t.block = &Block{
+			r:            t.r,
+			numberOrHash: &blockNrOrHash,
+		}
+		t.index = index
+		return t.tx, t.block, nil
+	}
+	// No finalized transaction, try to retrieve it from the pool
+	if t.tx == nil {
+		t.tx = t.r.backend.GetPoolTransaction(t.hash)
+	}
+	return t.tx, nil, nil
+}
+
+func (t *Transaction) Hash(ctx context.Context) common.Hash {
+	return t.hash
+}
+
+func (t *Transaction) InputData(ctx context.Context) (hexutil.Bytes, error) {
+	tx, _, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return hexutil.Bytes{}, err
+	}
+	return tx.Data(), nil
+}
+
+func (t *Transaction) Gas(ctx context.Context) (hexutil.Uint64, error) {
+	tx, _, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return 0, err
+	}
+	return hexutil.Uint64(tx.Gas()), nil
+}
+
+func (t *Transaction) GasPrice(ctx context.Context) (hexutil.Big, error) {
+	tx, block, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return hexutil.Big{}, err
+	}
+	switch tx.Type() {
+	case types.DynamicFeeTxType:
+		if block != nil {
+			if baseFee, _ := block.BaseFeePerGas(ctx); baseFee != nil {
+				// price = min(tip, gasFeeCap - baseFee) + baseFee
+				return (hexutil.Big)(*math.BigMin(new(big.Int).Add(tx.GasTipCap(), baseFee.ToInt()), tx.GasFeeCap())), nil
+			}
+		}
+		return hexutil.Big(*tx.GasPrice()), nil
+	default:
+		return hexutil.Big(*tx.GasPrice()), nil
+	}
+}
+
+func (t *Transaction) EffectiveGasPrice(ctx context.Context) (*hexutil.Big, error) {
+	tx, block, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	// Pending tx
+	if block == nil {
+		return nil, nil
+	}
+	header, err := block.resolve(ctx)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	if header.BaseFee == nil {
+		return (*hexutil.Big)(tx.GasPrice()), nil
+	}
+	return (*hexutil.Big)(math.BigMin(new(big.Int).Add(tx.GasTipCap(), header.BaseFee), tx.GasFeeCap())), nil
+}
+
+func (t *Transaction) MaxFeePerGas(ctx context.Context) (*hexutil.Big, error) {
+	tx, _, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	switch tx.Type() {
+	case types.DynamicFeeTxType:
+		return (*hexutil.Big)(tx.GasFeeCap()), nil
+	default:
+		return nil, nil
+	}
+}
+
+func (t *Transaction) MaxPriorityFeePerGas(ctx context.Context) (*hexutil.Big, error) {
+	tx, _, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	switch tx.Type() {
+	case types.DynamicFeeTxType:
+		return (*hexutil.Big)(tx.GasTipCap()), nil
+	default:
+		return nil, nil
+	}
+}
+
+func (t *Transaction) EffectiveTip(ctx context.Context) (*hexutil.Big, error) {
+	tx, block, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	// Pending tx
+	if block == nil {
+		return nil, nil
+	}
+	header, err := block.resolve(ctx)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	if header.BaseFee == nil {
+		return (*hexutil.Big)(tx.GasPrice()), nil
+	}
+	tip, err := tx.EffectiveGasTip(header.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(tip), nil
+}
+
+func (t *Transaction) Value(ctx context.Context) (hexutil.Big, error) {
+	tx, _, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return hexutil.Big{}, err
+	}
+	if tx.Value() == nil {
+		return hexutil.Big{}, fmt.Errorf("invalid transaction value %x", t.hash)
+	}
+	return hexutil.Big(*tx.Value()), nil
+}
+
+func (t *Transaction) Nonce(ctx context.Context) (hexutil.Uint64, error) {
+	tx, _, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return 0, err
+	}
+	return hexutil.Uint64(tx.Nonce()), nil
+}
+
+func (t *Transaction) To(ctx context.Context, args BlockNumberArgs) (*Account, error) {
+	tx, _, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	to := tx.To()
+	if to == nil {
+		return nil, nil
+	}
+	return &Account{
+		r:             t.r,
+		address:       *to,
+		blockNrOrHash: args.NumberOrLatest(),
+	}, nil
+}
+
+func (t *Transaction) From(ctx context.Context, args BlockNumberArgs) (*Account, error) {
+	tx, _, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	signer := gsignercache.Wrap(types.LatestSigner(t.r.backend.ChainConfig()))
+	from, _ := types.Sender(signer, tx)
+	return &Account{
+		r:             t.r,
+		address:       from,
+		blockNrOrHash: args.NumberOrLatest(),
+	}, nil
+}
+
+func (t *Transaction) Block(ctx context.Context) (*Block, error) {
+	_, block, err := t.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+func (t *Transaction) Index(ctx context.Context) (*int32, error) {
+	_, block, err := t.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, nil
+	}
+	index := int32(t.index)
+	return &index, nil
+}
+
+// getReceipt returns the receipt associated with this transaction, if any.
+func (t *Transaction) getReceipt(ctx context.Context) (*types.Receipt, error) {
+	_, block, err := t.resolve(ctx)
+	if err != nil || block == nil {
+		return nil, err
+	}
+	receipts, err := block.resolveReceipts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if t.index >= uint64(len(receipts)) {
+		return nil, nil
+	}
+	return receipts[t.index], nil
+}
+
+func (t *Transaction) Status(ctx context.Context) (*hexutil.Uint64, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	ret := hexutil.Uint64(receipt.Status)
+	return &ret, nil
+}
+
+func (t *Transaction) GasUsed(ctx context.Context) (*hexutil.Uint64, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	ret := hexutil.Uint64(receipt.GasUsed)
+	return &ret, nil
+}
+
+func (t *Transaction) CumulativeGasUsed(ctx context.Context) (*hexutil.Uint64, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	ret := hexutil.Uint64(receipt.CumulativeGasUsed)
+	return &ret, nil
+}
+
+func (t *Transaction) CreatedContract(ctx context.Context, args BlockNumberArgs) (*Account, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil || receipt.ContractAddress == (common.Address{}) {
+		return nil, err
+	}
+	return &Account{
+		r:             t.r,
+		address:       receipt.ContractAddress,
+		blockNrOrHash: args.NumberOrLatest(),
+	}, nil
+}
+
+func (t *Transaction) Logs(ctx context.Context) (*[]*Log, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	ret := make([]*Log, 0, len(receipt.Logs))
+	for _, log := range receipt.Logs {
+		ret = append(ret, &Log{
+			r:           t.r,
+			transaction: t,
+			log:         log,
+		})
+	}
+	return &ret, nil
+}
+
+func (t *Transaction) Type(ctx context.Context) (*int32, error) {
+	tx, _, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	txType := int32(tx.Type())
+	return &txType, nil
+}
+
+func (t *Transaction) AccessList(ctx context.Context) (*[]*AccessTuple, error) {
+	tx, _, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	accessList := tx.AccessList()
+	ret := make([]*AccessTuple, 0, len(accessList))
+	for _, al := range accessList {
+		ret = append(ret, &AccessTuple{
+			address:     al.Address,
+			storageKeys: al.StorageKeys,
+		})
+	}
+	return &ret, nil
+}
+
+func (t *Transaction) R(ctx context.Context) (hexutil.Big, error) {
+	tx, _, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return hexutil.Big{}, err
+	}
+	_, r, _ := tx.RawSignatureValues()
+	return hexutil.Big(*r), nil
+}
+
+func (t *Transaction) S(ctx context.Context) (hexutil.Big, error) {
+	tx, _, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return hexutil.Big{}, err
+	}
+	_, _, s := tx.RawSignatureValues()
+	return hexutil.Big(*s), nil
+}
+
+func (t *Transaction) V(ctx context.Context) (hexutil.Big, error) {
+	tx, _, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return hexutil.Big{}, err
+	}
+	v, _, _ := tx.RawSignatureValues()
+	return hexutil.Big(*v), nil
+}
+
+func (t *Transaction) Raw(ctx context.Context) (hexutil.Bytes, error) {
+	tx, _, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return hexutil.Bytes{}, err
+	}
+	return tx.MarshalBinary()
+}
+
+// Event returns the DAG event which brought the transaction, if any.
+func (t *Transaction) Event(ctx context.Context) (*Event, error) {
+	position := t.r.backend.GetTxPosition(t.hash)
+	if position == nil || position.Event.IsZero() {
+		return nil, nil
+	}
+	return &Event{
+		r:  t.r,
+		id: position.Event,
+	}, nil
+}
+
+// Block represents an Ethereum block.
+// backend, and numberOrHash are mandatory. All other fields are lazily fetched
+// when required.
+type Block struct {
+	r            *Resolver
+	mu           sync.Mutex
+	numberOrHash *rpc.BlockNumberOrHash
+	block        *evmcore.EvmBlock
+	receipts     []*types.Receipt
+	events       hash.Events
+}
+
+// resolve returns the internal Block object representing this block, fetching
+// it if necessary.
+func (b *Block) resolve(ctx context.Context) (*evmcore.EvmBlock, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.block != nil {
+		return b.block, nil
+	}
+	if b.numberOrHash == nil {
+		latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		b.numberOrHash = &latest
+	}
+	var err error
+	if h, ok := b.numberOrHash.Hash(); ok {
+		b.block, err = b.r.backend.BlockByHash(ctx, h)
+	} else if number, ok := b.numberOrHash.Number(); ok {
+		b.block, err = b.r.backend.BlockByNumber(ctx, number)
+	} else {
+		return nil, errBlockInvariant
+	}
+	return b.block, err
+}
+
+// resolveExisting returns the internal Block object, or an error if the block doesn't exist.
+func (b *Block) resolveExisting(ctx context.Context) (*evmcore.EvmBlock, error) {
+	block, err := b.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, errors.New("block not found")
+	}
+	return block, nil
+}
+
+// resolveReceipts returns the list of receipts for this block, fetching them
+// if necessary.
+func (b *Block) resolveReceipts(ctx context.Context) ([]*types.Receipt, error) {
+	block, err := b.resolveExisting(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.receipts == nil {
+		receipts, err := b.r.backend.GetReceiptsByNumber(ctx, rpc.BlockNumber(block.NumberU64()))
+		if err != nil {
+			return nil, err
+		}
+		b.receipts = receipts
+	}
+	return b.receipts, nil
+}
+
+// resolveEvents returns IDs of the DAG events confirmed by this block, fetching them
+// if necessary.
+func (b *Block) resolveEvents(ctx context.Context) (hash.Events, error) {
+	block, err := b.resolveExisting(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.events == nil {
+		events, err := b.r.backend.GetBlockEvents(ctx, rpc.BlockNumber(block.NumberU64()))
+		if err != nil {
+			return nil, err
+		}
+		b.events = events
+	}
+	return b.events, nil
+}
+
+// numberOrHashOf returns the selector of the block by its number.
+func (b *Block) numberOrHashOf(ctx context.Context) (rpc.BlockNumberOrHash, error) {
+	block, err := b.resolveExisting(ctx)
+	if err != nil {
+		return rpc.BlockNumberOrHash{}, err
+	}
+	return rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(block.NumberU64())), nil
+}
+
+func (b *Block) Number(ctx context.Context) (hexutil.Uint64, error) {
+	block, err := b.resolveExisting(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(block.NumberU64()), nil
+}
+
+func (b *Block) Hash(ctx context.Context) (common.Hash, error) {
+	block, err := b.resolveExisting(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return block.Hash, nil
+}
+
+func (b *Block) GasLimit(ctx context.Context) (hexutil.Uint64, error) {
+	return hexutil.Uint64(0xffffffffffff), nil // don't use too much bits here to avoid parsing issues
+}
+
+func (b *Block) GasUsed(ctx context.Context) (hexutil.Uint64, error) {
+	block, err := b.resolveExisting(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(block.GasUsed), nil
+}
+
+func (b *Block) BaseFeePerGas(ctx context.Context) (*hexutil.Big, error) {
+	block, err := b.resolveExisting(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if block.BaseFee == nil {
+		return nil, nil
+	}
+	return (*hexutil.Big)(block.BaseFee), nil
+}
+
+func (b *Block) Parent(ctx context.Context) (*Block, error) {
+	block, err := b.resolveExisting(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if block.NumberU64() == 0 {
+		return nil, nil
+	}
+	numberOrHash := rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(block.NumberU64() - 1))
+	return &Block{
+		r:            b.r,
+		numberOrHash: &numberOrHash,
+	}, nil
+}
+
+func (b *Block) Difficulty(ctx context.Context) (hexutil.Big, error) {
+	return hexutil.Big{}, nil
+}
+
+func (b *Block) Timestamp(ctx context.Context) (hexutil.Uint64, error) {
+	block, err := b.resolveExisting(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(block.Time.Unix()), nil
+}
+
+func (b *Block) Nonce(ctx context.Context) (hexutil.Bytes, error) {
+	return types.BlockNonce{}.MarshalText()
+}
+
+func (b *Block) MixHash(ctx context.Context) (common.Hash, error) {
+	return common.Hash{}, nil
+}
+
+func (b *Block) TransactionsRoot(ctx context.Context) (common.Hash, error) {
+	block, err := b.resolveExisting(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return block.TxHash, nil
+}
+
+func (b *Block) StateRoot(ctx context.Context) (common.Hash, error) {
+	block, err := b.resolveExisting(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return block.Root, nil
+}
+
+func (b *Block) ReceiptsRoot(ctx context.Context) (common.Hash, error) {
+	receipts, err := b.resolveReceipts(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if len(receipts) == 0 {
+		return types.EmptyRootHash, nil
+	}
+	return types.DeriveSha(types.Receipts(receipts), trie.NewStackTrie(nil)), nil
+}
+
+func (b *Block) OmmerHash(ctx context.Context) (common.Hash, error) {
+	return types.EmptyUncleHash, nil
+}
+
+func (b *Block) OmmerCount(ctx context.Context) (*int32, error) {
+	count := int32(0)
+	return &count, nil
+}
+
+func (b *Block) Ommers(ctx context.Context) (*[]*Block, error) {
+	ret := make([]*Block, 0)
+	return &ret, nil
+}
+
+func (b *Block) OmmerAt(ctx context.Context, args struct{ Index int32 }) (*Block, error) {
+	return nil, nil
+}
+
+func (b *Block) ExtraData(ctx context.Context) (hexutil.Bytes, error) {
+	return hexutil.Bytes{}, nil
+}
+
+func (b *Block) LogsBloom(ctx context.Context) (hexutil.Bytes, error) {
+	receipts, err := b.resolveReceipts(ctx)
+	if err != nil {
+		return hexutil.Bytes{}, err
+	}
+	return types.CreateBloom(receipts).Bytes(), nil
+}
+
+func (b *Block) TotalDifficulty(ctx context.Context) (hexutil.Big, error) {
+	block, err := b.resolveExisting(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	td := b.r.backend.GetTd(block.Hash)
+	if td == nil {
+		return hexutil.Big{}, nil
+	}
+	return hexutil.Big(*td), nil
+}
+
+func (b *Block) Miner(ctx context.Context, args BlockNumberArgs) (*Account, error) {
+	block, err := b.resolveExisting(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current, err := b.numberOrHashOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Account{
+		r:             b.r,
+		address:       block.Coinbase,
+		blockNrOrHash: args.NumberOr(current),
+	}, nil
+}
+
+func (b *Block) TransactionCount(ctx context.Context) (*int32, error) {
+	block, err := b.resolve(ctx)
+	if err != nil || block == nil {
+		return nil, err
+	}
+	count := int32(len(block.Transactions))
+	return &count, err
+}
+
+func (b *Block) Transactions(ctx context.Context) (*[]*Transaction, error) {
+	block, err := b.resolve(ctx)
+	if err != nil || block == nil {
+		return nil, err
+	}
+	ret := make([]*Transaction, 0, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		ret = append(ret, &Transaction{
+			r:     b.r,
+			hash:  tx.Hash(),
+			tx:    tx,
+			block: b,
+			index: uint64(i),
+		})
+	}
+	return &ret, nil
+}
+
+func (b *Block) TransactionAt(ctx context.Context, args struct{ Index int32 }) (*Transaction, error) {
+	block, err := b.resolve(ctx)
+	if err != nil || block == nil {
+		return nil, err
+	}
+	txs := block.Transactions
+	if args.Index < 0 || int(args.Index) >= len(txs) {
+		return nil, nil
+	}
+	tx := txs[args.Index]
+	return &Transaction{
+		r:     b.r,
+		hash:  tx.Hash(),
+		tx:    tx,
+		block: b,
+		index: uint64(args.Index),
+	}, nil
+}
+
+// BlockFilterCriteria encapsulates criteria passed to a `logs` accessor inside
+// a block.
+type BlockFilterCriteria struct {
+	Addresses *[]common.Address // restricts matches to events created by specific contracts
+
+	// The Topic list restricts matches to particular event topics. Each event has a list
+	// of topics. Topics matches a prefix of that list. An empty element slice matches any
+	// topic. Non-empty elements represent an alternative that matches any of the
+	// contained topics.
+	//
+	// Examples:
+	// {} or nil          matches any topic list
+	// {{A}}              matches topic A in first position
+	// {{}, {B}}          matches any topic in first position, B in second position
+	// {{A}, {B}}         matches topic A in first position, B in second position
+	// {{A, B}}, {C, D}}  matches topic (A OR B) in first position, (C OR D) in second position
+	Topics *[][]common.Hash
+}
+
+// runFilter accepts a filter and executes it, returning all its results as
+// `Log` objects.
+func runFilter(ctx context.Context, r *Resolver, filter *filters.Filter) ([]*Log, error) {
+	logs, err := filter.Logs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Log, 0, len(logs))
+	for _, log := range logs {
+		ret = append(ret, &Log{
+			r:           r,
+			transaction: &Transaction{r: r, hash: log.TxHash},
+			log:         log,
+		})
+	}
+	return ret, nil
+}
+
+func (b *Block) Logs(ctx context.Context, args struct{ Filter BlockFilterCriteria }) ([]*Log, error) {
+	block, err := b.resolveExisting(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var addresses []common.Address
+	if args.Filter.Addresses != nil {
+		addresses = *args.Filter.Addresses
+	}
+	var topics [][]common.Hash
+	if args.Filter.Topics != nil {
+		topics = *args.Filter.Topics
+	}
+	// Construct the range filter
+	filter := filters.NewBlockFilter(b.r.backend, b.r.filterConfig, block.Hash, addresses, topics)
+
+	// Run the filter and return all the logs
+	return runFilter(ctx, b.r, filter)
+}
+
+func (b *Block) Account(ctx context.Context, args struct {
+	Address common.Address
+}) (*Account, error) {
+	current, err := b.numberOrHashOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Account{
+		r:             b.r,
+		address:       args.Address,
+		blockNrOrHash: current,
+	}, nil
+}
+
+// CallData encapsulates arguments to `call` or `estimateGas`.
+// All arguments are optional.
+type CallData struct {
+	From                 *common.Address // The Ethereum address the call is from.
+	To                   *common.Address // The Ethereum address the call is to.
+	Gas                  *Long           // The amount of gas provided for the call.
+	GasPrice             *hexutil.Big    // The price of each unit of gas, in wei.
+	MaxFeePerGas         *hexutil.Big    // The max price of each unit of gas, in wei (1559).
+	MaxPriorityFeePerGas *hexutil.Big    // The max tip of each unit of gas, in wei (1559).
+	Value                *hexutil.Big    // The value sent along with the call.
+	Data                 *hexutil.Bytes  // Any data sent with the call.
+}
+
+// args converts the call data to the transaction arguments.
+func (c CallData) args() ethapi.TransactionArgs {
+	args := ethapi.TransactionArgs{
+		From:                 c.From,
+		To:                   c.To,
+		GasPrice:             c.GasPrice,
+		MaxFeePerGas:         c.MaxFeePerGas,
+		MaxPriorityFeePerGas: c.MaxPriorityFeePerGas,
+		Value:                c.Value,
+		Data:                 c.Data,
+	}
+	if c.Gas != nil {
+		gas := hexutil.Uint64(*c.Gas)
+		args.Gas = &gas
+	}
+	return args
+}
+
+// CallResult encapsulates the result of an invocation of the `call` accessor.
+type CallResult struct {
+	data    hexutil.Bytes  // The return data from the call
+	gasUsed hexutil.Uint64 // The amount of gas used
+	status  hexutil.Uint64 // The return status of the call - 0 for failure or 1 for success.
+}
+
+func (c *CallResult) Data() hexutil.Bytes {
+	return c.data
+}
+
+func (c *CallResult) GasUsed() hexutil.Uint64 {
+	return c.gasUsed
+}
+
+func (c *CallResult) Status() hexutil.Uint64 {
+	return c.status
+}
+
+func (r *Resolver) call(ctx context.Context, data CallData, blockNrOrHash rpc.BlockNumberOrHash) (*CallResult, error) {
+	result, err := ethapi.DoCall(ctx, r.backend, data.args(), blockNrOrHash, nil, r.backend.RPCTimeout(), r.backend.RPCGasCap())
+	if err != nil {
+		return nil, err
+	}
+	status := hexutil.Uint64(1)
+	if result.Failed() {
+		status = 0
+	}
+	return &CallResult{
+		data:    result.Return(),
+		gasUsed: hexutil.Uint64(result.UsedGas),
+		status:  status,
+	}, nil
+}
+
+func (r *Resolver) estimateGas(ctx context.Context, data CallData, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+	return ethapi.DoEstimateGas(ctx, r.backend, data.args(), blockNrOrHash, nil, r.backend.RPCGasCap())
+}
+
+func (b *Block) Call(ctx context.Context, args struct {
+	Data CallData
+}) (*CallResult, error) {
+	current, err := b.numberOrHashOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return b.r.call(ctx, args.Data, current)
+}
+
+func (b *Block) EstimateGas(ctx context.Context, args struct {
+	Data CallData
+}) (hexutil.Uint64, error) {
+	current, err := b.numberOrHashOf(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return b.r.estimateGas(ctx, args.Data, current)
+}
+
+// Epoch returns the epoch of the block.
+func (b *Block) Epoch(ctx context.Context) (hexutil.Uint64, error) {
+	block, err := b.resolveExisting(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(hash.Event(block.Hash).Epoch()), nil
+}
+
+// TimestampNano returns the timestamp of the block in nanoseconds.
+func (b *Block) TimestampNano(ctx context.Context) (hexutil.Uint64, error) {
+	block, err := b.resolveExisting(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(block.Time), nil
+}
+
+// SfcStateRoot returns the root of the SFC state after the block.
+func (b *Block) SfcStateRoot(ctx context.Context) (common.Hash, error) {
+	block, err := b.resolveExisting(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return block.SfcStateRoot, nil
+}
+
+// Atropos returns the DAG event which decided the block.
+func (b *Block) Atropos(ctx context.Context) (*Event, error) {
+	block, err := b.resolveExisting(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return b.r.event(ctx, hash.Event(block.Hash))
+}
+
+// EventCount returns the number of the DAG events confirmed by the block.
+func (b *Block) EventCount(ctx context.Context) (*int32, error) {
+	events, err := b.resolveEvents(ctx)
+	if err != nil || events == nil {
+		return nil, err
+	}
+	count := int32(len(events))
+	return &count, nil
+}
+
+// Events returns the DAG events confirmed by the block.
+func (b *Block) Events(ctx context.Context) (*[]*Event, error) {
+	events, err := b.resolveEvents(ctx)
+	if err != nil || events == nil {
+		return nil, err
+	}
+	ret := make([]*Event, 0, len(events))
+	for _, id := range events {
+		ret = append(ret, &Event{
+			r:  b.r,
+			id: id,
+		})
+	}
+	return &ret, nil
+}
+
+// Validator represents a validator in the epoch of an event.
+type Validator struct {
+	r     *Resolver
+	id    idx.ValidatorID
+	epoch idx.Epoch
+}
+
+// profile returns the profile of the validator in the epoch, or nil if it's unknown.
+func (v *Validator) profile(ctx context.Context) (*drivertype.Validator, error) {
+	_, es, err := v.r.backend.GetEpochBlockState(ctx, rpc.BlockNumber(v.epoch))
+	if err != nil {
+		return nil, err
+	}
+	if es == nil || es.Epoch != v.epoch {
+		// the state of the current epoch isn't sealed yet
+		_, es, err = v.r.backend.GetEpochBlockState(ctx, rpc.PendingBlockNumber)
+		if err != nil || es == nil || es.Epoch != v.epoch {
+			return nil, err
+		}
+	}
+	profile, ok := es.ValidatorProfiles[v.id]
+	if !ok {
+		return nil, nil
+	}
+	return &profile, nil
+}
+
+func (v *Validator) ID(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(v.id)
+}
+
+func (v *Validator) PubKey(ctx context.Context) (*hexutil.Bytes, error) {
+	profile, err := v.profile(ctx)
+	if err != nil || profile == nil {
+		return nil, err
+	}
+	pubkey := hexutil.Bytes(profile.PubKey.Bytes())
+	return &pubkey, nil
+}
+
+func (v *Validator) Weight(ctx context.Context) (*hexutil.Big, error) {
+	profile, err := v.profile(ctx)
+	if err != nil || profile == nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(profile.Weight), nil
+}
+
+// Event represents a DAG event.
+// backend and id are mandatory; all others will be fetched when required.
+type Event struct {
+	r       *Resolver
+	mu      sync.Mutex
+	id      hash.Event
+	event   *native.Event
+	payload *native.EventPayload
+}
+
+// resolve returns the header of the event, fetching it if needed.
+func (e *Event) resolve(ctx context.Context) (*native.Event, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.event != nil {
+		return e.event, nil
+	}
+	event, err := e.r.backend.GetEvent(ctx, e.id.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, fmt.Errorf("event %s not found", e.id.String())
+	}
+	e.event = event
+	return e.event, nil
+}
+
+// resolvePayload returns the event with its payload, fetching it if needed.
+func (e *Event) resolvePayload(ctx context.Context) (*native.EventPayload, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.payload != nil {
+		return e.payload, nil
+	}
+	payload, err := e.r.backend.GetEventPayload(ctx, e.id.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if payload == nil {
+		return nil, fmt.Errorf("event %s not found", e.id.String())
+	}
+	e.payload = payload
+	return e.payload, nil
+}
+
+func (e *Event) ID(ctx context.Context) common.Hash {
+	return common.Hash(e.id)
+}
+
+func (e *Event) Epoch(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(e.id.Epoch())
+}
+
+func (e *Event) Lamport(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(e.id.Lamport())
+}
+
+func (e *Event) Seq(ctx context.Context) (hexutil.Uint64, error) {
+	event, err := e.resolve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(event.Seq()), nil
+}
+
+func (e *Event) Frame(ctx context.Context) (hexutil.Uint64, error) {
+	event, err := e.resolve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(event.Frame()), nil
+}
+
+func (e *Event) Creator(ctx context.Context) (*Validator, error) {
+	event, err := e.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Validator{
+		r:     e.r,
+		id:    event.Creator(),
+		epoch: event.Epoch(),
+	}, nil
+}
+
+func (e *Event) CreationTime(ctx context.Context) (hexutil.Uint64, error) {
+	event, err := e.resolve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(event.CreationTime()), nil
+}
+
+func (e *Event) MedianTime(ctx context.Context) (hexutil.Uint64, error) {
+	event, err := e.resolve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(event.MedianTime()), nil
+}
+
+func (e *Event) Parents(ctx context.Context) ([]*Event, error) {
+	event, err := e.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Event, 0, len(event.Parents()))
+	for _, id := range event.Parents() {
+		ret = append(ret, &Event{
+			r:  e.r,
+			id: id,
+		})
+	}
+	return ret, nil
+}
+
+func (e *Event) ExtraData(ctx context.Context) (hexutil.Bytes, error) {
+	event, err := e.resolve(ctx)
+	if err != nil {
+		return hexutil.Bytes{}, err
+	}
+	return event.Extra(), nil
+}
+
+func (e *Event) GasPowerUsed(ctx context.Context) (hexutil.Uint64, error) {
+	event, err := e.resolve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(event.GasPowerUsed()), nil
+}
+
+func (e *Event) TransactionCount(ctx context.Context) (int32, error) {
+	payload, err := e.resolvePayload(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int32(payload.Txs().Len()), nil
+}
+
+func (e *Event) Transactions(ctx context.Context) ([]*Transaction, error) {
+	payload, err := e.resolvePayload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Transaction, 0, payload.Txs().Len())
+	for _, tx := range payload.Txs() {
+		ret = append(ret, &Transaction{
+			r:    e.r,
+			hash: tx.Hash(),
+			tx:   tx,
+		})
+	}
+	return ret, nil
+}
+
+// BlockNumberArgs encapsulates arguments to accessors that specify a block number.
+type BlockNumberArgs struct {
+	// TODO: Ideally we could use input unions to allow the query to specify the
+	// block parameter by hash, block number, or tag but input unions aren't part of the
+	// standard GraphQL schema SDL yet, see: https://github.com/graphql/graphql-spec/issues/488
+	Block *Long
+}
+
+// NumberOr returns the provided block number argument, or the "current" block number or hash if none
+// was provided.
+func (a BlockNumberArgs) NumberOr(current rpc.BlockNumberOrHash) rpc.BlockNumberOrHash {
+	if a.Block != nil {
+		blockNr := rpc.BlockNumber(*a.Block)
+		return rpc.BlockNumberOrHashWithNumber(blockNr)
+	}
+	return current
+}
+
+// NumberOrLatest returns the provided block number argument, or the "latest" block number if none
+// was provided.
+func (a BlockNumberArgs) NumberOrLatest() rpc.BlockNumberOrHash {
+	return a.NumberOr(rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+}
+
+// Pending represents the pending state, which is the state of the latest block
+// combined with the transactions of the pool.
+type Pending struct {
+	r *Resolver
+}
+
+func (p *Pending) TransactionCount(ctx context.Context) (int32, error) {
+	txs, err := p.r.backend.GetPoolTransactions()
+	return int32(len(txs)), err
+}
+
+func (p *Pending) Transactions(ctx context.Context) (*[]*Transaction, error) {
+	txs, err := p.r.backend.GetPoolTransactions()
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Transaction, 0, len(txs))
+	for _, tx := range txs {
+		ret = append(ret, &Transaction{
+			r:    p.r,
+			hash: tx.Hash(),
+			tx:   tx,
+		})
+	}
+	return &ret, nil
+}
+
+func (p *Pending) Account(ctx context.Context, args struct {
+	Address common.Address
+}) *Account {
+	return &Account{
+		r:             p.r,
+		address:       args.Address,
+		blockNrOrHash: rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber),
+	}
+}
+
+func (p *Pending) Call(ctx context.Context, args struct {
+	Data CallData
+}) (*CallResult, error) {
+	return p.r.call(ctx, args.Data, rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber))
+}
+
+func (p *Pending) EstimateGas(ctx context.Context, args struct {
+	Data CallData
+}) (hexutil.Uint64, error) {
+	return p.r.estimateGas(ctx, args.Data, rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber))
+}
+
+// Resolver is the top-level object in the GraphQL hierarchy.
+type Resolver struct {
+	backend      Backend
+	filterConfig filters.Config
+}
+
+func (r *Resolver) Block(ctx context.Context, args struct {
+	Number *Long
+	Hash   *common.Hash
+}) (*Block, error) {
+	var numberOrHash rpc.BlockNumberOrHash
+	if args.Number != nil {
+		if *args.Number < 0 {
+			return nil, nil
+		}
+		numberOrHash = rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(*args.Number))
+	} else if args.Hash != nil {
+		numberOrHash = rpc.BlockNumberOrHashWithHash(*args.Hash, false)
+	} else {
+		numberOrHash = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	}
+	block := &Block{
+		r:            r,
+		numberOrHash: &numberOrHash,
+	}
+	// Resolve the block, return nil if it doesn't exist
+	if b, err := block.resolve(ctx); err != nil || b == nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+func (r *Resolver) Blocks(ctx context.Context, args struct {
+	From *Long
+	To   *Long
+}) ([]*Block, error) {
+	var from, to rpc.BlockNumber
+	if args.From != nil {
+		from = rpc.BlockNumber(*args.From)
+	}
+	head := rpc.BlockNumber(r.backend.CurrentBlock().NumberU64())
+	if args.To != nil && rpc.BlockNumber(*args.To) < head {
+		to = rpc.BlockNumber(*args.To)
+	} else {
+		to = head
+	}
+	if from < 0 || to < from {
+		return []*Block{}, nil
+	}
+	ret := make([]*Block, 0, to-from+1)
+	for i := from; i <= to; i++ {
+		numberOrHash := rpc.BlockNumberOrHashWithNumber(i)
+		block := &Block{
+			r:            r,
+			numberOrHash: &numberOrHash,
+		}
+		// Resolve the block, stop at the first missing one
+		if b, err := block.resolve(ctx); err != nil {
+			return nil, err
+		} else if b == nil {
+			break
+		}
+		ret = append(ret, block)
+	}
+	return ret, nil
+}
+
+func (r *Resolver) Pending(ctx context.Context) *Pending {
+	return &Pending{r}
+}
+
+func (r *Resolver) Transaction(ctx context.Context, args struct{ Hash common.Hash }) (*Transaction, error) {
+	tx := &Transaction{
+		r:    r,
+		hash: args.Hash,
+	}
+	// Resolve the transaction; if it doesn't exist, return nil.
+	t, _, err := tx.resolve(ctx)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (r *Resolver) SendRawTransaction(ctx context.Context, args struct{ Data hexutil.Bytes }) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(args.Data); err != nil {
+		return common.Hash{}, err
+	}
+	return ethapi.SubmitTransaction(ctx, r.backend, tx)
+}
+
+// FilterCriteria encapsulates the arguments to `logs` on the root resolver object.
+type FilterCriteria struct {
+	FromBlock *Long             // beginning of the queried range, nil means latest block
+	ToBlock   *Long             // end of the range, nil means latest block
+	Addresses *[]common.Address // restricts matches to events created by specific contracts
+
+	// The Topic list restricts matches to particular event topics. Each event has a list
+	// of topics. Topics matches a prefix of that list. An empty element slice matches any
+	// topic. Non-empty elements represent an alternative that matches any of the
+	// contained topics.
+	//
+	// Examples:
+	// {} or nil          matches any topic list
+	// {{A}}              matches topic A in first position
+	// {{}, {B}}          matches any topic in first position, B in second position
+	// {{A}, {B}}         matches topic A in first position, B in second position
+	// {{A, B}}, {C, D}}  matches topic (A OR B) in first position, (C OR D) in second position
+	Topics *[][]common.Hash
+}
+
+func (r *Resolver) Logs(ctx context.Context, args struct{ Filter FilterCriteria }) ([]*Log, error) {
+	// Convert the RPC block numbers into internal representations
+	begin := rpc.LatestBlockNumber.Int64()
+	if args.Filter.FromBlock != nil {
+		begin = int64(*args.Filter.FromBlock)
+	}
+	end := rpc.LatestBlockNumber.Int64()
+	if args.Filter.ToBlock != nil {
+		end = int64(*args.Filter.ToBlock)
+	}
+	var addresses []common.Address
+	if args.Filter.Addresses != nil {
+		addresses = *args.Filter.Addresses
+	}
+	var topics [][]common.Hash
+	if args.Filter.Topics != nil {
+		topics = *args.Filter.Topics
+	}
+	// Construct the range filter
+	filter := filters.NewRangeFilter(r.backend, r.filterConfig, begin, end, addresses, topics)
+	return runFilter(ctx, r, filter)
+}
+
+func (r *Resolver) GasPrice(ctx context.Context) (hexutil.Big, error) {
+	tipcap := r.backend.SuggestGasTipCap(ctx, gasprice.AsDefaultCertainty)
+	if head := r.backend.CurrentBlock(); head.BaseFee != nil {
+		tipcap.Add(tipcap, head.BaseFee)
+	}
+	return (hexutil.Big)(*tipcap), nil
+}
+
+func (r *Resolver) MaxPriorityFeePerGas(ctx context.Context) (hexutil.Big, error) {
+	tipcap := r.backend.SuggestGasTipCap(ctx, gasprice.AsDefaultCertainty)
+	return (hexutil.Big)(*tipcap), nil
+}
+
+func (r *Resolver) ChainID(ctx context.Context) (hexutil.Big, error) {
+	return hexutil.Big(*r.backend.ChainConfig().ChainID), nil
+}
+
+// Event returns the DAG event by its ID, or nil if it doesn't exist.
+func (r *Resolver) Event(ctx context.Context, args struct{ ID common.Hash }) (*Event, error) {
+	return r.event(ctx, hash.Event(args.ID))
+}
+
+func (r *Resolver) event(ctx context.Context, id hash.Event) (*Event, error) {
+	event := &Event{
+		r:  r,
+		id: id,
+	}
+	if _, err := event.resolve(ctx); err != nil {
+		return nil, nil
+	}
+	return event, nil
+}
+
+// SyncState represents the synchronisation status returned from the `syncing` accessor.
+type SyncState struct {
+	progress ethapi.PeerProgress
+}
+
+func (s *SyncState) StartingBlock() hexutil.Uint64 {
+	return 0
+}
+
+func (s *SyncState) CurrentBlock() hexutil.Uint64 {
+	return hexutil.Uint64(s.progress.CurrentBlock)
+}
+
+func (s *SyncState) HighestBlock() hexutil.Uint64 {
+	return hexutil.Uint64(s.progress.HighestBlock)
+}
+
+func (s *SyncState) CurrentEpoch() hexutil.Uint64 {
+	return hexutil.Uint64(s.progress.CurrentEpoch)
+}
+
+func (s *SyncState) HighestEpoch() hexutil.Uint64 {
+	return hexutil.Uint64(s.progress.HighestEpoch)
+}
+
+// Syncing returns false in case the node is currently not syncing with the network. It can be up to date or has not
+// yet received the latest block headers from its pears. In case it is synchronizing:
+// - startingBlock: block number this node started to synchronise from
+// - currentBlock:  block number this node is currently importing
+// - highestBlock:  block number of the highest block header this node has received from peers
+// - currentEpoch:  epoch this node is currently importing
+// - highestEpoch:  epoch of the highest block header this node has received from peers
+func (r *Resolver) Syncing() (*SyncState, error) {
+	progress := r.backend.Progress()
+
+	// Return not syncing if the synchronisation already completed
+	if time.Since(progress.CurrentBlockTime.Time()) <= 90*time.Minute { // should be >> MaxEmitInterval
+		return nil, nil
+	}
+	// Otherwise gather the block sync stats
+	return &SyncState{progress}, nil
+}
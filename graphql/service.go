@@ -0,0 +1,82 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/unicornultrafoundation/go-u2u/gossip/filters"
+	"github.com/unicornultrafoundation/go-u2u/node"
+)
+
+type handler struct {
+	Schema *graphql.Schema
+}
+
+func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var params struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response := h.Schema.Exec(r.Context(), params.Query, params.OperationName, params.Variables)
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if len(response.Errors) > 0 {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	w.Write(responseJSON)
+}
+
+// NewHandler creates the GraphQL handler over the backend.
+func NewHandler(backend Backend, filterConfig filters.Config) (http.Handler, error) {
+	q := Resolver{
+		backend:      backend,
+		filterConfig: filterConfig,
+	}
+	s, err := graphql.ParseSchema(schema, &q)
+	if err != nil {
+		return nil, err
+	}
+	return handler{Schema: s}, nil
+}
+
+// New constructs a new GraphQL service instance and mounts it on the HTTP server of the node.
+func New(stack *node.Node, backend Backend, filterConfig filters.Config, cors, vhosts []string) error {
+	h, err := NewHandler(backend, filterConfig)
+	if err != nil {
+		return err
+	}
+	handler := node.NewHTTPHandlerStack(h, cors, vhosts)
+
+	stack.RegisterHandler("GraphQL UI", "/graphql/ui", GraphiQL{})
+	stack.RegisterHandler("GraphQL", "/graphql", handler)
+	stack.RegisterHandler("GraphQL", "/graphql/", handler)
+	return nil
+}
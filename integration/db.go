@@ -31,6 +31,8 @@ type DBsConfig struct {
 	RuntimeCache  DBsCacheConfig
 	GenesisCache  DBsCacheConfig
 	MigrationMode string
+	// Scheme of the EVM state, see rawdb.HashScheme and rawdb.PathScheme
+	StateScheme string
 }
 
 type DBCacheConfig struct {
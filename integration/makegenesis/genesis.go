@@ -144,12 +144,12 @@ func (b *GenesisBuilder) ExecuteGenesisTxs(blockProc BlockProc, genesisTxs types
 	txListener := blockProc.TxListenerModule.Start(blockCtx, bs, es, b.tmpStateDB)
 	evmProcessor := blockProc.EVMModule.Start(blockCtx, b.tmpStateDB, b.tmpSfcStateDB, dummyHeaderReturner{}, func(l *types.Log) {
 		txListener.OnNewLog(l)
-	}, es.Rules, es.Rules.EvmChainConfig([]u2u.UpgradeHeight{
+	}, nil, es.Rules, es.Rules.EvmChainConfig([]u2u.UpgradeHeight{
 		{
 			Upgrades: es.Rules.Upgrades,
 			Height:   0,
 		},
-	}))
+	}), u2u.DefaultVMConfig)
 
 	// Execute genesis transactions
 	evmProcessor.Execute(genesisTxs)
@@ -3,6 +3,8 @@ package integration
 import (
 	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/unicornultrafoundation/go-helios/u2udb/multidb"
+
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
 )
 
 var DefaultDBsConfig = PblLegacyDBsConfig
@@ -128,6 +130,13 @@ func Pbl1GenesisDBsCacheConfig(scale func(uint64) uint64, fdlimit uint64) DBsCac
 	}
 }
 
+// Pbl1PathDBsConfig is the pbl-1 config, which stores the EVM state by the path scheme
+func Pbl1PathDBsConfig(scale func(uint64) uint64, fdlimit uint64) DBsConfig {
+	cfg := Pbl1DBsConfig(scale, fdlimit)
+	cfg.StateScheme = rawdb.PathScheme
+	return cfg
+}
+
 /*
  * legacy-pbl config
  */
@@ -140,6 +149,13 @@ func PblLegacyDBsConfig(scale func(uint64) uint64, fdlimit uint64) DBsConfig {
 	}
 }
 
+// PblLegacyPathDBsConfig is the legacy-pbl config, which stores the EVM state by the path scheme
+func PblLegacyPathDBsConfig(scale func(uint64) uint64, fdlimit uint64) DBsConfig {
+	cfg := PblLegacyDBsConfig(scale, fdlimit)
+	cfg.StateScheme = rawdb.PathScheme
+	return cfg
+}
+
 func PblLegacyRoutingConfig() RoutingConfig {
 	return RoutingConfig{
 		Table: map[string]multidb.Route{
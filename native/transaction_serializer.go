@@ -10,7 +10,10 @@ import (
 	"github.com/unicornultrafoundation/go-u2u/utils/cser"
 )
 
-var ErrUnknownTxType = errors.New("unknown tx type")
+var (
+	ErrUnknownTxType   = errors.New("unknown tx type")
+	ErrSetCodeTxCreate = errors.New("set code tx without recipient")
+)
 
 func encodeSig(r, s *big.Int) (sig [64]byte) {
 	copy(sig[0:], cser.PaddedBytes(r.Bytes(), 32)[:32])
@@ -25,7 +28,7 @@ func decodeSig(sig [64]byte) (r, s *big.Int) {
 }
 
 func TransactionMarshalCSER(w *cser.Writer, tx *types.Transaction) error {
-	if tx.Type() != types.LegacyTxType && tx.Type() != types.AccessListTxType && tx.Type() != types.DynamicFeeTxType {
+	if tx.Type() != types.LegacyTxType && tx.Type() != types.AccessListTxType && tx.Type() != types.DynamicFeeTxType && tx.Type() != types.SetCodeTxType {
 		return ErrUnknownTxType
 	}
 	if tx.Type() != types.LegacyTxType {
@@ -38,7 +41,7 @@ func TransactionMarshalCSER(w *cser.Writer, tx *types.Transaction) error {
 	}
 	w.U64(tx.Nonce())
 	w.U64(tx.Gas())
-	if tx.Type() == types.DynamicFeeTxType {
+	if tx.Type() == types.DynamicFeeTxType || tx.Type() == types.SetCodeTxType {
 		w.BigInt(tx.GasTipCap())
 		w.BigInt(tx.GasFeeCap())
 	} else {
@@ -54,7 +57,7 @@ func TransactionMarshalCSER(w *cser.Writer, tx *types.Transaction) error {
 	w.BigInt(v)
 	sig := encodeSig(r, s)
 	w.FixedBytes(sig[:])
-	if tx.Type() == types.AccessListTxType || tx.Type() == types.DynamicFeeTxType {
+	if tx.Type() != types.LegacyTxType {
 		w.BigInt(tx.ChainId())
 		w.U32(uint32(len(tx.AccessList())))
 		for _, tuple := range tx.AccessList() {
@@ -65,6 +68,17 @@ func TransactionMarshalCSER(w *cser.Writer, tx *types.Transaction) error {
 			}
 		}
 	}
+	if tx.Type() == types.SetCodeTxType {
+		w.U32(uint32(len(tx.SetCodeAuthorizations())))
+		for _, auth := range tx.SetCodeAuthorizations() {
+			w.BigInt(auth.ChainID)
+			w.FixedBytes(auth.Address.Bytes())
+			w.U64(auth.Nonce)
+			w.U8(auth.V)
+			sig := encodeSig(auth.R, auth.S)
+			w.FixedBytes(sig[:])
+		}
+	}
 	return nil
 }
 
@@ -80,7 +94,7 @@ func TransactionUnmarshalCSER(r *cser.Reader) (*types.Transaction, error) {
 	var gasPrice *big.Int
 	var gasTipCap *big.Int
 	var gasFeeCap *big.Int
-	if txType == types.DynamicFeeTxType {
+	if txType == types.DynamicFeeTxType || txType == types.SetCodeTxType {
 		gasTipCap = r.BigInt()
 		gasFeeCap = r.BigInt()
 	} else {
@@ -113,7 +127,7 @@ func TransactionUnmarshalCSER(r *cser.Reader) (*types.Transaction, error) {
 			R:        _r,
 			S:        s,
 		}), nil
-	} else if txType == types.AccessListTxType || txType == types.DynamicFeeTxType {
+	} else if txType == types.AccessListTxType || txType == types.DynamicFeeTxType || txType == types.SetCodeTxType {
 		chainID := r.BigInt()
 		accessListLen := r.U32()
 		if accessListLen > ProtocolMaxMsgSize/24 {
@@ -145,6 +159,39 @@ func TransactionUnmarshalCSER(r *cser.Reader) (*types.Transaction, error) {
 				R:          _r,
 				S:          s,
 			}), nil
+		} else if txType == types.SetCodeTxType {
+			if to == nil {
+				return nil, ErrSetCodeTxCreate
+			}
+			authListLen := r.U32()
+			if authListLen > ProtocolMaxMsgSize/85 {
+				return nil, cser.ErrTooLargeAlloc
+			}
+			authList := make([]types.SetCodeAuthorization, authListLen)
+			for i := range authList {
+				authList[i].ChainID = r.BigInt()
+				r.FixedBytes(authList[i].Address[:])
+				authList[i].Nonce = r.U64()
+				authList[i].V = r.U8()
+				var authSig [64]byte
+				r.FixedBytes(authSig[:])
+				authList[i].R, authList[i].S = decodeSig(authSig)
+			}
+			return types.NewTx(&types.SetCodeTx{
+				ChainID:    chainID,
+				Nonce:      nonce,
+				GasTipCap:  gasTipCap,
+				GasFeeCap:  gasFeeCap,
+				Gas:        gasLimit,
+				To:         *to,
+				Value:      amount,
+				Data:       data,
+				AccessList: accessList,
+				AuthList:   authList,
+				V:          v,
+				R:          _r,
+				S:          s,
+			}), nil
 		} else {
 			return types.NewTx(&types.DynamicFeeTx{
 				ChainID:    chainID,
@@ -144,3 +144,55 @@ func TestSerializeDynamicFeeTx(t *testing.T) {
 	require.NoError(err)
 	require.Equal("020784b5570502540be4000506fc23ac00081bc16d674ec80000811a752c8cd697e3cb27279c330ed1ada745a8d7106ebaf477f83e051589c1188bcc6ddccd012d36b241b061a36a32ab7fe86c7aa9eb592dd59018cd0443adc0903590c16b02b05edcc541b4741c5cc6dd347c5ed9577ef293a62787b4510465fadbfe39ee4094010502de0b295669a9fd93d5f28d9ec85e40f4cb697bae0200000000000000000000000000000000000000000000000000000000000000030000000000000000000000000000000000000000000000000000000000000007bb9bc244d798123fde783fcc1c72d3bb8c18941300009464120085", hex.EncodeToString(encoded))
 }
+
+func TestSerializeSetCodeTx(t *testing.T) {
+	require := require.New(t)
+
+	r := new(big.Int)
+	r.SetString("36b241b061a36a32ab7fe86c7aa9eb592dd59018cd0443adc0903590c16b02b0", 16)
+
+	s := new(big.Int)
+	s.SetString("5edcc541b4741c5cc6dd347c5ed9577ef293a62787b4510465fadbfe39ee4094", 16)
+
+	v := types.NewTx(&types.SetCodeTx{
+		ChainID:   big.NewInt(5),
+		Nonce:     7,
+		GasTipCap: big.NewInt(10_000_000_000),
+		GasFeeCap: big.NewInt(30_000_000_000),
+		Gas:       5_748_100,
+		To:        common.HexToAddress("811a752c8cd697e3cb27279c330ed1ada745a8d7"),
+		Value:     big.NewInt(0),
+		AccessList: []types.AccessTuple{
+			types.AccessTuple{
+				Address: common.HexToAddress("bb9bc244d798123fde783fcc1c72d3bb8c189413"),
+			},
+		},
+		AuthList: []types.SetCodeAuthorization{
+			types.SetCodeAuthorization{
+				ChainID: big.NewInt(5),
+				Address: common.HexToAddress("de0b295669a9fd93d5f28d9ec85e40f4cb697bae"),
+				Nonce:   3,
+				V:       1,
+				R:       s,
+				S:       r,
+			},
+		},
+		V: big.NewInt(1),
+		R: r,
+		S: s,
+	})
+
+	encoded, err := cser.MarshalBinaryAdapter(func(w *cser.Writer) error {
+		return TransactionMarshalCSER(w, v)
+	})
+	require.NoError(err)
+	require.Equal("040784b5570502540be4000506fc23ac00811a752c8cd697e3cb27279c330ed1ada745a8d7010136b241b061a36a32ab7fe86c7aa9eb592dd59018cd0443adc0903590c16b02b05edcc541b4741c5cc6dd347c5ed9577ef293a62787b4510465fadbfe39ee4094010501bb9bc244d798123fde783fcc1c72d3bb8c18941300010105de0b295669a9fd93d5f28d9ec85e40f4cb697bae03015edcc541b4741c5cc6dd347c5ed9577ef293a62787b4510465fadbfe39ee409436b241b061a36a32ab7fe86c7aa9eb592dd59018cd0443adc0903590c16b02b000942012200086", hex.EncodeToString(encoded))
+
+	var decoded *types.Transaction
+	err = cser.UnmarshalBinaryAdapter(encoded, func(r *cser.Reader) (err error) {
+		decoded, err = TransactionUnmarshalCSER(r)
+		return err
+	})
+	require.NoError(err)
+	require.Equal(v.Hash(), decoded.Hash())
+}
@@ -135,6 +135,68 @@ func (tt *index) Push(recs ...*types.Log) error {
 	return nil
 }
 
+// DeleteInBlocks removes log records of block range along with their index.
+func (tt *index) DeleteInBlocks(from, to idx.Block) error {
+	if to < from {
+		return nil
+	}
+
+	var (
+		ids    []ID
+		topics [][]common.Hash
+	)
+	it := tt.table.Logrec.NewIterator(nil, uintToBytes(uint64(from)))
+	for it.Next() {
+		var id ID
+		copy(id[:], it.Key())
+		if id.BlockNumber() > uint64(to) {
+			break
+		}
+		ids = append(ids, id)
+		topics = append(topics, tt.indexedTopics(id, it.Value()))
+	}
+	err := it.Error()
+	it.Release()
+	if err != nil {
+		return err
+	}
+
+	for i, id := range ids {
+		for pos, topic := range topics[i] {
+			if err := tt.table.Topic.Delete(topicKey(topic, uint8(pos), id)); err != nil {
+				return err
+			}
+		}
+		if err := tt.table.Logrec.Delete(id.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexedTopics returns the address and the topics of the log record, which are indexed.
+// The record doesn't keep the topics count, so it's matched against the address index.
+func (tt *index) indexedTopics(id ID, buf []byte) []common.Hash {
+	for count := 0; count <= maxTopicsCount; count++ {
+		offset := count*common.HashLength + common.HashLength
+		if len(buf) < offset+common.AddressLength {
+			break
+		}
+		address := common.BytesToAddress(buf[offset : offset+common.AddressLength])
+		val, err := tt.table.Topic.Get(topicKey(address.Hash(), 0, id))
+		if err != nil || len(val) == 0 || bytesToPos(val) != uint8(count) {
+			continue
+		}
+		topics := make([]common.Hash, 0, count+1)
+		topics = append(topics, address.Hash())
+		for i := 0; i < count; i++ {
+			topics = append(topics, common.BytesToHash(buf[i*common.HashLength:(i+1)*common.HashLength]))
+		}
+		return topics
+	}
+	return nil
+}
+
 func (tt *index) Close() {
 	_ = tt.table.Topic.Close()
 	_ = tt.table.Logrec.Close()
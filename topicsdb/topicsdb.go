@@ -22,6 +22,7 @@ type Index interface {
 	FindInBlocks(ctx context.Context, from, to idx.Block, pattern [][]common.Hash) (logs []*types.Log, err error)
 	ForEachInBlocks(ctx context.Context, from, to idx.Block, pattern [][]common.Hash, onLog func(*types.Log) (gonext bool)) error
 	Push(recs ...*types.Log) error
+	DeleteInBlocks(from, to idx.Block) error
 	Close()
 
 	WrapTablesAsBatched() (unwrap func())
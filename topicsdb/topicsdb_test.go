@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/unicornultrafoundation/go-helios/hash"
 	"github.com/unicornultrafoundation/go-helios/native/idx"
+	"github.com/unicornultrafoundation/go-helios/u2udb"
 	"github.com/unicornultrafoundation/go-helios/u2udb/memorydb"
 	"github.com/unicornultrafoundation/go-u2u/common"
 	"github.com/unicornultrafoundation/go-u2u/core/types"
@@ -364,6 +365,45 @@ func TestIndexSearchSimple(t *testing.T) {
 
 }
 
+func TestIndexDeleteInBlocks(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	topics, recs, _ := genTestData(100)
+	index := newTestIndex()
+	for _, rec := range recs {
+		require.NoError(index.Push(rec))
+	}
+
+	require.NoError(index.DeleteInBlocks(0, 9))
+
+	for _, topic := range topics {
+		got, err := index.FindInBlocks(nil, 0, 0xffffffff, [][]common.Hash{{}, {topic}})
+		require.NoError(err)
+		for _, rec := range got {
+			require.Greater(rec.BlockNumber, uint64(9))
+		}
+	}
+	got, err := index.FindInBlocks(nil, 0, 0xffffffff, [][]common.Hash{{}, topics})
+	require.NoError(err)
+	require.Equal(50, len(got))
+
+	// no index records of the deleted log records are left
+	for _, table := range []u2udb.Store{index.table.Topic, index.table.Logrec} {
+		it := table.NewIterator(nil, nil)
+		for it.Next() {
+			var id ID
+			if len(it.Key()) == logrecKeySize {
+				copy(id[:], it.Key())
+			} else {
+				id = extractLogrecID(it.Key())
+			}
+			require.Greater(id.BlockNumber(), uint64(9))
+		}
+		it.Release()
+	}
+}
+
 func TestMaxTopicsCount(t *testing.T) {
 	logger.SetTestMode(t)
 
@@ -17,6 +17,7 @@
 package trie
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -28,6 +29,7 @@ import (
 	"github.com/VictoriaMetrics/fastcache"
 	"github.com/unicornultrafoundation/go-u2u/common"
 	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/crypto"
 	"github.com/unicornultrafoundation/go-u2u/ethdb"
 	"github.com/unicornultrafoundation/go-u2u/log"
 	"github.com/unicornultrafoundation/go-u2u/metrics"
@@ -68,6 +70,10 @@ var (
 // servers even while the trie is executing expensive garbage collection.
 type Database struct {
 	diskdb ethdb.KeyValueStore // Persistent storage for matured trie nodes
+	scheme string              // Scheme of the persisted trie nodes, see rawdb.HashScheme and rawdb.PathScheme
+
+	staleCommits int                      // Number of the recent path scheme commits, which overwritten nodes are kept
+	stale        []map[common.Hash][]byte // Nodes overwritten by the recent path scheme commits, the oldest commit first
 
 	greedyGC bool                        // run gc greedy or not
 	cleans   *fastcache.Cache            // GC friendly memory cache of clean node RLPs
@@ -281,6 +287,10 @@ type Config struct {
 	Journal   string // Journal of clean cache to survive node restarts
 	Preimages bool   // Flag whether the preimage of trie key is recorded
 	GreedyGC  bool   // "light" or "greedy" GC
+	Scheme    string // Scheme of the persisted trie nodes, the hash scheme if empty
+	// Number of the recent commits of the path scheme, which overwritten nodes are kept in memory,
+	// so the tries of the recent commits may be still read
+	StaleCommits int
 }
 
 // NewDatabase creates a new trie database to store ephemeral trie content before
@@ -304,6 +314,7 @@ func NewDatabaseWithConfig(diskdb ethdb.KeyValueStore, config *Config) *Database
 	}
 	db := &Database{
 		diskdb: diskdb,
+		scheme: rawdb.HashScheme,
 		cleans: cleans,
 		dirties: map[common.Hash]*cachedNode{{}: {
 			children: make(map[common.Hash]uint16),
@@ -311,6 +322,14 @@ func NewDatabaseWithConfig(diskdb ethdb.KeyValueStore, config *Config) *Database
 	}
 	if config != nil {
 		db.greedyGC = config.GreedyGC
+		if config.Scheme != "" {
+			db.scheme = config.Scheme
+		}
+		db.staleCommits = config.StaleCommits
+	}
+	if db.scheme == rawdb.PathScheme {
+		// committed nodes are never deleted by the path scheme
+		db.greedyGC = false
 	}
 	if config == nil || config.Preimages { // TODO(karalabe): Flip to default off in the future
 		db.preimages = make(map[common.Hash][]byte)
@@ -323,6 +342,31 @@ func (db *Database) DiskDB() ethdb.KeyValueStore {
 	return db.diskdb
 }
 
+// Scheme returns the scheme of the persisted trie nodes.
+func (db *Database) Scheme() string {
+	return db.scheme
+}
+
+// Readable reports whether the trie of the given root can be read. The hash scheme
+// keeps the old tries until they're pruned, while the path scheme can read only the
+// persisted trie, the tries of the recent commits and the tries, which aren't committed yet.
+func (db *Database) Readable(root common.Hash) bool {
+	if root == (common.Hash{}) || root == emptyRoot {
+		return true
+	}
+	db.lock.RLock()
+	_, dirty := db.dirties[root]
+	db.lock.RUnlock()
+	if dirty {
+		return true
+	}
+	if db.scheme != rawdb.PathScheme {
+		_, err := db.Node(root)
+		return err == nil
+	}
+	return db.diskNode(common.Hash{}, nil, root) != nil
+}
+
 // insert inserts a collapsed trie node into the memory database.
 // The blob size must be specified to allow proper size tracking.
 // All nodes inserted by this function will be reference tracked
@@ -375,8 +419,9 @@ func (db *Database) insertPreimage(hash common.Hash, preimage []byte) {
 }
 
 // node retrieves a cached trie node from memory, or returns nil if none can be
-// found in the memory cache.
-func (db *Database) node(hash common.Hash) node {
+// found in the memory cache. The owner and the path locate the node of the path
+// scheme on disk, see NewWithOwner.
+func (db *Database) node(owner common.Hash, path []byte, hash common.Hash) node {
 	// Retrieve the node from the clean cache if available
 	if db.cleans != nil {
 		if enc := db.cleans.Get(nil, hash[:]); enc != nil {
@@ -398,8 +443,8 @@ func (db *Database) node(hash common.Hash) node {
 	memcacheDirtyMissMeter.Mark(1)
 
 	// Content unavailable in memory, attempt to retrieve from disk
-	enc, err := db.diskdb.Get(hash[:])
-	if err != nil || enc == nil {
+	enc := db.diskNode(owner, path, hash)
+	if enc == nil {
 		return nil
 	}
 	if db.cleans != nil {
@@ -410,9 +455,38 @@ func (db *Database) node(hash common.Hash) node {
 	return mustDecodeNode(hash[:], enc)
 }
 
+// diskNode retrieves the encoded trie node from the persistent database, or returns
+// nil if it cannot be found. The path scheme keeps only the latest node at a path,
+// so the node is found only if it still belongs to the latest persisted trie, or if
+// it's overwritten by one of the recent commits.
+func (db *Database) diskNode(owner common.Hash, path []byte, hash common.Hash) []byte {
+	if db.scheme != rawdb.PathScheme {
+		return rawdb.ReadTrieNode(db.diskdb, hash)
+	}
+	enc := rawdb.ReadTrieNodeByPath(db.diskdb, owner, path)
+	if len(enc) != 0 && crypto.Keccak256Hash(enc) == hash {
+		return enc
+	}
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	for i := len(db.stale) - 1; i >= 0; i-- {
+		if enc, ok := db.stale[i][hash]; ok {
+			return enc
+		}
+	}
+	return nil
+}
+
 // Node retrieves an encoded cached trie node from memory. If it cannot be found
-// cached, the method queries the persistent database for the content.
+// cached, the method queries the persistent database for the content. The nodes of
+// the path scheme can be found on disk only by their paths, see NodeByPath.
 func (db *Database) Node(hash common.Hash) ([]byte, error) {
+	return db.NodeByPath(common.Hash{}, nil, hash)
+}
+
+// NodeByPath retrieves an encoded trie node by its hash and its path in the trie of
+// the given owner, which is the zero hash for the account trie.
+func (db *Database) NodeByPath(owner common.Hash, path []byte, hash common.Hash) ([]byte, error) {
 	// It doesn't make sense to retrieve the metaroot
 	if hash == (common.Hash{}) {
 		return nil, errors.New("not found")
@@ -438,7 +512,7 @@ func (db *Database) Node(hash common.Hash) ([]byte, error) {
 	memcacheDirtyMissMeter.Mark(1)
 
 	// Content unavailable in memory, attempt to retrieve from disk
-	enc := rawdb.ReadTrieNode(db.diskdb, hash)
+	enc := db.diskNode(owner, path, hash)
 	if len(enc) != 0 {
 		if db.cleans != nil {
 			db.cleans.Set(hash[:], enc)
@@ -468,6 +542,16 @@ func (db *Database) preimage(hash common.Hash) []byte {
 	return rawdb.ReadPreimage(db.diskdb, hash)
 }
 
+// Preimage retrieves a pre-image of a hashed key from memory or from the persistent
+// database. Unlike preimage, the persistent database is queried even if the
+// pre-image collection is disabled.
+func (db *Database) Preimage(hash common.Hash) []byte {
+	if preimage := db.preimage(hash); preimage != nil {
+		return preimage
+	}
+	return rawdb.ReadPreimage(db.diskdb, hash)
+}
+
 // Nodes retrieves the hashes of all the nodes cached within the memory database.
 // This method is extremely expensive and should only be used to validate internal
 // states in test code.
@@ -644,9 +728,10 @@ func (db *Database) Cap(limit common.StorageSize) error {
 			}
 		}
 	}
-	// Keep committing nodes from the flush-list until we're below allowance
+	// Keep committing nodes from the flush-list until we're below allowance. The paths
+	// of the nodes are unknown here, so the path scheme persists them only on Commit.
 	oldest := db.oldest
-	for size > limit && oldest != (common.Hash{}) {
+	for size > limit && oldest != (common.Hash{}) && db.scheme != rawdb.PathScheme {
 		// Fetch the oldest referenced node and push into the batch
 		node := db.dirties[oldest]
 		if !node.commited {
@@ -740,6 +825,15 @@ func (db *Database) Commit(node common.Hash, report bool, callback func(common.H
 	// Move the trie itself into the batch, flushing if enough data is accumulated
 	nodes, storage := len(db.dirties), db.dirtiesSize
 
+	if db.scheme == rawdb.PathScheme {
+		if err := db.commitPaths(node, batch, callback); err != nil {
+			log.Error("Failed to commit trie from trie database", "err", err)
+			return err
+		}
+		db.lock.Lock()
+		defer db.lock.Unlock()
+		return db.commitStats(nodes, storage, start, report)
+	}
 	var uncacher ethdb.KeyValueWriter
 	if db.greedyGC {
 		uncacher = &greedy{db}
@@ -763,6 +857,11 @@ func (db *Database) Commit(node common.Hash, report bool, callback func(common.H
 	}
 	batch.Reset()
 
+	return db.commitStats(nodes, storage, start, report)
+}
+
+// commitStats resets the storage counters and bumps the metrics after a commit.
+func (db *Database) commitStats(nodes int, storage common.StorageSize, start time.Time, report bool) error {
 	// Reset the storage counters and bumped metrics
 	if db.preimages != nil {
 		db.preimages, db.preimagesSize = make(map[common.Hash][]byte), 0
@@ -818,6 +917,117 @@ func (db *Database) commit(hash common.Hash, batch ethdb.Batch, uncacher ethdb.K
 	return nil
 }
 
+// commitPaths is the path scheme version of commit. The trie is walked from the root,
+// so the paths of the nodes are known, and the storage tries are reached through the
+// external children of the account leaves. The committed nodes stay in the dirty
+// cache until the whole trie is written, because a node may be found at several paths.
+// The overwritten nodes are kept for the readers of the tries of the recent commits.
+func (db *Database) commitPaths(root common.Hash, batch ethdb.Batch, callback func(common.Hash)) error {
+	committed := make(map[common.Hash][]byte)
+	if db.staleCommits > 0 {
+		db.lock.Lock()
+		db.stale = append(db.stale, make(map[common.Hash][]byte))
+		if len(db.stale) > db.staleCommits {
+			db.stale[0] = nil
+			db.stale = db.stale[1:]
+		}
+		db.lock.Unlock()
+	}
+	if err := db.commitPath(common.Hash{}, nil, root, batch, committed, callback); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		log.Error("Failed to write trie to disk", "err", err)
+		return err
+	}
+	batch.Reset()
+
+	// The trie is on disk, move the committed nodes into the clean cache
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	for hash, enc := range committed {
+		if node, ok := db.dirties[hash]; ok {
+			evictDirty(db, hash, node)
+		}
+		if db.cleans != nil {
+			db.cleans.Set(hash[:], enc)
+			memcacheCleanWriteMeter.Mark(int64(len(enc)))
+		}
+	}
+	return nil
+}
+
+// commitPath writes the dirty node at the path of the owner's trie after its children,
+// so the parents on disk never refer to missing nodes. The nodes missing from the dirty
+// cache are already on disk.
+func (db *Database) commitPath(owner common.Hash, path []byte, hash common.Hash, batch ethdb.Batch, committed map[common.Hash][]byte, callback func(common.Hash)) error {
+	node, ok := db.dirties[hash]
+	if !ok {
+		return nil
+	}
+	var err error
+	obj := node.obj(hash)
+	forPathChildren(obj, path, func(childPath []byte, child common.Hash) {
+		if err == nil {
+			err = db.commitPath(owner, childPath, child, batch, committed, callback)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	// The external children of an account leaf are the roots of its storage trie
+	if leaf, ok := obj.(*shortNode); ok && owner == (common.Hash{}) && len(node.children) != 0 {
+		if _, ok := leaf.Val.(valueNode); ok {
+			account := common.BytesToHash(hexToKeybytes(concat(path, leaf.Key...)))
+			for child := range node.children {
+				if err := db.commitPath(account, nil, child, batch, committed, callback); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	enc := node.rlp()
+	if db.staleCommits > 0 {
+		// the overwritten node is kept before the batch is written
+		if prev := rawdb.ReadTrieNodeByPath(db.diskdb, owner, path); len(prev) != 0 && !bytes.Equal(prev, enc) {
+			db.lock.Lock()
+			db.stale[len(db.stale)-1][crypto.Keccak256Hash(prev)] = prev
+			db.lock.Unlock()
+		}
+	}
+	rawdb.WriteTrieNodeByPath(batch, owner, path, enc)
+	committed[hash] = enc
+	if callback != nil {
+		callback(hash)
+	}
+	// If we've reached an optimal batch size, commit and start over
+	if batch.ValueSize() >= ethdb.IdealBatchSize {
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+	}
+	return nil
+}
+
+// forPathChildren traverses the node hierarchy of an expanded node and invokes the
+// callback for all the hashnode children with their paths.
+func forPathChildren(n node, path []byte, onChild func(path []byte, hash common.Hash)) {
+	switch n := n.(type) {
+	case *shortNode:
+		forPathChildren(n.Val, concat(path, n.Key...), onChild)
+	case *fullNode:
+		for i := 0; i < 16; i++ {
+			forPathChildren(n.Children[i], concat(path, byte(i)), onChild)
+		}
+	case hashNode:
+		onChild(path, common.BytesToHash(n))
+	case valueNode, nil:
+	default:
+		panic(fmt.Sprintf("unknown node type: %T", n))
+	}
+}
+
 // cleaner is a database batch replayer that takes a batch of write operations
 // and cleans up the trie database from anything written to disk.
 type cleaner struct {
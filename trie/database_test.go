@@ -17,9 +17,12 @@
 package trie
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/crypto"
 	"github.com/unicornultrafoundation/go-u2u/ethdb/memorydb"
 )
 
@@ -31,3 +34,126 @@ func TestDatabaseMetarootFetch(t *testing.T) {
 		t.Fatalf("metaroot retrieval succeeded")
 	}
 }
+
+// Tests that the path scheme stores the nodes by their paths and keeps only the
+// latest version of the trie.
+func TestDatabasePathScheme(t *testing.T) {
+	diskdb := memorydb.New()
+	config := &Config{Scheme: rawdb.PathScheme}
+
+	commit := func(root common.Hash, values map[string]string) common.Hash {
+		db := NewDatabaseWithConfig(diskdb, config)
+		tr, err := New(root, db)
+		if err != nil {
+			t.Fatalf("failed to open trie %x: %v", root, err)
+		}
+		for k, v := range values {
+			if v == "" {
+				tr.Delete(crypto.Keccak256([]byte(k)))
+			} else {
+				tr.Update(crypto.Keccak256([]byte(k)), []byte(v))
+			}
+		}
+		root, err = tr.Commit(nil)
+		if err != nil {
+			t.Fatalf("failed to commit trie: %v", err)
+		}
+		if err := db.Commit(root, false, nil); err != nil {
+			t.Fatalf("failed to commit trie database: %v", err)
+		}
+		return root
+	}
+	check := func(root common.Hash, values map[string]string) {
+		tr, err := New(root, NewDatabaseWithConfig(diskdb, config))
+		if err != nil {
+			t.Fatalf("failed to open trie %x: %v", root, err)
+		}
+		for k, v := range values {
+			if have := tr.Get(crypto.Keccak256([]byte(k))); !bytes.Equal(have, []byte(v)) {
+				t.Errorf("value mismatch of %s: have %x, want %x", k, have, v)
+			}
+		}
+	}
+	values := map[string]string{
+		"do":    "verb",
+		"dog":   "puppy",
+		"horse": "stallion",
+		"doge":  "coin",
+	}
+	for i := 0; i < 100; i++ {
+		values[string(rune('a'+i%26))+string(rune('A'+i/26))] = string(bytes.Repeat([]byte{byte(i)}, 40))
+	}
+	root := commit(common.Hash{}, values)
+	check(root, values)
+
+	// only the path keys are written
+	it := diskdb.NewIterator(nil, nil)
+	for it.Next() {
+		if !bytes.HasPrefix(it.Key(), rawdb.TrieNodeAccountPrefix) {
+			t.Errorf("unexpected key %x", it.Key())
+		}
+	}
+	it.Release()
+
+	// the updated trie replaces the old one
+	updated := map[string]string{"dog": "wolf", "doge": ""}
+	newRoot := commit(root, updated)
+	for k, v := range updated {
+		values[k] = v
+	}
+	check(newRoot, values)
+	if db := NewDatabaseWithConfig(diskdb, config); !db.Readable(newRoot) || db.Readable(root) {
+		t.Errorf("only the latest trie %x must be readable", newRoot)
+	}
+	if _, err := New(root, NewDatabaseWithConfig(diskdb, config)); err == nil {
+		t.Errorf("the overwritten trie %x is still readable", root)
+	} else if _, ok := err.(*MissingNodeError); !ok {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+// Tests that the path scheme keeps the tries of different owners apart.
+func TestDatabasePathSchemeOwners(t *testing.T) {
+	diskdb := memorydb.New()
+	db := NewDatabaseWithConfig(diskdb, &Config{Scheme: rawdb.PathScheme})
+
+	key := crypto.Keccak256([]byte("key"))
+	tr1, _ := NewWithOwner(common.Hash{1}, common.Hash{}, db)
+	tr1.Update(key, bytes.Repeat([]byte{1}, 40))
+	tr2, _ := NewWithOwner(common.Hash{2}, common.Hash{}, db)
+	tr2.Update(key, bytes.Repeat([]byte{2}, 40))
+	root1, _ := tr1.Commit(nil)
+	root2, _ := tr2.Commit(nil)
+
+	// the owners are found through the account leaves referencing the tries
+	accounts, _ := New(common.Hash{}, db)
+	accounts.Update(common.Hash{1}.Bytes(), bytes.Repeat([]byte{0xa}, 40))
+	accounts.Update(common.Hash{2}.Bytes(), bytes.Repeat([]byte{0xb}, 40))
+	root, _ := accounts.Commit(func(_ [][]byte, _ []byte, leaf []byte, parent common.Hash) error {
+		if leaf[0] == 0xa {
+			db.Reference(root1, parent)
+		} else {
+			db.Reference(root2, parent)
+		}
+		return nil
+	})
+	if err := db.Commit(root, false, nil); err != nil {
+		t.Fatalf("failed to commit trie database: %v", err)
+	}
+	if rawdb.ReadStorageTrieNode(diskdb, common.Hash{1}, nil) == nil || rawdb.ReadStorageTrieNode(diskdb, common.Hash{2}, nil) == nil {
+		t.Fatalf("storage tries are not written")
+	}
+	for owner, root := range map[common.Hash]common.Hash{{1}: root1, {2}: root2} {
+		tr, err := NewWithOwner(owner, root, NewDatabaseWithConfig(diskdb, &Config{Scheme: rawdb.PathScheme}))
+		if err != nil {
+			t.Fatalf("failed to open trie of %x: %v", owner, err)
+		}
+		if have := tr.Get(key); !bytes.Equal(have, bytes.Repeat(owner[:1], 40)) {
+			t.Errorf("value mismatch of %x: have %x", owner, have)
+		}
+	}
+	// a trie can't be read with another owner
+	if _, err := NewWithOwner(common.Hash{2}, root1, NewDatabaseWithConfig(diskdb, &Config{Scheme: rawdb.PathScheme})); err == nil {
+		t.Errorf("the trie is readable with a wrong owner")
+	}
+}
@@ -1,14 +1,201 @@
 package constant_manager
 
 import (
+	"math/big"
+	"strings"
+
+	"github.com/unicornultrafoundation/go-u2u/accounts/abi"
 	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
 	"github.com/unicornultrafoundation/go-u2u/core/vm"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/internal/precompiled"
+)
+
+// Storage layout of the ConstantsManager contract.
+// Initializable and Ownable are followed by the reserved gaps of 50 slots.
+const (
+	initializableSlot = 0
+	ownerSlot         = 51
+)
+
+const day = 24 * 60 * 60
+
+var decimalUnit = big.NewInt(1e18)
+
+// variable is a network constant, stored in its own slot. Its setter only
+// accepts the values in [min, max], a nil bound isn't checked.
+type variable struct {
+	name     string
+	slot     int64
+	min, max *big.Int
+}
+
+var variables = []variable{
+	{"minSelfStake", 102, units(100000), units(10000000)},
+	{"maxDelegatedRatio", 103, units(1), units(31)},
+	{"validatorCommission", 104, nil, ratio(1, 2)},
+	{"burntFeeShare", 105, nil, ratio(1, 2)},
+	{"treasuryFeeShare", 106, nil, ratio(1, 2)},
+	{"unlockedRewardRatio", 107, ratio(5, 100), ratio(1, 2)},
+	{"minLockupDuration", 108, big.NewInt(day), big.NewInt(30 * day)},
+	{"maxLockupDuration", 109, big.NewInt(30 * day), big.NewInt(1460 * day)},
+	{"withdrawalPeriodEpochs", 110, big.NewInt(2), big.NewInt(100)},
+	{"withdrawalPeriodTime", 111, big.NewInt(day), big.NewInt(30 * day)},
+	{"baseRewardPerSecond", 112, ratio(1, 2), units(32)},
+	{"offlinePenaltyThresholdBlocksNum", 113, big.NewInt(100), big.NewInt(1000000)},
+	{"offlinePenaltyThresholdTime", 114, big.NewInt(day), big.NewInt(10 * day)},
+	{"targetGasPowerPerSecond", 115, big.NewInt(1000000), big.NewInt(500000000)},
+	{"gasPriceBalancingCounterweight", 116, big.NewInt(100), big.NewInt(10 * day)},
+}
+
+func units(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), decimalUnit)
+}
+
+func ratio(num, denom int64) *big.Int {
+	return new(big.Int).Div(new(big.Int).Mul(decimalUnit, big.NewInt(num)), big.NewInt(denom))
+}
+
+var (
+	errTooSmall = precompiled.RevertError("too small value")
+	errTooLarge = precompiled.RevertError("too large value")
+
+	constsAbi abi.ABI
+	consts    *precompiled.Contract[*context]
 )
 
-// ConstantManagerPrecompile implements PrecompiledContract interface
+// context is the environment of a single ConstantsManager call
+type context struct {
+	stateDB  vm.StateDB
+	blockCtx vm.BlockContext
+	caller   common.Address
+}
+
+var handlers = map[string]precompiled.Handler[*context]{
+	"initialize":        handleInitialize,
+	"owner":             handleOwner,
+	"isOwner":           handleIsOwner,
+	"transferOwnership": handleTransferOwnership,
+	"renounceOwnership": handleRenounceOwnership,
+}
+
+func init() {
+	var err error
+	constsAbi, err = abi.JSON(strings.NewReader(ContractABI))
+	if err != nil {
+		panic(err)
+	}
+	for _, v := range variables {
+		handlers[v.name] = getter(v)
+		handlers["update"+strings.ToUpper(v.name[:1])+v.name[1:]] = setter(v)
+	}
+	consts = &precompiled.Contract[*context]{
+		Name:     "ConstantsManager",
+		Abi:      constsAbi,
+		Handlers: handlers,
+	}
+	consts.Validate()
+}
+
+// ConstantManagerPrecompile implements PrecompiledStateContract interface
 type ConstantManagerPrecompile struct{}
 
-// Run runs the precompiled contract
-func (c *ConstantManagerPrecompile) Run(stateDB vm.StateDB, blockCtx vm.BlockContext, txCtx vm.TxContext, caller common.Address, input []byte, suppliedGas uint64) ([]byte, uint64, error) {
-	return nil, 0, nil
+// Run runs the precompiled contract. It mirrors the storage writes and events of
+// the ConstantsManager bytecode, the getters return the same ABI-encoded values.
+// The supplied gas is returned untouched.
+func (p *ConstantManagerPrecompile) Run(stateDB vm.StateDB, blockCtx vm.BlockContext, txCtx vm.TxContext, caller common.Address, input []byte, suppliedGas uint64, value *big.Int) ([]byte, uint64, error) {
+	c := &context{
+		stateDB:  stateDB,
+		blockCtx: blockCtx,
+		caller:   caller,
+	}
+	return consts.Run(c, input, suppliedGas, value)
+}
+
+func slotOf(slot int64) common.Hash {
+	return common.BigToHash(big.NewInt(slot))
+}
+
+func (c *context) owner() common.Address {
+	return common.BytesToAddress(c.stateDB.GetState(ContractAddress, slotOf(ownerSlot)).Bytes())
+}
+
+func (c *context) onlyOwner() error {
+	if c.caller != c.owner() {
+		return precompiled.ErrNotOwner
+	}
+	return nil
+}
+
+func (c *context) transferOwnership(newOwner common.Address) {
+	event := constsAbi.Events["OwnershipTransferred"]
+	c.stateDB.AddLog(&types.Log{
+		Address:     ContractAddress,
+		Topics:      []common.Hash{event.ID, common.BytesToHash(c.owner().Bytes()), common.BytesToHash(newOwner.Bytes())},
+		BlockNumber: c.blockCtx.BlockNumber.Uint64(),
+	})
+	c.stateDB.SetState(ContractAddress, slotOf(ownerSlot), common.BytesToHash(newOwner.Bytes()))
+}
+
+func handleInitialize(c *context, args []interface{}) ([]byte, error) {
+	if precompiled.Initialized(c.stateDB, ContractAddress, slotOf(initializableSlot)) {
+		return nil, precompiled.ErrAlreadyInitialized
+	}
+	c.stateDB.SetState(ContractAddress, slotOf(initializableSlot), common.BigToHash(new(big.Int).Or(precompiled.InitializedFlag, precompiled.InitializingFlag)))
+	c.transferOwnership(c.caller)
+	c.stateDB.SetState(ContractAddress, slotOf(initializableSlot), common.BigToHash(precompiled.InitializedFlag))
+	return nil, nil
+}
+
+func handleOwner(c *context, args []interface{}) ([]byte, error) {
+	return constsAbi.Methods["owner"].Outputs.Pack(c.owner())
+}
+
+func handleIsOwner(c *context, args []interface{}) ([]byte, error) {
+	return constsAbi.Methods["isOwner"].Outputs.Pack(c.caller == c.owner())
+}
+
+func handleTransferOwnership(c *context, args []interface{}) ([]byte, error) {
+	if err := c.onlyOwner(); err != nil {
+		return nil, err
+	}
+	newOwner := args[0].(common.Address)
+	if newOwner == (common.Address{}) {
+		return nil, precompiled.ErrZeroOwner
+	}
+	c.transferOwnership(newOwner)
+	return nil, nil
+}
+
+func handleRenounceOwnership(c *context, args []interface{}) ([]byte, error) {
+	if err := c.onlyOwner(); err != nil {
+		return nil, err
+	}
+	c.transferOwnership(common.Address{})
+	return nil, nil
+}
+
+// getter returns the handler of the getter of a network constant
+func getter(v variable) precompiled.Handler[*context] {
+	return func(c *context, args []interface{}) ([]byte, error) {
+		return constsAbi.Methods[v.name].Outputs.Pack(c.stateDB.GetState(ContractAddress, slotOf(v.slot)).Big())
+	}
+}
+
+// setter returns the handler of the owner-only setter of a network constant
+func setter(v variable) precompiled.Handler[*context] {
+	return func(c *context, args []interface{}) ([]byte, error) {
+		if err := c.onlyOwner(); err != nil {
+			return nil, err
+		}
+		value := args[0].(*big.Int)
+		if v.min != nil && value.Cmp(v.min) < 0 {
+			return nil, errTooSmall
+		}
+		if v.max != nil && value.Cmp(v.max) > 0 {
+			return nil, errTooLarge
+		}
+		c.stateDB.SetState(ContractAddress, slotOf(v.slot), common.BigToHash(value))
+		return nil, nil
+	}
 }
@@ -0,0 +1,152 @@
+package constant_manager_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/unicornultrafoundation/go-u2u/accounts/abi"
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/core/state"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+	"github.com/unicornultrafoundation/go-u2u/evmcore"
+	"github.com/unicornultrafoundation/go-u2u/integration/makefakegenesis"
+	"github.com/unicornultrafoundation/go-u2u/u2u"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/constant_manager"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/driver"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/driver/drivercall"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/driverauth"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/evmwriter"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/netinit"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/sfc"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/sfclib"
+	"github.com/unicornultrafoundation/go-u2u/utils"
+)
+
+// constsEnv executes calls against the ConstantsManager bytecode deployed by the
+// NetworkInitializer, while the native precompile shadows them in a separate SFC state.
+type constsEnv struct {
+	t        *testing.T
+	state    *state.StateDB
+	sfcState *state.StateDB
+	abi      abi.ABI
+}
+
+func newConstsEnv(t *testing.T) (*constsEnv, common.Address) {
+	stateDB, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err)
+	sfcStateDB, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err)
+	constsAbi, err := abi.JSON(strings.NewReader(constant_manager.ContractABI))
+	require.NoError(t, err)
+	env := &constsEnv{
+		t:        t,
+		state:    stateDB,
+		sfcState: sfcStateDB,
+		abi:      constsAbi,
+	}
+
+	stateDB.SetCode(netinit.ContractAddress, netinit.GetContractBin())
+	stateDB.SetCode(driver.ContractAddress, driver.GetContractBin())
+	stateDB.SetCode(driverauth.ContractAddress, driverauth.GetContractBin())
+	stateDB.SetCode(sfc.ContractAddress, sfc.GetContractBin())
+	stateDB.SetCode(sfclib.ContractAddress, sfclib.GetContractBin())
+	stateDB.SetCode(evmwriter.ContractAddress, []byte{0})
+	validators := makefakegenesis.GetFakeValidators(1)
+	delegations := []drivercall.Delegation{{
+		Address:            validators[0].Address,
+		ValidatorID:        validators[0].ID,
+		Stake:              utils.ToU2U(5e6),
+		LockedStake:        new(big.Int),
+		EarlyUnlockPenalty: new(big.Int),
+		Rewards:            new(big.Int),
+	}}
+	owner := validators[0].Address
+	for _, tx := range makefakegenesis.GetGenesisTxs(0, validators, utils.ToU2U(1e9), delegations, owner) {
+		_, err := env.call(common.Address{}, *tx.To(), tx.Data())
+		require.NoError(t, err)
+	}
+	return env, owner
+}
+
+func (env *constsEnv) call(from common.Address, to common.Address, input []byte) ([]byte, error) {
+	blockCtx := vm.BlockContext{
+		CanTransfer: evmcore.CanTransfer,
+		Transfer:    evmcore.Transfer,
+		GetHash:     func(uint64) common.Hash { return common.Hash{} },
+		BlockNumber: big.NewInt(1),
+		Time:        big.NewInt(1),
+		Difficulty:  big.NewInt(1),
+		BaseFee:     big.NewInt(0),
+		GasLimit:    1e12,
+	}
+	txCtx := vm.TxContext{Origin: from, GasPrice: big.NewInt(0)}
+	evm := vm.NewEVM(blockCtx, txCtx, env.state, env.sfcState, u2u.FakeNetRules().EvmChainConfig(nil), u2u.DefaultVMConfig)
+	ret, _, err := evm.Call(vm.AccountRef(from), to, input, 1e10, new(big.Int))
+	env.state.IntermediateRoot(true)
+	env.sfcState.IntermediateRoot(true)
+	require.Equal(env.t, env.state.GetStorageRoot(constant_manager.ContractAddress), env.sfcState.GetStorageRoot(constant_manager.ContractAddress),
+		"ConstantsManager storage of the bytecode and of the precompile mismatch")
+	return ret, err
+}
+
+// constsCall calls the ConstantsManager and checks the precompile returns the same output as the bytecode does
+func (env *constsEnv) constsCall(from common.Address, method string, args ...interface{}) error {
+	input, err := env.abi.Pack(method, args...)
+	require.NoError(env.t, err)
+	gotRet, _, gotErr := (&constant_manager.ConstantManagerPrecompile{}).Run(env.sfcState.Copy(), vm.BlockContext{BlockNumber: big.NewInt(1)}, vm.TxContext{Origin: from}, from, input, 1e10, new(big.Int))
+	expRet, expErr := env.call(from, constant_manager.ContractAddress, input)
+	require.Equal(env.t, expErr, gotErr, method)
+	require.Equal(env.t, expRet, gotRet, method)
+	return expErr
+}
+
+func TestConstantManagerPrecompileParity(t *testing.T) {
+	require := require.New(t)
+	env, owner := newConstsEnv(t)
+	stranger := common.HexToAddress("0x5742a6e400000000000000000000000000000001")
+
+	for name, method := range env.abi.Methods {
+		if method.IsConstant() {
+			require.NoError(env.constsCall(owner, name), name)
+		}
+	}
+	for name, method := range env.abi.Methods {
+		if !strings.HasPrefix(name, "update") {
+			continue
+		}
+		getter := strings.ToLower(name[6:7]) + name[7:]
+		ret, err := env.call(owner, constant_manager.ContractAddress, env.abi.Methods[getter].ID)
+		require.NoError(err)
+		current := new(big.Int).SetBytes(ret)
+
+		require.Error(env.constsCall(stranger, name, current), name)
+		// the results of the out of bounds values are compared by constsCall
+		for _, v := range []*big.Int{
+			big.NewInt(0),
+			big.NewInt(1),
+			big.NewInt(2),
+			new(big.Int).Div(current, big.NewInt(2)),
+			new(big.Int).Mul(current, big.NewInt(2)),
+			new(big.Int).Mul(current, big.NewInt(100)),
+		} {
+			_ = env.constsCall(owner, name, v)
+		}
+		require.Error(env.constsCall(owner, name, new(big.Int).Lsh(big.NewInt(1), 255)), name)
+		require.NoError(env.constsCall(owner, name, current), name)
+		require.NoError(env.constsCall(owner, name, new(big.Int).Add(current, big.NewInt(1))), name)
+		require.NoError(env.constsCall(owner, getter), method.Name)
+	}
+
+	require.Error(env.constsCall(owner, "initialize"))
+	require.Error(env.constsCall(stranger, "transferOwnership", stranger))
+	require.Error(env.constsCall(owner, "transferOwnership", common.Address{}))
+	require.NoError(env.constsCall(owner, "transferOwnership", stranger))
+	require.NoError(env.constsCall(stranger, "isOwner"))
+	require.Error(env.constsCall(owner, "renounceOwnership"))
+	require.NoError(env.constsCall(stranger, "renounceOwnership"))
+	require.NoError(env.constsCall(stranger, "owner"))
+}
@@ -1,14 +1,248 @@
 package driver
 
 import (
+	"math/big"
+	"strings"
+
+	"github.com/unicornultrafoundation/go-u2u/accounts/abi"
 	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
 	"github.com/unicornultrafoundation/go-u2u/core/vm"
+	"github.com/unicornultrafoundation/go-u2u/gossip/contract/driver100"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/internal/precompiled"
+)
+
+// Storage layout of the NodeDriver contract.
+// Slots 1-50 are the reserved gap of Initializable.
+const (
+	initializableSlot = 0
+	sfcSlot           = 51
+	backendSlot       = 52
+	evmWriterSlot     = 53
+)
+
+var (
+	errNotBackend  = precompiled.RevertError("caller is not the backend")
+	errNotCallable = precompiled.RevertError("not callable")
+
+	driverAbi abi.ABI
+	driver    *precompiled.Contract[*context]
 )
 
+// context is the environment of a single NodeDriver call
+type context struct {
+	stateDB  vm.StateDB
+	blockCtx vm.BlockContext
+	txCtx    vm.TxContext
+	caller   common.Address
+}
+
+var handlers = map[string]precompiled.Handler[*context]{
+	"setBackend":            handleSetBackend,
+	"initialize":            handleInitialize,
+	"setBalance":            handleSetBalance,
+	"copyCode":              handleCopyCode,
+	"swapCode":              handleSwapCode,
+	"setStorage":            handleSetStorage,
+	"incNonce":              handleIncNonce,
+	"updateNetworkRules":    eventEmitter("UpdateNetworkRules"),
+	"updateNetworkVersion":  eventEmitter("UpdateNetworkVersion"),
+	"advanceEpochs":         eventEmitter("AdvanceEpochs"),
+	"updateValidatorWeight": eventEmitter("UpdateValidatorWeight"),
+	"updateValidatorPubkey": eventEmitter("UpdateValidatorPubkey"),
+	// the methods called by the node are only forwarded to the backend
+	"setGenesisValidator":  onlyNode,
+	"setGenesisDelegation": onlyNode,
+	"deactivateValidator":  onlyNode,
+	"sealEpochValidators":  onlyNode,
+	"sealEpoch":            onlyNode,
+	"sealEpochV1":          onlyNode,
+}
+
+func init() {
+	var err error
+	driverAbi, err = abi.JSON(strings.NewReader(driver100.ContractABI))
+	if err != nil {
+		panic(err)
+	}
+	driver = &precompiled.Contract[*context]{
+		Name:     "NodeDriver",
+		Abi:      driverAbi,
+		Handlers: handlers,
+	}
+	driver.Validate()
+}
+
 // DriverPrecompile implements PrecompiledStateContract interface
 type DriverPrecompile struct{}
 
-// Run runs the precompiled contract
-func (p *DriverPrecompile) Run(stateDB vm.StateDB, blockCtx vm.BlockContext, txCtx vm.TxContext, caller common.Address, input []byte, suppliedGas uint64) ([]byte, uint64, error) {
-	return nil, 0, nil
+// Run runs the precompiled contract. It mirrors the storage writes and events of
+// the NodeDriver bytecode, and the writes it makes through the EvmWriter. The calls
+// the contract forwards to its backend aren't repeated, they're executed by the
+// NodeDriverAuth precompile. The supplied gas is returned untouched.
+func (p *DriverPrecompile) Run(stateDB vm.StateDB, blockCtx vm.BlockContext, txCtx vm.TxContext, caller common.Address, input []byte, suppliedGas uint64, value *big.Int) ([]byte, uint64, error) {
+	c := &context{
+		stateDB:  stateDB,
+		blockCtx: blockCtx,
+		txCtx:    txCtx,
+		caller:   caller,
+	}
+	return driver.Run(c, input, suppliedGas, value)
+}
+
+func (c *context) getAddress(slot int64) common.Address {
+	return common.BytesToAddress(c.stateDB.GetState(ContractAddress, common.BigToHash(big.NewInt(slot))).Bytes())
+}
+
+func (c *context) setAddress(slot int64, addr common.Address) {
+	c.stateDB.SetState(ContractAddress, common.BigToHash(big.NewInt(slot)), common.BytesToHash(addr.Bytes()))
+}
+
+func (c *context) onlyBackend() error {
+	if c.caller != c.getAddress(backendSlot) {
+		return errNotBackend
+	}
+	return nil
+}
+
+// emit adds a log of the named event, topics are the indexed arguments.
+func (c *context) emit(name string, topics []common.Hash, args ...interface{}) error {
+	event := driverAbi.Events[name]
+	data, err := event.Inputs.NonIndexed().Pack(args...)
+	if err != nil {
+		return err
+	}
+	c.stateDB.AddLog(&types.Log{
+		Address:     ContractAddress,
+		Topics:      append([]common.Hash{event.ID}, topics...),
+		Data:        data,
+		BlockNumber: c.blockCtx.BlockNumber.Uint64(),
+	})
+	return nil
+}
+
+func onlyNode(c *context, args []interface{}) ([]byte, error) {
+	if c.caller != (common.Address{}) {
+		return nil, errNotCallable
+	}
+	return nil, nil
+}
+
+func handleSetBackend(c *context, args []interface{}) ([]byte, error) {
+	if err := c.onlyBackend(); err != nil {
+		return nil, err
+	}
+	backend := args[0].(common.Address)
+	if err := c.emit("UpdatedBackend", []common.Hash{common.BytesToHash(backend.Bytes())}); err != nil {
+		return nil, err
+	}
+	c.setAddress(backendSlot, backend)
+	return nil, nil
+}
+
+func handleInitialize(c *context, args []interface{}) ([]byte, error) {
+	backend := args[0].(common.Address)
+	evmWriter := args[1].(common.Address)
+
+	slot := common.BigToHash(big.NewInt(initializableSlot))
+	if precompiled.Initialized(c.stateDB, ContractAddress, slot) {
+		return nil, precompiled.ErrAlreadyInitialized
+	}
+	c.setAddress(backendSlot, backend)
+	if err := c.emit("UpdatedBackend", []common.Hash{common.BytesToHash(backend.Bytes())}); err != nil {
+		return nil, err
+	}
+	c.setAddress(evmWriterSlot, evmWriter)
+	c.stateDB.SetState(ContractAddress, slot, common.BigToHash(precompiled.InitializedFlag))
+	return nil, nil
+}
+
+// eventEmitter returns the handler of a backend-only method, which only emits
+// the named event with the method arguments. The first argument is indexed for
+// the events of validators.
+func eventEmitter(name string) precompiled.Handler[*context] {
+	return func(c *context, args []interface{}) ([]byte, error) {
+		if err := c.onlyBackend(); err != nil {
+			return nil, err
+		}
+		var topics []common.Hash
+		if driverAbi.Events[name].Inputs[0].Indexed {
+			topics = []common.Hash{common.BigToHash(args[0].(*big.Int))}
+			args = args[1:]
+		}
+		return nil, c.emit(name, topics, args...)
+	}
+}
+
+// The EvmWriter methods. The EvmWriter is a stateful precompile, which only
+// touches the main state, so its writes are repeated here.
+
+func handleSetBalance(c *context, args []interface{}) ([]byte, error) {
+	if err := c.onlyBackend(); err != nil {
+		return nil, err
+	}
+	acc := args[0].(common.Address)
+	value := args[1].(*big.Int)
+	if acc == c.txCtx.Origin {
+		return nil, vm.ErrExecutionReverted
+	}
+	balance := c.stateDB.GetBalance(acc)
+	if balance.Cmp(value) >= 0 {
+		c.stateDB.SubBalance(acc, new(big.Int).Sub(balance, value))
+	} else {
+		c.stateDB.AddBalance(acc, new(big.Int).Sub(value, balance))
+	}
+	return nil, nil
+}
+
+func handleCopyCode(c *context, args []interface{}) ([]byte, error) {
+	if err := c.onlyBackend(); err != nil {
+		return nil, err
+	}
+	acc := args[0].(common.Address)
+	from := args[1].(common.Address)
+	if acc != from {
+		c.stateDB.SetCode(acc, common.CopyBytes(c.stateDB.GetCode(from)))
+	}
+	return nil, nil
+}
+
+func handleSwapCode(c *context, args []interface{}) ([]byte, error) {
+	if err := c.onlyBackend(); err != nil {
+		return nil, err
+	}
+	acc0 := args[0].(common.Address)
+	acc1 := args[1].(common.Address)
+	if acc0 != acc1 {
+		code0 := common.CopyBytes(c.stateDB.GetCode(acc0))
+		code1 := common.CopyBytes(c.stateDB.GetCode(acc1))
+		c.stateDB.SetCode(acc0, code1)
+		c.stateDB.SetCode(acc1, code0)
+	}
+	return nil, nil
+}
+
+func handleSetStorage(c *context, args []interface{}) ([]byte, error) {
+	if err := c.onlyBackend(); err != nil {
+		return nil, err
+	}
+	acc := args[0].(common.Address)
+	key := args[1].([32]byte)
+	value := args[2].([32]byte)
+	c.stateDB.SetState(acc, key, value)
+	return nil, nil
+}
+
+func handleIncNonce(c *context, args []interface{}) ([]byte, error) {
+	if err := c.onlyBackend(); err != nil {
+		return nil, err
+	}
+	acc := args[0].(common.Address)
+	diff := args[1].(*big.Int)
+	// the EvmWriter only allows diffs in (0, 256)
+	if acc == c.txCtx.Origin || diff.Sign() <= 0 || diff.Cmp(common.Big256) >= 0 {
+		return nil, vm.ErrExecutionReverted
+	}
+	c.stateDB.SetNonce(acc, c.stateDB.GetNonce(acc)+diff.Uint64())
+	return nil, nil
 }
@@ -1,14 +1,214 @@
 package driverauth
 
 import (
+	"math/big"
+	"strings"
+
+	"github.com/unicornultrafoundation/go-u2u/accounts/abi"
 	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
 	"github.com/unicornultrafoundation/go-u2u/core/vm"
+	"github.com/unicornultrafoundation/go-u2u/gossip/contract/driverauth100"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/internal/precompiled"
+)
+
+// Storage layout of the NodeDriverAuth contract.
+// Initializable and Ownable are followed by the reserved gaps of 50 slots.
+const (
+	initializableSlot = 0
+	ownerSlot         = 51
+	sfcSlot           = 102
+	driverSlot        = 103
+)
+
+var (
+	errNotSFC          = precompiled.RevertError("caller is not the SFC contract")
+	errNotDriver       = precompiled.RevertError("caller is not the NodeDriver contract")
+	errNotSFCRecipient = precompiled.RevertError("recipient is not the SFC contract")
+
+	driverAuthAbi abi.ABI
+	driverAuth    *precompiled.Contract[*context]
 )
 
+// context is the environment of a single NodeDriverAuth call
+type context struct {
+	stateDB  vm.StateDB
+	blockCtx vm.BlockContext
+	caller   common.Address
+}
+
+var handlers = map[string]precompiled.Handler[*context]{
+	"initialize":        handleInitialize,
+	"owner":             handleOwner,
+	"isOwner":           handleIsOwner,
+	"transferOwnership": handleTransferOwnership,
+	"renounceOwnership": handleRenounceOwnership,
+	"execute":           handleExecute,
+	"mutExecute":        handleMutExecute,
+	"incBalance":        handleIncBalance,
+	// the methods which are only forwarded to NodeDriver
+	"migrateTo":             onlyOwner,
+	"upgradeCode":           onlyOwner,
+	"copyCode":              onlyOwner,
+	"incNonce":              onlyOwner,
+	"updateNetworkRules":    onlyOwner,
+	"updateNetworkVersion":  onlyOwner,
+	"advanceEpochs":         onlyOwner,
+	"updateMinGasPrice":     onlySFC,
+	"updateValidatorWeight": onlySFC,
+	"updateValidatorPubkey": onlySFC,
+	// the methods which are only forwarded to SFC
+	"setGenesisValidator":  onlyDriver,
+	"setGenesisDelegation": onlyDriver,
+	"deactivateValidator":  onlyDriver,
+	"sealEpochValidators":  onlyDriver,
+	"sealEpoch":            onlyDriver,
+}
+
+func init() {
+	var err error
+	driverAuthAbi, err = abi.JSON(strings.NewReader(driverauth100.ContractABI))
+	if err != nil {
+		panic(err)
+	}
+	driverAuth = &precompiled.Contract[*context]{
+		Name:     "NodeDriverAuth",
+		Abi:      driverAuthAbi,
+		Handlers: handlers,
+	}
+	driverAuth.Validate()
+}
+
 // DriverAuthPrecompile implements PrecompiledStateContract interface
 type DriverAuthPrecompile struct{}
 
-// Run runs the precompiled contract
-func (c *DriverAuthPrecompile) Run(stateDB vm.StateDB, blockCtx vm.BlockContext, txCtx vm.TxContext, caller common.Address, input []byte, suppliedGas uint64) ([]byte, uint64, error) {
-	return nil, 0, nil
+// Run runs the precompiled contract. It mirrors the storage writes and events of
+// the NodeDriverAuth bytecode. The calls the contract forwards to NodeDriver and SFC
+// aren't repeated, they're executed by the respective precompiles. The checks of
+// code are left to the bytecode, as the SFC state keeps no code. The supplied gas
+// is returned untouched.
+func (p *DriverAuthPrecompile) Run(stateDB vm.StateDB, blockCtx vm.BlockContext, txCtx vm.TxContext, caller common.Address, input []byte, suppliedGas uint64, value *big.Int) ([]byte, uint64, error) {
+	c := &context{
+		stateDB:  stateDB,
+		blockCtx: blockCtx,
+		caller:   caller,
+	}
+	return driverAuth.Run(c, input, suppliedGas, value)
+}
+
+func (c *context) getAddress(slot int64) common.Address {
+	return common.BytesToAddress(c.stateDB.GetState(ContractAddress, common.BigToHash(big.NewInt(slot))).Bytes())
+}
+
+func (c *context) setAddress(slot int64, addr common.Address) {
+	c.stateDB.SetState(ContractAddress, common.BigToHash(big.NewInt(slot)), common.BytesToHash(addr.Bytes()))
+}
+
+func (c *context) owner() common.Address {
+	return c.getAddress(ownerSlot)
+}
+
+func (c *context) transferOwnership(newOwner common.Address) {
+	event := driverAuthAbi.Events["OwnershipTransferred"]
+	c.stateDB.AddLog(&types.Log{
+		Address:     ContractAddress,
+		Topics:      []common.Hash{event.ID, common.BytesToHash(c.owner().Bytes()), common.BytesToHash(newOwner.Bytes())},
+		BlockNumber: c.blockCtx.BlockNumber.Uint64(),
+	})
+	c.setAddress(ownerSlot, newOwner)
+}
+
+func onlyOwner(c *context, args []interface{}) ([]byte, error) {
+	if c.caller != c.owner() {
+		return nil, precompiled.ErrNotOwner
+	}
+	return nil, nil
+}
+
+func onlySFC(c *context, args []interface{}) ([]byte, error) {
+	if c.caller != c.getAddress(sfcSlot) {
+		return nil, errNotSFC
+	}
+	return nil, nil
+}
+
+func onlyDriver(c *context, args []interface{}) ([]byte, error) {
+	if c.caller != c.getAddress(driverSlot) {
+		return nil, errNotDriver
+	}
+	return nil, nil
+}
+
+func handleInitialize(c *context, args []interface{}) ([]byte, error) {
+	sfc := args[0].(common.Address)
+	driver := args[1].(common.Address)
+	owner := args[2].(common.Address)
+
+	slot := common.BigToHash(big.NewInt(initializableSlot))
+	if precompiled.Initialized(c.stateDB, ContractAddress, slot) {
+		return nil, precompiled.ErrAlreadyInitialized
+	}
+	c.transferOwnership(owner)
+	c.setAddress(driverSlot, driver)
+	c.setAddress(sfcSlot, sfc)
+	c.stateDB.SetState(ContractAddress, slot, common.BigToHash(precompiled.InitializedFlag))
+	return nil, nil
+}
+
+func handleOwner(c *context, args []interface{}) ([]byte, error) {
+	return driverAuthAbi.Methods["owner"].Outputs.Pack(c.owner())
+}
+
+func handleIsOwner(c *context, args []interface{}) ([]byte, error) {
+	return driverAuthAbi.Methods["isOwner"].Outputs.Pack(c.caller == c.owner())
+}
+
+func handleTransferOwnership(c *context, args []interface{}) ([]byte, error) {
+	if _, err := onlyOwner(c, args); err != nil {
+		return nil, err
+	}
+	newOwner := args[0].(common.Address)
+	if newOwner == (common.Address{}) {
+		return nil, precompiled.ErrZeroOwner
+	}
+	c.transferOwnership(newOwner)
+	return nil, nil
+}
+
+func handleRenounceOwnership(c *context, args []interface{}) ([]byte, error) {
+	if _, err := onlyOwner(c, args); err != nil {
+		return nil, err
+	}
+	c.transferOwnership(common.Address{})
+	return nil, nil
+}
+
+// execute hands the ownership to the executable for the time of its execution,
+// and passes it to the new owner afterwards. Only the net effect is applied here,
+// so the calls the executable makes as the owner are only checked by the bytecode.
+func (c *context) execute(executable, newOwner common.Address) error {
+	if _, err := onlyOwner(c, nil); err != nil {
+		return err
+	}
+	c.transferOwnership(executable)
+	c.transferOwnership(newOwner)
+	return nil
+}
+
+func handleExecute(c *context, args []interface{}) ([]byte, error) {
+	return nil, c.execute(args[0].(common.Address), c.owner())
+}
+
+func handleMutExecute(c *context, args []interface{}) ([]byte, error) {
+	return nil, c.execute(args[0].(common.Address), args[1].(common.Address))
+}
+
+func handleIncBalance(c *context, args []interface{}) ([]byte, error) {
+	if _, err := onlySFC(c, args); err != nil {
+		return nil, err
+	}
+	if args[0].(common.Address) != c.getAddress(sfcSlot) {
+		return nil, errNotSFCRecipient
+	}
+	return nil, nil
 }
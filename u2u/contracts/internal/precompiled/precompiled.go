@@ -0,0 +1,97 @@
+// Package precompiled contains the scaffolding shared by the native implementations
+// of the system contracts: the method dispatch, the Solidity-like reverts and the
+// Initializable checks.
+package precompiled
+
+import (
+	"math/big"
+
+	"github.com/unicornultrafoundation/go-u2u/accounts/abi"
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+)
+
+// RevertError is a revert raised by the contract logic with a Solidity-like reason
+type RevertError string
+
+func (e RevertError) Error() string {
+	return string(e)
+}
+
+var (
+	ErrNotOwner           = RevertError("Ownable: caller is not the owner")
+	ErrZeroOwner          = RevertError("Ownable: new owner is the zero address")
+	ErrAlreadyInitialized = RevertError("Contract instance has already been initialized")
+
+	// revertSelector is the selector of Error(string), the ABI encoding of revert reasons
+	revertSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+	stringType, _  = abi.NewType("string", "", nil)
+)
+
+// Flags of the Initializable contract, packed into its storage slot
+var (
+	InitializedFlag  = big.NewInt(1)
+	InitializingFlag = big.NewInt(1 << 8)
+)
+
+// Handler executes a single method of the contract, args are the unpacked method inputs
+type Handler[C any] func(c C, args []interface{}) ([]byte, error)
+
+// Contract dispatches the calls of a native contract to the handlers of its methods
+type Contract[C any] struct {
+	Name     string
+	Abi      abi.ABI
+	Handlers map[string]Handler[C]
+}
+
+// Validate panics if a method of the ABI has no handler
+func (p *Contract[C]) Validate() {
+	for name := range p.Abi.Methods {
+		if _, ok := p.Handlers[name]; !ok {
+			panic("unhandled " + p.Name + " method " + name)
+		}
+	}
+}
+
+// Run unpacks the input and calls the handler of the method. The reverts of the
+// contract logic are returned with the encoded reason. The supplied gas is returned
+// untouched, unless the handler fails with an error other than a revert.
+func (p *Contract[C]) Run(c C, input []byte, suppliedGas uint64, value *big.Int) ([]byte, uint64, error) {
+	if len(input) < 4 {
+		return nil, suppliedGas, vm.ErrExecutionReverted
+	}
+	method, err := p.Abi.MethodById(input[:4])
+	if err != nil {
+		return nil, suppliedGas, vm.ErrExecutionReverted
+	}
+	if !method.IsPayable() && value.Sign() != 0 {
+		return nil, suppliedGas, vm.ErrExecutionReverted
+	}
+	args, err := method.Inputs.Unpack(input[4:])
+	if err != nil {
+		return nil, suppliedGas, vm.ErrExecutionReverted
+	}
+	ret, err := p.Handlers[method.Name](c, args)
+	if err != nil {
+		if reason, ok := err.(RevertError); ok {
+			return RevertOutput(reason), suppliedGas, vm.ErrExecutionReverted
+		}
+		return nil, 0, err
+	}
+	return ret, suppliedGas, nil
+}
+
+// RevertOutput encodes the revert reason the same way Solidity does
+func RevertOutput(reason RevertError) []byte {
+	data, err := abi.Arguments{{Type: stringType}}.Pack(string(reason))
+	if err != nil {
+		panic(err)
+	}
+	return append(common.CopyBytes(revertSelector), data...)
+}
+
+// Initialized reports whether the Initializable contract is initialized. The native
+// contracts are never under construction, so only the initialized flag matters.
+func Initialized(stateDB vm.StateDB, addr common.Address, slot common.Hash) bool {
+	return new(big.Int).And(stateDB.GetState(addr, slot).Big(), InitializedFlag).Sign() != 0
+}
@@ -0,0 +1,75 @@
+package precompiled
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/unicornultrafoundation/go-u2u/accounts/abi"
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+)
+
+const testAbi = `[
+	{"type":"function","name":"get","inputs":[],"outputs":[{"type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"fail","inputs":[{"type":"bool"}],"outputs":[],"stateMutability":"nonpayable"}
+]`
+
+func TestContractRun(t *testing.T) {
+	require := require.New(t)
+
+	parsed, err := abi.JSON(strings.NewReader(testAbi))
+	require.NoError(err)
+	p := &Contract[*big.Int]{
+		Name: "Test",
+		Abi:  parsed,
+		Handlers: map[string]Handler[*big.Int]{
+			"get": func(c *big.Int, args []interface{}) ([]byte, error) {
+				return parsed.Methods["get"].Outputs.Pack(c)
+			},
+			"fail": func(c *big.Int, args []interface{}) ([]byte, error) {
+				if args[0].(bool) {
+					return nil, ErrNotOwner
+				}
+				return nil, errors.New("internal")
+			},
+		},
+	}
+	p.Validate()
+
+	get := parsed.Methods["get"].ID
+	ret, gas, err := p.Run(big.NewInt(7), get, 100, common.Big0)
+	require.NoError(err)
+	require.Equal(uint64(100), gas)
+	require.Equal(common.BigToHash(big.NewInt(7)).Bytes(), ret)
+
+	// non-payable method
+	_, gas, err = p.Run(big.NewInt(7), get, 100, common.Big1)
+	require.Equal(vm.ErrExecutionReverted, err)
+	require.Equal(uint64(100), gas)
+
+	// unknown method
+	_, _, err = p.Run(big.NewInt(7), []byte{1, 2, 3, 4}, 100, common.Big0)
+	require.Equal(vm.ErrExecutionReverted, err)
+
+	// revert with a reason
+	input, err := parsed.Pack("fail", true)
+	require.NoError(err)
+	ret, gas, err = p.Run(nil, input, 100, common.Big0)
+	require.Equal(vm.ErrExecutionReverted, err)
+	require.Equal(uint64(100), gas)
+	require.Equal(RevertOutput(ErrNotOwner), ret)
+	reason, err := abi.UnpackRevert(ret)
+	require.NoError(err)
+	require.Equal(string(ErrNotOwner), reason)
+
+	// other errors consume the gas
+	input, err = parsed.Pack("fail", false)
+	require.NoError(err)
+	_, gas, err = p.Run(nil, input, 100, common.Big0)
+	require.EqualError(err, "internal")
+	require.Zero(gas)
+}
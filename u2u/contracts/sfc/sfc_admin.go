@@ -0,0 +1,74 @@
+package sfc
+
+import (
+	"math/big"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/internal/precompiled"
+)
+
+func (c *context) transferOwnership(newOwner common.Address) error {
+	if err := c.emit("OwnershipTransferred", []common.Hash{addressKey(c.owner()), addressKey(newOwner)}); err != nil {
+		return err
+	}
+	c.setAddress(slotOf(ownerSlot), newOwner)
+	return nil
+}
+
+func handleInitialize(c *context, args []interface{}) ([]byte, error) {
+	sealedEpoch := args[0].(*big.Int)
+	totalSupply := args[1].(*big.Int)
+	nodeDriver := args[2].(common.Address)
+	lib := args[3].(common.Address)
+	consts := args[4].(common.Address)
+	owner := args[5].(common.Address)
+
+	if precompiled.Initialized(c.stateDB, ContractAddress, slotOf(initializableSlot)) {
+		return nil, precompiled.ErrAlreadyInitialized
+	}
+	c.setState(slotOf(initializableSlot), new(big.Int).Or(precompiled.InitializedFlag, precompiled.InitializingFlag))
+
+	c.setAddress(slotOf(ownerSlot), owner)
+	if err := c.emit("OwnershipTransferred", []common.Hash{addressKey(common.Address{}), addressKey(owner)}); err != nil {
+		return nil, err
+	}
+	c.setState(slotOf(currentSealedEpochSlot), sealedEpoch)
+	c.setAddress(slotOf(nodeSlot), nodeDriver)
+	c.setAddress(slotOf(libAddressSlot), lib)
+	c.setAddress(slotOf(constantsManagerSlot), consts)
+	c.setState(slotOf(totalSupplySlot), totalSupply)
+	c.setState(slotOf(minGasPriceSlot), initialMinGasPrice)
+	c.setState(epochSnapshotSlotOf(sealedEpoch, snapshotEndTime), c.now())
+
+	c.setState(slotOf(initializableSlot), precompiled.InitializedFlag)
+	return nil, nil
+}
+
+func handleTransferOwnership(c *context, args []interface{}) ([]byte, error) {
+	if err := c.onlyOwner(); err != nil {
+		return nil, err
+	}
+	newOwner := args[0].(common.Address)
+	if err := require(newOwner != common.Address{}, "Ownable: new owner is the zero address"); err != nil {
+		return nil, err
+	}
+	return nil, c.transferOwnership(newOwner)
+}
+
+func handleRenounceOwnership(c *context, args []interface{}) ([]byte, error) {
+	if err := c.onlyOwner(); err != nil {
+		return nil, err
+	}
+	return nil, c.transferOwnership(common.Address{})
+}
+
+// addressSetter returns the handler of an owner-only setter of an address variable
+func addressSetter(slot int64) precompiled.Handler[*context] {
+	return func(c *context, args []interface{}) ([]byte, error) {
+		if err := c.onlyOwner(); err != nil {
+			return nil, err
+		}
+		c.setAddress(slotOf(slot), args[0].(common.Address))
+		return nil, nil
+	}
+}
@@ -0,0 +1,262 @@
+package sfc
+
+import (
+	"math/big"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/internal/precompiled"
+)
+
+// Validator status bits
+const (
+	okStatus      = 0
+	withdrawnBit  = 1
+	offlineBit    = 1 << 3
+	doublesignBit = 1 << 7
+	cheaterMask   = doublesignBit
+)
+
+// rewards mirrors the Rewards struct of the SFC
+type rewards struct {
+	lockupExtraReward *big.Int
+	lockupBaseReward  *big.Int
+	unlockedReward    *big.Int
+}
+
+func zeroRewards() rewards {
+	return rewards{new(big.Int), new(big.Int), new(big.Int)}
+}
+
+func (r rewards) isZero() bool {
+	return r.lockupExtraReward.Sign() == 0 && r.lockupBaseReward.Sign() == 0 && r.unlockedReward.Sign() == 0
+}
+
+// total returns unlockedReward.add(lockupBaseReward).add(lockupExtraReward)
+func (r rewards) total() (*big.Int, error) {
+	res, err := safeAdd(r.unlockedReward, r.lockupBaseReward)
+	if err != nil {
+		return nil, err
+	}
+	return safeAdd(res, r.lockupExtraReward)
+}
+
+func sumRewards(rr ...rewards) (rewards, error) {
+	res := zeroRewards()
+	var err error
+	for _, r := range rr {
+		if res.lockupExtraReward, err = safeAdd(res.lockupExtraReward, r.lockupExtraReward); err != nil {
+			return res, err
+		}
+		if res.lockupBaseReward, err = safeAdd(res.lockupBaseReward, r.lockupBaseReward); err != nil {
+			return res, err
+		}
+		if res.unlockedReward, err = safeAdd(res.unlockedReward, r.unlockedReward); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+func (c *context) getRewards(slot common.Hash) rewards {
+	return rewards{
+		lockupExtraReward: c.getState(offsetSlot(slot, rewardsLockupExtraReward)),
+		lockupBaseReward:  c.getState(offsetSlot(slot, rewardsLockupBaseReward)),
+		unlockedReward:    c.getState(offsetSlot(slot, rewardsUnlockedReward)),
+	}
+}
+
+func (c *context) setRewards(slot common.Hash, r rewards) {
+	c.setState(offsetSlot(slot, rewardsLockupExtraReward), r.lockupExtraReward)
+	c.setState(offsetSlot(slot, rewardsLockupBaseReward), r.lockupBaseReward)
+	c.setState(offsetSlot(slot, rewardsUnlockedReward), r.unlockedReward)
+}
+
+func (c *context) owner() common.Address {
+	return c.getAddress(slotOf(ownerSlot))
+}
+
+func (c *context) onlyOwner() error {
+	if c.caller != c.owner() {
+		return precompiled.ErrNotOwner
+	}
+	return nil
+}
+
+func (c *context) onlyDriver() error {
+	if c.caller != c.getAddress(slotOf(nodeSlot)) {
+		return errNotDriver
+	}
+	return nil
+}
+
+func (c *context) currentSealedEpoch() *big.Int {
+	return c.getState(slotOf(currentSealedEpochSlot))
+}
+
+func (c *context) currentEpoch() *big.Int {
+	return add(c.currentSealedEpoch(), common.Big1)
+}
+
+func (c *context) validator(validatorID *big.Int, field int64) *big.Int {
+	return c.getState(validatorFieldSlot(validatorID, field))
+}
+
+func (c *context) setValidator(validatorID *big.Int, field int64, value *big.Int) {
+	c.setState(validatorFieldSlot(validatorID, field), value)
+}
+
+func (c *context) validatorAuth(validatorID *big.Int) common.Address {
+	return c.getAddress(validatorFieldSlot(validatorID, validatorAuth))
+}
+
+func (c *context) validatorExists(validatorID *big.Int) bool {
+	return c.validator(validatorID, validatorCreatedTime).Sign() != 0
+}
+
+func (c *context) isSlashed(validatorID *big.Int) bool {
+	status := c.validator(validatorID, validatorStatus)
+	return new(big.Int).And(status, big.NewInt(cheaterMask)).Sign() != 0
+}
+
+func (c *context) stake(delegator common.Address, toValidatorID *big.Int) *big.Int {
+	return c.getState(delegationSlot(stakeSlot, delegator, toValidatorID))
+}
+
+func (c *context) setStake(delegator common.Address, toValidatorID *big.Int, value *big.Int) {
+	c.setState(delegationSlot(stakeSlot, delegator, toValidatorID), value)
+}
+
+func (c *context) lockup(delegator common.Address, toValidatorID *big.Int, field int64) *big.Int {
+	return c.getState(offsetSlot(delegationSlot(lockupInfoSlot, delegator, toValidatorID), field))
+}
+
+func (c *context) setLockup(delegator common.Address, toValidatorID *big.Int, field int64, value *big.Int) {
+	c.setState(offsetSlot(delegationSlot(lockupInfoSlot, delegator, toValidatorID), field), value)
+}
+
+func (c *context) deleteLockup(delegator common.Address, toValidatorID *big.Int) {
+	for field := int64(lockupLockedStake); field <= lockupDuration; field++ {
+		c.setLockup(delegator, toValidatorID, field, common.Big0)
+	}
+}
+
+func (c *context) isLockedUp(delegator common.Address, toValidatorID *big.Int) bool {
+	endTime := c.lockup(delegator, toValidatorID, lockupEndTime)
+	return endTime.Sign() != 0 && !c.isSlashed(toValidatorID) && c.now().Cmp(endTime) <= 0
+}
+
+func (c *context) getLockedStake(delegator common.Address, toValidatorID *big.Int) *big.Int {
+	if !c.isLockedUp(delegator, toValidatorID) {
+		return new(big.Int)
+	}
+	return c.lockup(delegator, toValidatorID, lockupLockedStake)
+}
+
+func (c *context) getUnlockedStake(delegator common.Address, toValidatorID *big.Int) (*big.Int, error) {
+	if !c.isLockedUp(delegator, toValidatorID) {
+		return c.stake(delegator, toValidatorID), nil
+	}
+	return safeSub(c.stake(delegator, toValidatorID), c.lockup(delegator, toValidatorID, lockupLockedStake))
+}
+
+func (c *context) getSelfStake(validatorID *big.Int) *big.Int {
+	return c.stake(c.validatorAuth(validatorID), validatorID)
+}
+
+func (c *context) checkDelegatedStakeLimit(validatorID *big.Int) (bool, error) {
+	limit, err := mulDiv(c.getSelfStake(validatorID), c.consts().maxDelegatedRatio(), decimalUnit)
+	if err != nil {
+		return false, err
+	}
+	return c.validator(validatorID, validatorReceivedStake).Cmp(limit) <= 0, nil
+}
+
+func (c *context) addTo(slot common.Hash, amount *big.Int) error {
+	res, err := safeAdd(c.getState(slot), amount)
+	if err != nil {
+		return err
+	}
+	c.setState(slot, res)
+	return nil
+}
+
+func (c *context) subFrom(slot common.Hash, amount *big.Int) error {
+	res, err := safeSub(c.getState(slot), amount)
+	if err != nil {
+		return err
+	}
+	c.setState(slot, res)
+	return nil
+}
+
+// syncValidator checks the validator may be synced with the node. The weight
+// and pubkey updates are sent by the contract through NodeDriverAuth, which is
+// handled by the NodeDriverAuth precompile on its own.
+func (c *context) syncValidator(validatorID *big.Int) error {
+	return require(c.validatorExists(validatorID), "validator doesn't exist")
+}
+
+func (c *context) setValidatorDeactivated(validatorID *big.Int, status *big.Int) error {
+	curStatus := c.validator(validatorID, validatorStatus)
+	if curStatus.Sign() == okStatus && status.Sign() != okStatus {
+		if err := c.subFrom(slotOf(totalActiveStakeSlot), c.validator(validatorID, validatorReceivedStake)); err != nil {
+			return err
+		}
+	}
+	// status as a number is proportional to severity
+	if status.Cmp(curStatus) > 0 {
+		c.setValidator(validatorID, validatorStatus, status)
+		if c.validator(validatorID, validatorDeactivatedEpoch).Sign() == 0 {
+			deactivatedEpoch, deactivatedTime := c.currentEpoch(), c.now()
+			c.setValidator(validatorID, validatorDeactivatedEpoch, deactivatedEpoch)
+			c.setValidator(validatorID, validatorDeactivatedTime, deactivatedTime)
+			if err := c.emit("DeactivatedValidator", []common.Hash{uintKey(validatorID)}, deactivatedEpoch, deactivatedTime); err != nil {
+				return err
+			}
+		}
+		return c.emit("ChangedValidatorStatus", []common.Hash{uintKey(validatorID)}, status)
+	}
+	return nil
+}
+
+// mintNativeToken accounts newly minted tokens. The balance itself is increased
+// by the node driver, which the contract calls through NodeDriverAuth.
+func (c *context) mintNativeToken(amount *big.Int) error {
+	return c.addTo(slotOf(totalSupplySlot), amount)
+}
+
+func (c *context) burnFTM(amount *big.Int) error {
+	if amount.Sign() == 0 {
+		return nil
+	}
+	c.payOut(amount)
+	return c.emit("BurntFTM", nil, amount)
+}
+
+// scaleLockupReward splits the full reward according to the lockup duration
+func (c *context) scaleLockupReward(fullReward *big.Int, duration *big.Int) (rewards, error) {
+	reward := zeroRewards()
+	consts := c.consts()
+	unlockedRewardRatio := consts.unlockedRewardRatio()
+	var err error
+	if duration.Sign() != 0 {
+		maxLockupExtraRatio := sub(decimalUnit, unlockedRewardRatio)
+		lockupExtraRatio, err := mulDiv(maxLockupExtraRatio, duration, consts.maxLockupDuration())
+		if err != nil {
+			return reward, err
+		}
+		totalScaledReward, err := mulDiv(fullReward, add(unlockedRewardRatio, lockupExtraRatio), decimalUnit)
+		if err != nil {
+			return reward, err
+		}
+		if reward.lockupBaseReward, err = mulDiv(fullReward, unlockedRewardRatio, decimalUnit); err != nil {
+			return reward, err
+		}
+		reward.lockupExtraReward = sub(totalScaledReward, reward.lockupBaseReward)
+	} else {
+		if reward.unlockedReward, err = mulDiv(fullReward, unlockedRewardRatio, decimalUnit); err != nil {
+			return reward, err
+		}
+	}
+	return reward, nil
+}
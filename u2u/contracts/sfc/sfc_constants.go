@@ -0,0 +1,105 @@
+package sfc
+
+import (
+	"math/big"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+)
+
+// Storage slots of the ConstantsManager variables
+const (
+	minSelfStakeSlot                     = 102
+	maxDelegatedRatioSlot                = 103
+	validatorCommissionSlot              = 104
+	burntFeeShareSlot                    = 105
+	treasuryFeeShareSlot                 = 106
+	unlockedRewardRatioSlot              = 107
+	minLockupDurationSlot                = 108
+	maxLockupDurationSlot                = 109
+	withdrawalPeriodEpochsSlot           = 110
+	withdrawalPeriodTimeSlot             = 111
+	baseRewardPerSecondSlot              = 112
+	offlinePenaltyThresholdBlocksNumSlot = 113
+	offlinePenaltyThresholdTimeSlot      = 114
+	targetGasPowerPerSecondSlot          = 115
+	gasPriceBalancingCounterweightSlot   = 116
+)
+
+// constants reads the network constants the SFC is configured with. They are
+// read straight from the storage of the ConstantsManager contract.
+type constants struct {
+	stateDB vm.StateDB
+	addr    common.Address
+}
+
+func (c *context) consts() constants {
+	return constants{
+		stateDB: c.stateDB,
+		addr:    c.getAddress(slotOf(constantsManagerSlot)),
+	}
+}
+
+func (c constants) get(slot int64) *big.Int {
+	return c.stateDB.GetState(c.addr, slotOf(slot)).Big()
+}
+
+func (c constants) minSelfStake() *big.Int {
+	return c.get(minSelfStakeSlot)
+}
+
+func (c constants) maxDelegatedRatio() *big.Int {
+	return c.get(maxDelegatedRatioSlot)
+}
+
+func (c constants) validatorCommission() *big.Int {
+	return c.get(validatorCommissionSlot)
+}
+
+func (c constants) burntFeeShare() *big.Int {
+	return c.get(burntFeeShareSlot)
+}
+
+func (c constants) treasuryFeeShare() *big.Int {
+	return c.get(treasuryFeeShareSlot)
+}
+
+func (c constants) unlockedRewardRatio() *big.Int {
+	return c.get(unlockedRewardRatioSlot)
+}
+
+func (c constants) minLockupDuration() *big.Int {
+	return c.get(minLockupDurationSlot)
+}
+
+func (c constants) maxLockupDuration() *big.Int {
+	return c.get(maxLockupDurationSlot)
+}
+
+func (c constants) withdrawalPeriodEpochs() *big.Int {
+	return c.get(withdrawalPeriodEpochsSlot)
+}
+
+func (c constants) withdrawalPeriodTime() *big.Int {
+	return c.get(withdrawalPeriodTimeSlot)
+}
+
+func (c constants) baseRewardPerSecond() *big.Int {
+	return c.get(baseRewardPerSecondSlot)
+}
+
+func (c constants) offlinePenaltyThresholdBlocksNum() *big.Int {
+	return c.get(offlinePenaltyThresholdBlocksNumSlot)
+}
+
+func (c constants) offlinePenaltyThresholdTime() *big.Int {
+	return c.get(offlinePenaltyThresholdTimeSlot)
+}
+
+func (c constants) targetGasPowerPerSecond() *big.Int {
+	return c.get(targetGasPowerPerSecondSlot)
+}
+
+func (c constants) gasPriceBalancingCounterweight() *big.Int {
+	return c.get(gasPriceBalancingCounterweightSlot)
+}
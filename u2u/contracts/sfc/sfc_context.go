@@ -0,0 +1,220 @@
+package sfc
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/common/math"
+	"github.com/unicornultrafoundation/go-u2u/core/types"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/internal/precompiled"
+)
+
+var decimalUnit = big.NewInt(1e18)
+
+// errIndexOutOfBounds is raised where the contract would hit an invalid opcode
+var errIndexOutOfBounds = errors.New("array index out of bounds")
+
+var (
+	errAdditionOverflow       = precompiled.RevertError("SafeMath: addition overflow")
+	errSubtractionOverflow    = precompiled.RevertError("SafeMath: subtraction overflow")
+	errMultiplicationOverflow = precompiled.RevertError("SafeMath: multiplication overflow")
+	errDivisionByZero         = precompiled.RevertError("SafeMath: division by zero")
+	errNotDriver              = precompiled.RevertError("caller is not the NodeDriverAuth contract")
+)
+
+// require mirrors the Solidity require statement
+func require(cond bool, reason string) error {
+	if !cond {
+		return precompiled.RevertError(reason)
+	}
+	return nil
+}
+
+// context is the environment of a single SFC call
+type context struct {
+	stateDB  vm.StateDB
+	blockCtx vm.BlockContext
+	caller   common.Address
+	value    *big.Int
+}
+
+func (c *context) getState(slot common.Hash) *big.Int {
+	return c.stateDB.GetState(ContractAddress, slot).Big()
+}
+
+func (c *context) setState(slot common.Hash, value *big.Int) {
+	c.stateDB.SetState(ContractAddress, slot, common.BigToHash(value))
+}
+
+func (c *context) getAddress(slot common.Hash) common.Address {
+	return common.BytesToAddress(c.stateDB.GetState(ContractAddress, slot).Bytes())
+}
+
+func (c *context) setAddress(slot common.Hash, addr common.Address) {
+	c.stateDB.SetState(ContractAddress, slot, addressKey(addr))
+}
+
+// getBytes reads a Solidity bytes value stored at the given slot.
+func (c *context) getBytes(slot common.Hash) []byte {
+	head := c.stateDB.GetState(ContractAddress, slot)
+	if head[31]&1 == 0 {
+		// short value, data and length*2 are packed into the slot
+		size := int(head[31]) / 2
+		return common.CopyBytes(head[:size])
+	}
+	size := new(big.Int).Rsh(head.Big(), 1).Uint64()
+	data := make([]byte, 0, size)
+	dataSlot := arrayDataSlot(slot)
+	for i := int64(0); uint64(len(data)) < size; i++ {
+		word := c.stateDB.GetState(ContractAddress, offsetSlot(dataSlot, i))
+		data = append(data, word[:]...)
+	}
+	return data[:size]
+}
+
+// setBytes writes a Solidity bytes value to the given slot, clearing the
+// leftovers of the previous value.
+func (c *context) setBytes(slot common.Hash, data []byte) {
+	oldHead := c.stateDB.GetState(ContractAddress, slot)
+	oldWords := int64(0)
+	if oldHead[31]&1 == 1 {
+		oldSize := new(big.Int).Rsh(oldHead.Big(), 1).Int64()
+		oldWords = (oldSize + 31) / 32
+	}
+	dataSlot := arrayDataSlot(slot)
+	newWords := int64(0)
+	if len(data) < 32 {
+		var head common.Hash
+		copy(head[:], data)
+		head[31] = byte(len(data) * 2)
+		c.stateDB.SetState(ContractAddress, slot, head)
+	} else {
+		c.setState(slot, big.NewInt(int64(len(data))*2+1))
+		newWords = (int64(len(data)) + 31) / 32
+		for i := int64(0); i < newWords; i++ {
+			var word common.Hash
+			copy(word[:], data[i*32:])
+			c.stateDB.SetState(ContractAddress, offsetSlot(dataSlot, i), word)
+		}
+	}
+	for i := newWords; i < oldWords; i++ {
+		c.stateDB.SetState(ContractAddress, offsetSlot(dataSlot, i), common.Hash{})
+	}
+}
+
+// getUintArray reads a uint256[] value stored at the given slot.
+func (c *context) getUintArray(slot common.Hash) []*big.Int {
+	size := c.getState(slot).Int64()
+	dataSlot := arrayDataSlot(slot)
+	res := make([]*big.Int, size)
+	for i := range res {
+		res[i] = c.getState(offsetSlot(dataSlot, int64(i)))
+	}
+	return res
+}
+
+// setUintArray writes a uint256[] value to the given slot, clearing the
+// leftovers of the previous value.
+func (c *context) setUintArray(slot common.Hash, values []*big.Int) {
+	oldSize := c.getState(slot).Int64()
+	dataSlot := arrayDataSlot(slot)
+	c.setState(slot, big.NewInt(int64(len(values))))
+	for i, v := range values {
+		c.setState(offsetSlot(dataSlot, int64(i)), v)
+	}
+	for i := int64(len(values)); i < oldSize; i++ {
+		c.setState(offsetSlot(dataSlot, i), common.Big0)
+	}
+}
+
+func (c *context) now() *big.Int {
+	return new(big.Int).Set(c.blockCtx.Time)
+}
+
+// emit adds a log of the named event, topics are the indexed arguments.
+func (c *context) emit(name string, topics []common.Hash, args ...interface{}) error {
+	event := sfcAbi.Events[name]
+	data, err := event.Inputs.NonIndexed().Pack(args...)
+	if err != nil {
+		return err
+	}
+	c.stateDB.AddLog(&types.Log{
+		Address:     ContractAddress,
+		Topics:      append([]common.Hash{event.ID}, topics...),
+		Data:        data,
+		BlockNumber: c.blockCtx.BlockNumber.Uint64(),
+	})
+	return nil
+}
+
+// payOut mirrors a transfer of native tokens from the SFC. Only the SFC side of
+// the transfer is tracked, the SFC state doesn't keep user accounts.
+func (c *context) payOut(amount *big.Int) {
+	balance := c.stateDB.GetBalance(ContractAddress)
+	if balance.Cmp(amount) < 0 {
+		amount = balance
+	}
+	c.stateDB.SubBalance(ContractAddress, amount)
+}
+
+// SafeMath operations, reverting the same way the contract does
+
+func safeAdd(a, b *big.Int) (*big.Int, error) {
+	res := new(big.Int).Add(a, b)
+	if res.Cmp(math.MaxBig256) > 0 {
+		return nil, errAdditionOverflow
+	}
+	return res, nil
+}
+
+func safeSub(a, b *big.Int) (*big.Int, error) {
+	if b.Cmp(a) > 0 {
+		return nil, errSubtractionOverflow
+	}
+	return new(big.Int).Sub(a, b), nil
+}
+
+func safeMul(a, b *big.Int) (*big.Int, error) {
+	res := new(big.Int).Mul(a, b)
+	if res.Cmp(math.MaxBig256) > 0 {
+		return nil, errMultiplicationOverflow
+	}
+	return res, nil
+}
+
+func safeDiv(a, b *big.Int) (*big.Int, error) {
+	if b.Sign() == 0 {
+		return nil, errDivisionByZero
+	}
+	return new(big.Int).Div(a, b), nil
+}
+
+// mulDiv computes a.mul(b).div(d)
+func mulDiv(a, b, d *big.Int) (*big.Int, error) {
+	res, err := safeMul(a, b)
+	if err != nil {
+		return nil, err
+	}
+	return safeDiv(res, d)
+}
+
+// Unchecked uint256 operations, wrapping around the same way the EVM does
+
+func add(a, b *big.Int) *big.Int {
+	return math.U256(new(big.Int).Add(a, b))
+}
+
+func sub(a, b *big.Int) *big.Int {
+	return math.U256(new(big.Int).Sub(a, b))
+}
+
+func mul(a, b *big.Int) *big.Int {
+	return math.U256(new(big.Int).Mul(a, b))
+}
+
+// div is only used with divisors which are known to be non-zero
+func div(a, b *big.Int) *big.Int {
+	return new(big.Int).Div(a, b)
+}
@@ -0,0 +1,273 @@
+package sfc
+
+import (
+	"math/big"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+)
+
+// Gas price limits of the GP library
+var (
+	maxGasPriceChangeRatio = new(big.Int).Div(new(big.Int).Mul(decimalUnit, big.NewInt(105)), big.NewInt(100))
+	minGasPriceChangeRatio = new(big.Int).Div(new(big.Int).Mul(decimalUnit, big.NewInt(95)), big.NewInt(100))
+	maxMinGasPrice         = big.NewInt(1000000 * 1e9)
+	minMinGasPrice         = big.NewInt(1e9)
+	initialMinGasPrice     = big.NewInt(100 * 1e9)
+)
+
+func trimGasPriceChangeRatio(x *big.Int) *big.Int {
+	if x.Cmp(maxGasPriceChangeRatio) > 0 {
+		return maxGasPriceChangeRatio
+	}
+	if x.Cmp(minGasPriceChangeRatio) < 0 {
+		return minGasPriceChangeRatio
+	}
+	return x
+}
+
+func trimMinGasPrice(x *big.Int) *big.Int {
+	if x.Cmp(maxMinGasPrice) > 0 {
+		return maxMinGasPrice
+	}
+	if x.Cmp(minMinGasPrice) < 0 {
+		return minMinGasPrice
+	}
+	return x
+}
+
+func calcRawValidatorEpochBaseReward(epochDuration, baseRewardPerSecond, baseRewardWeight, totalBaseRewardWeight *big.Int) (*big.Int, error) {
+	if baseRewardWeight.Sign() == 0 {
+		return new(big.Int), nil
+	}
+	totalReward, err := safeMul(epochDuration, baseRewardPerSecond)
+	if err != nil {
+		return nil, err
+	}
+	return mulDiv(totalReward, baseRewardWeight, totalBaseRewardWeight)
+}
+
+func (c *context) calcRawValidatorEpochTxReward(epochFee, txRewardWeight, totalTxRewardWeight *big.Int) (*big.Int, error) {
+	if txRewardWeight.Sign() == 0 {
+		return new(big.Int), nil
+	}
+	txReward, err := mulDiv(epochFee, txRewardWeight, totalTxRewardWeight)
+	if err != nil {
+		return nil, err
+	}
+	// fee reward except burntFeeShare and treasuryFeeShare
+	consts := c.consts()
+	return mulDiv(txReward, sub(sub(decimalUnit, consts.burntFeeShare()), consts.treasuryFeeShare()), decimalUnit)
+}
+
+func (c *context) sealEpochOffline(epoch *big.Int, validatorIDs, offlineTime, offlineBlocks []*big.Int) error {
+	consts := c.consts()
+	for i, validatorID := range validatorIDs {
+		if offlineBlocks[i].Cmp(consts.offlinePenaltyThresholdBlocksNum()) > 0 && offlineTime[i].Cmp(consts.offlinePenaltyThresholdTime()) >= 0 {
+			if err := c.setValidatorDeactivated(validatorID, big.NewInt(offlineBit)); err != nil {
+				return err
+			}
+			if err := c.syncValidator(validatorID); err != nil {
+				return err
+			}
+		}
+		// log data
+		c.setState(epochValidatorSlot(epoch, snapshotOfflineTime, validatorID), offlineTime[i])
+		c.setState(epochValidatorSlot(epoch, snapshotOfflineBlocks, validatorID), offlineBlocks[i])
+	}
+	return nil
+}
+
+func (c *context) sealEpochRewards(epochDuration *big.Int, epoch, prevEpoch *big.Int, validatorIDs, uptimes, accumulatedOriginatedTxsFee []*big.Int) error {
+	var (
+		baseRewardWeights     = make([]*big.Int, len(validatorIDs))
+		totalBaseRewardWeight = new(big.Int)
+		txRewardWeights       = make([]*big.Int, len(validatorIDs))
+		totalTxRewardWeight   = new(big.Int)
+		epochFee              = new(big.Int)
+		err                   error
+	)
+
+	for i, validatorID := range validatorIDs {
+		prevAccumulatedTxsFee := c.getState(epochValidatorSlot(prevEpoch, snapshotAccumulatedOriginatedTxsFee, validatorID))
+		originatedTxsFee := new(big.Int)
+		if accumulatedOriginatedTxsFee[i].Cmp(prevAccumulatedTxsFee) > 0 {
+			originatedTxsFee = sub(accumulatedOriginatedTxsFee[i], prevAccumulatedTxsFee)
+		}
+		// txRewardWeight = {originatedTxsFee} * {uptime}
+		// originatedTxsFee is roughly proportional to {uptime} * {stake}, so the whole formula is roughly
+		// {stake} * {uptime} ^ 2
+		txRewardWeights[i] = div(mul(originatedTxsFee, uptimes[i]), epochDuration)
+		if totalTxRewardWeight, err = safeAdd(totalTxRewardWeight, txRewardWeights[i]); err != nil {
+			return err
+		}
+		if epochFee, err = safeAdd(epochFee, originatedTxsFee); err != nil {
+			return err
+		}
+	}
+
+	for i, validatorID := range validatorIDs {
+		// baseRewardWeight = {stake} * {uptime ^ 2}
+		receivedStake := c.getState(epochValidatorSlot(epoch, snapshotReceivedStake, validatorID))
+		baseRewardWeights[i] = div(mul(div(mul(receivedStake, uptimes[i]), epochDuration), uptimes[i]), epochDuration)
+		if totalBaseRewardWeight, err = safeAdd(totalBaseRewardWeight, baseRewardWeights[i]); err != nil {
+			return err
+		}
+	}
+
+	consts := c.consts()
+	for i, validatorID := range validatorIDs {
+		rawReward, err := calcRawValidatorEpochBaseReward(epochDuration, consts.baseRewardPerSecond(), baseRewardWeights[i], totalBaseRewardWeight)
+		if err != nil {
+			return err
+		}
+		txReward, err := c.calcRawValidatorEpochTxReward(epochFee, txRewardWeights[i], totalTxRewardWeight)
+		if err != nil {
+			return err
+		}
+		if rawReward, err = safeAdd(rawReward, txReward); err != nil {
+			return err
+		}
+
+		validatorAddr := c.validatorAuth(validatorID)
+		// accounting validator's commission
+		commissionRewardFull, err := mulDiv(rawReward, consts.validatorCommission(), decimalUnit)
+		if err != nil {
+			return err
+		}
+		selfStake := c.stake(validatorAddr, validatorID)
+		if selfStake.Sign() != 0 {
+			lCommissionRewardFull := div(mul(commissionRewardFull, c.getLockedStake(validatorAddr, validatorID)), selfStake)
+			uCommissionRewardFull := sub(commissionRewardFull, lCommissionRewardFull)
+			lCommissionReward, err := c.scaleLockupReward(lCommissionRewardFull, c.lockup(validatorAddr, validatorID, lockupDuration))
+			if err != nil {
+				return err
+			}
+			uCommissionReward, err := c.scaleLockupReward(uCommissionRewardFull, common.Big0)
+			if err != nil {
+				return err
+			}
+			stashSlot := delegationSlot(rewardsStashSlot, validatorAddr, validatorID)
+			stash, err := sumRewards(c.getRewards(stashSlot), lCommissionReward, uCommissionReward)
+			if err != nil {
+				return err
+			}
+			c.setRewards(stashSlot, stash)
+			lockupStashSlot := delegationSlot(stashedLockupRewardsSlot, validatorAddr, validatorID)
+			lockupStash, err := sumRewards(c.getRewards(lockupStashSlot), lCommissionReward, uCommissionReward)
+			if err != nil {
+				return err
+			}
+			c.setRewards(lockupStashSlot, lockupStash)
+		}
+		// accounting reward per token for delegators
+		delegatorsReward := sub(rawReward, commissionRewardFull)
+		// note: use latest stake for the sake of rewards distribution accuracy, not snapshot.receivedStake
+		receivedStake := c.validator(validatorID, validatorReceivedStake)
+		rewardPerToken := new(big.Int)
+		if receivedStake.Sign() != 0 {
+			rewardPerToken = div(mul(delegatorsReward, decimalUnit), receivedStake)
+		}
+		prevRewardPerToken := c.getState(epochValidatorSlot(prevEpoch, snapshotAccumulatedRewardPerToken, validatorID))
+		c.setState(epochValidatorSlot(epoch, snapshotAccumulatedRewardPerToken, validatorID), add(prevRewardPerToken, rewardPerToken))
+
+		c.setState(epochValidatorSlot(epoch, snapshotAccumulatedOriginatedTxsFee, validatorID), accumulatedOriginatedTxsFee[i])
+		prevUptime := c.getState(epochValidatorSlot(prevEpoch, snapshotAccumulatedUptime, validatorID))
+		c.setState(epochValidatorSlot(epoch, snapshotAccumulatedUptime, validatorID), add(prevUptime, uptimes[i]))
+	}
+
+	c.setState(epochSnapshotSlotOf(epoch, snapshotEpochFee), epochFee)
+	c.setState(epochSnapshotSlotOf(epoch, snapshotTotalBaseRewardWeight), totalBaseRewardWeight)
+	c.setState(epochSnapshotSlotOf(epoch, snapshotTotalTxRewardWeight), totalTxRewardWeight)
+	totalSupply := c.getState(slotOf(totalSupplySlot))
+	if totalSupply.Cmp(epochFee) > 0 {
+		c.setState(slotOf(totalSupplySlot), sub(totalSupply, epochFee))
+	} else {
+		c.setState(slotOf(totalSupplySlot), common.Big0)
+	}
+
+	// transfer 10% of fees to treasury
+	if c.getAddress(slotOf(treasuryAddressSlot)) != (common.Address{}) {
+		feeShare := div(mul(epochFee, consts.treasuryFeeShare()), decimalUnit)
+		if err := c.mintNativeToken(feeShare); err != nil {
+			return err
+		}
+		c.payOut(feeShare)
+	}
+	return nil
+}
+
+func (c *context) sealEpochMinGasPrice(epochDuration *big.Int, epochGas *big.Int) {
+	consts := c.consts()
+	// change minGasPrice proportionally to the difference between target and received epoch gas
+	targetEpochGas := add(mul(epochDuration, consts.targetGasPowerPerSecond()), common.Big1)
+	gasPriceDeltaRatio := div(mul(epochGas, decimalUnit), targetEpochGas)
+	counterweight := consts.gasPriceBalancingCounterweight()
+	// scale down the change speed (estimate gasPriceDeltaRatio ^ (epochDuration / counterweight))
+	gasPriceDeltaRatio = div(add(mul(epochDuration, gasPriceDeltaRatio), mul(counterweight, decimalUnit)), add(epochDuration, counterweight))
+	// limit the max/min possible delta in one epoch
+	gasPriceDeltaRatio = trimGasPriceChangeRatio(gasPriceDeltaRatio)
+
+	// apply the ratio
+	newMinGasPrice := div(mul(c.getState(slotOf(minGasPriceSlot)), gasPriceDeltaRatio), decimalUnit)
+	// limit the max/min possible minGasPrice
+	newMinGasPrice = trimMinGasPrice(newMinGasPrice)
+	// apply new minGasPrice
+	c.setState(slotOf(minGasPriceSlot), newMinGasPrice)
+}
+
+func handleSealEpoch(c *context, args []interface{}) ([]byte, error) {
+	if err := c.onlyDriver(); err != nil {
+		return nil, err
+	}
+	offlineTime := args[0].([]*big.Int)
+	offlineBlocks := args[1].([]*big.Int)
+	uptimes := args[2].([]*big.Int)
+	originatedTxsFee := args[3].([]*big.Int)
+	epochGas := args[4].(*big.Int)
+
+	epoch := c.currentEpoch()
+	validatorIDs := c.getUintArray(epochSnapshotSlotOf(epoch, snapshotValidatorIDs))
+	if len(offlineTime) < len(validatorIDs) || len(offlineBlocks) < len(validatorIDs) ||
+		len(uptimes) < len(validatorIDs) || len(originatedTxsFee) < len(validatorIDs) {
+		return nil, errIndexOutOfBounds
+	}
+
+	if err := c.sealEpochOffline(epoch, validatorIDs, offlineTime, offlineBlocks); err != nil {
+		return nil, err
+	}
+	prevEpoch := c.currentSealedEpoch()
+	prevEndTime := c.getState(epochSnapshotSlotOf(prevEpoch, snapshotEndTime))
+	epochDuration := big.NewInt(1)
+	if c.now().Cmp(prevEndTime) > 0 {
+		epochDuration = sub(c.now(), prevEndTime)
+	}
+	if err := c.sealEpochRewards(epochDuration, epoch, prevEpoch, validatorIDs, uptimes, originatedTxsFee); err != nil {
+		return nil, err
+	}
+	c.sealEpochMinGasPrice(epochDuration, epochGas)
+
+	c.setState(slotOf(currentSealedEpochSlot), epoch)
+	c.setState(epochSnapshotSlotOf(epoch, snapshotEndTime), c.now())
+	c.setState(epochSnapshotSlotOf(epoch, snapshotBaseRewardPerSecond), c.consts().baseRewardPerSecond())
+	c.setState(epochSnapshotSlotOf(epoch, snapshotTotalSupply), c.getState(slotOf(totalSupplySlot)))
+	return nil, nil
+}
+
+func handleSealEpochValidators(c *context, args []interface{}) ([]byte, error) {
+	if err := c.onlyDriver(); err != nil {
+		return nil, err
+	}
+	nextValidatorIDs := args[0].([]*big.Int)
+
+	// fill data for the next snapshot
+	epoch := c.currentEpoch()
+	for _, validatorID := range nextValidatorIDs {
+		receivedStake := c.validator(validatorID, validatorReceivedStake)
+		c.setState(epochValidatorSlot(epoch, snapshotReceivedStake, validatorID), receivedStake)
+		if err := c.addTo(epochSnapshotSlotOf(epoch, snapshotTotalStake), receivedStake); err != nil {
+			return nil, err
+		}
+	}
+	c.setUintArray(epochSnapshotSlotOf(epoch, snapshotValidatorIDs), nextValidatorIDs)
+	return nil, nil
+}
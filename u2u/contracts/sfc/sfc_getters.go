@@ -0,0 +1,227 @@
+package sfc
+
+import (
+	"math/big"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/internal/precompiled"
+)
+
+// version is the value returned by the version() method of the contract
+var version = [3]byte{'3', '0', '4'}
+
+func packOutputs(method string, values ...interface{}) ([]byte, error) {
+	return sfcAbi.Methods[method].Outputs.Pack(values...)
+}
+
+func handleVersion(c *context, args []interface{}) ([]byte, error) {
+	return packOutputs("version", version)
+}
+
+func handleOwner(c *context, args []interface{}) ([]byte, error) {
+	return packOutputs("owner", c.owner())
+}
+
+func handleIsOwner(c *context, args []interface{}) ([]byte, error) {
+	return packOutputs("isOwner", c.caller == c.owner())
+}
+
+func handleCurrentEpoch(c *context, args []interface{}) ([]byte, error) {
+	return packOutputs("currentEpoch", c.currentEpoch())
+}
+
+func handleCurrentSealedEpoch(c *context, args []interface{}) ([]byte, error) {
+	return packOutputs("currentSealedEpoch", c.currentSealedEpoch())
+}
+
+func handleLastValidatorID(c *context, args []interface{}) ([]byte, error) {
+	return packOutputs("lastValidatorID", c.getState(slotOf(lastValidatorIDSlot)))
+}
+
+func handleTotalStake(c *context, args []interface{}) ([]byte, error) {
+	return packOutputs("totalStake", c.getState(slotOf(totalStakeSlot)))
+}
+
+func handleTotalActiveStake(c *context, args []interface{}) ([]byte, error) {
+	return packOutputs("totalActiveStake", c.getState(slotOf(totalActiveStakeSlot)))
+}
+
+func handleTotalSlashedStake(c *context, args []interface{}) ([]byte, error) {
+	return packOutputs("totalSlashedStake", c.getState(slotOf(totalSlashedStakeSlot)))
+}
+
+func handleTotalSupply(c *context, args []interface{}) ([]byte, error) {
+	return packOutputs("totalSupply", c.getState(slotOf(totalSupplySlot)))
+}
+
+func handleMinGasPrice(c *context, args []interface{}) ([]byte, error) {
+	return packOutputs("minGasPrice", c.getState(slotOf(minGasPriceSlot)))
+}
+
+func handleStakeTokenizerAddress(c *context, args []interface{}) ([]byte, error) {
+	return packOutputs("stakeTokenizerAddress", c.getAddress(slotOf(stakeTokenizerAddressSlot)))
+}
+
+func handleTreasuryAddress(c *context, args []interface{}) ([]byte, error) {
+	return packOutputs("treasuryAddress", c.getAddress(slotOf(treasuryAddressSlot)))
+}
+
+func handleVoteBookAddress(c *context, args []interface{}) ([]byte, error) {
+	return packOutputs("voteBookAddress", c.getAddress(slotOf(voteBookAddressSlot)))
+}
+
+func handleConstsAddress(c *context, args []interface{}) ([]byte, error) {
+	return packOutputs("constsAddress", c.getAddress(slotOf(constantsManagerSlot)))
+}
+
+func handleGetValidator(c *context, args []interface{}) ([]byte, error) {
+	validatorID := args[0].(*big.Int)
+	return packOutputs("getValidator",
+		c.validator(validatorID, validatorStatus),
+		c.validator(validatorID, validatorDeactivatedTime),
+		c.validator(validatorID, validatorDeactivatedEpoch),
+		c.validator(validatorID, validatorReceivedStake),
+		c.validator(validatorID, validatorCreatedEpoch),
+		c.validator(validatorID, validatorCreatedTime),
+		c.validatorAuth(validatorID))
+}
+
+func handleGetValidatorID(c *context, args []interface{}) ([]byte, error) {
+	auth := args[0].(common.Address)
+	return packOutputs("getValidatorID", c.getState(validatorIDSlotOf(auth)))
+}
+
+func handleGetValidatorPubkey(c *context, args []interface{}) ([]byte, error) {
+	validatorID := args[0].(*big.Int)
+	return packOutputs("getValidatorPubkey", c.getBytes(validatorPubkeySlotOf(validatorID)))
+}
+
+func handleGetStake(c *context, args []interface{}) ([]byte, error) {
+	delegator := args[0].(common.Address)
+	toValidatorID := args[1].(*big.Int)
+	return packOutputs("getStake", c.stake(delegator, toValidatorID))
+}
+
+func handleGetSelfStake(c *context, args []interface{}) ([]byte, error) {
+	validatorID := args[0].(*big.Int)
+	return packOutputs("getSelfStake", c.getSelfStake(validatorID))
+}
+
+func handleGetLockupInfo(c *context, args []interface{}) ([]byte, error) {
+	delegator := args[0].(common.Address)
+	toValidatorID := args[1].(*big.Int)
+	return packOutputs("getLockupInfo",
+		c.lockup(delegator, toValidatorID, lockupLockedStake),
+		c.lockup(delegator, toValidatorID, lockupFromEpoch),
+		c.lockup(delegator, toValidatorID, lockupEndTime),
+		c.lockup(delegator, toValidatorID, lockupDuration))
+}
+
+func handleGetStashedLockupRewards(c *context, args []interface{}) ([]byte, error) {
+	delegator := args[0].(common.Address)
+	toValidatorID := args[1].(*big.Int)
+	r := c.getRewards(delegationSlot(stashedLockupRewardsSlot, delegator, toValidatorID))
+	return packOutputs("getStashedLockupRewards", r.lockupExtraReward, r.lockupBaseReward, r.unlockedReward)
+}
+
+func handleStashedRewardsUntilEpoch(c *context, args []interface{}) ([]byte, error) {
+	delegator := args[0].(common.Address)
+	toValidatorID := args[1].(*big.Int)
+	return packOutputs("stashedRewardsUntilEpoch", c.getState(delegationSlot(stashedRewardsUntilEpochSlot, delegator, toValidatorID)))
+}
+
+func handleGetWithdrawalRequest(c *context, args []interface{}) ([]byte, error) {
+	delegator := args[0].(common.Address)
+	toValidatorID := args[1].(*big.Int)
+	wrID := args[2].(*big.Int)
+	return packOutputs("getWithdrawalRequest",
+		c.getState(withdrawalRequestSlotOf(delegator, toValidatorID, wrID, withdrawalEpoch)),
+		c.getState(withdrawalRequestSlotOf(delegator, toValidatorID, wrID, withdrawalTime)),
+		c.getState(withdrawalRequestSlotOf(delegator, toValidatorID, wrID, withdrawalAmount)))
+}
+
+func handleSlashingRefundRatio(c *context, args []interface{}) ([]byte, error) {
+	validatorID := args[0].(*big.Int)
+	return packOutputs("slashingRefundRatio", c.getState(slashingRefundRatioSlotOf(validatorID)))
+}
+
+func handleIsSlashed(c *context, args []interface{}) ([]byte, error) {
+	validatorID := args[0].(*big.Int)
+	return packOutputs("isSlashed", c.isSlashed(validatorID))
+}
+
+func handleIsLockedUp(c *context, args []interface{}) ([]byte, error) {
+	delegator := args[0].(common.Address)
+	toValidatorID := args[1].(*big.Int)
+	return packOutputs("isLockedUp", c.isLockedUp(delegator, toValidatorID))
+}
+
+func handleGetLockedStake(c *context, args []interface{}) ([]byte, error) {
+	delegator := args[0].(common.Address)
+	toValidatorID := args[1].(*big.Int)
+	return packOutputs("getLockedStake", c.getLockedStake(delegator, toValidatorID))
+}
+
+func handleGetUnlockedStake(c *context, args []interface{}) ([]byte, error) {
+	delegator := args[0].(common.Address)
+	toValidatorID := args[1].(*big.Int)
+	unlockedStake, err := c.getUnlockedStake(delegator, toValidatorID)
+	if err != nil {
+		return nil, err
+	}
+	return packOutputs("getUnlockedStake", unlockedStake)
+}
+
+func handleRewardsStash(c *context, args []interface{}) ([]byte, error) {
+	delegator := args[0].(common.Address)
+	validatorID := args[1].(*big.Int)
+	stash := c.getRewards(delegationSlot(rewardsStashSlot, delegator, validatorID))
+	total, err := safeAdd(stash.lockupBaseReward, stash.lockupExtraReward)
+	if err != nil {
+		return nil, err
+	}
+	if total, err = safeAdd(total, stash.unlockedReward); err != nil {
+		return nil, err
+	}
+	return packOutputs("rewardsStash", total)
+}
+
+func handlePendingRewards(c *context, args []interface{}) ([]byte, error) {
+	delegator := args[0].(common.Address)
+	toValidatorID := args[1].(*big.Int)
+	reward, err := c.pendingRewards(delegator, toValidatorID)
+	if err != nil {
+		return nil, err
+	}
+	total, err := reward.total()
+	if err != nil {
+		return nil, err
+	}
+	return packOutputs("pendingRewards", total)
+}
+
+func handleGetEpochSnapshot(c *context, args []interface{}) ([]byte, error) {
+	epoch := args[0].(*big.Int)
+	return packOutputs("getEpochSnapshot",
+		c.getState(epochSnapshotSlotOf(epoch, snapshotEndTime)),
+		c.getState(epochSnapshotSlotOf(epoch, snapshotEpochFee)),
+		c.getState(epochSnapshotSlotOf(epoch, snapshotTotalBaseRewardWeight)),
+		c.getState(epochSnapshotSlotOf(epoch, snapshotTotalTxRewardWeight)),
+		c.getState(epochSnapshotSlotOf(epoch, snapshotBaseRewardPerSecond)),
+		c.getState(epochSnapshotSlotOf(epoch, snapshotTotalStake)),
+		c.getState(epochSnapshotSlotOf(epoch, snapshotTotalSupply)))
+}
+
+func handleGetEpochValidatorIDs(c *context, args []interface{}) ([]byte, error) {
+	epoch := args[0].(*big.Int)
+	return packOutputs("getEpochValidatorIDs", c.getUintArray(epochSnapshotSlotOf(epoch, snapshotValidatorIDs)))
+}
+
+// epochValidatorGetter returns the handler of a getter of a per-validator value of the epoch snapshot
+func epochValidatorGetter(method string, field int64) precompiled.Handler[*context] {
+	return func(c *context, args []interface{}) ([]byte, error) {
+		epoch := args[0].(*big.Int)
+		validatorID := args[1].(*big.Int)
+		return packOutputs(method, c.getState(epochValidatorSlot(epoch, field, validatorID)))
+	}
+}
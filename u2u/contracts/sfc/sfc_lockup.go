@@ -0,0 +1,145 @@
+package sfc
+
+import (
+	"math/big"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+)
+
+func (c *context) lockStake(delegator common.Address, toValidatorID *big.Int, duration *big.Int, amount *big.Int) error {
+	unlockedStake, err := c.getUnlockedStake(delegator, toValidatorID)
+	if err != nil {
+		return err
+	}
+	if err := require(amount.Cmp(unlockedStake) <= 0, "not enough stake"); err != nil {
+		return err
+	}
+	if err := require(c.validator(toValidatorID, validatorStatus).Sign() == okStatus, "validator isn't active"); err != nil {
+		return err
+	}
+
+	consts := c.consts()
+	if err := require(duration.Cmp(consts.minLockupDuration()) >= 0 && duration.Cmp(consts.maxLockupDuration()) <= 0, "incorrect duration"); err != nil {
+		return err
+	}
+	endTime, err := safeAdd(c.now(), duration)
+	if err != nil {
+		return err
+	}
+	validatorAddr := c.validatorAuth(toValidatorID)
+	if delegator != validatorAddr {
+		validatorEndTime := c.lockup(validatorAddr, toValidatorID, lockupEndTime)
+		if err := require(validatorEndTime.Cmp(endTime) >= 0, "validator lockup period will end earlier"); err != nil {
+			return err
+		}
+	}
+
+	if _, err := c.stashRewards(delegator, toValidatorID); err != nil {
+		return err
+	}
+
+	// check lockup duration after stashRewards, which has erased previous lockup if it has unlocked already
+	if err := require(duration.Cmp(c.lockup(delegator, toValidatorID, lockupDuration)) >= 0, "lockup duration cannot decrease"); err != nil {
+		return err
+	}
+
+	lockedStake, err := safeAdd(c.lockup(delegator, toValidatorID, lockupLockedStake), amount)
+	if err != nil {
+		return err
+	}
+	c.setLockup(delegator, toValidatorID, lockupLockedStake, lockedStake)
+	c.setLockup(delegator, toValidatorID, lockupFromEpoch, c.currentEpoch())
+	c.setLockup(delegator, toValidatorID, lockupEndTime, endTime)
+	c.setLockup(delegator, toValidatorID, lockupDuration, duration)
+
+	return c.emit("LockedUpStake", []common.Hash{addressKey(delegator), uintKey(toValidatorID)}, duration, amount)
+}
+
+// popDelegationUnlockPenalty takes the share of the unlocked amount out of the stashed lockup rewards.
+// The deployed contract doesn't charge the penalty, so the share is forfeited and zero is returned.
+func (c *context) popDelegationUnlockPenalty(delegator common.Address, toValidatorID *big.Int, unlockAmount *big.Int, totalAmount *big.Int) (*big.Int, error) {
+	lockupStashSlot := delegationSlot(stashedLockupRewardsSlot, delegator, toValidatorID)
+	lockupRewards := c.getRewards(lockupStashSlot)
+	lockupExtraRewardShare, err := mulDiv(lockupRewards.lockupExtraReward, unlockAmount, totalAmount)
+	if err != nil {
+		return nil, err
+	}
+	lockupBaseRewardShare, err := mulDiv(lockupRewards.lockupBaseReward, unlockAmount, totalAmount)
+	if err != nil {
+		return nil, err
+	}
+	if lockupRewards.lockupExtraReward, err = safeSub(lockupRewards.lockupExtraReward, lockupExtraRewardShare); err != nil {
+		return nil, err
+	}
+	if lockupRewards.lockupBaseReward, err = safeSub(lockupRewards.lockupBaseReward, lockupBaseRewardShare); err != nil {
+		return nil, err
+	}
+	c.setState(offsetSlot(lockupStashSlot, rewardsLockupExtraReward), lockupRewards.lockupExtraReward)
+	c.setState(offsetSlot(lockupStashSlot, rewardsLockupBaseReward), lockupRewards.lockupBaseReward)
+	return new(big.Int), nil
+}
+
+func handleLockStake(c *context, args []interface{}) ([]byte, error) {
+	delegator := c.caller
+	toValidatorID := args[0].(*big.Int)
+	duration := args[1].(*big.Int)
+	amount := args[2].(*big.Int)
+	if err := require(amount.Sign() > 0, "zero amount"); err != nil {
+		return nil, err
+	}
+	if err := require(!c.isLockedUp(delegator, toValidatorID), "already locked up"); err != nil {
+		return nil, err
+	}
+	return nil, c.lockStake(delegator, toValidatorID, duration, amount)
+}
+
+func handleRelockStake(c *context, args []interface{}) ([]byte, error) {
+	delegator := c.caller
+	toValidatorID := args[0].(*big.Int)
+	duration := args[1].(*big.Int)
+	amount := args[2].(*big.Int)
+	return nil, c.lockStake(delegator, toValidatorID, duration, amount)
+}
+
+func handleUnlockStake(c *context, args []interface{}) ([]byte, error) {
+	delegator := c.caller
+	toValidatorID := args[0].(*big.Int)
+	amount := args[1].(*big.Int)
+
+	if err := require(amount.Sign() > 0, "zero amount"); err != nil {
+		return nil, err
+	}
+	if err := require(c.isLockedUp(delegator, toValidatorID), "not locked up"); err != nil {
+		return nil, err
+	}
+	lockedStake := c.lockup(delegator, toValidatorID, lockupLockedStake)
+	if err := require(amount.Cmp(lockedStake) <= 0, "not enough locked stake"); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.stashRewards(delegator, toValidatorID); err != nil {
+		return nil, err
+	}
+
+	penalty, err := c.popDelegationUnlockPenalty(delegator, toValidatorID, amount, lockedStake)
+	if err != nil {
+		return nil, err
+	}
+	if penalty.Cmp(amount) > 0 {
+		penalty = amount
+	}
+	c.setLockup(delegator, toValidatorID, lockupLockedStake, sub(lockedStake, amount))
+	if penalty.Sign() != 0 {
+		if err := c.rawUndelegate(delegator, toValidatorID, penalty, true); err != nil {
+			return nil, err
+		}
+		if err := c.burnFTM(penalty); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.emit("UnlockedStake", []common.Hash{addressKey(delegator), uintKey(toValidatorID)}, amount, penalty); err != nil {
+		return nil, err
+	}
+	return sfcAbi.Methods["unlockStake"].Outputs.Pack(penalty)
+}
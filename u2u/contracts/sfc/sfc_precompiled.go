@@ -1,14 +1,144 @@
 package sfc
 
 import (
+	"math/big"
+	"strings"
+
+	"github.com/unicornultrafoundation/go-u2u/accounts/abi"
 	"github.com/unicornultrafoundation/go-u2u/common"
 	"github.com/unicornultrafoundation/go-u2u/core/vm"
+	"github.com/unicornultrafoundation/go-u2u/gossip/contract/sfc100"
+	"github.com/unicornultrafoundation/go-u2u/gossip/contract/sfclib100"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/internal/precompiled"
 )
 
+var (
+	// sfcAbi is the joint ABI of SFC and SFCLib, the SFC delegates all the
+	// methods it doesn't implement to SFCLib
+	sfcAbi abi.ABI
+
+	handlers = map[string]precompiled.Handler[*context]{
+		// SFC
+		"initialize":                  handleInitialize,
+		"version":                     handleVersion,
+		"updateStakeTokenizerAddress": addressSetter(stakeTokenizerAddressSlot),
+		"updateLibAddress":            addressSetter(libAddressSlot),
+		"updateTreasuryAddress":       addressSetter(treasuryAddressSlot),
+		"updateConstsAddress":         addressSetter(constantsManagerSlot),
+		"updateVoteBookAddress":       addressSetter(voteBookAddressSlot),
+		"constsAddress":               handleConstsAddress,
+		"sealEpoch":                   handleSealEpoch,
+		"sealEpochValidators":         handleSealEpochValidators,
+		// Ownable
+		"owner":             handleOwner,
+		"isOwner":           handleIsOwner,
+		"transferOwnership": handleTransferOwnership,
+		"renounceOwnership": handleRenounceOwnership,
+		// SFCState
+		"currentEpoch":             handleCurrentEpoch,
+		"currentSealedEpoch":       handleCurrentSealedEpoch,
+		"lastValidatorID":          handleLastValidatorID,
+		"totalStake":               handleTotalStake,
+		"totalActiveStake":         handleTotalActiveStake,
+		"totalSlashedStake":        handleTotalSlashedStake,
+		"totalSupply":              handleTotalSupply,
+		"minGasPrice":              handleMinGasPrice,
+		"stakeTokenizerAddress":    handleStakeTokenizerAddress,
+		"treasuryAddress":          handleTreasuryAddress,
+		"voteBookAddress":          handleVoteBookAddress,
+		"getValidator":             handleGetValidator,
+		"getValidatorID":           handleGetValidatorID,
+		"getValidatorPubkey":       handleGetValidatorPubkey,
+		"getStake":                 handleGetStake,
+		"getLockupInfo":            handleGetLockupInfo,
+		"getStashedLockupRewards":  handleGetStashedLockupRewards,
+		"stashedRewardsUntilEpoch": handleStashedRewardsUntilEpoch,
+		"getWithdrawalRequest":     handleGetWithdrawalRequest,
+		"slashingRefundRatio":      handleSlashingRefundRatio,
+		"getEpochSnapshot":         handleGetEpochSnapshot,
+		"isLockedUp":               handleIsLockedUp,
+		"getLockedStake":           handleGetLockedStake,
+		"_syncValidator":           handleSyncValidator,
+		// SFCLib
+		"getEpochValidatorIDs":                handleGetEpochValidatorIDs,
+		"getEpochReceivedStake":               epochValidatorGetter("getEpochReceivedStake", snapshotReceivedStake),
+		"getEpochAccumulatedRewardPerToken":   epochValidatorGetter("getEpochAccumulatedRewardPerToken", snapshotAccumulatedRewardPerToken),
+		"getEpochAccumulatedUptime":           epochValidatorGetter("getEpochAccumulatedUptime", snapshotAccumulatedUptime),
+		"getEpochAccumulatedOriginatedTxsFee": epochValidatorGetter("getEpochAccumulatedOriginatedTxsFee", snapshotAccumulatedOriginatedTxsFee),
+		"getEpochOfflineTime":                 epochValidatorGetter("getEpochOfflineTime", snapshotOfflineTime),
+		"getEpochOfflineBlocks":               epochValidatorGetter("getEpochOfflineBlocks", snapshotOfflineBlocks),
+		"rewardsStash":                        handleRewardsStash,
+		"setGenesisValidator":                 handleSetGenesisValidator,
+		"setGenesisDelegation":                handleSetGenesisDelegation,
+		"createValidator":                     handleCreateValidator,
+		"getSelfStake":                        handleGetSelfStake,
+		"delegate":                            handleDelegate,
+		"recountVotes":                        handleRecountVotes,
+		"undelegate":                          handleUndelegate,
+		"isSlashed":                           handleIsSlashed,
+		"withdraw":                            handleWithdraw,
+		"deactivateValidator":                 handleDeactivateValidator,
+		"pendingRewards":                      handlePendingRewards,
+		"stashRewards":                        handleStashRewards,
+		"claimRewards":                        handleClaimRewards,
+		"restakeRewards":                      handleRestakeRewards,
+		"mintFTM":                             handleMintFTM,
+		"burnFTM":                             handleBurnFTM,
+		"getUnlockedStake":                    handleGetUnlockedStake,
+		"lockStake":                           handleLockStake,
+		"relockStake":                         handleRelockStake,
+		"unlockStake":                         handleUnlockStake,
+		"updateSlashingRefundRatio":           handleUpdateSlashingRefundRatio,
+	}
+
+	sfc *precompiled.Contract[*context]
+)
+
+func init() {
+	var err error
+	sfcAbi, err = abi.JSON(strings.NewReader(sfc100.ContractABI))
+	if err != nil {
+		panic(err)
+	}
+	lib, err := abi.JSON(strings.NewReader(sfclib100.ContractABI))
+	if err != nil {
+		panic(err)
+	}
+	for name, method := range lib.Methods {
+		if _, ok := sfcAbi.Methods[name]; !ok {
+			sfcAbi.Methods[name] = method
+		}
+	}
+	for name, event := range lib.Events {
+		if _, ok := sfcAbi.Events[name]; !ok {
+			sfcAbi.Events[name] = event
+		}
+	}
+	sfc = &precompiled.Contract[*context]{
+		Name:     "SFC",
+		Abi:      sfcAbi,
+		Handlers: handlers,
+	}
+	sfc.Validate()
+}
+
 // SfcPrecompile implements PrecompiledStateContract interface
 type SfcPrecompile struct{}
 
-// Run runs the precompiled contract
-func (p *SfcPrecompile) Run(stateDB vm.StateDB, blockCtx vm.BlockContext, txCtx vm.TxContext, caller common.Address, input []byte, suppliedGas uint64) ([]byte, uint64, error) {
-	return nil, 0, nil
+// Run runs the precompiled contract. It mirrors the storage writes and events
+// of the SFC bytecode. Gas is left to the bytecode, which is executed alongside,
+// so the supplied gas is returned untouched. Calls the contract makes to other
+// contracts (NodeDriverAuth, the vote book, the stake tokenizer, the treasury)
+// aren't repeated, they're executed by the respective precompiles, if any.
+func (p *SfcPrecompile) Run(stateDB vm.StateDB, blockCtx vm.BlockContext, txCtx vm.TxContext, caller common.Address, input []byte, suppliedGas uint64, value *big.Int) ([]byte, uint64, error) {
+	if len(input) == 0 {
+		return precompiled.RevertOutput("transfers not allowed"), suppliedGas, vm.ErrExecutionReverted
+	}
+	c := &context{
+		stateDB:  stateDB,
+		blockCtx: blockCtx,
+		caller:   caller,
+		value:    value,
+	}
+	return sfc.Run(c, input, suppliedGas, value)
 }
@@ -0,0 +1,389 @@
+package sfc_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unicornultrafoundation/go-helios/native/idx"
+
+	"github.com/unicornultrafoundation/go-u2u/accounts/abi"
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/core/rawdb"
+	"github.com/unicornultrafoundation/go-u2u/core/state"
+	"github.com/unicornultrafoundation/go-u2u/core/vm"
+	"github.com/unicornultrafoundation/go-u2u/crypto"
+	"github.com/unicornultrafoundation/go-u2u/evmcore"
+	"github.com/unicornultrafoundation/go-u2u/gossip/contract/sfc100"
+	"github.com/unicornultrafoundation/go-u2u/gossip/contract/sfclib100"
+	"github.com/unicornultrafoundation/go-u2u/integration/makefakegenesis"
+	"github.com/unicornultrafoundation/go-u2u/native"
+	"github.com/unicornultrafoundation/go-u2u/u2u"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/constant_manager"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/driver"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/driver/drivercall"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/driverauth"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/evmwriter"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/netinit"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/sfc"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/sfclib"
+	"github.com/unicornultrafoundation/go-u2u/utils"
+)
+
+// parityEnv executes calls against the SFC bytecode, while the native SFC
+// precompile shadows them in a separate SFC state.
+type parityEnv struct {
+	t        *testing.T
+	state    *state.StateDB
+	sfcState *state.StateDB
+	sfcAbi   abi.ABI
+	libAbi   abi.ABI
+	time     uint64
+	block    uint64
+	// validators of the current epoch
+	validators []idx.ValidatorID
+}
+
+func newParityEnv(t *testing.T, validatorsNum idx.Validator) *parityEnv {
+	stateDB, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err)
+	sfcStateDB, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err)
+	sfcAbi, err := abi.JSON(strings.NewReader(sfc100.ContractABI))
+	require.NoError(t, err)
+	libAbi, err := abi.JSON(strings.NewReader(sfclib100.ContractABI))
+	require.NoError(t, err)
+
+	env := &parityEnv{
+		t:        t,
+		state:    stateDB,
+		sfcState: sfcStateDB,
+		sfcAbi:   sfcAbi,
+		libAbi:   libAbi,
+		time:     uint64(makefakegenesis.FakeGenesisTime.Unix()),
+		block:    1,
+	}
+	for v := idx.ValidatorID(1); v <= idx.ValidatorID(validatorsNum); v++ {
+		env.validators = append(env.validators, v)
+	}
+	stateDB.SetCode(netinit.ContractAddress, netinit.GetContractBin())
+	stateDB.SetCode(driver.ContractAddress, driver.GetContractBin())
+	stateDB.SetCode(driverauth.ContractAddress, driverauth.GetContractBin())
+	stateDB.SetCode(sfc.ContractAddress, sfc.GetContractBin())
+	stateDB.SetCode(sfclib.ContractAddress, sfclib.GetContractBin())
+	stateDB.SetCode(evmwriter.ContractAddress, []byte{0})
+
+	validators := makefakegenesis.GetFakeValidators(validatorsNum)
+	delegations := make([]drivercall.Delegation, 0, len(validators))
+	for _, v := range validators {
+		stateDB.AddBalance(v.Address, utils.ToU2U(1e8))
+		delegations = append(delegations, drivercall.Delegation{
+			Address:            v.Address,
+			ValidatorID:        v.ID,
+			Stake:              utils.ToU2U(5e6),
+			LockedStake:        new(big.Int),
+			EarlyUnlockPenalty: new(big.Int),
+			Rewards:            new(big.Int),
+		})
+	}
+	txs := makefakegenesis.GetGenesisTxs(0, validators, utils.ToU2U(1e9), delegations, validators[0].Address)
+	for _, tx := range txs {
+		_, err := env.call(common.Address{}, *tx.To(), tx.Data(), nil)
+		require.NoError(t, err)
+	}
+	return env
+}
+
+func (env *parityEnv) blockContext() vm.BlockContext {
+	return vm.BlockContext{
+		CanTransfer: evmcore.CanTransfer,
+		Transfer:    evmcore.Transfer,
+		GetHash:     func(uint64) common.Hash { return common.Hash{} },
+		BlockNumber: new(big.Int).SetUint64(env.block),
+		Time:        new(big.Int).SetUint64(env.time),
+		Difficulty:  big.NewInt(1),
+		BaseFee:     big.NewInt(0),
+		GasLimit:    1e12,
+	}
+}
+
+func (env *parityEnv) call(from common.Address, to common.Address, input []byte, value *big.Int) ([]byte, error) {
+	if value == nil {
+		value = new(big.Int)
+	}
+	txCtx := vm.TxContext{Origin: from, GasPrice: big.NewInt(0)}
+	chainCfg := u2u.FakeNetRules().EvmChainConfig(nil)
+	evm := vm.NewEVM(env.blockContext(), txCtx, env.state, env.sfcState, chainCfg, u2u.DefaultVMConfig)
+	ret, _, err := evm.Call(vm.AccountRef(from), to, input, 1e10, value)
+	env.state.Finalise(true)
+	env.sfcState.Finalise(true)
+	env.checkParity()
+	return ret, err
+}
+
+func (env *parityEnv) checkParity() {
+	env.state.IntermediateRoot(true)
+	env.sfcState.IntermediateRoot(true)
+	for addr := range u2u.DefaultVMConfig.SfcPrecompiles {
+		require.Equal(env.t, env.state.GetStorageRoot(addr), env.sfcState.GetStorageRoot(addr),
+			"storage of %s of the bytecode and of the precompile mismatch", addr.Hex())
+	}
+}
+
+func (env *parityEnv) pack(method string, args ...interface{}) []byte {
+	if _, ok := env.sfcAbi.Methods[method]; ok {
+		data, err := env.sfcAbi.Pack(method, args...)
+		require.NoError(env.t, err)
+		return data
+	}
+	data, err := env.libAbi.Pack(method, args...)
+	require.NoError(env.t, err)
+	return data
+}
+
+// sfcCall calls the SFC and checks the precompile returns the same output as
+// the bytecode does. The precompile is run against a copy of the SFC state.
+func (env *parityEnv) sfcCall(from common.Address, value *big.Int, method string, args ...interface{}) ([]byte, error) {
+	input := env.pack(method, args...)
+	if value == nil {
+		value = new(big.Int)
+	}
+	gotRet, _, gotErr := (&sfc.SfcPrecompile{}).Run(env.sfcState.Copy(), env.blockContext(), vm.TxContext{Origin: from}, from, input, 1e10, value)
+	expRet, expErr := env.call(from, sfc.ContractAddress, input, value)
+	require.Equal(env.t, expErr, gotErr, method)
+	require.Equal(env.t, expRet, gotRet, method)
+	return expRet, expErr
+}
+
+func (env *parityEnv) view(method string, args ...interface{}) {
+	_, err := env.sfcCall(common.Address{}, nil, method, args...)
+	require.NoError(env.t, err, method)
+}
+
+// checkViews compares the view methods of both the paths
+func (env *parityEnv) checkViews(delegators []common.Address, validators []idx.ValidatorID) {
+	for _, method := range []string{"version", "owner", "currentEpoch", "currentSealedEpoch", "lastValidatorID",
+		"totalStake", "totalActiveStake", "totalSlashedStake", "totalSupply", "minGasPrice", "treasuryAddress",
+		"stakeTokenizerAddress", "voteBookAddress", "constsAddress", "isOwner"} {
+		env.view(method)
+	}
+	epoch := new(big.Int).SetUint64(env.state.GetState(sfc.ContractAddress, common.BigToHash(big.NewInt(103))).Big().Uint64())
+	env.view("getEpochSnapshot", epoch)
+	env.view("getEpochValidatorIDs", epoch)
+	for _, v := range validators {
+		id := big.NewInt(int64(v))
+		env.view("getValidator", id)
+		env.view("getValidatorPubkey", id)
+		env.view("getSelfStake", id)
+		env.view("isSlashed", id)
+		env.view("slashingRefundRatio", id)
+		for _, method := range []string{"getEpochReceivedStake", "getEpochAccumulatedRewardPerToken", "getEpochAccumulatedUptime",
+			"getEpochAccumulatedOriginatedTxsFee", "getEpochOfflineTime", "getEpochOfflineBlocks"} {
+			env.view(method, epoch, id)
+		}
+		for _, d := range delegators {
+			env.view("getStake", d, id)
+			env.view("getLockupInfo", d, id)
+			env.view("getStashedLockupRewards", d, id)
+			env.view("stashedRewardsUntilEpoch", d, id)
+			env.view("isLockedUp", d, id)
+			env.view("getLockedStake", d, id)
+			env.view("getUnlockedStake", d, id)
+			env.view("rewardsStash", d, id)
+			env.view("pendingRewards", d, id)
+			env.view("getWithdrawalRequest", d, id, big.NewInt(1))
+		}
+	}
+	for _, d := range delegators {
+		env.view("getValidatorID", d)
+	}
+}
+
+func (env *parityEnv) constant(slot int64) *big.Int {
+	return env.state.GetState(constant_manager.ContractAddress, common.BigToHash(big.NewInt(slot))).Big()
+}
+
+// sealEpoch seals the current epoch and starts a new one with the given validators
+func (env *parityEnv) sealEpoch(duration uint64, validators []idx.ValidatorID, offline ...idx.ValidatorID) {
+	env.time += duration
+	env.block++
+	metrics := make([]drivercall.ValidatorEpochMetric, len(env.validators))
+	for i, v := range env.validators {
+		metrics[i] = drivercall.ValidatorEpochMetric{
+			Uptime:          native.Timestamp(duration) * native.Timestamp(1e9),
+			OriginatedTxFee: utils.ToU2U(uint64(i+1) * env.block),
+		}
+		for _, o := range offline {
+			if o == v {
+				metrics[i] = drivercall.ValidatorEpochMetric{
+					Missed: u2u.BlocksMissed{
+						BlocksNum: 1000,
+						Period:    native.Timestamp(duration) * native.Timestamp(1e9),
+					},
+					OriginatedTxFee: new(big.Int),
+				}
+			}
+		}
+	}
+	_, err := env.call(common.Address{}, driver.ContractAddress, drivercall.SealEpoch(metrics), nil)
+	require.NoError(env.t, err)
+	_, err = env.call(common.Address{}, driver.ContractAddress, drivercall.SealEpochValidators(validators), nil)
+	require.NoError(env.t, err)
+	env.validators = validators
+}
+
+func (env *parityEnv) advance(duration uint64) {
+	env.time += duration
+	env.block++
+}
+
+func TestSfcPrecompileParity(t *testing.T) {
+	require := require.New(t)
+	env := newParityEnv(t, 3)
+	validators := []idx.ValidatorID{1, 2, 3}
+	v1 := makefakegenesis.FakeKey(1)
+	owner := crypto.PubkeyToAddress(v1.PublicKey)
+	validator2 := crypto.PubkeyToAddress(makefakegenesis.FakeKey(2).PublicKey)
+	delegator := common.HexToAddress("0xde1e6a7e00000000000000000000000000000001")
+	newValidator := common.HexToAddress("0xde1e6a7e00000000000000000000000000000002")
+	env.state.AddBalance(delegator, utils.ToU2U(1e9))
+	env.state.AddBalance(newValidator, utils.ToU2U(1e8))
+	delegators := []common.Address{owner, validator2, delegator, newValidator}
+	id1, id2 := big.NewInt(1), big.NewInt(2)
+
+	env.sealEpoch(600, validators)
+	env.sealEpoch(600, validators)
+	env.checkViews(delegators, validators)
+
+	// delegations
+	_, err := env.sfcCall(delegator, utils.ToU2U(1e6), "delegate", id1)
+	require.NoError(err)
+	_, err = env.sfcCall(delegator, utils.ToU2U(1e6), "delegate", big.NewInt(100))
+	require.Error(err)
+	_, err = env.sfcCall(delegator, new(big.Int), "delegate", id1)
+	require.Error(err)
+	_, err = env.sfcCall(delegator, utils.ToU2U(5e8), "delegate", id2)
+	require.Error(err)
+	env.sealEpoch(3600, validators)
+	env.checkViews(delegators, validators)
+
+	// rewards
+	_, err = env.sfcCall(delegator, nil, "claimRewards", id1)
+	require.NoError(err)
+	_, err = env.sfcCall(delegator, nil, "claimRewards", id1)
+	require.Error(err)
+	env.sealEpoch(3600, validators)
+	_, err = env.sfcCall(delegator, nil, "restakeRewards", id1)
+	require.NoError(err)
+	_, err = env.sfcCall(delegator, nil, "stashRewards", owner, id1)
+	require.NoError(err)
+	_, err = env.sfcCall(delegator, nil, "stashRewards", owner, id1)
+	require.Error(err)
+	env.checkViews(delegators, validators)
+
+	// lockups
+	minLockup, maxLockup := env.constant(108), env.constant(109)
+	_, err = env.sfcCall(delegator, nil, "lockStake", id1, minLockup, utils.ToU2U(1e5))
+	require.Error(err)
+	_, err = env.sfcCall(owner, nil, "lockStake", id1, maxLockup, utils.ToU2U(1e6))
+	require.NoError(err)
+	_, err = env.sfcCall(owner, nil, "lockStake", id1, maxLockup, utils.ToU2U(1e6))
+	require.Error(err)
+	env.advance(1)
+	_, err = env.sfcCall(delegator, nil, "lockStake", id1, maxLockup, utils.ToU2U(1e5))
+	require.Error(err)
+	_, err = env.sfcCall(delegator, nil, "lockStake", id1, minLockup, utils.ToU2U(5e5))
+	require.NoError(err)
+	_, err = env.sfcCall(delegator, nil, "lockStake", id1, minLockup, utils.ToU2U(1))
+	require.Error(err)
+	env.sealEpoch(3600, validators)
+	env.sealEpoch(3600, validators)
+	env.checkViews(delegators, validators)
+	_, err = env.sfcCall(delegator, nil, "relockStake", id1, new(big.Int).Add(minLockup, big.NewInt(3600)), utils.ToU2U(1e5))
+	require.NoError(err)
+	_, err = env.sfcCall(delegator, nil, "relockStake", id1, minLockup, utils.ToU2U(1e5))
+	require.Error(err)
+	env.sealEpoch(3600, validators)
+	_, err = env.sfcCall(delegator, nil, "unlockStake", id1, utils.ToU2U(2e5))
+	require.NoError(err)
+	_, err = env.sfcCall(owner, nil, "unlockStake", id1, utils.ToU2U(5e5))
+	require.NoError(err)
+	_, err = env.sfcCall(delegator, nil, "unlockStake", id1, utils.ToU2U(1e7))
+	require.Error(err)
+	_, err = env.sfcCall(delegator, nil, "undelegate", id1, big.NewInt(1), utils.ToU2U(1e6))
+	require.Error(err)
+	env.checkViews(delegators, validators)
+
+	// withdrawals
+	_, err = env.sfcCall(delegator, nil, "undelegate", id1, big.NewInt(1), utils.ToU2U(1e5))
+	require.NoError(err)
+	_, err = env.sfcCall(delegator, nil, "undelegate", id1, big.NewInt(1), utils.ToU2U(1e5))
+	require.Error(err)
+	_, err = env.sfcCall(delegator, nil, "withdraw", id1, big.NewInt(1))
+	require.Error(err)
+	env.advance(env.constant(111).Uint64())
+	_, err = env.sfcCall(delegator, nil, "withdraw", id1, big.NewInt(1))
+	require.Error(err)
+	for i := uint64(0); i < env.constant(110).Uint64(); i++ {
+		env.sealEpoch(60, validators)
+	}
+	_, err = env.sfcCall(delegator, nil, "withdraw", id1, big.NewInt(1))
+	require.NoError(err)
+	_, err = env.sfcCall(delegator, nil, "withdraw", id1, big.NewInt(1))
+	require.Error(err)
+	env.checkViews(delegators, validators)
+
+	// new validators
+	minSelfStake := env.constant(102)
+	_, err = env.sfcCall(newValidator, new(big.Int).Sub(minSelfStake, big.NewInt(1)), "createValidator", []byte{0xc0, 0x01})
+	require.Error(err)
+	_, err = env.sfcCall(newValidator, minSelfStake, "createValidator", []byte{})
+	require.Error(err)
+	_, err = env.sfcCall(newValidator, minSelfStake, "createValidator", make([]byte, 66))
+	require.NoError(err)
+	_, err = env.sfcCall(newValidator, minSelfStake, "createValidator", make([]byte, 66))
+	require.Error(err)
+	validators = append(validators, 4)
+	env.sealEpoch(3600, validators)
+	env.checkViews(delegators, validators)
+
+	// offline and slashed validators
+	env.sealEpoch(3600, validators, 3)
+	validators = validators[:2]
+	validators = append(validators, 4)
+	_, err = env.call(common.Address{}, driver.ContractAddress, drivercall.DeactivateValidator(2, 1<<7), nil)
+	require.NoError(err)
+	_, err = env.sfcCall(validator2, nil, "undelegate", id2, big.NewInt(1), utils.ToU2U(1e6))
+	require.NoError(err)
+	_, err = env.sfcCall(owner, nil, "updateSlashingRefundRatio", id1, big.NewInt(1e17))
+	require.Error(err)
+	_, err = env.sfcCall(owner, nil, "updateSlashingRefundRatio", id2, big.NewInt(5e17))
+	require.NoError(err)
+	for i := uint64(0); i < env.constant(110).Uint64(); i++ {
+		env.sealEpoch(env.constant(111).Uint64(), validators)
+	}
+	_, err = env.sfcCall(validator2, nil, "withdraw", id2, big.NewInt(1))
+	require.NoError(err)
+	env.checkViews(delegators, []idx.ValidatorID{1, 2, 3, 4})
+
+	// owner methods
+	_, err = env.sfcCall(delegator, nil, "updateTreasuryAddress", delegator)
+	require.Error(err)
+	_, err = env.sfcCall(owner, nil, "updateTreasuryAddress", delegator)
+	require.NoError(err)
+	env.sealEpoch(3600, validators)
+	_, err = env.sfcCall(owner, nil, "mintFTM", delegator, utils.ToU2U(100), "test")
+	require.NoError(err)
+	_, err = env.sfcCall(owner, nil, "burnFTM", utils.ToU2U(1))
+	require.NoError(err)
+	_, err = env.sfcCall(owner, nil, "transferOwnership", delegator)
+	require.NoError(err)
+	_, err = env.sfcCall(owner, nil, "renounceOwnership")
+	require.Error(err)
+	_, err = env.sfcCall(delegator, nil, "renounceOwnership")
+	require.NoError(err)
+	_, err = env.sfcCall(delegator, nil, "initialize", big.NewInt(0), big.NewInt(0), delegator, delegator, delegator, delegator)
+	require.Error(err)
+	env.checkViews(delegators, []idx.ValidatorID{1, 2, 3, 4})
+}
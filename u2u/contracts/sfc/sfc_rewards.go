@@ -0,0 +1,222 @@
+package sfc
+
+import (
+	"math/big"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+)
+
+func (c *context) highestPayableEpoch(validatorID *big.Int) *big.Int {
+	currentSealedEpoch := c.currentSealedEpoch()
+	deactivatedEpoch := c.validator(validatorID, validatorDeactivatedEpoch)
+	if deactivatedEpoch.Sign() != 0 {
+		if currentSealedEpoch.Cmp(deactivatedEpoch) < 0 {
+			return currentSealedEpoch
+		}
+		return deactivatedEpoch
+	}
+	return currentSealedEpoch
+}
+
+func (c *context) isLockedUpAtEpoch(delegator common.Address, toValidatorID *big.Int, epoch *big.Int) bool {
+	epochEndTime := c.getState(epochSnapshotSlotOf(epoch, snapshotEndTime))
+	return c.lockup(delegator, toValidatorID, lockupFromEpoch).Cmp(epoch) <= 0 &&
+		epochEndTime.Cmp(c.lockup(delegator, toValidatorID, lockupEndTime)) <= 0
+}
+
+// highestLockupEpoch finds the highest epoch such that isLockedUpAtEpoch returns true (using binary search)
+func (c *context) highestLockupEpoch(delegator common.Address, validatorID *big.Int) *big.Int {
+	l := c.lockup(delegator, validatorID, lockupFromEpoch)
+	r := c.currentSealedEpoch()
+	if c.isLockedUpAtEpoch(delegator, validatorID, r) {
+		return r
+	}
+	if !c.isLockedUpAtEpoch(delegator, validatorID, l) {
+		return new(big.Int)
+	}
+	if l.Cmp(r) > 0 {
+		return new(big.Int)
+	}
+	for l.Cmp(r) < 0 {
+		m := new(big.Int).Rsh(add(l, r), 1)
+		if c.isLockedUpAtEpoch(delegator, validatorID, m) {
+			l = add(m, common.Big1)
+		} else {
+			r = m
+		}
+	}
+	if r.Sign() == 0 {
+		return new(big.Int)
+	}
+	return sub(r, common.Big1)
+}
+
+func (c *context) newRewardsOf(stakeAmount *big.Int, toValidatorID *big.Int, fromEpoch *big.Int, toEpoch *big.Int) (*big.Int, error) {
+	if fromEpoch.Cmp(toEpoch) >= 0 {
+		return new(big.Int), nil
+	}
+	stashedRate := c.getState(epochValidatorSlot(fromEpoch, snapshotAccumulatedRewardPerToken, toValidatorID))
+	currentRate := c.getState(epochValidatorSlot(toEpoch, snapshotAccumulatedRewardPerToken, toValidatorID))
+	diff, err := safeSub(currentRate, stashedRate)
+	if err != nil {
+		return nil, err
+	}
+	return mulDiv(diff, stakeAmount, decimalUnit)
+}
+
+func (c *context) newRewards(delegator common.Address, toValidatorID *big.Int) (rewards, error) {
+	stashedUntil := c.getState(delegationSlot(stashedRewardsUntilEpochSlot, delegator, toValidatorID))
+	payableUntil := c.highestPayableEpoch(toValidatorID)
+	lockedUntil := c.highestLockupEpoch(delegator, toValidatorID)
+	if lockedUntil.Cmp(payableUntil) > 0 {
+		lockedUntil = payableUntil
+	}
+	if lockedUntil.Cmp(stashedUntil) < 0 {
+		lockedUntil = stashedUntil
+	}
+
+	lockedStake := c.lockup(delegator, toValidatorID, lockupLockedStake)
+	duration := c.lockup(delegator, toValidatorID, lockupDuration)
+	wholeStake := c.stake(delegator, toValidatorID)
+	unlockedStake, err := safeSub(wholeStake, lockedStake)
+	if err != nil {
+		return rewards{}, err
+	}
+
+	// count reward for locked stake during lockup epochs
+	fullReward, err := c.newRewardsOf(lockedStake, toValidatorID, stashedUntil, lockedUntil)
+	if err != nil {
+		return rewards{}, err
+	}
+	plReward, err := c.scaleLockupReward(fullReward, duration)
+	if err != nil {
+		return rewards{}, err
+	}
+	// count reward for unlocked stake during lockup epochs
+	fullReward, err = c.newRewardsOf(unlockedStake, toValidatorID, stashedUntil, lockedUntil)
+	if err != nil {
+		return rewards{}, err
+	}
+	puReward, err := c.scaleLockupReward(fullReward, common.Big0)
+	if err != nil {
+		return rewards{}, err
+	}
+	// count lockup reward for unlocked stake during unlocked epochs
+	fullReward, err = c.newRewardsOf(wholeStake, toValidatorID, lockedUntil, payableUntil)
+	if err != nil {
+		return rewards{}, err
+	}
+	wuReward, err := c.scaleLockupReward(fullReward, common.Big0)
+	if err != nil {
+		return rewards{}, err
+	}
+	return sumRewards(plReward, puReward, wuReward)
+}
+
+func (c *context) pendingRewards(delegator common.Address, toValidatorID *big.Int) (rewards, error) {
+	reward, err := c.newRewards(delegator, toValidatorID)
+	if err != nil {
+		return rewards{}, err
+	}
+	return sumRewards(c.getRewards(delegationSlot(rewardsStashSlot, delegator, toValidatorID)), reward)
+}
+
+func (c *context) stashRewards(delegator common.Address, toValidatorID *big.Int) (bool, error) {
+	nonStashedReward, err := c.newRewards(delegator, toValidatorID)
+	if err != nil {
+		return false, err
+	}
+	c.setState(delegationSlot(stashedRewardsUntilEpochSlot, delegator, toValidatorID), c.highestPayableEpoch(toValidatorID))
+
+	stashSlot := delegationSlot(rewardsStashSlot, delegator, toValidatorID)
+	stash, err := sumRewards(c.getRewards(stashSlot), nonStashedReward)
+	if err != nil {
+		return false, err
+	}
+	c.setRewards(stashSlot, stash)
+
+	lockupStashSlot := delegationSlot(stashedLockupRewardsSlot, delegator, toValidatorID)
+	lockupStash, err := sumRewards(c.getRewards(lockupStashSlot), nonStashedReward)
+	if err != nil {
+		return false, err
+	}
+	c.setRewards(lockupStashSlot, lockupStash)
+
+	if !c.isLockedUp(delegator, toValidatorID) {
+		c.deleteLockup(delegator, toValidatorID)
+		c.setRewards(lockupStashSlot, zeroRewards())
+	}
+	return !nonStashedReward.isZero(), nil
+}
+
+func (c *context) claimRewards(delegator common.Address, toValidatorID *big.Int) (rewards, error) {
+	if _, err := c.stashRewards(delegator, toValidatorID); err != nil {
+		return rewards{}, err
+	}
+	stashSlot := delegationSlot(rewardsStashSlot, delegator, toValidatorID)
+	reward := c.getRewards(stashSlot)
+	totalReward, err := reward.total()
+	if err != nil {
+		return rewards{}, err
+	}
+	if err := require(totalReward.Sign() != 0, "zero rewards"); err != nil {
+		return rewards{}, err
+	}
+	c.setRewards(stashSlot, zeroRewards())
+	// It's important that we mint after erasing (protection against Re-Entrancy)
+	if err := c.mintNativeToken(totalReward); err != nil {
+		return rewards{}, err
+	}
+	return reward, nil
+}
+
+func handleStashRewards(c *context, args []interface{}) ([]byte, error) {
+	delegator := args[0].(common.Address)
+	toValidatorID := args[1].(*big.Int)
+	updated, err := c.stashRewards(delegator, toValidatorID)
+	if err != nil {
+		return nil, err
+	}
+	return nil, require(updated, "nothing to stash")
+}
+
+func handleClaimRewards(c *context, args []interface{}) ([]byte, error) {
+	delegator := c.caller
+	toValidatorID := args[0].(*big.Int)
+	reward, err := c.claimRewards(delegator, toValidatorID)
+	if err != nil {
+		return nil, err
+	}
+	totalReward, err := reward.total()
+	if err != nil {
+		return nil, err
+	}
+	// It's important that we transfer after erasing (protection against Re-Entrancy)
+	c.payOut(totalReward)
+	return nil, c.emit("ClaimedRewards", []common.Hash{addressKey(delegator), uintKey(toValidatorID)},
+		reward.lockupExtraReward, reward.lockupBaseReward, reward.unlockedReward)
+}
+
+func handleRestakeRewards(c *context, args []interface{}) ([]byte, error) {
+	delegator := c.caller
+	toValidatorID := args[0].(*big.Int)
+	reward, err := c.claimRewards(delegator, toValidatorID)
+	if err != nil {
+		return nil, err
+	}
+	lockupReward, err := safeAdd(reward.lockupExtraReward, reward.lockupBaseReward)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := safeAdd(lockupReward, reward.unlockedReward)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.delegate(delegator, toValidatorID, amount); err != nil {
+		return nil, err
+	}
+	lockedStake := c.lockup(delegator, toValidatorID, lockupLockedStake)
+	c.setLockup(delegator, toValidatorID, lockupLockedStake, add(lockedStake, lockupReward))
+	return nil, c.emit("RestakedRewards", []common.Hash{addressKey(delegator), uintKey(toValidatorID)},
+		reward.lockupExtraReward, reward.lockupBaseReward, reward.unlockedReward)
+}
@@ -0,0 +1,368 @@
+package sfc
+
+import (
+	"math/big"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/u2u/contracts/internal/precompiled"
+)
+
+func (c *context) rawCreateValidator(auth common.Address, validatorID *big.Int, pubkey []byte, status *big.Int,
+	createdEpoch *big.Int, createdTime *big.Int, deactivatedEpoch *big.Int, deactivatedTime *big.Int) error {
+	if err := require(c.getState(validatorIDSlotOf(auth)).Sign() == 0, "validator already exists"); err != nil {
+		return err
+	}
+	c.setState(validatorIDSlotOf(auth), validatorID)
+	c.setValidator(validatorID, validatorStatus, status)
+	c.setValidator(validatorID, validatorCreatedEpoch, createdEpoch)
+	c.setValidator(validatorID, validatorCreatedTime, createdTime)
+	c.setValidator(validatorID, validatorDeactivatedTime, deactivatedTime)
+	c.setValidator(validatorID, validatorDeactivatedEpoch, deactivatedEpoch)
+	c.setAddress(validatorFieldSlot(validatorID, validatorAuth), auth)
+	c.setBytes(validatorPubkeySlotOf(validatorID), pubkey)
+
+	topics := []common.Hash{uintKey(validatorID)}
+	if err := c.emit("CreatedValidator", []common.Hash{uintKey(validatorID), addressKey(auth)}, createdEpoch, createdTime); err != nil {
+		return err
+	}
+	if deactivatedEpoch.Sign() != 0 {
+		if err := c.emit("DeactivatedValidator", topics, deactivatedEpoch, deactivatedTime); err != nil {
+			return err
+		}
+	}
+	if status.Sign() != 0 {
+		if err := c.emit("ChangedValidatorStatus", topics, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *context) rawDelegate(delegator common.Address, toValidatorID *big.Int, amount *big.Int) error {
+	if err := require(amount.Sign() > 0, "zero amount"); err != nil {
+		return err
+	}
+	if _, err := c.stashRewards(delegator, toValidatorID); err != nil {
+		return err
+	}
+
+	stake, err := safeAdd(c.stake(delegator, toValidatorID), amount)
+	if err != nil {
+		return err
+	}
+	c.setStake(delegator, toValidatorID, stake)
+	origStake := c.validator(toValidatorID, validatorReceivedStake)
+	receivedStake, err := safeAdd(origStake, amount)
+	if err != nil {
+		return err
+	}
+	c.setValidator(toValidatorID, validatorReceivedStake, receivedStake)
+	if err := c.addTo(slotOf(totalStakeSlot), amount); err != nil {
+		return err
+	}
+	if c.validator(toValidatorID, validatorStatus).Sign() == okStatus {
+		if err := c.addTo(slotOf(totalActiveStakeSlot), amount); err != nil {
+			return err
+		}
+	}
+
+	if err := c.syncValidator(toValidatorID); err != nil {
+		return err
+	}
+	return c.emit("Delegated", []common.Hash{addressKey(delegator), uintKey(toValidatorID)}, amount)
+}
+
+func (c *context) delegate(delegator common.Address, toValidatorID *big.Int, amount *big.Int) error {
+	if err := require(c.validatorExists(toValidatorID), "validator doesn't exist"); err != nil {
+		return err
+	}
+	if err := require(c.validator(toValidatorID, validatorStatus).Sign() == okStatus, "validator isn't active"); err != nil {
+		return err
+	}
+	if err := c.rawDelegate(delegator, toValidatorID, amount); err != nil {
+		return err
+	}
+	ok, err := c.checkDelegatedStakeLimit(toValidatorID)
+	if err != nil {
+		return err
+	}
+	return require(ok, "validator's delegations limit is exceeded")
+}
+
+func (c *context) rawUndelegate(delegator common.Address, toValidatorID *big.Int, amount *big.Int, strict bool) error {
+	c.setStake(delegator, toValidatorID, sub(c.stake(delegator, toValidatorID), amount))
+	if err := c.subFrom(validatorFieldSlot(toValidatorID, validatorReceivedStake), amount); err != nil {
+		return err
+	}
+	if err := c.subFrom(slotOf(totalStakeSlot), amount); err != nil {
+		return err
+	}
+	if c.validator(toValidatorID, validatorStatus).Sign() == okStatus {
+		if err := c.subFrom(slotOf(totalActiveStakeSlot), amount); err != nil {
+			return err
+		}
+	}
+
+	selfStakeAfterwards := c.getSelfStake(toValidatorID)
+	if selfStakeAfterwards.Sign() != 0 && c.validator(toValidatorID, validatorStatus).Sign() == okStatus {
+		if selfStakeAfterwards.Cmp(c.consts().minSelfStake()) < 0 {
+			if strict {
+				return precompiled.RevertError("insufficient self-stake")
+			}
+			if err := c.setValidatorDeactivated(toValidatorID, big.NewInt(withdrawnBit)); err != nil {
+				return err
+			}
+		}
+		ok, err := c.checkDelegatedStakeLimit(toValidatorID)
+		if err != nil {
+			return err
+		}
+		return require(ok, "validator's delegations limit is exceeded")
+	}
+	return c.setValidatorDeactivated(toValidatorID, big.NewInt(withdrawnBit))
+}
+
+func getSlashingPenalty(amount *big.Int, isCheater bool, refundRatio *big.Int) (*big.Int, error) {
+	if !isCheater || refundRatio.Cmp(decimalUnit) >= 0 {
+		return new(big.Int), nil
+	}
+	// round penalty upwards (ceiling) to prevent dust amount attacks
+	penalty, err := mulDiv(amount, sub(decimalUnit, refundRatio), decimalUnit)
+	if err != nil {
+		return nil, err
+	}
+	if penalty, err = safeAdd(penalty, common.Big1); err != nil {
+		return nil, err
+	}
+	if penalty.Cmp(amount) > 0 {
+		return amount, nil
+	}
+	return penalty, nil
+}
+
+func handleSetGenesisValidator(c *context, args []interface{}) ([]byte, error) {
+	if err := c.onlyDriver(); err != nil {
+		return nil, err
+	}
+	auth := args[0].(common.Address)
+	validatorID := args[1].(*big.Int)
+	pubkey := args[2].([]byte)
+	status := args[3].(*big.Int)
+	createdEpoch := args[4].(*big.Int)
+	createdTime := args[5].(*big.Int)
+	deactivatedEpoch := args[6].(*big.Int)
+	deactivatedTime := args[7].(*big.Int)
+	if err := c.rawCreateValidator(auth, validatorID, pubkey, status, createdEpoch, createdTime, deactivatedEpoch, deactivatedTime); err != nil {
+		return nil, err
+	}
+	if validatorID.Cmp(c.getState(slotOf(lastValidatorIDSlot))) > 0 {
+		c.setState(slotOf(lastValidatorIDSlot), validatorID)
+	}
+	return nil, nil
+}
+
+func handleSetGenesisDelegation(c *context, args []interface{}) ([]byte, error) {
+	if err := c.onlyDriver(); err != nil {
+		return nil, err
+	}
+	delegator := args[0].(common.Address)
+	toValidatorID := args[1].(*big.Int)
+	stake := args[2].(*big.Int)
+	lockedStake := args[3].(*big.Int)
+	fromEpoch := args[4].(*big.Int)
+	endTime := args[5].(*big.Int)
+	duration := args[6].(*big.Int)
+	earlyUnlockPenalty := args[7].(*big.Int)
+	reward := args[8].(*big.Int)
+
+	if err := c.rawDelegate(delegator, toValidatorID, stake); err != nil {
+		return nil, err
+	}
+	stashSlot := delegationSlot(rewardsStashSlot, delegator, toValidatorID)
+	c.setState(offsetSlot(stashSlot, rewardsUnlockedReward), reward)
+	if err := c.mintNativeToken(stake); err != nil {
+		return nil, err
+	}
+	if lockedStake.Sign() != 0 {
+		if err := require(lockedStake.Cmp(stake) <= 0, "locked stake is greater than the whole stake"); err != nil {
+			return nil, err
+		}
+		c.setLockup(delegator, toValidatorID, lockupLockedStake, lockedStake)
+		c.setLockup(delegator, toValidatorID, lockupFromEpoch, fromEpoch)
+		c.setLockup(delegator, toValidatorID, lockupEndTime, endTime)
+		c.setLockup(delegator, toValidatorID, lockupDuration, duration)
+		lockupStashSlot := delegationSlot(stashedLockupRewardsSlot, delegator, toValidatorID)
+		c.setState(offsetSlot(lockupStashSlot, rewardsLockupExtraReward), earlyUnlockPenalty)
+		return nil, c.emit("LockedUpStake", []common.Hash{addressKey(delegator), uintKey(toValidatorID)}, duration, lockedStake)
+	}
+	return nil, nil
+}
+
+func handleCreateValidator(c *context, args []interface{}) ([]byte, error) {
+	pubkey := args[0].([]byte)
+	if err := require(c.value.Cmp(c.consts().minSelfStake()) >= 0, "insufficient self-stake"); err != nil {
+		return nil, err
+	}
+	if err := require(len(pubkey) > 0, "empty pubkey"); err != nil {
+		return nil, err
+	}
+	validatorID := add(c.getState(slotOf(lastValidatorIDSlot)), common.Big1)
+	c.setState(slotOf(lastValidatorIDSlot), validatorID)
+	if err := c.rawCreateValidator(c.caller, validatorID, pubkey, big.NewInt(okStatus), c.currentEpoch(), c.now(), common.Big0, common.Big0); err != nil {
+		return nil, err
+	}
+	return nil, c.delegate(c.caller, validatorID, c.value)
+}
+
+func handleDelegate(c *context, args []interface{}) ([]byte, error) {
+	toValidatorID := args[0].(*big.Int)
+	return nil, c.delegate(c.caller, toValidatorID, c.value)
+}
+
+func handleUndelegate(c *context, args []interface{}) ([]byte, error) {
+	delegator := c.caller
+	toValidatorID := args[0].(*big.Int)
+	wrID := args[1].(*big.Int)
+	amount := args[2].(*big.Int)
+
+	if _, err := c.stashRewards(delegator, toValidatorID); err != nil {
+		return nil, err
+	}
+	if err := require(amount.Sign() > 0, "zero amount"); err != nil {
+		return nil, err
+	}
+	unlockedStake, err := c.getUnlockedStake(delegator, toValidatorID)
+	if err != nil {
+		return nil, err
+	}
+	if err := require(amount.Cmp(unlockedStake) <= 0, "not enough unlocked stake"); err != nil {
+		return nil, err
+	}
+	requestAmount := c.getState(withdrawalRequestSlotOf(delegator, toValidatorID, wrID, withdrawalAmount))
+	if err := require(requestAmount.Sign() == 0, "wrID already exists"); err != nil {
+		return nil, err
+	}
+
+	if err := c.rawUndelegate(delegator, toValidatorID, amount, true); err != nil {
+		return nil, err
+	}
+
+	c.setState(withdrawalRequestSlotOf(delegator, toValidatorID, wrID, withdrawalAmount), amount)
+	c.setState(withdrawalRequestSlotOf(delegator, toValidatorID, wrID, withdrawalEpoch), c.currentEpoch())
+	c.setState(withdrawalRequestSlotOf(delegator, toValidatorID, wrID, withdrawalTime), c.now())
+
+	if err := c.syncValidator(toValidatorID); err != nil {
+		return nil, err
+	}
+	return nil, c.emit("Undelegated", []common.Hash{addressKey(delegator), uintKey(toValidatorID), uintKey(wrID)}, amount)
+}
+
+func handleWithdraw(c *context, args []interface{}) ([]byte, error) {
+	delegator := c.caller
+	toValidatorID := args[0].(*big.Int)
+	wrID := args[1].(*big.Int)
+
+	requestEpoch := c.getState(withdrawalRequestSlotOf(delegator, toValidatorID, wrID, withdrawalEpoch))
+	requestTime := c.getState(withdrawalRequestSlotOf(delegator, toValidatorID, wrID, withdrawalTime))
+	amount := c.getState(withdrawalRequestSlotOf(delegator, toValidatorID, wrID, withdrawalAmount))
+	if err := require(requestEpoch.Sign() != 0, "request doesn't exist"); err != nil {
+		return nil, err
+	}
+
+	deactivatedTime := c.validator(toValidatorID, validatorDeactivatedTime)
+	if deactivatedTime.Sign() != 0 && deactivatedTime.Cmp(requestTime) < 0 {
+		requestTime = deactivatedTime
+		requestEpoch = c.validator(toValidatorID, validatorDeactivatedEpoch)
+	}
+
+	consts := c.consts()
+	if err := require(c.now().Cmp(add(requestTime, consts.withdrawalPeriodTime())) >= 0, "not enough time passed"); err != nil {
+		return nil, err
+	}
+	if err := require(c.currentEpoch().Cmp(add(requestEpoch, consts.withdrawalPeriodEpochs())) >= 0, "not enough epochs passed"); err != nil {
+		return nil, err
+	}
+
+	isCheater := c.isSlashed(toValidatorID)
+	penalty, err := getSlashingPenalty(amount, isCheater, c.getState(slashingRefundRatioSlotOf(toValidatorID)))
+	if err != nil {
+		return nil, err
+	}
+	c.setState(withdrawalRequestSlotOf(delegator, toValidatorID, wrID, withdrawalEpoch), common.Big0)
+	c.setState(withdrawalRequestSlotOf(delegator, toValidatorID, wrID, withdrawalTime), common.Big0)
+	c.setState(withdrawalRequestSlotOf(delegator, toValidatorID, wrID, withdrawalAmount), common.Big0)
+
+	c.setState(slotOf(totalSlashedStakeSlot), add(c.getState(slotOf(totalSlashedStakeSlot)), penalty))
+	if err := require(amount.Cmp(penalty) > 0, "stake is fully slashed"); err != nil {
+		return nil, err
+	}
+	// It's important that we transfer after erasing (protection against Re-Entrancy)
+	c.payOut(sub(amount, penalty))
+	if err := c.burnFTM(penalty); err != nil {
+		return nil, err
+	}
+	return nil, c.emit("Withdrawn", []common.Hash{addressKey(delegator), uintKey(toValidatorID), uintKey(wrID)}, amount)
+}
+
+func handleDeactivateValidator(c *context, args []interface{}) ([]byte, error) {
+	if err := c.onlyDriver(); err != nil {
+		return nil, err
+	}
+	validatorID := args[0].(*big.Int)
+	status := args[1].(*big.Int)
+	if err := require(status.Sign() != okStatus, "wrong status"); err != nil {
+		return nil, err
+	}
+	if err := c.setValidatorDeactivated(validatorID, status); err != nil {
+		return nil, err
+	}
+	return nil, c.syncValidator(validatorID)
+}
+
+func handleSyncValidator(c *context, args []interface{}) ([]byte, error) {
+	validatorID := args[0].(*big.Int)
+	return nil, c.syncValidator(validatorID)
+}
+
+// handleRecountVotes has no effect on the SFC storage, votes are recounted by the vote book contract
+func handleRecountVotes(c *context, args []interface{}) ([]byte, error) {
+	return nil, nil
+}
+
+func handleUpdateSlashingRefundRatio(c *context, args []interface{}) ([]byte, error) {
+	if err := c.onlyOwner(); err != nil {
+		return nil, err
+	}
+	validatorID := args[0].(*big.Int)
+	refundRatio := args[1].(*big.Int)
+	if err := require(c.isSlashed(validatorID), "validator isn't slashed"); err != nil {
+		return nil, err
+	}
+	if err := require(refundRatio.Cmp(decimalUnit) <= 0, "must be less than or equal to 1.0"); err != nil {
+		return nil, err
+	}
+	c.setState(slashingRefundRatioSlotOf(validatorID), refundRatio)
+	return nil, c.emit("UpdatedSlashingRefundRatio", []common.Hash{uintKey(validatorID)}, refundRatio)
+}
+
+func handleMintFTM(c *context, args []interface{}) ([]byte, error) {
+	if err := c.onlyOwner(); err != nil {
+		return nil, err
+	}
+	receiver := args[0].(common.Address)
+	amount := args[1].(*big.Int)
+	justification := args[2].(string)
+	if err := c.mintNativeToken(amount); err != nil {
+		return nil, err
+	}
+	c.payOut(amount)
+	return nil, c.emit("InflatedFTM", []common.Hash{addressKey(receiver)}, amount, justification)
+}
+
+func handleBurnFTM(c *context, args []interface{}) ([]byte, error) {
+	if err := c.onlyOwner(); err != nil {
+		return nil, err
+	}
+	amount := args[0].(*big.Int)
+	return nil, c.burnFTM(amount)
+}
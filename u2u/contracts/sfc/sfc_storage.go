@@ -0,0 +1,153 @@
+package sfc
+
+import (
+	"math/big"
+
+	"github.com/unicornultrafoundation/go-u2u/common"
+	"github.com/unicornultrafoundation/go-u2u/crypto"
+)
+
+// Storage layout of the SFC contract (SFCState), shared by SFC and SFCLib.
+// Slots 1-50 and 52-101 are the reserved gaps of Initializable and Ownable.
+const (
+	initializableSlot            = 0
+	ownerSlot                    = 51
+	nodeSlot                     = 102
+	currentSealedEpochSlot       = 103
+	validatorSlot                = 104
+	validatorIDSlot              = 105
+	validatorPubkeySlot          = 106
+	lastValidatorIDSlot          = 107
+	totalStakeSlot               = 108
+	totalActiveStakeSlot         = 109
+	totalSlashedStakeSlot        = 110
+	rewardsStashSlot             = 111
+	stashedRewardsUntilEpochSlot = 112
+	withdrawalRequestSlot        = 113
+	stakeSlot                    = 114
+	lockupInfoSlot               = 115
+	stashedLockupRewardsSlot     = 116
+	totalSupplySlot              = 118
+	epochSnapshotSlot            = 119
+	slashingRefundRatioSlot      = 122
+	stakeTokenizerAddressSlot    = 123
+	minGasPriceSlot              = 126
+	treasuryAddressSlot          = 127
+	libAddressSlot               = 128
+	constantsManagerSlot         = 129
+	voteBookAddressSlot          = 130
+)
+
+// Member offsets of the Validator struct
+const (
+	validatorStatus = iota
+	validatorDeactivatedTime
+	validatorDeactivatedEpoch
+	validatorReceivedStake
+	validatorCreatedEpoch
+	validatorCreatedTime
+	validatorAuth
+)
+
+// Member offsets of the Rewards struct
+const (
+	rewardsLockupExtraReward = iota
+	rewardsLockupBaseReward
+	rewardsUnlockedReward
+)
+
+// Member offsets of the LockedDelegation struct
+const (
+	lockupLockedStake = iota
+	lockupFromEpoch
+	lockupEndTime
+	lockupDuration
+)
+
+// Member offsets of the WithdrawalRequest struct
+const (
+	withdrawalEpoch = iota
+	withdrawalTime
+	withdrawalAmount
+)
+
+// Member offsets of the EpochSnapshot struct
+const (
+	snapshotReceivedStake = iota
+	snapshotAccumulatedRewardPerToken
+	snapshotAccumulatedUptime
+	snapshotAccumulatedOriginatedTxsFee
+	snapshotOfflineTime
+	snapshotOfflineBlocks
+	snapshotValidatorIDs
+	snapshotEndTime
+	snapshotEpochFee
+	snapshotTotalBaseRewardWeight
+	snapshotTotalTxRewardWeight
+	snapshotBaseRewardPerSecond
+	snapshotTotalStake
+	snapshotTotalSupply
+)
+
+// slotOf returns the storage slot of a plain state variable.
+func slotOf(slot int64) common.Hash {
+	return common.BigToHash(big.NewInt(slot))
+}
+
+// offsetSlot returns the slot of a struct member or an array element.
+func offsetSlot(slot common.Hash, offset int64) common.Hash {
+	return common.BigToHash(new(big.Int).Add(slot.Big(), big.NewInt(offset)))
+}
+
+// mappingSlot returns the slot of the mapping value for the given key.
+func mappingSlot(key common.Hash, slot common.Hash) common.Hash {
+	return crypto.Keccak256Hash(key.Bytes(), slot.Bytes())
+}
+
+// arrayDataSlot returns the slot of the first element of a dynamic array or
+// of the data of a long byte string.
+func arrayDataSlot(slot common.Hash) common.Hash {
+	return crypto.Keccak256Hash(slot.Bytes())
+}
+
+func addressKey(addr common.Address) common.Hash {
+	return common.BytesToHash(addr.Bytes())
+}
+
+func uintKey(v *big.Int) common.Hash {
+	return common.BigToHash(v)
+}
+
+func validatorFieldSlot(validatorID *big.Int, field int64) common.Hash {
+	return offsetSlot(mappingSlot(uintKey(validatorID), slotOf(validatorSlot)), field)
+}
+
+func validatorIDSlotOf(auth common.Address) common.Hash {
+	return mappingSlot(addressKey(auth), slotOf(validatorIDSlot))
+}
+
+func validatorPubkeySlotOf(validatorID *big.Int) common.Hash {
+	return mappingSlot(uintKey(validatorID), slotOf(validatorPubkeySlot))
+}
+
+// delegationSlot returns the slot of a mapping(address => mapping(uint256 => T)) value.
+func delegationSlot(slot int64, delegator common.Address, toValidatorID *big.Int) common.Hash {
+	return mappingSlot(uintKey(toValidatorID), mappingSlot(addressKey(delegator), slotOf(slot)))
+}
+
+func withdrawalRequestSlotOf(delegator common.Address, toValidatorID *big.Int, wrID *big.Int, field int64) common.Hash {
+	return offsetSlot(mappingSlot(uintKey(wrID), delegationSlot(withdrawalRequestSlot, delegator, toValidatorID)), field)
+}
+
+func epochSnapshotSlotOf(epoch *big.Int, field int64) common.Hash {
+	return offsetSlot(mappingSlot(uintKey(epoch), slotOf(epochSnapshotSlot)), field)
+}
+
+// epochValidatorSlot returns the slot of a per-validator mapping of an epoch snapshot.
+func epochValidatorSlot(epoch *big.Int, field int64, validatorID *big.Int) common.Hash {
+	return mappingSlot(uintKey(validatorID), epochSnapshotSlotOf(epoch, field))
+}
+
+func slashingRefundRatioSlotOf(validatorID *big.Int) common.Hash {
+	return mappingSlot(uintKey(validatorID), slotOf(slashingRefundRatioSlot))
+}
@@ -93,6 +93,18 @@ func (u Upgrades) EncodeRLP(w io.Writer) error {
 	if u.Llr {
 		bitmap.V |= llrBit
 	}
+	if u.Shanghai {
+		bitmap.V |= shanghaiBit
+	}
+	if u.Cancun {
+		bitmap.V |= cancunBit
+	}
+	if u.Prague {
+		bitmap.V |= pragueBit
+	}
+	if u.P256Verify {
+		bitmap.V |= p256VerifyBit
+	}
 	return rlp.Encode(w, &bitmap)
 }
 
@@ -108,6 +120,10 @@ func (u *Upgrades) DecodeRLP(s *rlp.Stream) error {
 	u.Berlin = (bitmap.V & berlinBit) != 0
 	u.London = (bitmap.V & londonBit) != 0
 	u.Llr = (bitmap.V & llrBit) != 0
+	u.Shanghai = (bitmap.V & shanghaiBit) != 0
+	u.Cancun = (bitmap.V & cancunBit) != 0
+	u.Prague = (bitmap.V & pragueBit) != 0
+	u.P256Verify = (bitmap.V & p256VerifyBit) != 0
 	return nil
 }
 
@@ -79,6 +79,58 @@ func TestRulesLondonRLP(t *testing.T) {
 	require.True(decodedRules.Upgrades.London)
 }
 
+func TestRulesShanghaiRLP(t *testing.T) {
+	rules := MainNetRules()
+	rules.Upgrades.Shanghai = true
+	require := require.New(t)
+
+	b, err := rlp.EncodeToBytes(rules)
+	require.NoError(err)
+
+	decodedRules := Rules{}
+	require.NoError(rlp.DecodeBytes(b, &decodedRules))
+
+	require.Equal(rules.String(), decodedRules.String())
+	require.True(decodedRules.Upgrades.Berlin)
+	require.True(decodedRules.Upgrades.London)
+	require.True(decodedRules.Upgrades.Shanghai)
+}
+
+func TestRulesCancunRLP(t *testing.T) {
+	rules := MainNetRules()
+	rules.Upgrades.Shanghai = true
+	rules.Upgrades.Cancun = true
+	require := require.New(t)
+
+	b, err := rlp.EncodeToBytes(rules)
+	require.NoError(err)
+
+	decodedRules := Rules{}
+	require.NoError(rlp.DecodeBytes(b, &decodedRules))
+
+	require.Equal(rules.String(), decodedRules.String())
+	require.True(decodedRules.Upgrades.Shanghai)
+	require.True(decodedRules.Upgrades.Cancun)
+}
+
+func TestFakeNetPragueRules(t *testing.T) {
+	require := require.New(t)
+
+	block := big.NewInt(1)
+	cfg := FakeNetRules().EvmChainConfig([]UpgradeHeight{{Upgrades: FakeNetRules().Upgrades}})
+	require.True(cfg.IsLondon(block))
+	require.False(cfg.IsShanghai(block))
+	require.False(cfg.IsCancun(block))
+	require.False(cfg.IsPrague(block))
+	require.False(cfg.IsP256Verify(block))
+
+	cfg = FakeNetPragueRules().EvmChainConfig([]UpgradeHeight{{Upgrades: FakeNetPragueRules().Upgrades}})
+	require.True(cfg.IsShanghai(block))
+	require.True(cfg.IsCancun(block))
+	require.True(cfg.IsPrague(block))
+	require.True(cfg.IsP256Verify(block))
+}
+
 func TestRulesBerlinCompatibilityRLP(t *testing.T) {
 	require := require.New(t)
 
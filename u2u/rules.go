@@ -26,6 +26,10 @@ const (
 	berlinBit              = 1 << 0
 	londonBit              = 1 << 1
 	llrBit                 = 1 << 2
+	shanghaiBit            = 1 << 3
+	cancunBit              = 1 << 4
+	pragueBit              = 1 << 5
+	p256VerifyBit          = 1 << 6
 )
 
 var DefaultVMConfig = vm.Config{
@@ -122,9 +126,14 @@ type BlocksRules struct {
 }
 
 type Upgrades struct {
-	Berlin bool
-	London bool
-	Llr    bool
+	Berlin   bool
+	London   bool
+	Llr      bool
+	Shanghai bool
+	Cancun   bool
+	Prague   bool
+	// P256Verify enables the RIP-7212 secp256r1 signature verification precompile
+	P256Verify bool
 }
 
 type UpgradeHeight struct {
@@ -138,6 +147,10 @@ func (r Rules) EvmChainConfig(hh []UpgradeHeight) *ethparams.ChainConfig {
 	cfg.ChainID = new(big.Int).SetUint64(r.NetworkID)
 	cfg.BerlinBlock = nil
 	cfg.LondonBlock = nil
+	cfg.ShanghaiBlock = nil
+	cfg.CancunBlock = nil
+	cfg.PragueBlock = nil
+	cfg.P256VerifyBlock = nil
 	for i, h := range hh {
 		height := new(big.Int)
 		if i > 0 {
@@ -156,6 +169,34 @@ func (r Rules) EvmChainConfig(hh []UpgradeHeight) *ethparams.ChainConfig {
 		if !h.Upgrades.London {
 			cfg.LondonBlock = nil
 		}
+
+		if cfg.ShanghaiBlock == nil && h.Upgrades.Shanghai {
+			cfg.ShanghaiBlock = height
+		}
+		if !h.Upgrades.Shanghai {
+			cfg.ShanghaiBlock = nil
+		}
+
+		if cfg.CancunBlock == nil && h.Upgrades.Cancun {
+			cfg.CancunBlock = height
+		}
+		if !h.Upgrades.Cancun {
+			cfg.CancunBlock = nil
+		}
+
+		if cfg.PragueBlock == nil && h.Upgrades.Prague {
+			cfg.PragueBlock = height
+		}
+		if !h.Upgrades.Prague {
+			cfg.PragueBlock = nil
+		}
+
+		if cfg.P256VerifyBlock == nil && h.Upgrades.P256Verify {
+			cfg.P256VerifyBlock = height
+		}
+		if !h.Upgrades.P256Verify {
+			cfg.P256VerifyBlock = nil
+		}
 	}
 	return &cfg
 }
@@ -217,6 +258,16 @@ func FakeNetRules() Rules {
 	}
 }
 
+// FakeNetPragueRules returns fakenet rules with the upgrades up to Prague and P256Verify enabled
+func FakeNetPragueRules() Rules {
+	rules := FakeNetRules()
+	rules.Upgrades.Shanghai = true
+	rules.Upgrades.Cancun = true
+	rules.Upgrades.Prague = true
+	rules.Upgrades.P256Verify = true
+	return rules
+}
+
 // DefaultEconomyRules returns mainnet economy
 func DefaultEconomyRules() EconomyRules {
 	return EconomyRules{